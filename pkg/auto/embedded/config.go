@@ -0,0 +1,263 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// configContext holds what the config commands need to read and write a stack's configuration.
+type configContext struct {
+	inv    *invocation
+	proj   *workspace.Project
+	stack  backend.Stack
+	ps     *workspace.ProjectStack
+	psPath string
+}
+
+func (inv *invocation) loadConfig(ctx context.Context) (*configContext, error) {
+	proj, projPath, err := inv.project()
+	if err != nil {
+		return nil, err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return nil, err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return nil, err
+	}
+	psPath := projectStackPath(proj, projPath, s.Ref())
+	ps, err := workspace.LoadProjectStack(proj, psPath)
+	if err != nil {
+		return nil, err
+	}
+	return &configContext{inv: inv, proj: proj, stack: s, ps: ps, psPath: psPath}, nil
+}
+
+// parseKey parses a configuration key, treating keys without a namespace as belonging to the project.
+func (c *configContext) parseKey(key string) (config.Key, error) {
+	if !strings.Contains(key, tokens.TokenDelimiter) {
+		key = fmt.Sprintf("%s:%s", c.proj.Name, key)
+	}
+	k, err := config.ParseKey(key)
+	if err != nil {
+		return config.Key{}, fmt.Errorf("invalid configuration key: %w", err)
+	}
+	return k, nil
+}
+
+// set sets a configuration value, encrypting it if it is a secret.
+func (c *configContext) set(ctx context.Context, key, value string, secret, path bool) error {
+	k, err := c.parseKey(key)
+	if err != nil {
+		return err
+	}
+	v := config.NewValue(value)
+	if secret {
+		sm, err := c.inv.secretsManager(c.ps, c.psPath, false /* rotate */)
+		if err != nil {
+			return err
+		}
+		enc, err := sm.Encrypter()
+		if err != nil {
+			return err
+		}
+		ciphertext, err := enc.EncryptValue(ctx, value)
+		if err != nil {
+			return err
+		}
+		v = config.NewSecureValue(ciphertext)
+	}
+	return c.ps.Config.Set(k, v, path)
+}
+
+func (c *configContext) remove(key string, path bool) error {
+	k, err := c.parseKey(key)
+	if err != nil {
+		return err
+	}
+	return c.ps.Config.Remove(k, path)
+}
+
+// value returns the Automation API form of a configuration value, decrypting it if it is a secret and showSecrets
+// is true.
+func (c *configContext) value(v config.Value, showSecrets bool) (auto.ConfigValue, error) {
+	value := auto.ConfigValue{Secret: v.Secure()}
+	if v.Secure() && !showSecrets {
+		return value, nil
+	}
+
+	var dec config.Decrypter = config.NewPanicCrypter()
+	if v.Secure() {
+		sm, err := c.inv.secretsManager(c.ps, c.psPath, false /* rotate */)
+		if err != nil {
+			return auto.ConfigValue{}, err
+		}
+		if dec, err = sm.Decrypter(); err != nil {
+			return auto.ConfigValue{}, err
+		}
+	}
+	plaintext, err := v.Value(dec)
+	if err != nil {
+		return auto.ConfigValue{}, fmt.Errorf("could not decrypt configuration value: %w", err)
+	}
+	value.Value = plaintext
+	return value, nil
+}
+
+func (inv *invocation) runConfig(ctx context.Context, subcommand string, args []string) error {
+	if subcommand == "" {
+		return inv.configList(ctx, args)
+	}
+
+	flags := inv.newFlagSet("config " + subcommand)
+	path := flags.Bool("path", false, "")
+	switch subcommand {
+	case "get":
+		flags.Bool("json", false, "")
+		rest, err := parse(flags, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(rest) != 1 {
+			return errors.New("config get: expected a key")
+		}
+		c, err := inv.loadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if err := c.applyProjectConfig(); err != nil {
+			return err
+		}
+		k, err := c.parseKey(rest[0])
+		if err != nil {
+			return err
+		}
+		v, ok, err := c.ps.Config.Get(k, *path)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return fmt.Errorf("configuration key '%s' not found for stack '%s'", rest[0], c.stack.Ref())
+		}
+		value, err := c.value(v, true /* showSecrets */)
+		if err != nil {
+			return err
+		}
+		return inv.printJSON(value)
+	case "set":
+		secret := flags.Bool("secret", false, "")
+		flags.Bool("plaintext", false, "")
+		rest, err := parse(flags, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(rest) != 2 {
+			return errors.New("config set: expected a key and a value")
+		}
+		c, err := inv.loadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		if err := c.set(ctx, rest[0], rest[1], *secret, *path); err != nil {
+			return err
+		}
+		return c.ps.Save(c.psPath)
+	case "set-all":
+		plaintexts := flags.StringArray("plaintext", nil, "")
+		secrets := flags.StringArray("secret", nil, "")
+		if _, err := parse(flags, args[1:]); err != nil {
+			return err
+		}
+		c, err := inv.loadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		for _, values := range []struct {
+			pairs  []string
+			secret bool
+		}{{*plaintexts, false}, {*secrets, true}} {
+			for _, pair := range values.pairs {
+				key, value, ok := strings.Cut(pair, "=")
+				if !ok {
+					return fmt.Errorf("could not parse %q as a configuration key/value pair", pair)
+				}
+				if err := c.set(ctx, key, value, values.secret, *path); err != nil {
+					return err
+				}
+			}
+		}
+		return c.ps.Save(c.psPath)
+	case "rm", "rm-all":
+		rest, err := parse(flags, args[1:])
+		if err != nil {
+			return err
+		}
+		c, err := inv.loadConfig(ctx)
+		if err != nil {
+			return err
+		}
+		for _, key := range rest {
+			if err := c.remove(key, *path); err != nil {
+				return err
+			}
+		}
+		return c.ps.Save(c.psPath)
+	}
+	return fmt.Errorf("the command \"config %s\" is not supported by the embedded engine", subcommand)
+}
+
+func (inv *invocation) configList(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("config")
+	flags.Bool("json", false, "")
+	showSecrets := flags.Bool("show-secrets", false, "")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	c, err := inv.loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.applyProjectConfig(); err != nil {
+		return err
+	}
+	values := auto.ConfigMap{}
+	for k, v := range c.ps.Config {
+		value, err := c.value(v, *showSecrets)
+		if err != nil {
+			return err
+		}
+		values[k.String()] = value
+	}
+	return inv.printJSON(values)
+}
+
+// applyProjectConfig adds the project's configuration values to the stack's, as the CLI does when reading them.
+func (c *configContext) applyProjectConfig() error {
+	return workspace.ApplyProjectConfig(c.stack.Ref().Name().String(), c.proj, esc.Value{}, c.ps.Config, nil)
+}
@@ -0,0 +1,371 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package embedded runs Automation API operations in-process with the Pulumi engine, rather than by executing the
+// `pulumi` binary. This removes the need for a CLI installation, and the overhead of starting one for each operation:
+//
+//	stack, err := auto.UpsertStackInlineSource(ctx, "dev", "myproject", program,
+//		auto.Pulumi(embedded.NewPulumiCommand()),
+//		auto.EnvVars(map[string]string{"PULUMI_BACKEND_URL": "file://~"}))
+//
+// Only self-managed (filestate) backends are supported, and plugins must already be installed locally. The commands
+// understood are those the Automation API uses to manage stacks, their configuration, outputs and state, and to run
+// previews, updates, refreshes and destroys; any other command fails. Inline programs are run in-process.
+//
+// Commands may run concurrently. The environment variables given to a command configure its backend and
+// passphrase, and are passed to the plugins it runs, without changing the environment of the current process.
+// Settings that the engine reads from the process environment, such as PULUMI_HOME and the credentials of cloud
+// secrets providers, are shared by all commands.
+package embedded
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+
+	"github.com/spf13/pflag"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/cloud"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/pkg/v3/version"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// errorExitCode is the exit code reported for failed commands, matching that of the CLI.
+const errorExitCode = 255
+
+type pulumiCommand struct{}
+
+// NewPulumiCommand returns an auto.PulumiCommand that runs commands in-process with the Pulumi engine.
+func NewPulumiCommand() auto.InlinePulumiCommand {
+	return pulumiCommand{}
+}
+
+func (c pulumiCommand) Run(ctx context.Context,
+	workdir string,
+	additionalOutput []io.Writer,
+	additionalErrorOutput []io.Writer,
+	additionalEnv []string,
+	args ...string,
+) (string, string, int, error) {
+	return c.RunInline(ctx, workdir, additionalOutput, additionalErrorOutput, additionalEnv, nil, args...)
+}
+
+func (pulumiCommand) RunInline(ctx context.Context,
+	workdir string,
+	additionalOutput []io.Writer,
+	additionalErrorOutput []io.Writer,
+	additionalEnv []string,
+	program pulumi.RunFunc,
+	args ...string,
+) (string, string, int, error) {
+	var stdout, stderr bytes.Buffer
+	inv := &invocation{
+		workdir: workdir,
+		stdout:  io.MultiWriter(append(additionalOutput, &stdout)...),
+		stderr:  io.MultiWriter(append(additionalErrorOutput, &stderr)...),
+		env:     additionalEnv,
+		program: program,
+	}
+	if err := inv.run(ctx, args); err != nil {
+		fmt.Fprintf(inv.stderr, "error: %v\n", err)
+		return stdout.String(), stderr.String(), errorExitCode, err
+	}
+	return stdout.String(), stderr.String(), 0, nil
+}
+
+// environ is the environment of a command: that of the current process, overridden by the KEY=VALUE variables given
+// to the command. It implements env.Store.
+type environ []string
+
+func (e environ) Raw(key string) (string, bool) {
+	for i := len(e) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(e[i], "="); ok && k == key {
+			return v, true
+		}
+	}
+	return os.LookupEnv(key)
+}
+
+// invocation holds the state of a single command.
+type invocation struct {
+	workdir string
+	stdout  io.Writer
+	stderr  io.Writer
+	env     environ
+
+	// The inline program to run, if any.
+	program pulumi.RunFunc
+
+	// Flags common to all commands.
+	stackName string
+	color     string
+}
+
+// newFlagSet returns a flag set for a command, with the flags common to all commands registered.
+func (inv *invocation) newFlagSet(name string) *pflag.FlagSet {
+	flags := pflag.NewFlagSet(name, pflag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	flags.StringVarP(&inv.stackName, "stack", "s", "", "")
+	flags.StringVar(&inv.color, "color", "", "")
+	flags.Bool("non-interactive", false, "")
+
+	// Debug logging is not configurable in-process; these are accepted and ignored.
+	flags.Bool("logtostderr", false, "")
+	flags.IntP("verbose", "v", 0, "")
+	flags.Bool("logflow", false, "")
+	flags.String("tracing", "", "")
+	flags.Bool("debug", false, "")
+	return flags
+}
+
+func (inv *invocation) run(ctx context.Context, args []string) error {
+	var command, subcommand string
+	if len(args) > 0 {
+		command = args[0]
+	}
+	if len(args) > 1 && !strings.HasPrefix(args[1], "-") {
+		subcommand = args[1]
+	}
+
+	switch command {
+	case "version":
+		fmt.Fprintln(inv.stdout, pulumiVersion())
+		return nil
+	case "stack":
+		return inv.runStack(ctx, subcommand, args[1:])
+	case "config":
+		return inv.runConfig(ctx, subcommand, args[1:])
+	case "state":
+		return inv.runState(ctx, subcommand, args[1:])
+	case "preview", "up", "refresh", "destroy":
+		return inv.runUpdate(ctx, command, args[1:])
+	}
+	return fmt.Errorf("the command %q is not supported by the embedded engine", strings.Join(args, " "))
+}
+
+// parse parses the arguments of a command, returning its positional arguments.
+func parse(flags *pflag.FlagSet, args []string) ([]string, error) {
+	if err := flags.Parse(args); err != nil {
+		return nil, err
+	}
+	return flags.Args(), nil
+}
+
+// pulumiVersion returns the version of the engine, which is that of the pkg module this package was built from.
+func pulumiVersion() string {
+	if version.Version != "" {
+		return version.Version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/pulumi/pulumi/pkg/v3" {
+				return dep.Version
+			}
+		}
+	}
+	return ""
+}
+
+func (inv *invocation) colorization() colors.Colorization {
+	switch inv.color {
+	case "always":
+		return colors.Always
+	case "raw":
+		return colors.Raw
+	default:
+		// Output is never written to a terminal, so "auto" means no colors.
+		return colors.Never
+	}
+}
+
+func (inv *invocation) sink() diag.Sink {
+	return diag.DefaultSink(inv.stdout, inv.stderr, diag.FormatOptions{Color: inv.colorization()})
+}
+
+// project loads the project in the invocation's working directory, returning it with the path to its
+// Pulumi.yaml.
+func (inv *invocation) project() (*workspace.Project, string, error) {
+	path, err := workspace.DetectProjectPathFrom(inv.workdir)
+	if err != nil {
+		return nil, "", err
+	}
+	if path == "" {
+		return nil, "", fmt.Errorf("no Pulumi.yaml project file found in %s", inv.workdir)
+	}
+	proj, err := workspace.LoadProject(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return proj, path, nil
+}
+
+// backend returns the self-managed backend to use for the given project. The backend URL is taken from the
+// PULUMI_BACKEND_URL environment variable or the project's settings.
+func (inv *invocation) backend(ctx context.Context, proj *workspace.Project) (filestate.Backend, error) {
+	url, _ := inv.env.Raw(workspace.PulumiBackendURLEnvVar)
+	if url == "" && proj.Backend != nil {
+		url = proj.Backend.URL
+	}
+	if url == "" {
+		return nil, fmt.Errorf("the embedded engine requires a self-managed backend; set %s or the project's "+
+			"backend.url", workspace.PulumiBackendURLEnvVar)
+	}
+	if !filestate.IsFileStateBackendURL(url) {
+		return nil, fmt.Errorf("the embedded engine only supports self-managed backends, not %q", url)
+	}
+	return filestate.NewWithEnv(ctx, inv.sink(), url, proj, env.NewEnv(inv.env))
+}
+
+// stack returns the stack named by the --stack flag.
+func (inv *invocation) stack(ctx context.Context, b backend.Backend) (backend.Stack, error) {
+	if inv.stackName == "" {
+		return nil, errors.New("no stack selected; pass --stack")
+	}
+	ref, err := b.ParseStackReference(inv.stackName)
+	if err != nil {
+		return nil, err
+	}
+	s, err := b.GetStack(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("no stack named '%s' found", inv.stackName)
+	}
+	return s, nil
+}
+
+// projectStackPath returns the path to the stack's settings file, Pulumi.<stack>.yaml.
+func projectStackPath(proj *workspace.Project, projPath string, ref backend.StackReference) string {
+	name := strings.ReplaceAll(ref.Name().String(), tokens.QNameDelimiter, "-")
+	dir := filepath.Dir(projPath)
+	if proj.StackConfigDir != "" {
+		dir = filepath.Join(dir, proj.StackConfigDir)
+	}
+	return filepath.Join(dir, fmt.Sprintf("%s.%s%s", workspace.ProjectFile, name, filepath.Ext(projPath)))
+}
+
+// secretsManager returns the secrets manager for the stack, saving its settings if creating the secrets manager
+// changed them. If rotate is true, the secrets manager uses a new key.
+func (inv *invocation) secretsManager(
+	ps *workspace.ProjectStack, psPath string, rotate bool,
+) (secrets.Manager, error) {
+	oldKey, oldSalt, oldProvider := ps.EncryptedKey, ps.EncryptionSalt, ps.SecretsProvider
+
+	var sm secrets.Manager
+	var err error
+	if ps.SecretsProvider != passphrase.Type && ps.SecretsProvider != "default" && ps.SecretsProvider != "" {
+		sm, err = cloud.NewCloudSecretsManager(ps, ps.SecretsProvider, rotate)
+	} else {
+		// The default secrets manager of self-managed backends is the passphrase secrets manager.
+		sm, err = inv.passphraseSecretsManager(ps, rotate)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get stack secrets manager: %w", err)
+	}
+
+	if ps.EncryptedKey != oldKey || ps.EncryptionSalt != oldSalt || ps.SecretsProvider != oldProvider {
+		if err := ps.Save(psPath); err != nil {
+			return nil, fmt.Errorf("save stack config: %w", err)
+		}
+	}
+	return stack.NewCachingSecretsManager(sm), nil
+}
+
+// passphraseSecretsManager is like passphrase.NewPromptingPassphraseSecretsManager, but reads the passphrase from the
+// command's environment and never prompts for one.
+func (inv *invocation) passphraseSecretsManager(ps *workspace.ProjectStack, rotate bool) (secrets.Manager, error) {
+	phrase, err := inv.passphrase()
+	if err != nil {
+		return nil, err
+	}
+
+	if rotate {
+		ps.EncryptionSalt = ""
+	}
+	ps.EncryptedKey, ps.SecretsProvider = "", ""
+	if ps.EncryptionSalt != "" {
+		return passphrase.GetPassphraseSecretsManager(phrase, ps.EncryptionSalt)
+	}
+
+	salt, sm, err := passphrase.NewPassphraseSecretsManager(phrase)
+	if err != nil {
+		return nil, err
+	}
+	ps.EncryptionSalt = salt
+	return sm, nil
+}
+
+// passphrase returns the passphrase of the stack's secrets, from PULUMI_CONFIG_PASSPHRASE or the file named by
+// PULUMI_CONFIG_PASSPHRASE_FILE.
+func (inv *invocation) passphrase() (string, error) {
+	if phrase, ok := inv.env.Raw("PULUMI_CONFIG_PASSPHRASE"); ok {
+		return phrase, nil
+	}
+	if path, ok := inv.env.Raw("PULUMI_CONFIG_PASSPHRASE_FILE"); ok && path != "" {
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(inv.workdir, path)
+		}
+		phrase, err := os.ReadFile(path)
+		if err != nil {
+			return "", fmt.Errorf("unable to read PULUMI_CONFIG_PASSPHRASE_FILE: %w", err)
+		}
+		return strings.TrimSpace(string(phrase)), nil
+	}
+	return "", errors.New("passphrase must be set with PULUMI_CONFIG_PASSPHRASE or " +
+		"PULUMI_CONFIG_PASSPHRASE_FILE environment variables")
+}
+
+// secretsProvider returns the secrets.Provider with which to read the stack's deployments. Unlike
+// stack.DefaultSecretsProvider, it reads the passphrase of passphrase secrets managers from the command's
+// environment.
+func (inv *invocation) secretsProvider() secrets.Provider {
+	return secretsProvider{inv}
+}
+
+type secretsProvider struct {
+	inv *invocation
+}
+
+func (p secretsProvider) OfType(ty string, state json.RawMessage) (secrets.Manager, error) {
+	if ty != passphrase.Type {
+		return stack.DefaultSecretsProvider.OfType(ty, state)
+	}
+
+	// Deployments can be read without the passphrase, as long as their secrets aren't.
+	phrase, _ := p.inv.passphrase()
+	sm, err := passphrase.NewPassphraseSecretsManagerFromState(phrase, state)
+	if err != nil {
+		return nil, fmt.Errorf("constructing secrets manager of type %q: %w", ty, err)
+	}
+	return stack.NewCachingSecretsManager(sm), nil
+}
@@ -0,0 +1,232 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstatedelete"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi/config"
+)
+
+func TestInlineProgram(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	program := func(ctx *pulumi.Context) error {
+		cfg := config.New(ctx, "")
+		ctx.Export("greeting", pulumi.Sprintf("hello, %s", cfg.Require("name")))
+		ctx.Export("password", cfg.RequireSecret("password"))
+		return nil
+	}
+	s, err := auto.UpsertStackInlineSource(ctx, "dev", "embedded", program,
+		auto.Pulumi(NewPulumiCommand()),
+		auto.PulumiHome(t.TempDir()),
+		auto.EnvVars(map[string]string{
+			"PULUMI_BACKEND_URL":                       "file://" + filepath.ToSlash(t.TempDir()),
+			"PULUMI_CONFIG_PASSPHRASE":                 "correct horse battery staple",
+			"PULUMI_AUTOMATION_API_SKIP_VERSION_CHECK": "true",
+		}))
+	require.NoError(t, err)
+
+	require.NoError(t, s.SetConfig(ctx, "name", auto.ConfigValue{Value: "world"}))
+	require.NoError(t, s.SetConfig(ctx, "password", auto.ConfigValue{Value: "hunter2", Secret: true}))
+	cfg, err := s.GetAllConfig(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, auto.ConfigMap{
+		"embedded:name":     {Value: "world"},
+		"embedded:password": {Value: "hunter2", Secret: true},
+	}, cfg)
+
+	prev, err := s.Preview(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[apitype.OpType]int{apitype.OpCreate: 1}, prev.ChangeSummary)
+
+	eventsCh := make(chan events.EngineEvent)
+	var seen []events.EngineEvent
+	eventsDone := make(chan struct{})
+	go func() {
+		for e := range eventsCh {
+			seen = append(seen, e)
+		}
+		close(eventsDone)
+	}()
+	up, err := s.Up(ctx, optup.EventStreams(eventsCh))
+	require.NoError(t, err)
+	<-eventsDone
+	assert.Equal(t, "succeeded", up.Summary.Result)
+	assert.Equal(t, "update", up.Summary.Kind)
+	assert.Equal(t, auto.OutputMap{
+		"greeting": {Value: "hello, world"},
+		"password": {Value: "hunter2", Secret: true},
+	}, up.Outputs)
+	assert.Contains(t, up.StdOut, "Updating (dev)")
+	var summary *apitype.SummaryEvent
+	for _, e := range seen {
+		if e.SummaryEvent != nil {
+			summary = e.SummaryEvent
+		}
+	}
+	require.NotNil(t, summary)
+	assert.Equal(t, map[apitype.OpType]int{apitype.OpCreate: 1}, summary.ResourceChanges)
+
+	ref, err := s.Refresh(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "refresh", ref.Summary.Kind)
+
+	dest, err := s.Destroy(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "destroy", dest.Summary.Kind)
+	assert.Equal(t, map[string]int{"delete": 1}, *dest.Summary.ResourceChanges)
+
+	history, err := s.History(ctx, 0, 0)
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	require.NoError(t, s.Workspace().RemoveStack(ctx, "dev"))
+	_, err = auto.SelectStackInlineSource(ctx, "dev", "embedded", program,
+		auto.Pulumi(NewPulumiCommand()),
+		auto.WorkDir(s.Workspace().WorkDir()),
+		auto.EnvVars(s.Workspace().GetEnvVars()))
+	assert.True(t, auto.IsSelectStack404Error(err))
+}
+
+func TestUnsupportedCommand(t *testing.T) {
+	t.Parallel()
+
+	_, stderr, code, err := NewPulumiCommand().Run(context.Background(), t.TempDir(), nil, nil, nil, "whoami")
+	assert.ErrorContains(t, err, `the command "whoami" is not supported by the embedded engine`)
+	assert.Equal(t, errorExitCode, code)
+	assert.Contains(t, stderr, "error: ")
+}
+
+// newStack creates a stack for the given inline program, in a backend of its own with the given passphrase.
+func newStack(t *testing.T, stackName, passphrase string, program pulumi.RunFunc) auto.Stack {
+	s, err := auto.UpsertStackInlineSource(context.Background(), stackName, "embedded", program,
+		auto.Pulumi(NewPulumiCommand()),
+		auto.EnvVars(map[string]string{
+			"PULUMI_BACKEND_URL":                       "file://" + filepath.ToSlash(t.TempDir()),
+			"PULUMI_CONFIG_PASSPHRASE":                 passphrase,
+			"PULUMI_AUTOMATION_API_SKIP_VERSION_CHECK": "true",
+		}))
+	require.NoError(t, err)
+	return s
+}
+
+func TestConcurrentCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	program := func(ctx *pulumi.Context) error {
+		ctx.Export("secret", config.New(ctx, "").RequireSecret("secret"))
+		return nil
+	}
+
+	// Each stack has its own backend and passphrase, which are only set in the environment of its commands.
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		i := i
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			secret := fmt.Sprintf("secret-%d", i)
+			s := newStack(t, fmt.Sprintf("stack-%d", i), fmt.Sprintf("passphrase-%d", i), program)
+			if !assert.NoError(t, s.SetConfig(ctx, "secret", auto.ConfigValue{Value: secret, Secret: true})) {
+				return
+			}
+			up, err := s.Up(ctx)
+			if assert.NoError(t, err) {
+				assert.Equal(t, auto.OutputMap{"secret": {Value: secret, Secret: true}}, up.Outputs)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStateCommands(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	program := func(ctx *pulumi.Context) error {
+		var parent, child pulumi.ResourceState
+		if err := ctx.RegisterComponentResource("test:index:Component", "parent", &parent); err != nil {
+			return err
+		}
+		err := ctx.RegisterComponentResource("test:index:Component", "child", &child,
+			pulumi.Parent(&parent), pulumi.Protect(true))
+		if err != nil {
+			return err
+		}
+		ctx.Export("password", config.New(ctx, "").RequireSecret("password"))
+		return nil
+	}
+	s := newStack(t, "dev", "correct horse battery staple", program)
+	require.NoError(t, s.SetConfig(ctx, "password", auto.ConfigValue{Value: "hunter2", Secret: true}))
+	_, err := s.Up(ctx)
+	require.NoError(t, err)
+
+	const parentURN = "urn:pulumi:dev::embedded::test:index:Component::parent"
+	const childURN = "urn:pulumi:dev::embedded::test:index:Component$test:index:Component::child"
+
+	err = s.StateDelete(ctx, "urn:pulumi:dev::embedded::test:index:Component::missing")
+	var notFound *auto.ResourceNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+
+	err = s.StateDelete(ctx, parentURN)
+	var hasDependents *auto.ResourceHasDependentsError
+	if assert.ErrorAs(t, err, &hasDependents) {
+		assert.Equal(t, []string{childURN}, hasDependents.Dependents)
+	}
+
+	err = s.StateDelete(ctx, childURN)
+	var protected *auto.ResourceProtectedError
+	assert.ErrorAs(t, err, &protected)
+
+	require.NoError(t, s.StateUnprotect(ctx, childURN))
+	require.NoError(t, s.StateDelete(ctx, parentURN, optstatedelete.TargetDependents()))
+
+	prev, err := s.Preview(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, map[apitype.OpType]int{apitype.OpCreate: 2, apitype.OpSame: 1}, prev.ChangeSummary)
+
+	// Secrets can still be read after the stack is renamed and its secrets provider is changed.
+	require.NoError(t, s.Rename(ctx, "prod"))
+	require.NoError(t, s.ChangeSecretsProvider(ctx, "passphrase"))
+	_, err = auto.SelectStackInlineSource(ctx, "dev", "embedded", program,
+		auto.Pulumi(NewPulumiCommand()),
+		auto.WorkDir(s.Workspace().WorkDir()),
+		auto.EnvVars(s.Workspace().GetEnvVars()))
+	assert.True(t, auto.IsSelectStack404Error(err))
+	password, err := s.GetConfig(ctx, "password")
+	require.NoError(t, err)
+	assert.Equal(t, "hunter2", password.Value)
+	outputs, err := s.Outputs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, auto.OutputMap{"password": {Value: "hunter2", Secret: true}}, outputs)
+}
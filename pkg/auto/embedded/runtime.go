@@ -0,0 +1,147 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/blang/semver"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
+)
+
+// languageRuntime is a plugin.LanguageRuntime that runs an inline program in-process, in place of the language
+// runtime server that the Automation API serves inline programs with.
+type languageRuntime struct {
+	ctx     context.Context // the context of the operation running the program.
+	program pulumi.RunFunc
+}
+
+var _ plugin.LanguageRuntime = (*languageRuntime)(nil)
+
+// errNotSupported is returned by the methods of languageRuntime that don't apply to inline programs.
+var errNotSupported = errors.New("not supported by inline programs")
+
+func (r *languageRuntime) Close() error {
+	return nil
+}
+
+func (r *languageRuntime) GetRequiredPlugins(info plugin.ProgInfo) ([]workspace.PluginSpec, error) {
+	return nil, nil
+}
+
+func (r *languageRuntime) Run(info plugin.RunInfo) (string, bool, error) {
+	// The engine passes its address as the program's only argument.
+	var engineAddress string
+	if len(info.Args) > 0 {
+		engineAddress = info.Args[0]
+	}
+	config := make(map[string]string, len(info.Config))
+	for k, v := range info.Config {
+		config[k.String()] = v
+	}
+	configSecretKeys := make([]string, len(info.ConfigSecretKeys))
+	for i, k := range info.ConfigSecretKeys {
+		configSecretKeys[i] = k.String()
+	}
+	runInfo := pulumi.RunInfo{
+		EngineAddr:       engineAddress,
+		MonitorAddr:      info.MonitorAddress,
+		Config:           config,
+		ConfigSecretKeys: configSecretKeys,
+		Project:          info.Project,
+		Stack:            info.Stack,
+		Parallel:         info.Parallel,
+		DryRun:           info.DryRun,
+		Organization:     info.Organization,
+	}
+
+	pulumiCtx, err := pulumi.NewContext(r.ctx, runInfo)
+	if err != nil {
+		return "", false, err
+	}
+	defer pulumiCtx.Close()
+
+	err = func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				if pErr, ok := r.(error); ok {
+					err = fmt.Errorf("go inline source runtime error, an unhandled error occurred: %w", pErr)
+				} else {
+					err = errors.New("go inline source runtime error, an unhandled error occurred: unknown error")
+				}
+			}
+		}()
+
+		return pulumi.RunWithContext(pulumiCtx, r.program)
+	}()
+	if err != nil {
+		return err.Error(), false, nil
+	}
+	return "", false, nil
+}
+
+func (r *languageRuntime) GetPluginInfo() (workspace.PluginInfo, error) {
+	version := semver.MustParse("1.0.0")
+	return workspace.PluginInfo{Name: "client", Kind: workspace.LanguagePlugin, Version: &version}, nil
+}
+
+func (r *languageRuntime) InstallDependencies(directory string) error {
+	return nil
+}
+
+func (r *languageRuntime) About() (plugin.AboutInfo, error) {
+	return plugin.AboutInfo{}, errNotSupported
+}
+
+func (r *languageRuntime) GetProgramDependencies(
+	info plugin.ProgInfo, transitiveDependencies bool,
+) ([]plugin.DependencyInfo, error) {
+	return nil, errNotSupported
+}
+
+func (r *languageRuntime) RunPlugin(info plugin.RunPluginInfo) (io.Reader, io.Reader, context.CancelFunc, error) {
+	return nil, nil, nil, errNotSupported
+}
+
+func (r *languageRuntime) GenerateProject(sourceDirectory, targetDirectory, project string,
+	strict bool, loaderTarget string, localDependencies map[string]string,
+) (hcl.Diagnostics, error) {
+	return nil, errNotSupported
+}
+
+func (r *languageRuntime) GeneratePackage(
+	directory string, schema string, extraFiles map[string][]byte, loaderTarget string,
+) error {
+	return errNotSupported
+}
+
+func (r *languageRuntime) GenerateProgram(
+	program map[string]string, loaderTarget string,
+) (map[string][]byte, hcl.Diagnostics, error) {
+	return nil, nil, errNotSupported
+}
+
+func (r *languageRuntime) Pack(
+	packageDirectory string, version semver.Version, destinationDirectory string,
+) (string, error) {
+	return "", errNotSupported
+}
@@ -0,0 +1,465 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/passphrase"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// timeFormat is the format of times in JSON output, matching that of the CLI.
+const timeFormat = "2006-01-02T15:04:05.000Z07:00"
+
+// errorDecryptingValue replaces configuration values in stack history that cannot be decrypted.
+const errorDecryptingValue = "ERROR_UNABLE_TO_DECRYPT"
+
+func (inv *invocation) runStack(ctx context.Context, subcommand string, args []string) error {
+	switch subcommand {
+	case "":
+		// `pulumi stack --stack <name>` checks that the stack exists.
+		return inv.stackSelect(ctx, args)
+	case "select":
+		return inv.stackSelect(ctx, args[1:])
+	case "init":
+		return inv.stackInit(ctx, args[1:])
+	case "ls":
+		return inv.stackList(ctx, args[1:])
+	case "rm":
+		return inv.stackRemove(ctx, args[1:])
+	case "output":
+		return inv.stackOutput(ctx, args[1:])
+	case "history":
+		return inv.stackHistory(ctx, args[1:])
+	case "rename":
+		return inv.stackRename(ctx, args[1:])
+	case "change-secrets-provider":
+		return inv.stackChangeSecretsProvider(ctx, args[1:])
+	}
+	return fmt.Errorf("the command \"stack %s\" is not supported by the embedded engine", subcommand)
+}
+
+// stackSelect checks that the given stack exists. Commands always name their stack, so there is no current stack
+// to record.
+func (inv *invocation) stackSelect(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack select")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		inv.stackName = rest[0]
+	}
+
+	proj, _, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	_, err = inv.stack(ctx, b)
+	return err
+}
+
+func (inv *invocation) stackInit(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack init")
+	secretsProvider := flags.String("secrets-provider", "", "")
+	flags.Bool("no-select", false, "")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		inv.stackName = rest[0]
+	}
+	if inv.stackName == "" {
+		return errors.New("missing stack name")
+	}
+
+	proj, projPath, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	ref, err := b.ParseStackReference(inv.stackName)
+	if err != nil {
+		return err
+	}
+	s, err := b.CreateStack(ctx, ref, "", nil)
+	if err != nil {
+		return err
+	}
+
+	// Set up the stack's secrets manager now, as `pulumi stack init` does.
+	psPath := projectStackPath(proj, projPath, s.Ref())
+	ps, err := workspace.LoadProjectStack(proj, psPath)
+	if err != nil {
+		return err
+	}
+	if *secretsProvider != "" && *secretsProvider != "default" {
+		ps.SecretsProvider = *secretsProvider
+	}
+	_, err = inv.secretsManager(ps, psPath, false /* rotate */)
+	return err
+}
+
+func (inv *invocation) stackList(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack ls")
+	flags.Bool("json", false, "")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	proj, _, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	projName := string(proj.Name)
+	summaries, _, err := b.ListStacks(ctx, backend.ListStacksFilter{Project: &projName}, nil)
+	if err != nil {
+		return err
+	}
+
+	stacks := make([]auto.StackSummary, len(summaries))
+	for i, summary := range summaries {
+		stacks[i] = auto.StackSummary{
+			Name:          summary.Name().Name().String(),
+			ResourceCount: summary.ResourceCount(),
+		}
+		if lastUpdate := summary.LastUpdate(); lastUpdate != nil {
+			stacks[i].LastUpdate = lastUpdate.UTC().Format(timeFormat)
+		}
+	}
+	return inv.printJSON(stacks)
+}
+
+func (inv *invocation) stackRemove(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack rm")
+	flags.BoolP("yes", "y", false, "")
+	force := flags.BoolP("force", "f", false, "")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) > 0 {
+		inv.stackName = rest[0]
+	}
+
+	proj, projPath, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return err
+	}
+	hasResources, err := b.RemoveStack(ctx, s, *force)
+	if err != nil {
+		if hasResources {
+			return fmt.Errorf("'%s' still has resources; removal rejected. Possible actions:\n"+
+				"- Make sure that '%[1]s' is the stack that you want to destroy\n"+
+				"- Run `pulumi destroy` to delete the resources, then run `pulumi stack rm`\n"+
+				"- Run `pulumi stack rm --force` to override this error", s.Ref())
+		}
+		return err
+	}
+
+	// Remove the stack's settings too, as `pulumi stack rm` does.
+	if err := os.Remove(projectStackPath(proj, projPath, s.Ref())); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (inv *invocation) stackOutput(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack output")
+	flags.Bool("json", false, "")
+	showSecrets := flags.Bool("show-secrets", false, "")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	proj, _, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return err
+	}
+	snap, err := s.Snapshot(ctx, inv.secretsProvider())
+	if err != nil {
+		return err
+	}
+
+	outputs := map[string]interface{}{}
+	res, err := stack.GetRootStackResource(snap)
+	if err != nil {
+		return err
+	}
+	if res != nil {
+		// MassageSecrets removes all secrets from the outputs, so it is safe to serialize them with a panic crypter.
+		outputs, err = stack.SerializeProperties(display.MassageSecrets(res.Outputs, *showSecrets),
+			config.NewPanicCrypter(), *showSecrets)
+		if err != nil {
+			return err
+		}
+	}
+	return inv.printJSON(outputs)
+}
+
+func (inv *invocation) stackHistory(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack history")
+	flags.Bool("json", false, "")
+	showSecrets := flags.Bool("show-secrets", false, "")
+	pageSize := flags.Int("page-size", 0, "")
+	page := flags.Int("page", 0, "")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+
+	proj, projPath, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return err
+	}
+	updates, err := b.GetHistory(ctx, s.Ref(), *pageSize, *page)
+	if err != nil {
+		return fmt.Errorf("getting history: %w", err)
+	}
+
+	var decrypter config.Decrypter
+	if *showSecrets {
+		psPath := projectStackPath(proj, projPath, s.Ref())
+		ps, err := workspace.LoadProjectStack(proj, psPath)
+		if err != nil {
+			return err
+		}
+		sm, err := inv.secretsManager(ps, psPath, false /* rotate */)
+		if err != nil {
+			return err
+		}
+		if decrypter, err = sm.Decrypter(); err != nil {
+			return err
+		}
+	}
+
+	history := make([]auto.UpdateSummary, len(updates))
+	for i, update := range updates {
+		history[i] = updateSummary(update, decrypter)
+	}
+	return inv.printJSON(history)
+}
+
+func (inv *invocation) stackRename(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack rename")
+	jsonOut := flags.BoolP("json", "j", false, "")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("expected the new name of the stack")
+	}
+
+	proj, projPath, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return err
+	}
+	newRef, err := s.Rename(ctx, tokens.QName(rest[0]))
+	if err != nil {
+		return inv.reportCLIError(*jsonOut, err)
+	}
+
+	// Move the stack's settings too, as `pulumi stack rename` does.
+	oldPath, newPath := projectStackPath(proj, projPath, s.Ref()), projectStackPath(proj, projPath, newRef)
+	if err := os.Rename(oldPath, newPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("renaming configuration file to %s: %w", filepath.Base(newPath), err)
+	}
+	fmt.Fprintf(inv.stdout, "Renamed %s to %s\n", s.Ref(), newRef)
+	return nil
+}
+
+// stackChangeSecretsProvider re-encrypts the stack's configuration and state with a new secrets provider. The
+// passphrase of the passphrase secrets provider is read from the command's environment, whether it is the current
+// provider or the new one.
+func (inv *invocation) stackChangeSecretsProvider(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("stack change-secrets-provider")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("expected the new secrets provider of the stack")
+	}
+	newProvider := rest[0]
+	if newProvider == "default" {
+		// The default secrets provider of self-managed backends is the passphrase secrets provider.
+		newProvider = passphrase.Type
+	}
+
+	c, err := inv.loadConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Read the configuration and state with the current secrets provider.
+	var decrypter config.Decrypter = config.NewPanicCrypter()
+	if c.ps.Config.HasSecureValue() {
+		sm, err := inv.secretsManager(c.ps, c.psPath, false /* rotate */)
+		if err != nil {
+			return err
+		}
+		if decrypter, err = sm.Decrypter(); err != nil {
+			return err
+		}
+	}
+	snap, err := c.stack.Snapshot(ctx, inv.secretsProvider())
+	if err != nil {
+		return err
+	}
+
+	// Changing to the current provider rotates its key.
+	rotate := newProvider == c.ps.SecretsProvider || newProvider == passphrase.Type && c.ps.SecretsProvider == ""
+	c.ps.SecretsProvider = newProvider
+	sm, err := inv.secretsManager(c.ps, c.psPath, rotate)
+	if err != nil {
+		return err
+	}
+	encrypter, err := sm.Encrypter()
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintln(inv.stdout, "Migrating old configuration and state to new secrets provider")
+	if c.ps.Config, err = c.ps.Config.Copy(decrypter, encrypter); err != nil {
+		return err
+	}
+	if err := c.ps.Save(c.psPath); err != nil {
+		return fmt.Errorf("save stack config: %w", err)
+	}
+	if snap == nil {
+		return nil
+	}
+	return importSnapshot(ctx, c.stack, snap, sm)
+}
+
+// importSnapshot replaces the stack's state with the given snapshot, encrypting its secrets with sm.
+func importSnapshot(ctx context.Context, s backend.Stack, snap *deploy.Snapshot, sm secrets.Manager) error {
+	sdep, err := stack.SerializeDeployment(snap, sm, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing deployment: %w", err)
+	}
+	bytes, err := json.Marshal(sdep)
+	if err != nil {
+		return err
+	}
+	return s.ImportDeployment(ctx, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	})
+}
+
+// updateSummary converts an update's information to its Automation API form. Secret configuration values are only
+// included if a decrypter is given.
+func updateSummary(update backend.UpdateInfo, decrypter config.Decrypter) auto.UpdateSummary {
+	summary := auto.UpdateSummary{
+		Version:     update.Version,
+		Kind:        string(update.Kind),
+		StartTime:   time.Unix(update.StartTime, 0).UTC().Format(timeFormat),
+		Message:     update.Message,
+		Environment: update.Environment,
+		Config:      auto.ConfigMap{},
+		Result:      string(update.Result),
+	}
+	for k, v := range update.Config {
+		value := auto.ConfigValue{Secret: v.Secure()}
+		if !v.Secure() || decrypter != nil {
+			plaintext, err := v.Value(decrypter)
+			if err != nil {
+				plaintext = errorDecryptingValue
+			}
+			value.Value = plaintext
+		}
+		summary.Config[k.String()] = value
+	}
+	if update.Result != backend.InProgressResult {
+		endTime := time.Unix(update.EndTime, 0).UTC().Format(timeFormat)
+		summary.EndTime = &endTime
+		resourceChanges := map[string]int{}
+		for k, v := range update.ResourceChanges {
+			resourceChanges[string(k)] = v
+		}
+		summary.ResourceChanges = &resourceChanges
+	}
+	return summary
+}
+
+func (inv *invocation) printJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(inv.stdout, string(out))
+	return err
+}
@@ -0,0 +1,210 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func (inv *invocation) runState(ctx context.Context, subcommand string, args []string) error {
+	switch subcommand {
+	case "delete":
+		return inv.stateDelete(ctx, args[1:])
+	case "unprotect":
+		return inv.stateUnprotect(ctx, args[1:])
+	}
+	return fmt.Errorf("the command \"state %s\" is not supported by the embedded engine", subcommand)
+}
+
+func (inv *invocation) stateDelete(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("state delete")
+	flags.BoolP("yes", "y", false, "")
+	force := flags.Bool("force", false, "")
+	targetDependents := flags.Bool("target-dependents", false, "")
+	jsonOut := flags.BoolP("json", "j", false, "")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if len(rest) != 1 {
+		return errors.New("expected the URN of the resource to delete")
+	}
+	urn := resource.URN(rest[0])
+
+	err = inv.editState(ctx, func(snap *deploy.Snapshot) error {
+		res, err := locateResource(snap, urn)
+		if err != nil {
+			return err
+		}
+		var handleProtected func(*resource.State) error
+		if *force {
+			handleProtected = func(res *resource.State) error {
+				fmt.Fprintf(inv.stderr, "warning: deleting protected resource %s due to presence of --force\n", res.URN)
+				return edit.UnprotectResource(nil, res)
+			}
+		}
+		return edit.DeleteResource(snap, res, handleProtected, *targetDependents)
+	})
+	if err == nil {
+		fmt.Fprintln(inv.stdout, "Resource deleted")
+		return nil
+	}
+
+	displayErr := err
+	switch e := err.(type) {
+	case edit.ResourceHasDependenciesError:
+		var message strings.Builder
+		fmt.Fprintf(&message, "%s can't be safely deleted because the following resources depend on it:\n",
+			e.Condemned.URN)
+		for _, dependent := range e.Dependencies {
+			fmt.Fprintf(&message, " * %-15q (%s)\n", dependent.URN.Name(), dependent.URN)
+		}
+		message.WriteString("\nDelete those resources first or pass --target-dependents.")
+		displayErr = errors.New(message.String())
+	case edit.ResourceProtectedError:
+		displayErr = fmt.Errorf("%s can't be safely deleted because it is protected. "+
+			"Re-run this command with --force to force deletion", e.Condemned.URN)
+	}
+	if *jsonOut {
+		inv.printCLIError(err, displayErr)
+	}
+	return displayErr
+}
+
+func (inv *invocation) stateUnprotect(ctx context.Context, args []string) error {
+	flags := inv.newFlagSet("state unprotect")
+	flags.BoolP("yes", "y", false, "")
+	all := flags.Bool("all", false, "")
+	jsonOut := flags.BoolP("json", "j", false, "")
+	rest, err := parse(flags, args)
+	if err != nil {
+		return err
+	}
+	if *all == (len(rest) != 0) {
+		return errors.New("expected either the URN of the resource to unprotect or --all")
+	}
+
+	return inv.reportCLIError(*jsonOut, inv.editState(ctx, func(snap *deploy.Snapshot) error {
+		if *all {
+			for _, res := range snap.Resources {
+				contract.AssertNoErrorf(edit.UnprotectResource(snap, res), "unprotecting resource %s", res.URN)
+			}
+			return nil
+		}
+		res, err := locateResource(snap, resource.URN(rest[0]))
+		if err != nil {
+			return err
+		}
+		return edit.UnprotectResource(snap, res)
+	}))
+}
+
+// editState edits the state of the stack in-place with the given function, as the `pulumi state` commands do.
+func (inv *invocation) editState(ctx context.Context, operation func(snap *deploy.Snapshot) error) error {
+	proj, _, err := inv.project()
+	if err != nil {
+		return err
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return err
+	}
+	snap, err := s.Snapshot(ctx, inv.secretsProvider())
+	if err != nil {
+		return err
+	} else if snap == nil {
+		return nil
+	}
+
+	// If the snapshot was valid before the edit, it must still be valid after it.
+	stackIsAlreadyHosed := snap.VerifyIntegrity() != nil
+	if err := operation(snap); err != nil {
+		return err
+	}
+	if !stackIsAlreadyHosed {
+		contract.AssertNoErrorf(snap.VerifyIntegrity(), "state edit produced an invalid snapshot")
+	}
+	return importSnapshot(ctx, s, snap, snap.SecretsManager)
+}
+
+// locateResource returns the resource with the given URN.
+func locateResource(snap *deploy.Snapshot, urn resource.URN) (*resource.State, error) {
+	switch resources := edit.LocateResource(snap, urn); len(resources) {
+	case 0:
+		return nil, resourceNotFoundError{urn: urn}
+	case 1:
+		return resources[0], nil
+	default:
+		return nil, fmt.Errorf("Resource URN %q ambiguously refers to %d resources", urn, len(resources))
+	}
+}
+
+// resourceNotFoundError is returned by the state commands when the resource they operate on does not exist.
+type resourceNotFoundError struct {
+	urn resource.URN
+}
+
+func (e resourceNotFoundError) Error() string {
+	return fmt.Sprintf("No such resource %q exists in the current state", e.urn)
+}
+
+// reportCLIError writes the structured form of err to stdout if jsonOut is true and err is not nil, as the commands
+// of the CLI do when they are run with --json. It returns err.
+func (inv *invocation) reportCLIError(jsonOut bool, err error) error {
+	if jsonOut && err != nil {
+		inv.printCLIError(err, err)
+	}
+	return err
+}
+
+// printCLIError writes the structured form of an error to stdout. The kind of the error is determined from cause,
+// and its message is the message of err, which may describe cause in more detail.
+func (inv *invocation) printCLIError(cause, err error) {
+	cliErr := apitype.CLIError{Message: err.Error()}
+
+	var notFound resourceNotFoundError
+	var hasDependencies edit.ResourceHasDependenciesError
+	var protected edit.ResourceProtectedError
+	var alreadyExists *backend.StackAlreadyExistsError
+	switch {
+	case errors.As(cause, &notFound):
+		cliErr.Kind, cliErr.URN = apitype.ResourceNotFoundCLIError, string(notFound.urn)
+	case errors.As(cause, &hasDependencies):
+		cliErr.Kind, cliErr.URN = apitype.ResourceHasDependentsCLIError, string(hasDependencies.Condemned.URN)
+		for _, dep := range hasDependencies.Dependencies {
+			cliErr.Dependents = append(cliErr.Dependents, string(dep.URN))
+		}
+	case errors.As(cause, &protected):
+		cliErr.Kind, cliErr.URN = apitype.ResourceProtectedCLIError, string(protected.Condemned.URN)
+	case errors.As(cause, &alreadyExists):
+		cliErr.Kind, cliErr.StackName = apitype.StackAlreadyExistsCLIError, alreadyExists.StackName
+	}
+
+	contract.IgnoreError(inv.printJSON(cliErr))
+}
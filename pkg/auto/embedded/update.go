@@ -0,0 +1,279 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package embedded
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"path/filepath"
+	"strconv"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	sdkDisplay "github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/util/cancel"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/constant"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// updateFlags are the flags accepted by preview, up, refresh and destroy.
+type updateFlags struct {
+	message           string
+	expectNoChanges   bool
+	diff              bool
+	replaces          []string
+	targets           []string
+	targetDependents  bool
+	policyPacks       []string
+	policyPackConfigs []string
+	parallel          int
+	refresh           string
+	execKind          string
+	execAgent         string
+	client            string
+	eventLog          string
+	skipPreview       bool
+	previewOnly       bool
+	plan              string
+}
+
+func (inv *invocation) runUpdate(ctx context.Context, command string, args []string) error {
+	var f updateFlags
+	flags := inv.newFlagSet(command)
+	flags.StringVarP(&f.message, "message", "m", "", "")
+	flags.BoolVar(&f.expectNoChanges, "expect-no-changes", false, "")
+	flags.BoolVar(&f.diff, "diff", false, "")
+	flags.StringArrayVar(&f.replaces, "replace", nil, "")
+	flags.StringArrayVarP(&f.targets, "target", "t", nil, "")
+	flags.BoolVar(&f.targetDependents, "target-dependents", false, "")
+	flags.StringSliceVar(&f.policyPacks, "policy-pack", nil, "")
+	flags.StringSliceVar(&f.policyPackConfigs, "policy-pack-config", nil, "")
+	flags.IntVarP(&f.parallel, "parallel", "p", math.MaxInt32, "")
+	flags.StringVarP(&f.refresh, "refresh", "r", "", "")
+	flags.Lookup("refresh").NoOptDefVal = "true"
+	flags.StringVar(&f.execKind, "exec-kind", "", "")
+	flags.StringVar(&f.execAgent, "exec-agent", "", "")
+	flags.StringVar(&f.client, "client", "", "")
+	flags.StringVar(&f.eventLog, "event-log", "", "")
+	flags.BoolP("yes", "y", false, "")
+	flags.BoolVarP(&f.skipPreview, "skip-preview", "f", false, "")
+	flags.BoolVar(&f.previewOnly, "preview-only", false, "")
+	flags.StringVar(&f.plan, "plan", "", "")
+	flags.StringVar(&f.plan, "save-plan", "", "")
+	if _, err := parse(flags, args); err != nil {
+		return err
+	}
+	if f.plan != "" {
+		return errors.New("update plans are not supported by the embedded engine")
+	}
+
+	proj, projPath, err := inv.project()
+	if err != nil {
+		return err
+	}
+	root := filepath.Dir(projPath)
+	if f.client != "" {
+		// The program is served by the client at this address, as with `pulumi up --client`.
+		proj.Runtime = workspace.NewProjectRuntimeInfo("client", map[string]interface{}{
+			"address": f.client,
+		})
+	}
+	b, err := inv.backend(ctx, proj)
+	if err != nil {
+		return err
+	}
+	s, err := inv.stack(ctx, b)
+	if err != nil {
+		return err
+	}
+
+	cfg, sm, err := inv.stackConfiguration(proj, projPath, s)
+	if err != nil {
+		return err
+	}
+
+	refresh := proj.Options != nil && proj.Options.Refresh == "always"
+	if f.refresh != "" {
+		if refresh, err = strconv.ParseBool(f.refresh); err != nil {
+			return errors.New("unable to determine value for --refresh")
+		}
+	}
+
+	displayType := display.DisplayProgress
+	if f.diff {
+		displayType = display.DisplayDiff
+	}
+	opts := backend.UpdateOptions{
+		AutoApprove: true,
+		SkipPreview: f.skipPreview,
+		PreviewOnly: f.previewOnly,
+		Engine: engine.UpdateOptions{
+			LocalPolicyPacks: engine.MakeLocalPolicyPacks(f.policyPacks, f.policyPackConfigs),
+			Parallel:         f.parallel,
+			Refresh:          refresh,
+			ReplaceTargets:   deploy.NewUrnTargets(f.replaces),
+			Targets:          deploy.NewUrnTargets(f.targets),
+			TargetDependents: f.targetDependents,
+			Env:              inv.env,
+		},
+		Display: display.Options{
+			Color:          inv.colorization(),
+			TruncateOutput: true,
+			Type:           displayType,
+			EventLogPath:   f.eventLog,
+			Stdout:         inv.stdout,
+			Stderr:         inv.stderr,
+		},
+	}
+
+	if inv.program != nil && f.client == "" {
+		opts.Engine.LanguageRuntime = &languageRuntime{ctx: ctx, program: inv.program}
+	}
+
+	m := &backend.UpdateMetadata{
+		Message:     f.message,
+		Environment: map[string]string{backend.ExecutionKind: constant.ExecKindAutoLocal},
+	}
+	if f.execKind == constant.ExecKindAutoInline {
+		m.Environment[backend.ExecutionKind] = f.execKind
+	}
+	if f.execAgent != "" {
+		m.Environment[backend.ExecutionAgent] = f.execAgent
+	}
+
+	op := backend.UpdateOperation{
+		Proj:               proj,
+		Root:               root,
+		M:                  m,
+		Opts:               opts,
+		StackConfiguration: cfg,
+		SecretsManager:     sm,
+		SecretsProvider:    inv.secretsProvider(),
+		Scopes:             contextScopes{ctx},
+	}
+
+	var changes sdkDisplay.ResourceChanges
+	var res result.Result
+	switch command {
+	case "preview":
+		_, changes, res = s.Preview(ctx, op)
+	case "up":
+		changes, res = s.Update(ctx, op)
+	case "refresh":
+		changes, res = s.Refresh(ctx, op)
+	case "destroy":
+		changes, res = s.Destroy(ctx, op)
+	}
+	switch {
+	case res != nil && res.Error() == context.Canceled:
+		return fmt.Errorf("%s cancelled", command)
+	case res != nil && res.IsBail():
+		// The failure has already been reported through the display.
+		return fmt.Errorf("%s failed", command)
+	case res != nil:
+		return res.Error()
+	case f.expectNoChanges && changes != nil && engine.HasChanges(changes):
+		return errors.New("no changes were expected but changes occurred")
+	}
+	return nil
+}
+
+// stackConfiguration loads the stack's configuration and secrets manager, validating the configuration against the
+// project's.
+func (inv *invocation) stackConfiguration(
+	proj *workspace.Project, projPath string, s backend.Stack,
+) (backend.StackConfiguration, secrets.Manager, error) {
+	psPath := projectStackPath(proj, projPath, s.Ref())
+	ps, err := workspace.LoadProjectStack(proj, psPath)
+	if err != nil {
+		return backend.StackConfiguration{}, nil, err
+	}
+	if len(ps.EnvironmentBytes()) != 0 {
+		return backend.StackConfiguration{}, nil, errors.New("environments are not supported by the embedded engine")
+	}
+	sm, err := inv.secretsManager(ps, psPath, false /* rotate */)
+	if err != nil {
+		return backend.StackConfiguration{}, nil, err
+	}
+
+	// As with the CLI, only create a decrypter if there are secrets to decrypt.
+	var decrypter config.Decrypter = config.NewPanicCrypter()
+	if ps.Config.HasSecureValue() {
+		if decrypter, err = sm.Decrypter(); err != nil {
+			return backend.StackConfiguration{}, nil, fmt.Errorf("getting configuration decrypter: %w", err)
+		}
+	}
+	cfg := backend.StackConfiguration{
+		Environment:   esc.Value{},
+		Config:        ps.Config,
+		Decrypter:     decrypter,
+		IgnoreChanges: ps.IgnoreChanges,
+		Parallelism:   ps.Parallelism,
+	}
+
+	stackDecrypter, err := sm.Decrypter()
+	if err != nil {
+		return backend.StackConfiguration{}, nil, fmt.Errorf("getting stack decrypter: %w", err)
+	}
+	encrypter, err := sm.Encrypter()
+	if err != nil {
+		return backend.StackConfiguration{}, nil, fmt.Errorf("getting stack encrypter: %w", err)
+	}
+	if err := workspace.ValidateStackConfigAndApplyProjectConfig(s.Ref().Name().String(), proj,
+		cfg.Environment, cfg.Config, encrypter, stackDecrypter); err != nil {
+		return backend.StackConfiguration{}, nil, fmt.Errorf("validating stack config: %w", err)
+	}
+	return cfg, sm, nil
+}
+
+// contextScopes is a backend.CancellationScopeSource that cancels operations when a context is done, in place of
+// the CLI's handling of interrupts.
+type contextScopes struct {
+	ctx context.Context
+}
+
+type contextScope struct {
+	context *cancel.Context
+	done    chan struct{}
+}
+
+func (s contextScopes) NewScope(events chan<- engine.Event, isPreview bool) backend.CancellationScope {
+	cancelContext, cancelSource := cancel.NewContext(context.Background())
+	scope := &contextScope{context: cancelContext, done: make(chan struct{})}
+	go func() {
+		select {
+		case <-s.ctx.Done():
+			cancelSource.Cancel()
+		case <-scope.done:
+		}
+	}()
+	return scope
+}
+
+func (s *contextScope) Context() *cancel.Context {
+	return s.context
+}
+
+func (s *contextScope) Close() {
+	close(s.done)
+}
@@ -15,13 +15,16 @@
 package backend
 
 import (
+	"context"
 	"testing"
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -61,3 +64,31 @@ func makeResourcePreEvent(urn, resType string, op display.StepOp, retainOnDelete
 
 	return event
 }
+
+func TestPreviewThenPromptThenExecutePreviewOnly(t *testing.T) {
+	t.Parallel()
+
+	var dryRuns []bool
+	apply := func(ctx context.Context, kind apitype.UpdateKind, stack Stack, op UpdateOperation,
+		opts ApplierOptions, events chan<- engine.Event,
+	) (*deploy.Plan, display.ResourceChanges, result.Result) {
+		dryRuns = append(dryRuns, opts.DryRun)
+		return nil, display.ResourceChanges{deploy.OpDelete: 1}, nil
+	}
+
+	for _, kind := range []apitype.UpdateKind{apitype.RefreshUpdate, apitype.DestroyUpdate} {
+		dryRuns = nil
+		op := UpdateOperation{Opts: UpdateOptions{PreviewOnly: true}}
+		changes, res := PreviewThenPromptThenExecute(context.Background(), kind, &MockStack{}, op, apply)
+		assert.Nil(t, res)
+		assert.Equal(t, display.ResourceChanges{deploy.OpDelete: 1}, changes)
+		// Only the preview is run.
+		assert.Equal(t, []bool{true}, dryRuns)
+
+		dryRuns = nil
+		op = UpdateOperation{Opts: UpdateOptions{AutoApprove: true}}
+		_, res = PreviewThenPromptThenExecute(context.Background(), kind, &MockStack{}, op, apply)
+		assert.Nil(t, res)
+		assert.Equal(t, []bool{true, false}, dryRuns)
+	}
+}
@@ -42,7 +42,7 @@ import (
 // ErrNoPreviousDeployment is returned when there isn't a previous deployment.
 var ErrNoPreviousDeployment = errors.New("no previous deployment")
 
-// StackAlreadyExistsError is returned from CreateStack when the stack already exists in the backend.
+// StackAlreadyExistsError is returned from CreateStack and RenameStack when the stack already exists in the backend.
 type StackAlreadyExistsError struct {
 	StackName string
 }
@@ -187,7 +187,7 @@ type Backend interface {
 	// Destroy destroys all of this stack's resources.
 	Destroy(ctx context.Context, stack Stack, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
 	// Watch watches the project's working directory for changes and automatically updates the active stack.
-	Watch(ctx context.Context, stack Stack, op UpdateOperation, paths []string) result.Result
+	Watch(ctx context.Context, stack Stack, op UpdateOperation, opts WatchOptions) result.Result
 
 	// Query against the resource outputs in a stack's state checkpoint.
 	Query(ctx context.Context, op QueryOperation) error
@@ -263,9 +263,11 @@ type QueryOperation struct {
 
 // StackConfiguration holds the configuration for a stack and it's associated decrypter.
 type StackConfiguration struct {
-	Environment esc.Value
-	Config      config.Map
-	Decrypter   config.Decrypter
+	Environment   esc.Value
+	Config        config.Map
+	Decrypter     config.Decrypter
+	IgnoreChanges *workspace.StackIgnoreChanges
+	Parallelism   *workspace.StackParallelism
 }
 
 // UpdateOptions is the full set of update options, including backend and engine options.
@@ -279,6 +281,8 @@ type UpdateOptions struct {
 	AutoApprove bool
 	// SkipPreview, when true, causes the preview step to be skipped.
 	SkipPreview bool
+	// PreviewOnly, when true, causes only the preview step to be run. The changes are not applied.
+	PreviewOnly bool
 }
 
 // QueryOptions configures a query to operate against a backend and the engine.
@@ -96,7 +96,7 @@ func ShowDiffEvents(op string, events <-chan engine.Event, done chan<- bool, opt
 
 func RenderDiffEvent(event engine.Event, seen map[resource.URN]engine.StepEventMetadata, opts Options) string {
 	switch event.Type {
-	case engine.CancelEvent:
+	case engine.CancelEvent, engine.ProgressEvent, engine.ResourceWaitingEvent:
 		return ""
 
 		// Currently, prelude, summary, and stdout events are printed the same for both the diff and
@@ -401,9 +401,25 @@ func renderDiff(
 
 	fprintIgnoreError(out, opts.Color.Colorize(summary))
 	fprintIgnoreError(out, opts.Color.Colorize(details))
+	fprintIgnoreError(out, opts.Color.Colorize(getIgnoredChangesDetails(metadata, indent+1)))
 	fprintIgnoreError(out, opts.Color.Colorize(colors.Reset))
 }
 
+// getIgnoredChangesDetails annotates each change to the resource's inputs that an ignoreChanges rule suppressed with
+// the rule that suppressed it.
+func getIgnoredChangesDetails(metadata engine.StepEventMetadata, indent int) string {
+	var b strings.Builder
+	for _, change := range metadata.IgnoredChanges {
+		source := "ignoreChanges"
+		if change.Default {
+			source = "the stack's default ignoreChanges"
+		}
+		fmt.Fprintf(&b, "%s%s%v: change ignored by %s rule %q%s\n", getIndentationString(indent, deploy.OpSame, false),
+			colors.SpecUnimportant, change.Path, source, change.Rule, colors.Reset)
+	}
+	return b.String()
+}
+
 func renderDiffResourcePreEvent(
 	payload engine.ResourcePreEventPayload,
 	seen map[resource.URN]engine.StepEventMetadata,
@@ -91,7 +91,7 @@ func ShowEvents(
 		contract.Failf("DisplayQuery can only be used in query mode, which should be invoked " +
 			"directly instead of through ShowEvents")
 	case DisplayWatch:
-		ShowWatchEvents(op, events, done, opts)
+		ShowWatchEvents(op, events, done, opts, isPreview)
 	default:
 		contract.Failf("Unknown display type %d", opts.Type)
 	}
@@ -190,6 +190,10 @@ func shouldShow(step engine.StepEventMetadata, opts Options) bool {
 		if step.Old.Protect != step.New.Protect {
 			return true
 		}
+		// Likewise, show it if ignoreChanges suppressed changes to its inputs, so that the suppression is visible.
+		if len(step.IgnoredChanges) != 0 {
+			return true
+		}
 		return opts.ShowSameResources
 	}
 
@@ -8,6 +8,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
@@ -120,6 +121,20 @@ func ConvertEngineEvent(e engine.Event, showSecrets bool) (apitype.EngineEvent,
 			Config: cfg,
 		}
 
+	case engine.ProgressEvent:
+		p, ok := e.Payload().(engine.ProgressEventPayload)
+		if !ok {
+			return apiEvent, eventTypePayloadMismatch
+		}
+		apiEvent.ProgressEvent = &apitype.ProgressEvent{
+			Type:      p.Type,
+			ID:        p.ID,
+			Message:   p.Message,
+			Completed: p.Completed,
+			Total:     p.Total,
+			Done:      p.Done,
+		}
+
 	case engine.SummaryEvent:
 		p, ok := e.Payload().(engine.SummaryEventPayload)
 		if !ok {
@@ -147,6 +162,16 @@ func ConvertEngineEvent(e engine.Event, showSecrets bool) (apitype.EngineEvent,
 			Planning: p.Planning,
 		}
 
+	case engine.ResourceWaitingEvent:
+		p, ok := e.Payload().(engine.ResourceWaitingEventPayload)
+		if !ok {
+			return apiEvent, eventTypePayloadMismatch
+		}
+		apiEvent.ResourceWaitingEvent = &apitype.ResourceWaitingEvent{
+			Metadata: convertStepEventMetadata(p.Metadata, showSecrets),
+			Planning: p.Planning,
+		}
+
 	case engine.ResourceOutputsEvent:
 		p, ok := e.Payload().(engine.ResourceOutputsEventPayload)
 		if !ok {
@@ -214,6 +239,15 @@ func convertStepEventMetadata(md engine.StepEventMetadata, showSecrets bool) api
 		}
 	}
 
+	var ignoredChanges []apitype.IgnoredChange
+	for _, v := range md.IgnoredChanges {
+		ignoredChanges = append(ignoredChanges, apitype.IgnoredChange{
+			Path:    v.Path.String(),
+			Rule:    v.Rule,
+			Default: v.Default,
+		})
+	}
+
 	return apitype.StepEventMetadata{
 		Op:   apitype.OpType(md.Op),
 		URN:  string(md.URN),
@@ -222,11 +256,12 @@ func convertStepEventMetadata(md engine.StepEventMetadata, showSecrets bool) api
 		Old: convertStepEventStateMetadata(md.Old, showSecrets),
 		New: convertStepEventStateMetadata(md.New, showSecrets),
 
-		Keys:         keys,
-		Diffs:        diffs,
-		DetailedDiff: detailedDiff,
-		Logical:      md.Logical,
-		Provider:     md.Provider,
+		Keys:           keys,
+		Diffs:          diffs,
+		DetailedDiff:   detailedDiff,
+		Logical:        md.Logical,
+		Provider:       md.Provider,
+		IgnoredChanges: ignoredChanges,
 	}
 }
 
@@ -359,6 +394,13 @@ func ConvertJSONEvent(apiEvent apitype.EngineEvent) (engine.Event, error) {
 			Planning: p.Planning,
 		})
 
+	case apiEvent.ResourceWaitingEvent != nil:
+		p := apiEvent.ResourceWaitingEvent
+		event = engine.NewEvent(engine.ResourceWaitingEvent, engine.ResourceWaitingEventPayload{
+			Metadata: convertJSONStepEventMetadata(p.Metadata),
+			Planning: p.Planning,
+		})
+
 	case apiEvent.ResOutputsEvent != nil:
 		p := apiEvent.ResOutputsEvent
 		event = engine.NewEvent(engine.ResourceOutputsEvent, engine.ResourceOutputsEventPayload{
@@ -374,6 +416,17 @@ func ConvertJSONEvent(apiEvent apitype.EngineEvent) (engine.Event, error) {
 			Steps:    p.Steps,
 		})
 
+	case apiEvent.ProgressEvent != nil:
+		p := apiEvent.ProgressEvent
+		event = engine.NewEvent(engine.ProgressEvent, engine.ProgressEventPayload{
+			Type:      p.Type,
+			ID:        p.ID,
+			Message:   p.Message,
+			Completed: p.Completed,
+			Total:     p.Total,
+			Done:      p.Done,
+		})
+
 	default:
 		return event, errors.New("unknown event type")
 	}
@@ -418,6 +471,19 @@ func convertJSONStepEventMetadata(md apitype.StepEventMetadata) engine.StepEvent
 		}
 	}
 
+	var ignoredChanges []deploy.IgnoredChange
+	for _, v := range md.IgnoredChanges {
+		path, err := resource.ParsePropertyPath(v.Path)
+		if err != nil {
+			continue
+		}
+		ignoredChanges = append(ignoredChanges, deploy.IgnoredChange{
+			Path:    path,
+			Rule:    v.Rule,
+			Default: v.Default,
+		})
+	}
+
 	old, new := convertJSONStepEventStateMetadata(md.Old), convertJSONStepEventStateMetadata(md.New)
 
 	res := old
@@ -439,6 +505,8 @@ func convertJSONStepEventMetadata(md apitype.StepEventMetadata) engine.StepEvent
 		DetailedDiff: detailedDiff,
 		Logical:      md.Logical,
 		Provider:     md.Provider,
+
+		IgnoredChanges: ignoredChanges,
 	}
 }
 
@@ -234,6 +234,13 @@ func (r *messageRenderer) systemMessage(display *ProgressDisplay, payload engine
 	}
 }
 
+func (r *messageRenderer) progress(display *ProgressDisplay, payload engine.ProgressEventPayload, first bool) {
+	// Progress can't be updated in place in this display, so just note when each piece of work starts.
+	if first {
+		r.writeSimpleMessage(payload.Message + "...")
+	}
+}
+
 func (r *messageRenderer) done(display *ProgressDisplay) {
 	if r.isInteractive {
 		r.render(display, false)
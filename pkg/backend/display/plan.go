@@ -0,0 +1,135 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// PrintPlan writes a saved plan to out, rendered like the diff of a preview: each resource's planned operations and the
+// changes planned to its inputs, nested under its parent, followed by a summary of the planned operations.
+func PrintPlan(out io.Writer, plan *deploy.Plan, opts Options) {
+	// Order the resources as a tree, so that children are shown under their parents.
+	children := map[resource.URN][]resource.URN{}
+	var roots []resource.URN
+	for urn, rp := range plan.ResourcePlans {
+		if rp.Goal != nil && rp.Goal.Parent != "" {
+			if _, has := plan.ResourcePlans[rp.Goal.Parent]; has {
+				children[rp.Goal.Parent] = append(children[rp.Goal.Parent], urn)
+				continue
+			}
+		}
+		roots = append(roots, urn)
+	}
+
+	var b bytes.Buffer
+	changes := display.ResourceChanges{}
+	var printResource func(urn resource.URN, indent int)
+	printResource = func(urn resource.URN, indent int) {
+		rp := plan.ResourcePlans[urn]
+		op := summaryOp(rp)
+		if len(rp.Ops) > 0 {
+			changes[op]++
+		}
+
+		ops := make([]string, len(rp.Ops))
+		for i, op := range rp.Ops {
+			ops[i] = string(op)
+		}
+		writeString(&b, getIndentationString(indent, op, false))
+		writeString(&b, deploy.Prefix(op, true /*done*/))
+		writeString(&b, fmt.Sprintf("%s: (%s)%s\n", urn.Type(), strings.Join(ops, ", "), colors.Reset))
+		writeWithIndentNoPrefix(&b, indent+1, considerSameIfNotCreateOrDelete(op), "[urn=%s]\n", urn)
+
+		if rp.Goal != nil {
+			inputs := rp.Goal.InputDiff
+			PrintObject(&b, inputs.Adds, true /*planning*/, indent+1,
+				deploy.OpCreate, true /*prefix*/, opts.TruncateOutput, opts.Debug)
+			PrintObject(&b, inputs.Updates, true /*planning*/, indent+1,
+				deploy.OpUpdate, true /*prefix*/, opts.TruncateOutput, opts.Debug)
+			deletes := append([]resource.PropertyKey(nil), inputs.Deletes...)
+			sort.Slice(deletes, func(i, j int) bool { return deletes[i] < deletes[j] })
+			for _, k := range deletes {
+				writeWithIndent(&b, indent+1, deploy.OpDelete, true /*prefix*/, "%s\n", k)
+			}
+		}
+
+		sortURNs(children[urn])
+		for _, child := range children[urn] {
+			printResource(child, indent+1)
+		}
+	}
+	sortURNs(roots)
+	for _, urn := range roots {
+		printResource(urn, 0)
+	}
+
+	fprintIgnoreError(out, opts.Color.Colorize(b.String()))
+	fprintIgnoreError(out, "\n")
+	fprintIgnoreError(out, renderSummaryEvent(engine.SummaryEventPayload{
+		IsPreview:       true,
+		ResourceChanges: changes,
+	}, false /*hasError*/, false /*diffStyleSummary*/, opts))
+}
+
+// PrintPlanDiff writes the differences between two saved plans, as returned by deploy.DiffPlans, to out.
+func PrintPlanDiff(out io.Writer, changes []deploy.ResourcePlanChange, opts Options) {
+	var b bytes.Buffer
+	for _, change := range changes {
+		switch {
+		case change.Old == nil:
+			writeWithIndent(&b, 0, deploy.OpCreate, false /*prefix*/, "%s%s (added to the plan)\n",
+				deploy.RawPrefix(deploy.OpCreate), change.URN)
+		case change.New == nil:
+			writeWithIndent(&b, 0, deploy.OpDelete, false /*prefix*/, "%s%s (removed from the plan)\n",
+				deploy.RawPrefix(deploy.OpDelete), change.URN)
+		default:
+			writeWithIndent(&b, 0, deploy.OpUpdate, false /*prefix*/, "%s%s\n",
+				deploy.RawPrefix(deploy.OpUpdate), change.URN)
+			for _, difference := range change.Differences {
+				writeWithIndentNoPrefix(&b, 1, deploy.OpSame, "%s\n", difference)
+			}
+		}
+	}
+	fprintIgnoreError(out, opts.Color.Colorize(b.String()))
+}
+
+// summaryOp returns the operation that best summarizes a resource's planned operations: a replacement if the resource
+// is planned to be replaced, and otherwise its first planned operation.
+func summaryOp(rp *deploy.ResourcePlan) display.StepOp {
+	if len(rp.Ops) == 0 {
+		return deploy.OpSame
+	}
+	for _, op := range rp.Ops {
+		if op == deploy.OpReplace {
+			return op
+		}
+	}
+	return rp.Ops[0]
+}
+
+func sortURNs(urns []resource.URN) {
+	sort.Slice(urns, func(i, j int) bool { return urns[i] < urns[j] })
+}
@@ -0,0 +1,100 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package display
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestPrintPlan(t *testing.T) {
+	t.Parallel()
+
+	const (
+		stack  = resource.URN("urn:pulumi:test::test::pulumi:pulumi:Stack::test-test")
+		bucket = resource.URN("urn:pulumi:test::test::pulumi:pulumi:Stack$aws:s3/bucket:Bucket::b")
+		queue  = resource.URN("urn:pulumi:test::test::pulumi:pulumi:Stack$aws:sqs/queue:Queue::q")
+	)
+	plan := &deploy.Plan{ResourcePlans: map[resource.URN]*deploy.ResourcePlan{
+		stack: {
+			Goal: &deploy.GoalPlan{Type: "pulumi:pulumi:Stack"},
+			Ops:  []display.StepOp{deploy.OpSame},
+		},
+		bucket: {
+			Goal: &deploy.GoalPlan{
+				Type:   "aws:s3/bucket:Bucket",
+				Custom: true,
+				Parent: stack,
+				InputDiff: deploy.PlanDiff{
+					Adds:    resource.PropertyMap{"acl": resource.NewStringProperty("private")},
+					Updates: resource.PropertyMap{"tags": resource.NewStringProperty("prod")},
+					Deletes: []resource.PropertyKey{"website"},
+				},
+			},
+			Ops: []display.StepOp{deploy.OpCreateReplacement, deploy.OpReplace, deploy.OpDeleteReplaced},
+		},
+		queue: {
+			Ops: []display.StepOp{deploy.OpDelete},
+		},
+	}}
+
+	var out bytes.Buffer
+	PrintPlan(&out, plan, Options{Color: colors.Never})
+	assert.Equal(t, `- aws:sqs/queue:Queue: (delete)
+    [urn=urn:pulumi:test::test::pulumi:pulumi:Stack$aws:sqs/queue:Queue::q]
+  pulumi:pulumi:Stack: (same)
+    [urn=urn:pulumi:test::test::pulumi:pulumi:Stack::test-test]
+    +-aws:s3/bucket:Bucket: (create-replacement, replace, delete-replaced)
+        [urn=urn:pulumi:test::test::pulumi:pulumi:Stack$aws:s3/bucket:Bucket::b]
+      + acl: "private"
+      ~ tags: "prod"
+      - website
+
+Resources:
+    - 1 to delete
+    +-1 to replace
+    2 changes. 1 unchanged
+`, out.String())
+}
+
+func TestPrintPlanDiff(t *testing.T) {
+	t.Parallel()
+
+	changes := []deploy.ResourcePlanChange{
+		{
+			URN:         "urn:pulumi:test::test::pkgA:m:typA::resA",
+			Old:         &deploy.ResourcePlan{},
+			New:         &deploy.ResourcePlan{},
+			Differences: []string{"protect changed: false => true"},
+		},
+		{URN: "urn:pulumi:test::test::pkgA:m:typA::resB", Old: &deploy.ResourcePlan{}},
+		{URN: "urn:pulumi:test::test::pkgA:m:typA::resC", New: &deploy.ResourcePlan{}},
+	}
+
+	var out bytes.Buffer
+	PrintPlanDiff(&out, changes, Options{Color: colors.Never})
+	assert.Equal(t, `~ urn:pulumi:test::test::pkgA:m:typA::resA
+    protect changed: false => true
+- urn:pulumi:test::test::pkgA:m:typA::resB (removed from the plan)
++ urn:pulumi:test::test::pkgA:m:typA::resC (added to the plan)
+`, out.String())
+}
@@ -24,7 +24,9 @@ import (
 	"strings"
 	"time"
 	"unicode"
+	"unicode/utf8"
 
+	"github.com/dustin/go-humanize"
 	"github.com/dustin/go-humanize/english"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display/internal/terminal"
 	"github.com/pulumi/pulumi/pkg/v3/display"
@@ -68,6 +70,7 @@ type progressRenderer interface {
 	tick(display *ProgressDisplay)
 	rowUpdated(display *ProgressDisplay, row Row)
 	systemMessage(display *ProgressDisplay, payload engine.StdoutEventPayload)
+	progress(display *ProgressDisplay, payload engine.ProgressEventPayload, first bool)
 	done(display *ProgressDisplay)
 	println(display *ProgressDisplay, line string)
 }
@@ -106,6 +109,10 @@ type ProgressDisplay struct {
 	// Any system events we've received.  They will be printed at the bottom of all the status rows
 	systemEventPayloads []engine.StdoutEventPayload
 
+	// The latest progress of ongoing work, such as plugin downloads, in the order the work started. Work is removed
+	// once it's done.
+	progressEventPayloads []engine.ProgressEventPayload
+
 	// Used to record the order that rows are created in.  That way, when we present in a tree, we
 	// can keep things ordered so they will not jump around.
 	displayOrderCounter int
@@ -164,6 +171,9 @@ func getEventUrnAndMetadata(event engine.Event) (resource.URN, *engine.StepEvent
 	case engine.ResourcePreEvent:
 		payload := event.Payload().(engine.ResourcePreEventPayload)
 		return payload.Metadata.URN, &payload.Metadata
+	case engine.ResourceWaitingEvent:
+		payload := event.Payload().(engine.ResourceWaitingEventPayload)
+		return payload.Metadata.URN, &payload.Metadata
 	case engine.ResourceOutputsEvent:
 		payload := event.Payload().(engine.ResourceOutputsEventPayload)
 		return payload.Metadata.URN, &payload.Metadata
@@ -858,6 +868,9 @@ func (display *ProgressDisplay) processNormalEvent(event engine.Event) {
 	case engine.StdoutColorEvent:
 		display.handleSystemEvent(event.Payload().(engine.StdoutEventPayload))
 		return
+	case engine.ProgressEvent:
+		display.handleProgressEvent(event.Payload().(engine.ProgressEventPayload))
+		return
 	}
 
 	// At this point, all events should relate to resources.
@@ -920,6 +933,10 @@ func (display *ProgressDisplay) processNormalEvent(event engine.Event) {
 		delete(display.opStopwatch.end, step.URN)
 
 		row.SetStep(step)
+		row.SetWaiting(false)
+	} else if event.Type == engine.ResourceWaitingEvent {
+		row.SetStep(event.Payload().(engine.ResourceWaitingEventPayload).Metadata)
+		row.SetWaiting(true)
 	} else if event.Type == engine.ResourceOutputsEvent {
 		isRefresh := display.getStepOp(row.Step()) == deploy.OpRefresh
 		step := event.Payload().(engine.ResourceOutputsEventPayload).Metadata
@@ -970,6 +987,59 @@ func (display *ProgressDisplay) handleSystemEvent(payload engine.StdoutEventPayl
 	display.renderer.systemMessage(display, payload)
 }
 
+func (display *ProgressDisplay) handleProgressEvent(payload engine.ProgressEventPayload) {
+	// Make sure we have a header to display
+	display.ensureHeaderAndStackRows()
+
+	index := -1
+	for i, p := range display.progressEventPayloads {
+		if p.ID == payload.ID {
+			index = i
+			break
+		}
+	}
+	switch {
+	case index == -1 && !payload.Done:
+		display.progressEventPayloads = append(display.progressEventPayloads, payload)
+	case index != -1 && payload.Done:
+		display.progressEventPayloads = append(display.progressEventPayloads[:index],
+			display.progressEventPayloads[index+1:]...)
+	case index != -1:
+		display.progressEventPayloads[index] = payload
+	}
+
+	display.renderer.progress(display, payload, index == -1)
+}
+
+// renderProgressEvent renders the progress of some work as a single line no wider than the given width.
+func renderProgressEvent(payload engine.ProgressEventPayload, width int) string {
+	if payload.Type != apitype.PluginDownload {
+		return payload.Message + "..."
+	}
+
+	amount := humanize.Bytes(uint64(payload.Completed))
+	if payload.Total <= 0 {
+		return fmt.Sprintf("%s %s", payload.Message, amount)
+	}
+	amount += "/" + humanize.Bytes(uint64(payload.Total))
+
+	// Fit a bar of up to 40 characters between the message and the amount, if there's room for one.
+	barWidth := width - utf8.RuneCountInString(payload.Message) - utf8.RuneCountInString(amount) - 4
+	if barWidth > 40 {
+		barWidth = 40
+	}
+	if barWidth < 10 {
+		return fmt.Sprintf("%s %s", payload.Message, amount)
+	}
+	completed := payload.Completed
+	if completed > payload.Total {
+		completed = payload.Total
+	}
+	filled := int(int64(barWidth) * completed / payload.Total)
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", barWidth-filled)
+	return fmt.Sprintf("%s [%s] %s", payload.Message, bar, amount)
+}
+
 func (display *ProgressDisplay) ensureHeaderAndStackRows() {
 	if display.headerRow == nil {
 		// about to make our first status message.  make sure we present the header line first.
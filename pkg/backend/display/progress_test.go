@@ -27,6 +27,7 @@ import (
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -248,3 +249,44 @@ func TestPrintDiagnosticsIsTolerantOfDiagnostics(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderProgressEvent(t *testing.T) {
+	t.Parallel()
+
+	download := engine.ProgressEventPayload{
+		Type:      apitype.PluginDownload,
+		ID:        "resource:aws-5.4.0",
+		Message:   "Downloading resource plugin aws-5.4.0",
+		Completed: 500,
+		Total:     1000,
+	}
+	unknownSize := download
+	unknownSize.Total = 0
+	install := engine.ProgressEventPayload{
+		Type:    apitype.PluginInstall,
+		ID:      "resource:aws-5.4.0",
+		Message: "Installing resource plugin aws-5.4.0",
+	}
+
+	tests := []struct {
+		name    string
+		payload engine.ProgressEventPayload
+		width   int
+		want    string
+	}{
+		{"download", download, 80,
+			"Downloading resource plugin aws-5.4.0 [=============              ] 500 B/1.0 kB"},
+		{"wide download", download, 200,
+			"Downloading resource plugin aws-5.4.0 [====================                    ] 500 B/1.0 kB"},
+		{"narrow download", download, 60, "Downloading resource plugin aws-5.4.0 500 B/1.0 kB"},
+		{"unknown size", unknownSize, 80, "Downloading resource plugin aws-5.4.0 500 B"},
+		{"install", install, 80, "Installing resource plugin aws-5.4.0..."},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, renderProgressEvent(tt.payload, tt.width))
+		})
+	}
+}
@@ -77,7 +77,7 @@ func ShowQueryEvents(op string, events <-chan engine.Event,
 
 func renderQueryEvent(event engine.Event, opts Options) string {
 	switch event.Type {
-	case engine.CancelEvent:
+	case engine.CancelEvent, engine.ProgressEvent, engine.ResourceWaitingEvent:
 		return ""
 
 	case engine.StdoutColorEvent:
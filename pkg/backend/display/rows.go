@@ -56,6 +56,9 @@ type ResourceRow interface {
 
 	IsDone() bool
 
+	// SetWaiting records whether the row's step is waiting for a parallelism limit before it can start.
+	SetWaiting(waiting bool)
+
 	SetFailed()
 
 	DiagInfo() *DiagInfo
@@ -128,6 +131,9 @@ type resourceRowData struct {
 	// If we failed this operation for any reason.
 	failed bool
 
+	// If the step is waiting for a parallelism limit before it can start.
+	waiting bool
+
 	diagInfo                  *DiagInfo
 	policyPayloads            []engine.PolicyViolationEventPayload
 	policyRemediationPayloads []engine.PolicyRemediationEventPayload
@@ -181,6 +187,10 @@ func (data *resourceRowData) SetFailed() {
 	data.failed = true
 }
 
+func (data *resourceRowData) SetWaiting(waiting bool) {
+	data.waiting = waiting
+}
+
 func (data *resourceRowData) DiagInfo() *DiagInfo {
 	return data.diagInfo
 }
@@ -322,7 +332,11 @@ func (data *resourceRowData) ColorizedColumns() []string {
 
 	failed := data.failed || diagInfo.ErrorCount > 0
 
-	columns[statusColumn] = data.display.getStepStatus(step, done, failed)
+	if data.waiting && !done {
+		columns[statusColumn] = "waiting"
+	} else {
+		columns[statusColumn] = data.display.getStepStatus(step, done, failed)
+	}
 	columns[infoColumn] = data.getInfoColumn()
 	return columns
 }
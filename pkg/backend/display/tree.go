@@ -43,6 +43,7 @@ type treeRenderer struct {
 	rewind int  // The number of lines we need to rewind to redraw the entire screen.
 
 	treeTableRows         []string
+	progressPayloads      []engine.ProgressEventPayload
 	systemMessages        []string
 	statusMessage         string
 	statusMessageDeadline time.Time
@@ -96,6 +97,10 @@ func (r *treeRenderer) systemMessage(display *ProgressDisplay, _ engine.StdoutEv
 	r.render(display)
 }
 
+func (r *treeRenderer) progress(display *ProgressDisplay, _ engine.ProgressEventPayload, _ bool) {
+	r.render(display)
+}
+
 func (r *treeRenderer) done(display *ProgressDisplay) {
 	r.render(display)
 
@@ -166,6 +171,8 @@ func (r *treeRenderer) render(display *ProgressDisplay) {
 		r.treeTableRows = append(r.treeTableRows, rendered)
 	}
 
+	r.progressPayloads = append(r.progressPayloads[:0], display.progressEventPayloads...)
+
 	// Convert system events into lines.
 	r.systemMessages = r.systemMessages[:0]
 	for _, payload := range display.systemEventPayloads {
@@ -190,6 +197,7 @@ func (r *treeRenderer) markDirty() {
 // | treetable header                           |
 // | treetable contents...                      |
 // | treetable footer                           |
+// | progress of ongoing work...                |
 // | system messages header                     |
 // | system messages contents...                |
 // | status message                             |
@@ -209,6 +217,7 @@ func (r *treeRenderer) frame(locked, done bool) {
 	contract.IgnoreError(err)
 
 	treeTableRows := r.treeTableRows
+	progress := r.progressPayloads
 	systemMessages := r.systemMessages
 	statusMessage := r.statusMessage
 
@@ -219,6 +228,8 @@ func (r *treeRenderer) frame(locked, done bool) {
 		treeTableHeight = 1 + len(treeTableRows)
 	}
 
+	progressHeight := len(progress)
+
 	systemMessagesHeight := len(systemMessages)
 	if len(systemMessages) > 0 {
 		systemMessagesHeight += 3 // Account for padding + title
@@ -233,7 +244,7 @@ func (r *treeRenderer) frame(locked, done bool) {
 	autoscroll := r.treeTableOffset == r.maxTreeTableOffset
 
 	// Layout the display. The extra '1' accounts for the fact that we terminate each line with a newline.
-	totalHeight := treeTableHeight + systemMessagesHeight + statusMessageHeight + 1
+	totalHeight := treeTableHeight + progressHeight + systemMessagesHeight + statusMessageHeight + 1
 	r.maxTreeTableOffset = 0
 
 	// If this is not the final frame and the terminal is not large enough to show the entire display:
@@ -254,11 +265,11 @@ func (r *treeRenderer) frame(locked, done bool) {
 			}
 		}
 
-		// If there are no system messages and we have a status message to display, fold the status message into the
-		// last line of the tree table (where the scroll indicator is displayed).
-		mergeLastLine := systemMessagesHeight == 0 && statusMessageHeight != 0
+		// If there are no progress or system messages and we have a status message to display, fold the status message
+		// into the last line of the tree table (where the scroll indicator is displayed).
+		mergeLastLine := progressHeight == 0 && systemMessagesHeight == 0 && statusMessageHeight != 0
 
-		treeTableHeight = termHeight - systemMessagesHeight - statusMessageHeight - 1
+		treeTableHeight = termHeight - progressHeight - systemMessagesHeight - statusMessageHeight - 1
 		r.maxTreeTableOffset = len(treeTableRows) - treeTableHeight + 1
 		scrollable := r.maxTreeTableOffset != 0
 
@@ -272,7 +283,7 @@ func (r *treeRenderer) frame(locked, done bool) {
 		}
 		treeTableRows = treeTableRows[r.treeTableOffset : r.treeTableOffset+treeTableHeight-1]
 
-		totalHeight = treeTableHeight + systemMessagesHeight + statusMessageHeight + 1
+		totalHeight = treeTableHeight + progressHeight + systemMessagesHeight + statusMessageHeight + 1
 
 		footer := ""
 		if scrollable {
@@ -304,7 +315,7 @@ func (r *treeRenderer) frame(locked, done bool) {
 		}
 		treeTableFooter = r.opts.Color.Colorize(prefix + strings.Repeat(" ", padding) + footer)
 
-		if systemMessagesHeight > 0 {
+		if progressHeight > 0 || systemMessagesHeight > 0 {
 			treeTableFooter += "\n"
 		}
 	}
@@ -329,6 +340,11 @@ func (r *treeRenderer) frame(locked, done bool) {
 		r.over(treeTableFooter)
 	}
 
+	// Render the progress of ongoing work.
+	for _, payload := range progress {
+		r.overln(r.clampLine(renderProgressEvent(payload, termWidth), termWidth))
+	}
+
 	// Render the system messages.
 	if systemMessagesHeight > 0 {
 		r.overln("")
@@ -33,8 +33,9 @@ import (
 // See https://tools.ietf.org/html/rfc5424#section-6.2.3.
 const timeFormat = "15:04:05.000"
 
-// ShowWatchEvents renders incoming engine events for display in Watch Mode.
-func ShowWatchEvents(op string, events <-chan engine.Event, done chan<- bool, opts Options) {
+// ShowWatchEvents renders incoming engine events for display in Watch Mode. Watch mode uses previews to decide which
+// resources to update, so only diagnostics are shown for previews.
+func ShowWatchEvents(op string, events <-chan engine.Event, done chan<- bool, opts Options, isPreview bool) {
 	// Ensure we close the done channel before exiting.
 	defer func() { close(done) }()
 	for e := range events {
@@ -46,9 +47,12 @@ func ShowWatchEvents(op string, events <-chan engine.Event, done chan<- bool, op
 		// For all other events, use the payload to build up the JSON digest we'll emit later.
 		switch e.Type {
 		// Events occurring early:
-		case engine.PreludeEvent, engine.SummaryEvent, engine.StdoutColorEvent:
+		case engine.PreludeEvent, engine.SummaryEvent, engine.StdoutColorEvent, engine.ProgressEvent:
 			// Ignore it
 			continue
+		case engine.ResourceWaitingEvent:
+			// Steps are only printed once they start.
+			continue
 		case engine.PolicyViolationEvent:
 			// At this point in time, we don't handle policy events as part of pulumi watch
 			continue
@@ -63,19 +67,19 @@ func ShowWatchEvents(op string, events <-chan engine.Event, done chan<- bool, op
 				"%s", renderDiffDiagEvent(p, opts))
 		case engine.ResourcePreEvent:
 			p := e.Payload().(engine.ResourcePreEventPayload)
-			if shouldShow(p.Metadata, opts) {
+			if !isPreview && shouldShow(p.Metadata, opts) {
 				PrintfWithWatchPrefix(time.Now(), string(p.Metadata.URN.Name()),
 					"%s %s\n", p.Metadata.Op, p.Metadata.URN.Type())
 			}
 		case engine.ResourceOutputsEvent:
 			p := e.Payload().(engine.ResourceOutputsEventPayload)
-			if shouldShow(p.Metadata, opts) {
+			if !isPreview && shouldShow(p.Metadata, opts) {
 				PrintfWithWatchPrefix(time.Now(), string(p.Metadata.URN.Name()),
 					"done %s %s\n", p.Metadata.Op, p.Metadata.URN.Type())
 			}
 		case engine.ResourceOperationFailed:
 			p := e.Payload().(engine.ResourceOperationFailedPayload)
-			if shouldShow(p.Metadata, opts) {
+			if !isPreview && shouldShow(p.Metadata, opts) {
 				PrintfWithWatchPrefix(time.Now(), string(p.Metadata.URN.Name()),
 					"failed %s %s\n", p.Metadata.Op, p.Metadata.URN.Type())
 			}
@@ -39,6 +39,7 @@ import (
 	_ "gocloud.dev/blob/s3blob"    // driver for s3://
 	"gocloud.dev/gcerrors"
 
+	"github.com/pulumi/esc"
 	"github.com/pulumi/pulumi/pkg/v3/authhelpers"
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
@@ -90,6 +91,19 @@ type Backend interface {
 
 	// Upgrade to the latest state store version.
 	Upgrade(ctx context.Context, opts *UpgradeOptions) error
+
+	// ListEnvironments returns the names of the environments stored in the backend, in sorted order.
+	ListEnvironments(ctx context.Context) ([]string, error)
+	// GetEnvironment returns the definition of the environment with the given name.
+	GetEnvironment(ctx context.Context, name string) ([]byte, error)
+	// UpdateEnvironment creates or replaces the definition of the environment with the given name.
+	UpdateEnvironment(ctx context.Context, name string, definition []byte) error
+	// DeleteEnvironment deletes the environment with the given name.
+	DeleteEnvironment(ctx context.Context, name string) error
+	// OpenEnvironment evaluates the given environment definition, decrypting its secrets with the given decrypter.
+	OpenEnvironment(
+		ctx context.Context, name string, definition []byte, decrypter EnvironmentDecrypterFunc,
+	) (*esc.Environment, []apitype.EnvironmentDiagnostic, error)
 }
 
 type localBackend struct {
@@ -110,6 +124,11 @@ type localBackend struct {
 
 	Env env.Env
 
+	// envStore is where environment definitions are stored. It is opened on first use by environments.
+	envStoreOnce sync.Once
+	envStore     environmentStore
+	envStoreErr  error
+
 	// The current project, if any.
 	currentProject atomic.Pointer[workspace.Project]
 
@@ -196,6 +215,15 @@ func New(ctx context.Context, d diag.Sink, originalURL string, project *workspac
 	return newLocalBackend(ctx, d, originalURL, project, nil)
 }
 
+// NewWithEnv constructs a new filestate backend like New,
+// but reads the environment variables that configure it from e
+// rather than from the environment of the current process.
+func NewWithEnv(
+	ctx context.Context, d diag.Sink, originalURL string, project *workspace.Project, e env.Env,
+) (Backend, error) {
+	return newLocalBackend(ctx, d, originalURL, project, &localBackendOptions{Env: e})
+}
+
 type localBackendOptions struct {
 	// Env specifies how to get environment variables.
 	//
@@ -819,7 +847,7 @@ func (b *localBackend) renameStack(ctx context.Context, oldRef *localBackendRefe
 		return err
 	}
 	if hasExisting {
-		return fmt.Errorf("a stack named %s already exists", newRef.String())
+		return &backend.StackAlreadyExistsError{StackName: newRef.String()}
 	}
 
 	// Get the current state from the stack to be renamed.
@@ -948,9 +976,9 @@ func (b *localBackend) Query(ctx context.Context, op backend.QueryOperation) err
 }
 
 func (b *localBackend) Watch(ctx context.Context, stk backend.Stack,
-	op backend.UpdateOperation, paths []string,
+	op backend.UpdateOperation, opts backend.WatchOptions,
 ) result.Result {
-	return backend.Watch(ctx, b, stk, op, b.apply, paths)
+	return backend.Watch(ctx, b, stk, op, b.apply, opts)
 }
 
 // apply actually performs the provided type of update on a locally hosted stack.
@@ -972,9 +1000,14 @@ func (b *localBackend) apply(
 	stackName := stackRef.FullyQualifiedName()
 	actionLabel := backend.ActionLabel(kind, opts.DryRun)
 
+	stdout := op.Opts.Display.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+
 	if !(op.Opts.Display.JSONDisplay || op.Opts.Display.Type == display.DisplayWatch) {
 		// Print a banner so it's clear this is a local deployment.
-		fmt.Printf(op.Opts.Display.Color.Colorize(
+		fmt.Fprintf(stdout, op.Opts.Display.Color.Colorize(
 			colors.SpecHeadline+"%s (%s):"+colors.Reset+"\n"), actionLabel, stackRef)
 	}
 
@@ -1124,7 +1157,7 @@ func (b *localBackend) apply(
 		}
 
 		if link != "" {
-			fmt.Printf(op.Opts.Display.Color.Colorize(
+			fmt.Fprintf(stdout, op.Opts.Display.Color.Colorize(
 				colors.SpecHeadline+"Permalink: "+
 					colors.Underline+colors.BrightBlue+"%s"+colors.Reset+"\n"), link)
 		}
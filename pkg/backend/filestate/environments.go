@@ -0,0 +1,371 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/pulumi/esc"
+	"github.com/pulumi/esc/eval"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/fileblob"
+	"gocloud.dev/gcerrors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// EnvironmentsDir is a path under the state's root directory where the filestate backend stores environment
+// definitions, unless PULUMI_SELF_MANAGED_ENVIRONMENTS_DIR names a local directory to store them in instead.
+var EnvironmentsDir = filepath.Join(workspace.BookkeepingDir, "environments")
+
+// ErrEnvironmentNotFound is returned when an environment does not exist.
+var ErrEnvironmentNotFound = errors.New("environment not found")
+
+// environmentNameRegexp matches valid environment names.
+var environmentNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// ValidateEnvironmentName returns an error if the given name is not a valid environment name.
+func ValidateEnvironmentName(name string) error {
+	if !environmentNameRegexp.MatchString(name) || name == "." || name == ".." {
+		return fmt.Errorf("invalid environment name %q: environment names may only contain alphanumerics, "+
+			"hyphens, underscores, and periods", name)
+	}
+	return nil
+}
+
+// EnvironmentDecrypterFunc returns the decrypter used to decrypt the secrets in an environment definition. It is
+// only called if the definition contains encrypted secrets.
+type EnvironmentDecrypterFunc func() (config.Decrypter, error)
+
+var _ backend.EnvironmentsBackend = (*localBackend)(nil)
+
+// environmentStore is the location of a backend's environment definitions: a directory within a bucket.
+type environmentStore struct {
+	bucket Bucket
+	dir    string
+}
+
+func (s environmentStore) path(name string) string {
+	return filepath.Join(s.dir, name+".yaml")
+}
+
+// environments returns the store that holds the backend's environments. Environments are stored in the state bucket
+// unless PULUMI_SELF_MANAGED_ENVIRONMENTS_DIR names a local directory, which is created if it does not exist.
+func (b *localBackend) environments() (environmentStore, error) {
+	b.envStoreOnce.Do(func() {
+		dir := b.Env.GetString(env.SelfManagedEnvironmentsDir)
+		if dir == "" {
+			b.envStore = environmentStore{bucket: b.bucket, dir: EnvironmentsDir}
+			return
+		}
+
+		dir, err := filepath.Abs(dir)
+		if err != nil {
+			b.envStoreErr = err
+			return
+		}
+		bucket, err := fileblob.OpenBucket(dir, &fileblob.Options{CreateDir: true})
+		if err != nil {
+			b.envStoreErr = fmt.Errorf("opening environments directory %q: %w", dir, err)
+			return
+		}
+		b.envStore = environmentStore{bucket: &wrappedBucket{bucket: bucket}}
+	})
+	return b.envStore, b.envStoreErr
+}
+
+// ListEnvironments returns the names of the environments stored in the backend, in sorted order.
+func (b *localBackend) ListEnvironments(ctx context.Context) ([]string, error) {
+	store, err := b.environments()
+	if err != nil {
+		return nil, err
+	}
+
+	// Unlike listBucket, this must also list the root of the bucket when the store's directory is empty.
+	prefix := ""
+	if store.dir != "" {
+		prefix = filepath.ToSlash(store.dir) + "/"
+	}
+	iter := store.bucket.List(&blob.ListOptions{Delimiter: "/", Prefix: prefix})
+
+	var names []string
+	for {
+		file, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("listing environments: %w", err)
+		}
+		if file.IsDir {
+			continue
+		}
+		if name, ok := strings.CutSuffix(objectName(file), ".yaml"); ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// GetEnvironment returns the definition of the environment with the given name. Secrets in the definition are
+// returned in their encrypted form.
+func (b *localBackend) GetEnvironment(ctx context.Context, name string) ([]byte, error) {
+	if err := ValidateEnvironmentName(name); err != nil {
+		return nil, err
+	}
+	store, err := b.environments()
+	if err != nil {
+		return nil, err
+	}
+	bytes, err := store.bucket.ReadAll(ctx, store.path(name))
+	if err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return nil, fmt.Errorf("%q: %w", name, ErrEnvironmentNotFound)
+		}
+		return nil, fmt.Errorf("reading environment %q: %w", name, err)
+	}
+	return bytes, nil
+}
+
+// UpdateEnvironment creates or replaces the definition of the environment with the given name. The definition must
+// be a valid environment definition.
+func (b *localBackend) UpdateEnvironment(ctx context.Context, name string, definition []byte) error {
+	if err := ValidateEnvironmentName(name); err != nil {
+		return err
+	}
+	if _, diags, err := eval.LoadYAMLBytes(name, definition); err != nil {
+		return fmt.Errorf("parsing environment %q: %w", name, err)
+	} else if diags.HasErrors() {
+		return fmt.Errorf("parsing environment %q: %w", name, diags)
+	}
+	store, err := b.environments()
+	if err != nil {
+		return err
+	}
+	if err := store.bucket.WriteAll(ctx, store.path(name), definition, nil); err != nil {
+		return fmt.Errorf("writing environment %q: %w", name, err)
+	}
+	return nil
+}
+
+// DeleteEnvironment deletes the environment with the given name.
+func (b *localBackend) DeleteEnvironment(ctx context.Context, name string) error {
+	if err := ValidateEnvironmentName(name); err != nil {
+		return err
+	}
+	store, err := b.environments()
+	if err != nil {
+		return err
+	}
+	if err := store.bucket.Delete(ctx, store.path(name)); err != nil {
+		if gcerrors.Code(err) == gcerrors.NotFound {
+			return fmt.Errorf("%q: %w", name, ErrEnvironmentNotFound)
+		}
+		return fmt.Errorf("deleting environment %q: %w", name, err)
+	}
+	return nil
+}
+
+// OpenEnvironment evaluates the given environment definition. Imported environments are loaded from the backend.
+// Encrypted secrets in the definition and its imports are decrypted using the decrypter returned by decrypter.
+//
+// Providers (i.e. fn::open) are not supported by locally-evaluated environments.
+func (b *localBackend) OpenEnvironment(
+	ctx context.Context,
+	name string,
+	definition []byte,
+	decrypter EnvironmentDecrypterFunc,
+) (*esc.Environment, []apitype.EnvironmentDiagnostic, error) {
+	loader := &environmentLoader{b: b, decrypter: decrypter}
+
+	definition, err := loader.decryptSecrets(ctx, name, definition)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decl, diags, err := eval.LoadYAMLBytes(name, definition)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !diags.HasErrors() {
+		env, evalDiags := eval.EvalEnvironment(ctx, name, decl, noProviders{}, loader)
+		if !evalDiags.HasErrors() {
+			return env, nil, nil
+		}
+		diags = evalDiags
+	}
+
+	var envDiags []apitype.EnvironmentDiagnostic
+	for _, d := range diags {
+		if d.Severity != hcl.DiagError {
+			continue
+		}
+		envDiag := apitype.EnvironmentDiagnostic{Summary: d.Summary, Detail: d.Detail}
+		if d.Subject != nil {
+			envDiag.Range = &esc.Range{
+				Environment: d.Subject.Filename,
+				Begin:       esc.Pos{Line: d.Subject.Start.Line, Column: d.Subject.Start.Column},
+				End:         esc.Pos{Line: d.Subject.End.Line, Column: d.Subject.End.Column},
+			}
+		}
+		envDiags = append(envDiags, envDiag)
+	}
+	return nil, envDiags, nil
+}
+
+// OpenYAMLEnvironment implements backend.EnvironmentsBackend. Environments that are opened this way may not contain
+// encrypted secrets.
+func (b *localBackend) OpenYAMLEnvironment(
+	ctx context.Context,
+	org string,
+	yaml []byte,
+	duration time.Duration,
+) (*esc.Environment, []apitype.EnvironmentDiagnostic, error) {
+	return b.OpenEnvironment(ctx, "yaml", yaml, nil)
+}
+
+// environmentLoader loads imported environments from the backend.
+type environmentLoader struct {
+	b         *localBackend
+	decrypter EnvironmentDecrypterFunc
+
+	dec config.Decrypter
+}
+
+func (l *environmentLoader) LoadEnvironment(ctx context.Context, name string) ([]byte, error) {
+	definition, err := l.b.GetEnvironment(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return l.decryptSecrets(ctx, name, definition)
+}
+
+// decryptSecrets replaces each encrypted secret in the given environment definition, i.e. each
+// `fn::secret: {ciphertext: ...}`, with its plaintext.
+func (l *environmentLoader) decryptSecrets(ctx context.Context, name string, definition []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(definition, &doc); err != nil {
+		// Let the evaluator report the syntax error.
+		return definition, nil
+	}
+
+	changed := false
+	var walk func(n *yaml.Node) error
+	walk = func(n *yaml.Node) error {
+		if n.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				key, value := n.Content[i], n.Content[i+1]
+				if ciphertext, ok := encryptedSecret(key, value); ok {
+					plaintext, err := l.decrypt(ctx, ciphertext)
+					if err != nil {
+						return fmt.Errorf("decrypting secret in environment %q: %w", name, err)
+					}
+					n.Content[i+1] = &yaml.Node{
+						Kind:   yaml.ScalarNode,
+						Tag:    "!!str",
+						Value:  plaintext,
+						Line:   value.Line,
+						Column: value.Column,
+					}
+					changed = true
+					continue
+				}
+				if err := walk(value); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		for _, c := range n.Content {
+			if err := walk(c); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(&doc); err != nil {
+		return nil, err
+	}
+	if !changed {
+		return definition, nil
+	}
+	return yaml.Marshal(&doc)
+}
+
+func (l *environmentLoader) decrypt(ctx context.Context, ciphertext string) (string, error) {
+	if l.dec == nil {
+		if l.decrypter == nil {
+			return "", errors.New("no decrypter is available for encrypted secrets")
+		}
+		dec, err := l.decrypter()
+		if err != nil {
+			return "", err
+		}
+		l.dec = dec
+	}
+	return l.dec.DecryptValue(ctx, ciphertext)
+}
+
+// encryptedSecret returns the ciphertext of the given mapping entry if it is an encrypted secret of the form
+// `fn::secret: {ciphertext: ...}`.
+func encryptedSecret(key, value *yaml.Node) (string, bool) {
+	if key.Value != "fn::secret" || value.Kind != yaml.MappingNode || len(value.Content) != 2 {
+		return "", false
+	}
+	if value.Content[0].Value != "ciphertext" || value.Content[1].Kind != yaml.ScalarNode {
+		return "", false
+	}
+	return value.Content[1].Value, true
+}
+
+// EncryptedSecret returns the YAML node for an encrypted secret with the given ciphertext.
+func EncryptedSecret(ciphertext string) *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Value: "fn::secret"},
+			{
+				Kind:  yaml.MappingNode,
+				Style: yaml.FlowStyle,
+				Content: []*yaml.Node{
+					{Kind: yaml.ScalarNode, Value: "ciphertext"},
+					{Kind: yaml.ScalarNode, Value: ciphertext},
+				},
+			},
+		},
+	}
+}
+
+// noProviders is a provider loader for locally-evaluated environments, which do not support providers.
+type noProviders struct{}
+
+func (noProviders) LoadProvider(ctx context.Context, name string) (esc.Provider, error) {
+	return nil, fmt.Errorf("provider %q is not available: providers are not supported by self-managed backends", name)
+}
@@ -0,0 +1,164 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filestate
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pulumi/esc"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+)
+
+func TestEnvironments(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+
+	names, err := b.ListEnvironments(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	_, err = b.GetEnvironment(ctx, "base")
+	assert.ErrorIs(t, err, ErrEnvironmentNotFound)
+	assert.ErrorIs(t, b.DeleteEnvironment(ctx, "base"), ErrEnvironmentNotFound)
+	assert.ErrorContains(t, b.UpdateEnvironment(ctx, "../base", nil), "invalid environment name")
+
+	sm := b64.NewBase64SecretsManager()
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(ctx, "hunter2")
+	require.NoError(t, err)
+	password, err := yaml.Marshal(map[string]*yaml.Node{"password": EncryptedSecret(ciphertext)})
+	require.NoError(t, err)
+
+	require.NoError(t, b.UpdateEnvironment(ctx, "base", []byte("values:\n  region: us-west-2\n  "+
+		string(password))))
+	require.NoError(t, b.UpdateEnvironment(ctx, "app", []byte(`imports:
+  - base
+values:
+  pulumiConfig:
+    aws:region: ${region}
+  environmentVariables:
+    PASSWORD: ${password}
+`)))
+
+	names, err = b.ListEnvironments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "base"}, names)
+
+	// The definition stores the secret in its encrypted form.
+	definition, err := b.GetEnvironment(ctx, "base")
+	require.NoError(t, err)
+	assert.NotContains(t, string(definition), "hunter2")
+	assert.Contains(t, string(definition), ciphertext)
+
+	decrypterCalls := 0
+	decrypter := func() (config.Decrypter, error) {
+		decrypterCalls++
+		return sm.Decrypter()
+	}
+	env, diags, err := b.OpenEnvironment(ctx, "yaml", []byte("imports:\n  - app\n"), decrypter)
+	require.NoError(t, err)
+	require.Empty(t, diags)
+	assert.Equal(t, 1, decrypterCalls)
+	assert.Equal(t, "us-west-2",
+		env.Properties["pulumiConfig"].Value.(map[string]esc.Value)["aws:region"].Value)
+	password2 := env.Properties["environmentVariables"].Value.(map[string]esc.Value)["PASSWORD"]
+	assert.Equal(t, "hunter2", password2.Value)
+	assert.True(t, password2.Secret)
+
+	// Environments without encrypted secrets never load the decrypter.
+	_, diags, err = b.OpenEnvironment(ctx, "yaml", []byte("values:\n  foo: bar\n"), nil)
+	require.NoError(t, err)
+	assert.Empty(t, diags)
+
+	require.NoError(t, b.DeleteEnvironment(ctx, "app"))
+	names, err = b.ListEnvironments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base"}, names)
+}
+
+func TestEnvironmentsDir(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	stateDir, envDir := t.TempDir(), filepath.Join(t.TempDir(), "environments")
+	s := make(env.MapStore)
+	s[env.SelfManagedEnvironmentsDir.Var().Name()] = envDir
+	b, err := newLocalBackend(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(stateDir), nil,
+		&localBackendOptions{Env: env.NewEnv(s)})
+	require.NoError(t, err)
+
+	// The directory is created on first use.
+	names, err := b.ListEnvironments(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, names)
+
+	require.NoError(t, b.UpdateEnvironment(ctx, "base", []byte("values:\n  region: us-west-2\n")))
+	require.NoError(t, os.WriteFile(filepath.Join(envDir, "app.yaml"), []byte("imports:\n  - base\n"), 0o600))
+
+	names, err = b.ListEnvironments(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app", "base"}, names)
+
+	// Environments are stored in the directory rather than in the state bucket.
+	assert.FileExists(t, filepath.Join(envDir, "base.yaml"))
+	assert.NoFileExists(t, filepath.Join(stateDir, EnvironmentsDir, "base.yaml"))
+
+	environment, diags, err := b.OpenEnvironment(ctx, "yaml", []byte("imports:\n  - app\n"), nil)
+	require.NoError(t, err)
+	require.Empty(t, diags)
+	assert.Equal(t, "us-west-2", environment.Properties["region"].Value)
+
+	require.NoError(t, b.DeleteEnvironment(ctx, "base"))
+	assert.NoFileExists(t, filepath.Join(envDir, "base.yaml"))
+}
+
+func TestOpenEnvironmentDiagnostics(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	b, err := New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(tmpDir), nil)
+	require.NoError(t, err)
+
+	_, diags, err := b.OpenEnvironment(ctx, "yaml", []byte("imports:\n  - missing\n"), nil)
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Summary, "missing")
+
+	_, diags, err = b.OpenEnvironment(ctx, "yaml", []byte(`values:
+  creds:
+    fn::open:
+      provider: aws-login
+      inputs: {}
+`), nil)
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Summary, "providers are not supported by self-managed backends")
+}
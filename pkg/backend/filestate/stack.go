@@ -103,8 +103,10 @@ func (s *localStack) Destroy(ctx context.Context, op backend.UpdateOperation) (d
 	return backend.DestroyStack(ctx, s, op)
 }
 
-func (s *localStack) Watch(ctx context.Context, op backend.UpdateOperation, paths []string) result.Result {
-	return backend.WatchStack(ctx, s, op, paths)
+func (s *localStack) Watch(ctx context.Context, op backend.UpdateOperation,
+	opts backend.WatchOptions,
+) result.Result {
+	return backend.WatchStack(ctx, s, op, opts)
 }
 
 func (s *localStack) GetLogs(ctx context.Context, secretsProvider secrets.Provider, cfg backend.StackConfiguration,
@@ -103,6 +103,8 @@ func (b *localBackend) newUpdate(
 	if err != nil {
 		return nil, err
 	}
+	target.IgnoreChanges = op.StackConfiguration.IgnoreChanges
+	target.Parallelism = op.StackConfiguration.Parallelism
 
 	// Construct and return a new update.
 	return &update{
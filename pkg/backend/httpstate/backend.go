@@ -1001,6 +1001,10 @@ func (b *cloudBackend) RenameStack(ctx context.Context, stack backend.Stack,
 	}
 
 	if err = b.client.RenameStack(ctx, stackID, newIdentity); err != nil {
+		if errResp, ok := err.(*apitype.ErrorResponse); ok && errResp.Code == http.StatusConflict &&
+			strings.Contains(errResp.Message, "already exists") {
+			return nil, &backend.StackAlreadyExistsError{StackName: newIdentity.String()}
+		}
 		return nil, err
 	}
 	return newRef, nil
@@ -1044,9 +1048,9 @@ func (b *cloudBackend) Destroy(ctx context.Context, stack backend.Stack,
 }
 
 func (b *cloudBackend) Watch(ctx context.Context, stk backend.Stack,
-	op backend.UpdateOperation, paths []string,
+	op backend.UpdateOperation, opts backend.WatchOptions,
 ) result.Result {
-	return backend.Watch(ctx, b, stk, op, b.apply, paths)
+	return backend.Watch(ctx, b, stk, op, b.apply, opts)
 }
 
 func (b *cloudBackend) Query(ctx context.Context, op backend.QueryOperation) error {
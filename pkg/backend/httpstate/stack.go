@@ -186,8 +186,10 @@ func (s *cloudStack) Destroy(ctx context.Context, op backend.UpdateOperation) (s
 	return backend.DestroyStack(ctx, s, op)
 }
 
-func (s *cloudStack) Watch(ctx context.Context, op backend.UpdateOperation, paths []string) result.Result {
-	return backend.WatchStack(ctx, s, op, paths)
+func (s *cloudStack) Watch(ctx context.Context, op backend.UpdateOperation,
+	opts backend.WatchOptions,
+) result.Result {
+	return backend.WatchStack(ctx, s, op, opts)
 }
 
 func (s *cloudStack) GetLogs(ctx context.Context, secretsProvider secrets.Provider, cfg backend.StackConfiguration,
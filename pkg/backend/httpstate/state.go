@@ -197,6 +197,8 @@ func (b *cloudBackend) newUpdate(ctx context.Context, stackRef backend.StackRefe
 	if err != nil {
 		return nil, err
 	}
+	target.IgnoreChanges = op.StackConfiguration.IgnoreChanges
+	target.Parallelism = op.StackConfiguration.Parallelism
 
 	// Construct and return a new update.
 	return &cloudUpdate{
@@ -358,6 +360,10 @@ func persistEngineEvents(
 			if isDebugDiagEvent(e) && !persistDebugEvents {
 				break
 			}
+			// Progress and waiting events are ephemeral, so aren't persisted.
+			if e.Type == engine.ProgressEvent || e.Type == engine.ResourceWaitingEvent {
+				break
+			}
 
 			// Stop processing once we see the CancelEvent.
 			if e.Type == engine.CancelEvent {
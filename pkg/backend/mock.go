@@ -71,7 +71,7 @@ type MockBackend struct {
 	DestroyF func(context.Context, Stack,
 		UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
 	WatchF func(context.Context, Stack,
-		UpdateOperation, []string) result.Result
+		UpdateOperation, WatchOptions) result.Result
 	GetLogsF func(context.Context, secrets.Provider, Stack, StackConfiguration,
 		operations.LogQuery) ([]operations.LogEntry, error)
 
@@ -272,10 +272,10 @@ func (be *MockBackend) Destroy(ctx context.Context, stack Stack,
 }
 
 func (be *MockBackend) Watch(ctx context.Context, stack Stack,
-	op UpdateOperation, paths []string,
+	op UpdateOperation, opts WatchOptions,
 ) result.Result {
 	if be.WatchF != nil {
-		return be.WatchF(ctx, stack, op, paths)
+		return be.WatchF(ctx, stack, op, opts)
 	}
 	panic("not implemented")
 }
@@ -399,7 +399,7 @@ type MockStack struct {
 		imports []deploy.Import) (sdkDisplay.ResourceChanges, result.Result)
 	RefreshF func(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
 	DestroyF func(ctx context.Context, op UpdateOperation) (sdkDisplay.ResourceChanges, result.Result)
-	WatchF   func(ctx context.Context, op UpdateOperation, paths []string) result.Result
+	WatchF   func(ctx context.Context, op UpdateOperation, opts WatchOptions) result.Result
 	QueryF   func(ctx context.Context, op UpdateOperation) result.Result
 	RemoveF  func(ctx context.Context, force bool) (bool, error)
 	RenameF  func(ctx context.Context, newName tokens.QName) (StackReference, error)
@@ -494,9 +494,9 @@ func (ms *MockStack) Destroy(ctx context.Context, op UpdateOperation) (sdkDispla
 	panic("not implemented")
 }
 
-func (ms *MockStack) Watch(ctx context.Context, op UpdateOperation, paths []string) result.Result {
+func (ms *MockStack) Watch(ctx context.Context, op UpdateOperation, opts WatchOptions) result.Result {
 	if ms.WatchF != nil {
-		return ms.WatchF(ctx, op, paths)
+		return ms.WatchF(ctx, op, opts)
 	}
 	panic("not implemented")
 }
@@ -213,6 +213,12 @@ func (ssm *sameSnapshotMutation) mustWrite(step *deploy.SameStep) bool {
 		return true
 	}
 
+	// We need to persist the changes if the taint marker has been cleared
+	if old.Taint != new.Taint {
+		logging.V(9).Infof("SnapshotManager: mustWrite() true because of Taint")
+		return true
+	}
+
 	contract.Assertf(old.ID == new.ID,
 		"old and new resource IDs must be equal, got %v (old) != %v (new)", old.ID, new.ID)
 
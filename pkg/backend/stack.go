@@ -52,7 +52,7 @@ type Stack interface {
 	// Destroy this stack's resources.
 	Destroy(ctx context.Context, op UpdateOperation) (display.ResourceChanges, result.Result)
 	// Watch this stack.
-	Watch(ctx context.Context, op UpdateOperation, paths []string) result.Result
+	Watch(ctx context.Context, op UpdateOperation, opts WatchOptions) result.Result
 
 	// remove this stack.
 	Remove(ctx context.Context, force bool) (bool, error)
@@ -113,8 +113,8 @@ func DestroyStack(ctx context.Context, s Stack, op UpdateOperation) (display.Res
 
 // WatchStack watches the projects working directory for changes and automatically updates the
 // active stack.
-func WatchStack(ctx context.Context, s Stack, op UpdateOperation, paths []string) result.Result {
-	return s.Backend().Watch(ctx, s, op, paths)
+func WatchStack(ctx context.Context, s Stack, op UpdateOperation, opts WatchOptions) result.Result {
+	return s.Backend().Watch(ctx, s, op, opts)
 }
 
 // GetLatestConfiguration returns the configuration for the most recent deployment of the stack.
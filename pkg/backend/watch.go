@@ -27,53 +27,46 @@ import (
 	"time"
 
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/operations"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
 )
 
+// WatchOptions controls the behavior of watch mode.
+type WatchOptions struct {
+	// Paths are the paths to watch for changes. Relative paths are relative to the project's root directory.
+	Paths []string
+	// Debounce is the amount of time to wait for a burst of changes to settle before updating.
+	Debounce time.Duration
+	// FullUpdates disables selective updates: every change triggers an update of the entire stack rather than a
+	// targeted update of the resources that changed and their dependents.
+	FullUpdates bool
+	// ShowLogs enables tailing the logs of the stack's resources alongside update progress.
+	ShowLogs bool
+}
+
 // Watch watches the project's working directory for changes and automatically updates the active
 // stack.
+//
+// Unless full updates are requested, each batch of changes first runs a preview to determine which resources have
+// changed since the last successful update. Only those resources and their dependents are then updated. Failed
+// previews and updates are reported, and watching continues until the context is canceled.
 func Watch(ctx context.Context, b Backend, stack Stack, op UpdateOperation,
-	apply Applier, paths []string,
+	apply Applier, opts WatchOptions,
 ) result.Result {
-	opts := ApplierOptions{
-		DryRun:   false,
-		ShowLink: false,
+	if opts.ShowLogs {
+		go tailLogs(ctx, b, stack, op)
 	}
 
-	startTime := time.Now()
-
-	go func() {
-		shown := map[operations.LogEntry]bool{}
-		for {
-			logs, err := b.GetLogs(ctx, op.SecretsProvider, stack, op.StackConfiguration, operations.LogQuery{
-				StartTime: &startTime,
-			})
-			if err != nil {
-				logging.V(5).Infof("failed to get logs: %v", err.Error())
-			}
-
-			for _, logEntry := range logs {
-				if _, shownAlready := shown[logEntry]; !shownAlready {
-					eventTime := time.Unix(0, logEntry.Timestamp*1000000)
-
-					message := strings.TrimRight(logEntry.Message, "\n")
-					display.PrintfWithWatchPrefix(eventTime, logEntry.ID, "%s\n", message)
-
-					shown[logEntry] = true
-				}
-			}
-			time.Sleep(10 * time.Second)
-		}
-	}()
-
 	// Provided paths can be both relative and absolute.
-	events, stop, err := watchPaths(op.Root, paths)
+	events, stop, err := watchPaths(op.Root, opts.Paths)
 	if err != nil {
 		return result.FromError(err)
 	}
@@ -82,28 +75,155 @@ func Watch(ctx context.Context, b Backend, stack Stack, op UpdateOperation,
 	fmt.Printf(op.Opts.Display.Color.Colorize(
 		colors.SpecHeadline+"Watching (%s):"+colors.Reset+"\n"), stack.Ref())
 
-	for range events {
+	printStatus := func(msg string) {
 		display.PrintfWithWatchPrefix(time.Now(), "",
-			op.Opts.Display.Color.Colorize(colors.SpecImportant+"Updating..."+colors.Reset+"\n"))
+			op.Opts.Display.Color.Colorize(colors.SpecImportant+msg+colors.Reset+"\n"))
+	}
+
+	for range debounce(events, opts.Debounce) {
+		updateOp := op
+		if !opts.FullUpdates {
+			printStatus("Checking for changes...")
+
+			changed, res := changedResources(ctx, stack, op, apply)
+			if res != nil {
+				logging.V(5).Infof("watch preview failed: %v", res.Error())
+				if res.Error() == context.Canceled {
+					return res
+				}
+				printStatus("Preview failed.")
+				continue
+			}
+			if len(changed) == 0 {
+				printStatus("No changes.")
+				continue
+			}
+
+			updateOp.Opts.Engine.Targets = deploy.NewUrnTargetsFromUrns(changed)
+			updateOp.Opts.Engine.TargetDependents = true
+			printStatus(fmt.Sprintf("Updating %d changed resource(s) and their dependents...", len(changed)))
+		} else {
+			printStatus("Updating...")
+		}
 
 		// Perform the update operation
-		_, _, res := apply(ctx, apitype.UpdateUpdate, stack, op, opts, nil)
+		_, _, res := apply(ctx, apitype.UpdateUpdate, stack, updateOp, ApplierOptions{}, nil)
 		if res != nil {
 			logging.V(5).Infof("watch update failed: %v", res.Error())
 			if res.Error() == context.Canceled {
 				return res
 			}
-			display.PrintfWithWatchPrefix(time.Now(), "",
-				op.Opts.Display.Color.Colorize(colors.SpecImportant+"Update failed."+colors.Reset+"\n"))
+			printStatus("Update failed.")
 		} else {
-			display.PrintfWithWatchPrefix(time.Now(), "",
-				op.Opts.Display.Color.Colorize(colors.SpecImportant+"Update complete."+colors.Reset+"\n"))
+			printStatus("Update complete.")
 		}
 	}
 
 	return nil
 }
 
+// tailLogs periodically prints the logs of the stack's resources until the context is canceled.
+func tailLogs(ctx context.Context, b Backend, stack Stack, op UpdateOperation) {
+	startTime := time.Now()
+	shown := map[operations.LogEntry]bool{}
+	for {
+		logs, err := b.GetLogs(ctx, op.SecretsProvider, stack, op.StackConfiguration, operations.LogQuery{
+			StartTime: &startTime,
+		})
+		if err != nil {
+			logging.V(5).Infof("failed to get logs: %v", err.Error())
+		}
+
+		for _, logEntry := range logs {
+			if _, shownAlready := shown[logEntry]; !shownAlready {
+				eventTime := time.Unix(0, logEntry.Timestamp*1000000)
+
+				message := strings.TrimRight(logEntry.Message, "\n")
+				display.PrintfWithWatchPrefix(eventTime, logEntry.ID, "%s\n", message)
+
+				shown[logEntry] = true
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(10 * time.Second):
+		}
+	}
+}
+
+// changedResources runs a preview of the given update and returns the URNs of the resources that it would change.
+func changedResources(ctx context.Context, stack Stack, op UpdateOperation, apply Applier,
+) ([]resource.URN, result.Result) {
+	events := make(chan engine.Event)
+	done := make(chan []resource.URN)
+	go func() {
+		done <- collectChangedResources(events)
+	}()
+
+	_, _, res := apply(ctx, apitype.UpdateUpdate, stack, op, ApplierOptions{DryRun: true}, events)
+	close(events)
+	changed := <-done
+	if res != nil {
+		return nil, res
+	}
+	return changed, nil
+}
+
+// collectChangedResources returns the URNs of the resources that are created, updated, replaced, or deleted by the
+// steps described by the given events, in the order in which they are first seen.
+func collectChangedResources(events <-chan engine.Event) []resource.URN {
+	var changed []resource.URN
+	seen := map[resource.URN]bool{}
+	for e := range events {
+		if e.Type != engine.ResourcePreEvent {
+			continue
+		}
+		p := e.Payload().(engine.ResourcePreEventPayload)
+		switch p.Metadata.Op {
+		case deploy.OpSame, deploy.OpRead, deploy.OpReadDiscard, deploy.OpRefresh:
+			continue
+		}
+		if !seen[p.Metadata.URN] {
+			seen[p.Metadata.URN] = true
+			changed = append(changed, p.Metadata.URN)
+		}
+	}
+	return changed
+}
+
+// debounce coalesces bursts of events into batches. A batch is sent once no new events have been received for the
+// given interval. Events that arrive while the previous batch has not yet been received are added to the next batch.
+func debounce(events <-chan string, interval time.Duration) <-chan []string {
+	out := make(chan []string)
+	go func() {
+		defer close(out)
+
+		var pending []string
+		var quiet <-chan time.Time
+		var ready chan<- []string
+		for {
+			select {
+			case e, ok := <-events:
+				if !ok {
+					if len(pending) != 0 {
+						out <- pending
+					}
+					return
+				}
+				pending = append(pending, e)
+				quiet, ready = time.After(interval), nil
+			case <-quiet:
+				quiet, ready = nil, out
+			case ready <- pending:
+				pending, ready = nil, nil
+			}
+		}
+	}()
+	return out
+}
+
 func watchPaths(root string, paths []string) (chan string, func(), error) {
 	args := []string{"--origin", root}
 	for _, p := range paths {
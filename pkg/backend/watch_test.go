@@ -0,0 +1,68 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package backend
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestCollectChangedResources(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan engine.Event, 8)
+	events <- makeResourcePreEvent("same", "custom:resource:Type", deploy.OpSame, false)
+	events <- makeResourcePreEvent("update", "custom:resource:Type", deploy.OpUpdate, false)
+	events <- makeResourcePreEvent("read", "custom:resource:Type", deploy.OpRead, false)
+	events <- makeResourcePreEvent("replace", "custom:resource:Type", deploy.OpCreateReplacement, false)
+	events <- makeResourcePreEvent("replace", "custom:resource:Type", deploy.OpReplace, false)
+	events <- makeResourcePreEvent("delete", "custom:resource:Type", deploy.OpDelete, false)
+	events <- engine.NewEvent(engine.CancelEvent, nil)
+	close(events)
+
+	assert.Equal(t, []resource.URN{"update", "replace", "delete"}, collectChangedResources(events))
+}
+
+func TestDebounce(t *testing.T) {
+	t.Parallel()
+
+	events := make(chan string)
+	batches := debounce(events, 50*time.Millisecond)
+
+	// A burst of events is delivered as a single batch.
+	events <- "a"
+	events <- "b"
+	events <- "c"
+	assert.Equal(t, []string{"a", "b", "c"}, <-batches)
+
+	// Events that arrive while the consumer is busy are held for the next batch.
+	events <- "d"
+	time.Sleep(100 * time.Millisecond)
+	events <- "e"
+	assert.Equal(t, []string{"d", "e"}, <-batches)
+
+	// Pending events are flushed when the event stream is closed.
+	events <- "f"
+	close(events)
+	assert.Equal(t, []string{"f"}, <-batches)
+	_, ok := <-batches
+	assert.False(t, ok)
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/pulumi/esc"
 	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
@@ -1074,23 +1075,38 @@ func openStackEnv(
 	ctx context.Context,
 	stack backend.Stack,
 	workspaceStack *workspace.ProjectStack,
+	sm secrets.Manager, // optional
 ) (esc.Value, map[string]esc.Value, []apitype.EnvironmentDiagnostic, error) {
 	yaml := workspaceStack.EnvironmentBytes()
 	if len(yaml) == 0 {
 		return esc.Value{}, nil, nil, nil
 	}
 
-	envs, ok := stack.Backend().(backend.EnvironmentsBackend)
-	if !ok {
-		return esc.Value{}, nil, nil, fmt.Errorf("backend %v does not support environments", stack.Backend().Name())
-	}
-	orgNamer, ok := stack.(interface{ OrgName() string })
-	if !ok {
-		return esc.Value{}, nil, nil, fmt.Errorf("cannot determine organzation for stack %v", stack.Ref())
-	}
-	orgName := orgNamer.OrgName()
+	var env *esc.Environment
+	var diags []apitype.EnvironmentDiagnostic
+	var err error
+	if fb, ok := stack.Backend().(filestate.Backend); ok {
+		// Self-managed backends evaluate environments locally, decrypting any secrets with the stack's secrets
+		// manager.
+		env, diags, err = fb.OpenEnvironment(ctx, "yaml", yaml, func() (config.Decrypter, error) {
+			if sm == nil {
+				return nil, errors.New("the stack has no secrets manager")
+			}
+			return sm.Decrypter()
+		})
+	} else {
+		envs, ok := stack.Backend().(backend.EnvironmentsBackend)
+		if !ok {
+			return esc.Value{}, nil, nil, fmt.Errorf("backend %v does not support environments", stack.Backend().Name())
+		}
+		orgNamer, ok := stack.(interface{ OrgName() string })
+		if !ok {
+			return esc.Value{}, nil, nil, fmt.Errorf("cannot determine organzation for stack %v", stack.Ref())
+		}
+		orgName := orgNamer.OrgName()
 
-	env, diags, err := envs.OpenYAMLEnvironment(ctx, orgName, yaml, 2*time.Hour)
+		env, diags, err = envs.OpenYAMLEnvironment(ctx, orgName, yaml, 2*time.Hour)
+	}
 	if err != nil {
 		return esc.Value{}, nil, nil, err
 	}
@@ -1135,7 +1151,7 @@ func getStackConfigurationWithFallback(
 		}
 	}
 
-	pulumiEnv, envVars, diags, err := openStackEnv(ctx, stack, workspaceStack)
+	pulumiEnv, envVars, diags, err := openStackEnv(ctx, stack, workspaceStack, sm)
 	if err != nil {
 		return backend.StackConfiguration{}, nil, fmt.Errorf("opening environment: %w", err)
 	}
@@ -1173,9 +1189,11 @@ func getStackConfigurationWithFallback(
 	// the correct decrypter for the local backend would involve prompting for a passphrase)
 	if !needsCrypter(workspaceStack.Config, pulumiEnv) {
 		return backend.StackConfiguration{
-			Environment: pulumiEnv,
-			Config:      workspaceStack.Config,
-			Decrypter:   config.NewPanicCrypter(),
+			Environment:   pulumiEnv,
+			Config:        workspaceStack.Config,
+			Decrypter:     config.NewPanicCrypter(),
+			IgnoreChanges: workspaceStack.IgnoreChanges,
+			Parallelism:   workspaceStack.Parallelism,
 		}, sm, nil
 	}
 
@@ -1185,8 +1203,10 @@ func getStackConfigurationWithFallback(
 	}
 
 	return backend.StackConfiguration{
-		Environment: pulumiEnv,
-		Config:      workspaceStack.Config,
-		Decrypter:   crypter,
+		Environment:   pulumiEnv,
+		Config:        workspaceStack.Config,
+		Decrypter:     crypter,
+		IgnoreChanges: workspaceStack.IgnoreChanges,
+		Parallelism:   workspaceStack.Parallelism,
 	}, sm, nil
 }
@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"errors"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -26,9 +27,12 @@ import (
 
 	"github.com/pulumi/esc"
 	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
 	"github.com/pulumi/pulumi/pkg/v3/secrets"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -173,7 +177,7 @@ func TestOpenStackEnvNoEnv(t *testing.T) {
 	err := yaml.Unmarshal([]byte(""), &projectStack)
 	require.NoError(t, err)
 
-	_, _, _, err = openStackEnv(context.Background(), stack, &projectStack)
+	_, _, _, err = openStackEnv(context.Background(), stack, &projectStack, nil)
 	assert.NoError(t, err)
 }
 
@@ -187,7 +191,7 @@ func TestOpenStackEnvUnsupportedBackend(t *testing.T) {
 	err := yaml.Unmarshal([]byte("environment:\n  - test"), &projectStack)
 	require.NoError(t, err)
 
-	_, _, _, err = openStackEnv(context.Background(), stack, &projectStack)
+	_, _, _, err = openStackEnv(context.Background(), stack, &projectStack, nil)
 	assert.Error(t, err)
 }
 
@@ -228,7 +232,7 @@ func TestOpenStackEnv(t *testing.T) {
 	err := yaml.Unmarshal([]byte("environment:\n  - test"), &projectStack)
 	require.NoError(t, err)
 
-	pulumiEnv, envVars, diags, err := openStackEnv(context.Background(), stack, &projectStack)
+	pulumiEnv, envVars, diags, err := openStackEnv(context.Background(), stack, &projectStack, nil)
 	require.NoError(t, err)
 	assert.Len(t, diags, 0)
 	assert.Equal(t, env["pulumiConfig"], pulumiEnv)
@@ -260,7 +264,7 @@ func TestOpenStackEnvDiags(t *testing.T) {
 	err := yaml.Unmarshal([]byte("environment:\n  - test"), &projectStack)
 	require.NoError(t, err)
 
-	_, _, diags, err := openStackEnv(context.Background(), stack, &projectStack)
+	_, _, diags, err := openStackEnv(context.Background(), stack, &projectStack, nil)
 	require.NoError(t, err)
 	assert.Len(t, diags, 1)
 }
@@ -290,6 +294,46 @@ func TestOpenStackEnvError(t *testing.T) {
 	err := yaml.Unmarshal([]byte("environment:\n  - test"), &projectStack)
 	require.NoError(t, err)
 
-	_, _, _, err = openStackEnv(context.Background(), stack, &projectStack)
+	_, _, _, err = openStackEnv(context.Background(), stack, &projectStack, nil)
 	assert.Error(t, err)
 }
+
+func TestOpenStackEnvFilestate(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	be, err := filestate.New(ctx, diagtest.LogSink(t), "file://"+filepath.ToSlash(t.TempDir()), nil)
+	require.NoError(t, err)
+
+	sm := b64.NewBase64SecretsManager()
+	enc, err := sm.Encrypter()
+	require.NoError(t, err)
+	ciphertext, err := enc.EncryptValue(ctx, "hunter2")
+	require.NoError(t, err)
+	require.NoError(t, be.UpdateEnvironment(ctx, "test", []byte(`values:
+  pulumiConfig:
+    test:string: esc
+  environmentVariables:
+    TEST_VAR:
+      fn::secret: {ciphertext: `+ciphertext+`}
+`)))
+
+	stack := &backend.MockStack{BackendF: func() backend.Backend { return be }}
+
+	var projectStack workspace.ProjectStack
+	err = yaml.Unmarshal([]byte("environment:\n  - test"), &projectStack)
+	require.NoError(t, err)
+
+	pulumiEnv, envVars, diags, err := openStackEnv(ctx, stack, &projectStack, sm)
+	require.NoError(t, err)
+	assert.Len(t, diags, 0)
+	assert.Equal(t, "esc", pulumiEnv.Value.(map[string]esc.Value)["test:string"].Value)
+	assert.Equal(t, "hunter2", envVars["TEST_VAR"].Value)
+	assert.True(t, envVars["TEST_VAR"].Secret)
+
+	// Secrets can't be decrypted without a secrets manager.
+	_, _, diags, err = openStackEnv(ctx, stack, &projectStack, nil)
+	require.NoError(t, err)
+	require.Len(t, diags, 1)
+	assert.Contains(t, diags[0].Summary, "no secrets manager")
+}
@@ -334,8 +334,9 @@ func runConvert(
 		}
 
 		pluginSpec := workspace.PluginSpec{
-			Name: string(provider),
-			Kind: workspace.ResourcePlugin,
+			Name:   string(provider),
+			Kind:   workspace.ResourcePlugin,
+			Mirror: pCtx.PluginMirror,
 		}
 		version, err := pkgWorkspace.InstallPlugin(pluginSpec, log)
 		if err != nil {
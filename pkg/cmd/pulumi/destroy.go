@@ -61,10 +61,12 @@ func newDestroyCmd() *cobra.Command {
 	var showReplacementSteps bool
 	var showSames bool
 	var skipPreview bool
+	var previewOnly bool
 	var suppressOutputs bool
 	var suppressPermalink string
 	var yes bool
 	var targets *[]string
+	var locked bool
 	var targetDependents bool
 	var excludeProtected bool
 
@@ -92,12 +94,24 @@ func newDestroyCmd() *cobra.Command {
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			ctx := commandContext()
 
+			if previewOnly {
+				if skipPreview {
+					return result.FromError(errors.New("--preview-only and --skip-preview cannot be used together"))
+				}
+				if remove {
+					return result.FromError(errors.New("--preview-only and --remove cannot be used together"))
+				}
+				if remoteArgs.remote {
+					return result.FromError(errors.New("--preview-only is not supported for remote operations"))
+				}
+			}
+
 			// Remote implies we're skipping previews.
 			if remoteArgs.remote {
 				skipPreview = true
 			}
 
-			yes = yes || skipPreview || skipConfirmations()
+			yes = yes || skipPreview || previewOnly || skipConfirmations()
 			interactive := cmdutil.Interactive()
 			if !interactive && !yes {
 				return result.FromError(
@@ -108,6 +122,7 @@ func newDestroyCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			opts.PreviewOnly = previewOnly
 
 			displayType := display.DisplayProgress
 			if diffDisplay {
@@ -272,6 +287,16 @@ func newDestroyCmd() *cobra.Command {
 				DisableResourceReferences: disableResourceReferences(),
 				DisableOutputValues:       disableOutputValues(),
 				Experimental:              hasExperimentalCommands(),
+				Locked:                    locked,
+			}
+
+			// Plugins are checked against the lock, which is only updated by `pulumi up` and `pulumi plugin install`.
+			if root != "" {
+				if opts.Engine.PluginLock, err = loadPluginLock(root, locked); err != nil {
+					return result.FromError(err)
+				}
+			} else if locked {
+				return result.FromError(errors.New("--locked requires a project"))
 			}
 
 			_, res := s.Destroy(ctx, backend.UpdateOperation{
@@ -285,6 +310,10 @@ func newDestroyCmd() *cobra.Command {
 				Scopes:             backend.CancellationScopes,
 			})
 
+			if previewOnly {
+				return PrintEngineResult(res)
+			}
+
 			if res == nil && protectedCount > 0 && !jsonDisplay {
 				fmt.Printf("All unprotected resources were destroyed. There are still %d protected resources"+
 					" associated with this stack.\n", protectedCount)
@@ -341,6 +370,9 @@ func newDestroyCmd() *cobra.Command {
 		"Allows destroying of dependent targets discovered but not specified in --target list")
 	cmd.PersistentFlags().BoolVar(&excludeProtected, "exclude-protected", false, "Do not destroy protected resources."+
 		" Destroy all other resources.")
+	cmd.PersistentFlags().BoolVar(
+		&locked, "locked", false,
+		"Fail if a plugin is missing from, or differs from, the project's Pulumi.lock file")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().BoolVar(
@@ -368,6 +400,9 @@ func newDestroyCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(
 		&skipPreview, "skip-preview", "f", false,
 		"Do not calculate a preview before performing the destroy")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview-only", false,
+		"Only show a preview of the destroy, but don't perform the destroy itself")
 	cmd.PersistentFlags().BoolVar(
 		&suppressOutputs, "suppress-outputs", false,
 		"Suppress display of stack outputs (in case they contain sensitive values)")
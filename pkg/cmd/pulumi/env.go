@@ -35,6 +35,9 @@ func newEnvCmd() *cobra.Command {
 
 	// Add the `env` command to the root.
 	envCommand := escCLI.Commands()[0]
+
+	// Self-managed backends store and evaluate environments locally.
+	addLocalEnvCommands(envCommand)
 	return envCommand
 }
 
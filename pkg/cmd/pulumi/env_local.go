@@ -0,0 +1,436 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// localEnvCommands holds the implementations of the `env` subcommands for self-managed backends, which store
+// environments themselves and evaluate them locally.
+var localEnvCommands = map[string]func(cmd *cobra.Command, args []string) error{
+	"ls":   runLocalEnvLs,
+	"init": runLocalEnvInit,
+	"get":  runLocalEnvGet,
+	"set":  runLocalEnvSet,
+	"open": runLocalEnvOpen,
+	"rm":   runLocalEnvRm,
+}
+
+// addLocalEnvCommands makes the subcommands of the given `env` command use their local implementations when the
+// current backend is a self-managed backend. The backend is only checked when a subcommand runs, so that building the
+// command tree does not read the project or the stored credentials.
+func addLocalEnvCommands(envCmd *cobra.Command) {
+	envCmd.Long += "\n" +
+		"\n" +
+		"Environments in self-managed backends are stored alongside the state, or in the directory named by\n" +
+		"PULUMI_SELF_MANAGED_ENVIRONMENTS_DIR, and are evaluated locally. Secrets are encrypted using the secrets\n" +
+		"manager of a stack, and providers (i.e. `fn::open`) are not supported."
+
+	for _, sub := range envCmd.Commands() {
+		name, runE, local := sub.Name(), sub.RunE, localEnvCommands[sub.Name()]
+		sub.RunE = func(cmd *cobra.Command, args []string) error {
+			isFilestate, err := isFilestateBackend(display.Options{})
+			if err != nil {
+				return err
+			}
+			if !isFilestate {
+				return runE(cmd, args)
+			}
+			if local == nil {
+				return fmt.Errorf("`pulumi env %s` is not supported by self-managed backends", name)
+			}
+			return local(cmd, args)
+		}
+
+		switch name {
+		case "set":
+			sub.Flags().StringP("stack", "s", "",
+				"The name of the stack whose secrets manager encrypts secrets in self-managed backends. "+
+					"Defaults to the current stack")
+		case "open":
+			sub.Flags().StringP("stack", "s", "",
+				"The name of the stack whose secrets manager decrypts secrets in self-managed backends. "+
+					"Defaults to the current stack")
+		}
+	}
+}
+
+// localEnvArgs returns the name of the environment to operate on, which is given either by the `--env` flag or by
+// the first argument, and the remaining arguments. It is an error for more than maxArgs arguments to remain.
+func localEnvArgs(cmd *cobra.Command, args []string, maxArgs int) (string, []string, error) {
+	name, err := cmd.Flags().GetString("env")
+	if err != nil {
+		return "", nil, err
+	}
+	if name == "" {
+		if len(args) == 0 {
+			return "", nil, errors.New("no environment name specified")
+		}
+		name, args = args[0], args[1:]
+	}
+	if len(args) > maxArgs {
+		return "", nil, fmt.Errorf("`pulumi env %s` does not support property paths with self-managed backends",
+			cmd.Name())
+	}
+	return name, args, nil
+}
+
+// currentLocalEnvBackend returns the current backend, which must be a self-managed backend.
+func currentLocalEnvBackend(ctx context.Context) (filestate.Backend, error) {
+	project, _, err := readProject()
+	if err != nil && !errors.Is(err, workspace.ErrProjectNotFound) {
+		return nil, err
+	}
+	b, err := currentBackend(ctx, project, display.Options{Color: cmdutil.GetGlobalColorization()})
+	if err != nil {
+		return nil, err
+	}
+	fb, ok := b.(filestate.Backend)
+	if !ok {
+		return nil, fmt.Errorf("backend %v does not support local environments", b.Name())
+	}
+	return fb, nil
+}
+
+// localEnvDecrypter returns a function that lazily loads the decrypter of the given stack.
+func localEnvDecrypter(ctx context.Context, stackName string) filestate.EnvironmentDecrypterFunc {
+	return func() (config.Decrypter, error) {
+		project, _, err := readProject()
+		if err != nil {
+			return nil, err
+		}
+		s, err := requireStack(ctx, stackName, stackLoadOnly, display.Options{Color: cmdutil.GetGlobalColorization()})
+		if err != nil {
+			return nil, err
+		}
+		ps, err := loadProjectStack(project, s)
+		if err != nil {
+			return nil, err
+		}
+		dec, needsSave, err := getStackDecrypter(s, ps)
+		if err != nil {
+			return nil, err
+		}
+		if needsSave {
+			if err := saveProjectStack(s, ps); err != nil {
+				return nil, err
+			}
+		}
+		return dec, nil
+	}
+}
+
+func runLocalEnvLs(cmd *cobra.Command, args []string) error {
+	if org, err := cmd.Flags().GetString("organization"); err != nil {
+		return err
+	} else if org != "" {
+		return errors.New("self-managed backends do not support organizations")
+	}
+
+	ctx := commandContext()
+	b, err := currentLocalEnvBackend(ctx)
+	if err != nil {
+		return err
+	}
+	names, err := b.ListEnvironments(ctx)
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		fmt.Fprintln(cmd.OutOrStdout(), name)
+	}
+	return nil
+}
+
+func runLocalEnvInit(cmd *cobra.Command, args []string) error {
+	name, _, err := localEnvArgs(cmd, args, 0)
+	if err != nil {
+		return err
+	}
+	file, err := cmd.Flags().GetString("file")
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext()
+	b, err := currentLocalEnvBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := b.GetEnvironment(ctx, name); err == nil {
+		return fmt.Errorf("environment %q already exists", name)
+	} else if !errors.Is(err, filestate.ErrEnvironmentNotFound) {
+		return err
+	}
+
+	definition := []byte("values: {}\n")
+	if file != "" {
+		if file == "-" {
+			definition, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			definition, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if err := b.UpdateEnvironment(ctx, name, definition); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Environment %q created.\n", name)
+	return nil
+}
+
+// runLocalEnvGet prints an environment's definition. Secrets are printed in their encrypted form.
+func runLocalEnvGet(cmd *cobra.Command, args []string) error {
+	name, _, err := localEnvArgs(cmd, args, 0)
+	if err != nil {
+		return err
+	}
+	if value, err := cmd.Flags().GetString("value"); err != nil {
+		return err
+	} else if value != "" {
+		return errors.New("self-managed backends do not support --value")
+	}
+
+	ctx := commandContext()
+	b, err := currentLocalEnvBackend(ctx)
+	if err != nil {
+		return err
+	}
+	definition, err := b.GetEnvironment(ctx, name)
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(definition)
+	return err
+}
+
+// runLocalEnvSet sets a value at a dot-separated path under an environment's `values`, e.g.
+// `pulumiConfig.aws:region`. Secrets are encrypted using the secrets manager of the stack given by --stack.
+func runLocalEnvSet(cmd *cobra.Command, args []string) error {
+	name, args, err := localEnvArgs(cmd, args, 2)
+	if err != nil {
+		return err
+	}
+	if len(args) != 2 {
+		return errors.New("expected a path and a value")
+	}
+	path, value := args[0], args[1]
+
+	secret, err := cmd.Flags().GetBool("secret")
+	if err != nil {
+		return err
+	}
+	stackName, err := cmd.Flags().GetString("stack")
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext()
+	b, err := currentLocalEnvBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	definition, err := b.GetEnvironment(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	node := &yaml.Node{Kind: yaml.ScalarNode, Value: value}
+	if secret {
+		project, _, err := readProject()
+		if err != nil {
+			return err
+		}
+		s, err := requireStack(ctx, stackName, stackLoadOnly, display.Options{Color: cmdutil.GetGlobalColorization()})
+		if err != nil {
+			return err
+		}
+		ps, err := loadProjectStack(project, s)
+		if err != nil {
+			return err
+		}
+		enc, needsSave, err := getStackEncrypter(s, ps)
+		if err != nil {
+			return err
+		}
+		if needsSave {
+			if err := saveProjectStack(s, ps); err != nil {
+				return err
+			}
+		}
+		ciphertext, err := enc.EncryptValue(ctx, value)
+		if err != nil {
+			return err
+		}
+		node = filestate.EncryptedSecret(ciphertext)
+	}
+
+	definition, err = setEnvironmentValue(definition, path, node)
+	if err != nil {
+		return err
+	}
+	return b.UpdateEnvironment(ctx, name, definition)
+}
+
+// runLocalEnvOpen evaluates an environment and prints its values as JSON. Secrets are decrypted using the secrets
+// manager of the stack given by --stack.
+func runLocalEnvOpen(cmd *cobra.Command, args []string) error {
+	name, _, err := localEnvArgs(cmd, args, 0)
+	if err != nil {
+		return err
+	}
+	if format, err := cmd.Flags().GetString("format"); err != nil {
+		return err
+	} else if format != "json" {
+		return fmt.Errorf("self-managed backends only support the json format, not %q", format)
+	}
+	stackName, err := cmd.Flags().GetString("stack")
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext()
+	b, err := currentLocalEnvBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	definition, err := b.GetEnvironment(ctx, name)
+	if err != nil {
+		return err
+	}
+	env, diags, err := b.OpenEnvironment(ctx, name, definition, localEnvDecrypter(ctx, stackName))
+	if err != nil {
+		return err
+	}
+	if len(diags) != 0 {
+		for _, d := range diags {
+			if d.Range != nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "%v:%v:%v: ", d.Range.Environment, d.Range.Begin.Line, d.Range.Begin.Column)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "%v\n", d.Summary)
+		}
+		return fmt.Errorf("opening environment %q: too many errors", name)
+	}
+
+	values := map[string]any{}
+	for k, v := range env.Properties {
+		values[k] = v.ToJSON(false /* redact */)
+	}
+	return printJSON(values)
+}
+
+func runLocalEnvRm(cmd *cobra.Command, args []string) error {
+	name, _, err := localEnvArgs(cmd, args, 0)
+	if err != nil {
+		return err
+	}
+	yes, err := cmd.Flags().GetBool("yes")
+	if err != nil {
+		return err
+	}
+
+	ctx := commandContext()
+	b, err := currentLocalEnvBackend(ctx)
+	if err != nil {
+		return err
+	}
+
+	yes = yes || skipConfirmations()
+	if !yes && !confirmPrompt(fmt.Sprintf("This will permanently remove the environment %q!", name), name,
+		display.Options{Color: cmdutil.GetGlobalColorization()}) {
+		return errors.New("confirmation declined")
+	}
+
+	if err := b.DeleteEnvironment(ctx, name); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "Environment %q removed.\n", name)
+	return nil
+}
+
+// setEnvironmentValue sets the value at the given dot-separated path under the `values` key of the given environment
+// definition, creating any missing maps along the way.
+func setEnvironmentValue(definition []byte, path string, value *yaml.Node) ([]byte, error) {
+	keys := strings.Split(path, ".")
+	for _, k := range keys {
+		if k == "" {
+			return nil, fmt.Errorf("invalid path %q", path)
+		}
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(definition, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) != 1 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, errors.New("the environment's definition must be a map")
+	}
+
+	node, fullPath := doc.Content[0], append([]string{"values"}, keys...)
+	for i, key := range fullPath {
+		last := i == len(keys)
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("cannot set %q: %q is not a map", path, strings.Join(fullPath[:i], "."))
+		}
+
+		var child *yaml.Node
+		for j := 0; j+1 < len(node.Content); j += 2 {
+			if node.Content[j].Value == key {
+				if last {
+					node.Content[j+1] = value
+				}
+				child = node.Content[j+1]
+				break
+			}
+		}
+		if child == nil {
+			child = value
+			if !last {
+				child = &yaml.Node{Kind: yaml.MappingNode}
+			}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, child)
+		}
+		if !last && child.Kind == yaml.MappingNode {
+			// Expand maps written in flow style (e.g. `values: {}`) so that new entries are written in block style.
+			child.Style = 0
+		}
+		node = child
+	}
+
+	return yaml.Marshal(&doc)
+}
@@ -0,0 +1,109 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/filestate"
+)
+
+func TestSetEnvironmentValue(t *testing.T) {
+	t.Parallel()
+
+	scalar := func(v string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Value: v}
+	}
+
+	definition, err := setEnvironmentValue([]byte("values: {}\n"), "pulumiConfig.aws:region", scalar("us-west-2"))
+	require.NoError(t, err)
+	assert.Equal(t, "values:\n    pulumiConfig:\n        aws:region: us-west-2\n", string(definition))
+
+	definition, err = setEnvironmentValue(definition, "pulumiConfig.aws:region", scalar("us-east-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "values:\n    pulumiConfig:\n        aws:region: us-east-1\n", string(definition))
+
+	definition, err = setEnvironmentValue(definition, "password", filestate.EncryptedSecret("Y2lwaGVy"))
+	require.NoError(t, err)
+	assert.Equal(t, "values:\n"+
+		"    pulumiConfig:\n"+
+		"        aws:region: us-east-1\n"+
+		"    password:\n"+
+		"        fn::secret: {ciphertext: Y2lwaGVy}\n", string(definition))
+
+	// Imports and other top-level keys are preserved.
+	definition, err = setEnvironmentValue([]byte("imports:\n  - base\n"), "foo", scalar("bar"))
+	require.NoError(t, err)
+	assert.Equal(t, "imports:\n    - base\nvalues:\n    foo: bar\n", string(definition))
+
+	_, err = setEnvironmentValue([]byte("values:\n  foo: bar\n"), "foo.baz", scalar("qux"))
+	assert.ErrorContains(t, err, `"values.foo" is not a map`)
+
+	_, err = setEnvironmentValue([]byte("values: {}\n"), "foo..baz", scalar("qux"))
+	assert.ErrorContains(t, err, "invalid path")
+}
+
+//nolint:paralleltest // changes environment variables and the working directory
+func TestLocalEnvCommands(t *testing.T) {
+	stateDir := t.TempDir()
+	t.Setenv("PULUMI_HOME", t.TempDir())
+	t.Setenv("PULUMI_BACKEND_URL", "file://"+filepath.ToSlash(stateDir))
+	t.Setenv("PULUMI_SKIP_CONFIRMATIONS", "true")
+	chdir(t, t.TempDir())
+
+	run := func(args ...string) (string, error) {
+		cmd := &cobra.Command{Use: "pulumi"}
+		cmd.AddCommand(newEnvCmd())
+		var stdout bytes.Buffer
+		cmd.SetOut(&stdout)
+		cmd.SetArgs(append([]string{"env"}, args...))
+		err := cmd.Execute()
+		return stdout.String(), err
+	}
+
+	_, err := run("init", "base")
+	require.NoError(t, err)
+	_, err = run("set", "base", "region", "us-west-2")
+	require.NoError(t, err)
+	_, err = run("init", "--env", "app")
+	require.NoError(t, err)
+
+	out, err := run("ls")
+	require.NoError(t, err)
+	assert.Equal(t, "app\nbase\n", out)
+
+	out, err = run("get", "base")
+	require.NoError(t, err)
+	assert.Equal(t, "values:\n    region: us-west-2\n", out)
+	assert.FileExists(t, filepath.Join(stateDir, filestate.EnvironmentsDir, "base.yaml"))
+
+	_, err = run("get", "base", "region")
+	assert.ErrorContains(t, err, "does not support property paths")
+	_, err = run("edit", "base")
+	assert.ErrorContains(t, err, "`pulumi env edit` is not supported by self-managed backends")
+
+	_, err = run("rm", "app")
+	require.NoError(t, err)
+	out, err = run("ls")
+	require.NoError(t, err)
+	assert.Equal(t, "base\n", out)
+}
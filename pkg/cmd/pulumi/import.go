@@ -409,7 +409,7 @@ func generateImportedDefinitions(ctx *plugin.Context,
 	}
 
 	loader := schema.NewPluginLoader(ctx.Host)
-	return true, importer.GenerateLanguageDefinitions(out, loader, func(w io.Writer, p *pcl.Program) error {
+	return true, importer.GenerateLanguageDefinitionsWithReferences(out, loader, func(w io.Writer, p *pcl.Program) error {
 		files, _, err := programGenerator(p, loader)
 		if err != nil {
 			return err
@@ -424,7 +424,7 @@ func generateImportedDefinitions(ctx *plugin.Context,
 			return err
 		}
 		return nil
-	}, resources, names)
+	}, resources, snap.Resources, names)
 }
 
 func newImportCmd() *cobra.Command {
@@ -575,8 +575,9 @@ func newImportCmd() *cobra.Command {
 					}
 
 					pluginSpec := workspace.PluginSpec{
-						Name: string(provider),
-						Kind: workspace.ResourcePlugin,
+						Name:   string(provider),
+						Kind:   workspace.ResourcePlugin,
+						Mirror: pCtx.PluginMirror,
 					}
 					version, err := pkgWorkspace.InstallPlugin(pluginSpec, log)
 					if err != nil {
@@ -953,5 +954,7 @@ func newImportCmd() *cobra.Command {
 	// ignore err, only happens if flag does not exist
 	_ = cmd.PersistentFlags().MarkHidden("exec-agent")
 
+	cmd.AddCommand(newImportDiscoverCmd())
+
 	return cmd
 }
@@ -0,0 +1,321 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newImportDiscoverCmd() *cobra.Command {
+	var typ string
+	var providerName string
+	var filters []string
+	var outputFilePath string
+	var stackName string
+
+	cmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Discover existing resources that can be imported into a stack",
+		Long: "Discover existing resources that can be imported into a stack.\n" +
+			"\n" +
+			"This command asks a resource provider to list the existing resources of the given\n" +
+			"type and writes an import file that can be passed to `pulumi import --file`.\n" +
+			"Resources that are already managed by the stack are skipped. Not all providers\n" +
+			"support discovery.\n" +
+			"\n" +
+			"By default, the provider is configured using the stack's configuration, as it\n" +
+			"would be for the default provider. To use an explicit provider resource from the\n" +
+			"stack instead, pass its name and URN:\n" +
+			"\n" +
+			"    pulumi import discover --type 'aws:s3/bucket:Bucket' --provider 'admin=<urn>'\n" +
+			"\n" +
+			"Providers may accept filters that restrict the set of resources that are listed:\n" +
+			"\n" +
+			"    pulumi import discover --type 'aws:s3/bucket:Bucket' --filter region=us-west-2 -o import.json",
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
+			ctx := commandContext()
+
+			if typ == "" {
+				return result.Errorf("a resource type must be specified with --type")
+			}
+			filterMap, err := parseDiscoverFilters(filters)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return result.FromError(fmt.Errorf("get working directory: %w", err))
+			}
+			sink := cmdutil.Diag()
+			pCtx, err := plugin.NewContext(sink, sink, nil, nil, cwd, nil, true, nil)
+			if err != nil {
+				return result.FromError(fmt.Errorf("create plugin context: %w", err))
+			}
+			defer contract.IgnoreClose(pCtx)
+
+			proj, _, err := readProject()
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			snap, err := getCurrentDeploymentForStack(ctx, s)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			provider, providerName, providerURN, err := loadDiscoveryProvider(
+				ctx, pCtx, s, proj, snap, tokens.Type(typ), providerName)
+			if err != nil {
+				return result.FromError(err)
+			}
+
+			lister, ok := provider.(plugin.ResourceLister)
+			if !ok {
+				return result.Errorf("the %v provider does not support resource discovery", provider.Pkg())
+			}
+			listed, err := lister.ListResources(tokens.Type(typ), filterMap)
+			if err != nil {
+				if errors.Is(err, plugin.ErrNotYetImplemented) {
+					return result.Errorf("the %v provider does not support resource discovery", provider.Pkg())
+				}
+				return result.FromError(fmt.Errorf("list resources: %w", err))
+			}
+
+			f, skipped := makeDiscoveredImportFile(tokens.Type(typ), listed, snap, providerName, providerURN)
+			if skipped != 0 {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Skipped %d resource(s) that are already managed by the stack.\n", skipped)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Discovered %d resource(s) to import.\n", len(f.Resources))
+
+			output := cmd.OutOrStdout()
+			if outputFilePath != "" {
+				out, err := os.Create(outputFilePath)
+				if err != nil {
+					return result.Errorf("could not open output file: %v", err)
+				}
+				defer contract.IgnoreClose(out)
+				output = out
+			}
+
+			enc := json.NewEncoder(output)
+			enc.SetIndent("", "    ")
+			if err := enc.Encode(f); err != nil {
+				return result.FromError(err)
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().StringVar(
+		&typ, "type", "", "The type token of the resources to discover")
+	cmd.Flags().StringVar(
+		//nolint:lll
+		&providerName, "provider", "", "The name of an explicit provider resource in the stack to use for discovery. Defaults to the default provider configured from the stack's configuration")
+	cmd.Flags().StringArrayVar(
+		&filters, "filter", nil, "A provider-specific filter in the format key=value. May be specified multiple times")
+	cmd.Flags().StringVarP(
+		&outputFilePath, "out", "o", "", "The path to the import file to write. Defaults to stdout")
+	cmd.Flags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+
+	return cmd
+}
+
+// parseDiscoverFilters parses a list of key=value filters into a property map.
+func parseDiscoverFilters(filters []string) (resource.PropertyMap, error) {
+	result := resource.PropertyMap{}
+	for _, f := range filters {
+		key, value, ok := strings.Cut(f, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("filter '%s' must be of the form key=value", f)
+		}
+		result[resource.PropertyKey(key)] = resource.NewStringProperty(value)
+	}
+	return result, nil
+}
+
+// findDiscoveryProvider returns the provider resource for the given package with the given name in the snapshot. It
+// is an error if there is no such provider or if the name is ambiguous.
+func findDiscoveryProvider(snap *deploy.Snapshot, pkg tokens.Package, name string) (*resource.State, error) {
+	var state *resource.State
+	if snap != nil {
+		for _, r := range snap.Resources {
+			if r.Delete || !providers.IsProviderType(r.Type) || providers.GetProviderPackage(r.Type) != pkg ||
+				string(r.URN.Name()) != name {
+				continue
+			}
+			if state != nil {
+				return nil, fmt.Errorf("provider name '%s' is ambiguous: it matches both '%s' and '%s'",
+					name, state.URN, r.URN)
+			}
+			state = r
+		}
+	}
+	if state == nil {
+		return nil, fmt.Errorf("no %v provider named '%s' exists in the stack", pkg, name)
+	}
+	return state, nil
+}
+
+// loadDiscoveryProvider loads and configures the provider used to discover resources of the given type. If a provider
+// name is given, the provider is configured using the inputs of the provider resource with that name in the snapshot.
+// Otherwise, the provider is configured using the stack's configuration for the type's package.
+func loadDiscoveryProvider(ctx context.Context, pCtx *plugin.Context, s backend.Stack, proj *workspace.Project,
+	snap *deploy.Snapshot, typ tokens.Type, providerName string,
+) (plugin.Provider, string, resource.URN, error) {
+	pkg := typ.Package()
+
+	var inputs resource.PropertyMap
+	var name string
+	var urn resource.URN
+	if providerName != "" {
+		state, err := findDiscoveryProvider(snap, pkg, providerName)
+		if err != nil {
+			return nil, "", "", err
+		}
+		name, urn, inputs = providerName, state.URN, state.Inputs
+	} else {
+		cfg, _, err := getStackConfiguration(ctx, s, proj, nil)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("getting stack configuration: %w", err)
+		}
+		target := &deploy.Target{Config: cfg.Config, Decrypter: cfg.Decrypter}
+		if inputs, err = target.GetPackageConfig(pkg); err != nil {
+			return nil, "", "", fmt.Errorf("getting configuration for package %v: %w", pkg, err)
+		}
+		urn = resource.NewURN(s.Ref().Name().Q(), proj.Name, "", providers.MakeProviderType(pkg), "default")
+	}
+
+	version, err := providers.GetProviderVersion(inputs)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("parse version for %v provider '%v': %w", pkg, urn, err)
+	}
+	provider, err := pCtx.Host.Provider(pkg, version)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("load plugin for %v provider '%v': %w", pkg, urn, err)
+	}
+	if provider == nil {
+		return nil, "", "", fmt.Errorf("find plugin for %v provider '%v' at version %v", pkg, urn, version)
+	}
+
+	checked, failures, err := provider.CheckConfig(urn, nil, inputs, false)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("check configuration for provider '%v': %w", urn, err)
+	}
+	if len(failures) != 0 {
+		var msgs []string
+		for _, f := range failures {
+			msgs = append(msgs, fmt.Sprintf("%v: %v", f.Property, f.Reason))
+		}
+		return nil, "", "", fmt.Errorf("invalid configuration for provider '%v': %v", urn, strings.Join(msgs, "; "))
+	}
+	if err := provider.Configure(checked); err != nil {
+		return nil, "", "", fmt.Errorf("configure provider '%v': %w", urn, err)
+	}
+
+	return provider, name, urn, nil
+}
+
+// makeDiscoveredImportFile builds an import file for the given discovered resources. Resources whose IDs match those
+// of resources of the same type that are already managed by the stack are skipped; the number of skipped resources is
+// returned alongside the import file. If providerName is not empty, each resource is imported using that provider.
+func makeDiscoveredImportFile(typ tokens.Type, listed []plugin.ListedResource, snap *deploy.Snapshot,
+	providerName string, providerURN resource.URN,
+) (importFile, int) {
+	managed := map[resource.ID]bool{}
+	names := map[string]bool{}
+	if snap != nil {
+		for _, r := range snap.Resources {
+			if r.Type != typ || r.Delete {
+				continue
+			}
+			managed[r.ID] = true
+			names[string(r.URN.Name())] = true
+		}
+	}
+
+	nameTable := map[string]resource.URN{}
+	if providerName != "" {
+		nameTable[providerName] = providerURN
+	}
+
+	skipped := 0
+	specs := []importSpec{}
+	for _, r := range listed {
+		if r.ID == "" {
+			continue
+		}
+		if managed[r.ID] {
+			skipped++
+			continue
+		}
+		managed[r.ID] = true
+
+		name := suggestImportName(r)
+		for i := 2; names[name]; i++ {
+			name = fmt.Sprintf("%s-%d", suggestImportName(r), i)
+		}
+		names[name] = true
+
+		specs = append(specs, importSpec{
+			Type:     typ,
+			Name:     tokens.QName(name),
+			ID:       r.ID,
+			Provider: providerName,
+		})
+	}
+
+	return importFile{NameTable: nameTable, Resources: specs}, skipped
+}
+
+// suggestImportName returns a resource name for a discovered resource. The provider's suggested name is preferred,
+// falling back to the resource's ID.
+func suggestImportName(r plugin.ListedResource) string {
+	name := r.Name
+	if name == "" {
+		name = string(r.ID)
+	}
+	return string(tokens.IntoQName(strings.ReplaceAll(name, tokens.QNameDelimiter, "-")))
+}
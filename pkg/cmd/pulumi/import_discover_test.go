@@ -0,0 +1,138 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestParseDiscoverFilters(t *testing.T) {
+	t.Parallel()
+
+	filters, err := parseDiscoverFilters([]string{"region=us-west-2", "tag=a=b"})
+	require.NoError(t, err)
+	assert.Equal(t, resource.PropertyMap{
+		"region": resource.NewStringProperty("us-west-2"),
+		"tag":    resource.NewStringProperty("a=b"),
+	}, filters)
+
+	_, err = parseDiscoverFilters([]string{"region"})
+	assert.ErrorContains(t, err, "filter 'region' must be of the form key=value")
+
+	_, err = parseDiscoverFilters([]string{"=value"})
+	assert.Error(t, err)
+}
+
+func TestMakeDiscoveredImportFile(t *testing.T) {
+	t.Parallel()
+
+	typ := tokens.Type("aws:s3/bucket:Bucket")
+	managedURN := resource.NewURN("dev", "proj", "", typ, "logs")
+	snap := &deploy.Snapshot{
+		Resources: []*resource.State{
+			{URN: managedURN, Type: typ, ID: "bucket-logs", Custom: true},
+			// Resources of other types with matching IDs are not considered to be managed.
+			{
+				URN:    resource.NewURN("dev", "proj", "", "aws:s3/bucketPolicy:BucketPolicy", "policy"),
+				Type:   "aws:s3/bucketPolicy:BucketPolicy",
+				ID:     "bucket-data",
+				Custom: true,
+			},
+		},
+	}
+
+	listed := []plugin.ListedResource{
+		{ID: "bucket-logs", Name: "logs"},
+		{ID: "bucket-data", Name: "data"},
+		{ID: "bucket-assets"},
+		{ID: "bucket-other", Name: "logs"},
+		{ID: "arn/with/slashes"},
+		{ID: ""},
+	}
+
+	providerURN := resource.NewURN("dev", "proj", "", "pulumi:providers:aws", "west")
+	f, skipped := makeDiscoveredImportFile(typ, listed, snap, "west", providerURN)
+	assert.Equal(t, 1, skipped)
+	assert.Equal(t, importFile{
+		NameTable: map[string]resource.URN{"west": providerURN},
+		Resources: []importSpec{
+			{Type: typ, Name: "data", ID: "bucket-data", Provider: "west"},
+			{Type: typ, Name: "bucket-assets", ID: "bucket-assets", Provider: "west"},
+			{Type: typ, Name: "logs-2", ID: "bucket-other", Provider: "west"},
+			{Type: typ, Name: "arn-with-slashes", ID: "arn/with/slashes", Provider: "west"},
+		},
+	}, f)
+
+	// The generated file must be accepted by the import command.
+	imports, _, err := parseImportFile(f, true)
+	require.NoError(t, err)
+	assert.Len(t, imports, 4)
+}
+
+func TestFindDiscoveryProvider(t *testing.T) {
+	t.Parallel()
+
+	awsType := providers.MakeProviderType("aws")
+	west := resource.NewURN("dev", "proj", "", awsType, "west")
+	snap := &deploy.Snapshot{
+		Resources: []*resource.State{
+			{URN: west, Type: awsType, ID: "west-id", Custom: true},
+			{URN: resource.NewURN("dev", "proj", "", awsType, "east"), Type: awsType, ID: "east-id", Custom: true},
+			{
+				URN:    resource.NewURN("dev", "proj", "", providers.MakeProviderType("gcp"), "east"),
+				Type:   providers.MakeProviderType("gcp"),
+				ID:     "gcp-id",
+				Custom: true,
+			},
+			{
+				URN:    resource.NewURN("dev", "proj", "", awsType, "old"),
+				Type:   awsType,
+				ID:     "old-id",
+				Custom: true,
+				Delete: true,
+			},
+		},
+	}
+
+	state, err := findDiscoveryProvider(snap, "aws", "west")
+	require.NoError(t, err)
+	assert.Equal(t, west, state.URN)
+
+	// Providers for other packages are not considered, so "east" is not ambiguous.
+	state, err = findDiscoveryProvider(snap, "aws", "east")
+	require.NoError(t, err)
+	assert.Equal(t, awsType, state.Type)
+
+	_, err = findDiscoveryProvider(snap, "aws", "old")
+	assert.ErrorContains(t, err, "no aws provider named 'old' exists in the stack")
+
+	snap.Resources = append(snap.Resources, &resource.State{
+		URN:    resource.NewURN("dev", "proj", "parent$", awsType, "west"),
+		Type:   awsType,
+		ID:     "other-west-id",
+		Custom: true,
+	})
+	_, err = findDiscoveryProvider(snap, "aws", "west")
+	assert.ErrorContains(t, err, "provider name 'west' is ambiguous")
+}
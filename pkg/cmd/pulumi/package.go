@@ -49,6 +49,7 @@ Subcommands of this command are useful to package authors during development.`,
 	}
 	cmd.AddCommand(
 		newExtractSchemaCommand(),
+		newGenSchemaCommand(),
 		newExtractMappingCommand(),
 		newGenSdkCommand(),
 		newPackagePublishCmd(),
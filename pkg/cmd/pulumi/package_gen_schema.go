@@ -0,0 +1,112 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/openapi"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newGenSchemaCommand() *cobra.Command {
+	var from string
+	var name string
+	var version string
+	var module string
+	var out string
+
+	cmd := &cobra.Command{
+		Use:   "gen-schema <file>",
+		Args:  cobra.ExactArgs(1),
+		Short: "Generate a Pulumi package schema from an API definition",
+		Long: `Generate a Pulumi package schema from an API definition.
+
+<file> is the path to an OpenAPI 3 document or a JSON Schema document in JSON or YAML format.
+
+For OpenAPI documents, each component schema that describes an object or an enum becomes
+a type, and each group of CRUD endpoints (e.g. a POST on '/pets' and a GET on '/pets/{petId}')
+becomes a resource. For JSON Schema documents, the schema's definitions become types.
+
+The generated schema can be passed to 'pulumi package gen-sdk' to scaffold SDKs.`,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			data, err := os.ReadFile(args[0])
+			if err != nil {
+				return err
+			}
+
+			var doc *openapi.Document
+			switch from {
+			case "openapi":
+				doc, err = openapi.Load(data)
+			case "jsonschema":
+				doc, err = openapi.LoadJSONSchema(data)
+			default:
+				return fmt.Errorf("unsupported source format %q: expected 'openapi' or 'jsonschema'", from)
+			}
+			if err != nil {
+				return fmt.Errorf("could not read %s: %w", args[0], err)
+			}
+
+			spec, err := openapi.GeneratePackageSpec(doc, openapi.Options{
+				Name:    name,
+				Version: version,
+				Module:  module,
+			})
+			if err != nil {
+				return err
+			}
+
+			// Make sure that the generated schema is valid before writing it out.
+			_, diags, err := schema.BindSpec(spec, nil)
+			if err != nil {
+				return err
+			}
+			if diags.HasErrors() {
+				return diags
+			}
+
+			bytes, err := json.MarshalIndent(spec, "", "  ")
+			if err != nil {
+				return err
+			}
+			bytes = append(bytes, '\n')
+
+			if out == "" {
+				_, err = os.Stdout.Write(bytes)
+				return err
+			}
+			return os.WriteFile(out, bytes, 0o600)
+		}),
+	}
+
+	cmd.Flags().StringVar(&from, "from", "openapi",
+		"The format of the API definition: 'openapi' or 'jsonschema'")
+	cmd.Flags().StringVar(&name, "name", "",
+		"The name of the package. Defaults to a name derived from the document's title")
+	cmd.Flags().StringVar(&version, "version", "",
+		"The version of the package. Defaults to the document's version")
+	cmd.Flags().StringVar(&module, "module", "index",
+		"The module that contains the generated resources and types")
+	cmd.Flags().StringVarP(&out, "out", "o", "",
+		"The path of the schema file to write. Defaults to stdout")
+
+	return cmd
+}
@@ -0,0 +1,109 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/spf13/cobra"
+)
+
+func newPclCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pcl",
+		Short: "Work with PCL programs",
+		Long: `Work with PCL programs
+
+Subcommands of this command can be used to check and format programs written in the Pulumi
+Configuration Language (PCL), such as those produced by 'pulumi convert' and 'pulumi import'.
+This can be useful to keep hand-written PCL examples correct.`,
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newPclCheckCommand())
+	cmd.AddCommand(newPclFmtCommand())
+	return cmd
+}
+
+// findPCLPrograms finds the PCL source files named by the given paths and groups them by directory, as each directory
+// holds a single program. Directories are searched recursively. If no paths are given, the current directory is
+// searched.
+func findPCLPrograms(paths []string) (map[string][]string, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	programs := map[string][]string{}
+	seen := map[string]bool{}
+	add := func(path string) {
+		path = filepath.Clean(path)
+		if seen[path] {
+			return
+		}
+		seen[path] = true
+
+		dir := filepath.Dir(path)
+		programs[dir] = append(programs[dir], path)
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && filepath.Ext(p) == ".pp" {
+				add(p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, files := range programs {
+		sort.Strings(files)
+	}
+	return programs, nil
+}
+
+// pclProgramFiles returns the PCL source files of the program in the given directory.
+func pclProgramFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && filepath.Ext(e.Name()) == ".pp" {
+			files = append(files, filepath.Join(dir, e.Name()))
+		}
+	}
+	return files, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/pcl"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func newPclCheckCommand() *cobra.Command {
+	var strict bool
+
+	cmd := &cobra.Command{
+		Use:   "check [path...]",
+		Short: "Check PCL programs for errors",
+		Long: "Check PCL programs for errors.\n" +
+			"\n" +
+			"Parses and binds the PCL programs at the given paths, loading the schemas of the\n" +
+			"packages that they use, and prints any diagnostics with their source ranges.\n" +
+			"Each directory that contains .pp files is checked as a single program, including\n" +
+			"when only some of its files are named. Directories are searched recursively. If no\n" +
+			"paths are given, the current directory is checked.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			programs, err := findPCLPrograms(args)
+			if err != nil {
+				return err
+			}
+			if len(programs) == 0 {
+				return fmt.Errorf("no PCL files found")
+			}
+
+			cwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			pCtx, err := newPluginContext(cwd)
+			if err != nil {
+				return fmt.Errorf("create plugin host: %w", err)
+			}
+			defer contract.IgnoreClose(pCtx.Host)
+			loader := schema.NewPluginLoader(pCtx.Host)
+
+			color := cmdutil.GetGlobalColorization() == colors.Always
+
+			failed := 0
+			dirs := make([]string, 0, len(programs))
+			for dir := range programs {
+				dirs = append(dirs, dir)
+			}
+			sort.Strings(dirs)
+			for _, dir := range dirs {
+				// A program is made up of every file in its directory, even if only some of them were named.
+				files, err := pclProgramFiles(dir)
+				if err != nil {
+					return err
+				}
+				diags, err := checkPCLProgram(os.Stderr, dir, files, loader, strict, color)
+				if err != nil {
+					return fmt.Errorf("checking %v: %w", dir, err)
+				}
+				if diags.HasErrors() {
+					failed++
+				}
+			}
+
+			if failed != 0 {
+				return fmt.Errorf("%d of %d program(s) failed to check", failed, len(programs))
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&strict, "strict", true,
+		"Type check resources, invokes, and ranges. Pass --strict=false to allow missing or mistyped properties")
+
+	return cmd
+}
+
+// checkPCLProgram parses and binds the PCL program made up of the given files, writing any diagnostics to w.
+func checkPCLProgram(w io.Writer, dir string, files []string, loader schema.ReferenceLoader, strict, color bool,
+) (diags hcl.Diagnostics, err error) {
+	parser := syntax.NewParser()
+	for _, path := range files {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = parser.ParseFile(f, filepath.Base(path))
+		contract.IgnoreClose(f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	diagWriter := parser.NewDiagnosticWriter(w, 0, color)
+	defer func() {
+		contract.IgnoreError(diagWriter.WriteDiagnostics(diags))
+	}()
+
+	diags = parser.Diagnostics
+	if diags.HasErrors() {
+		return diags, nil
+	}
+
+	opts := []pcl.BindOption{
+		pcl.Loader(loader),
+		pcl.DirPath(dir),
+		pcl.ComponentBinder(pcl.ComponentProgramBinderFromFileSystem()),
+	}
+	if !strict {
+		opts = append(opts, pcl.NonStrictBindOptions()...)
+	}
+
+	_, bindDiags, err := pcl.BindProgram(parser.Files, opts...)
+	if bindDiags == nil && err != nil {
+		// This is an error setting up the binder rather than an error in the program.
+		return nil, err
+	}
+	return append(diags, bindDiags...), nil
+}
@@ -0,0 +1,402 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/syntax"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+func newPclFmtCommand() *cobra.Command {
+	var check bool
+
+	cmd := &cobra.Command{
+		Use:   "fmt [path...]",
+		Short: "Format PCL programs",
+		Long: "Format PCL programs.\n" +
+			"\n" +
+			"Rewrites the .pp files at the given paths in the canonical format and prints the\n" +
+			"names of the files that changed. The canonical format is the layout of the programs\n" +
+			"generated by 'pulumi convert' and 'pulumi import'; comments are preserved.\n" +
+			"Directories are searched recursively. If no paths\n" +
+			"are given, the current directory is formatted. Files with syntax errors are reported\n" +
+			"and left unchanged.\n" +
+			"\n" +
+			"With --check, files are not rewritten; instead, the command fails if any file is not\n" +
+			"formatted.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			programs, err := findPCLPrograms(args)
+			if err != nil {
+				return err
+			}
+
+			var files []string
+			for _, p := range programs {
+				files = append(files, p...)
+			}
+			sort.Strings(files)
+
+			color := cmdutil.GetGlobalColorization() == colors.Always
+
+			invalid, unformatted := 0, 0
+			for _, path := range files {
+				src, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+
+				formatted, diags := formatPCL(src, path)
+				if diags.HasErrors() {
+					fileMap := map[string]*hcl.File{path: {Bytes: src}}
+					diagWriter := hcl.NewDiagnosticTextWriter(os.Stderr, fileMap, 0, color)
+					contract.IgnoreError(diagWriter.WriteDiagnostics(diags))
+					invalid++
+					continue
+				}
+				if bytes.Equal(src, formatted) {
+					continue
+				}
+
+				unformatted++
+				fmt.Println(path)
+				if !check {
+					info, err := os.Stat(path)
+					if err != nil {
+						return err
+					}
+					if err := os.WriteFile(path, formatted, info.Mode().Perm()); err != nil {
+						return err
+					}
+				}
+			}
+
+			switch {
+			case invalid != 0:
+				return fmt.Errorf("%d file(s) could not be parsed", invalid)
+			case check && unformatted != 0:
+				return fmt.Errorf("%d file(s) are not formatted", unformatted)
+			}
+			return nil
+		}),
+	}
+
+	cmd.Flags().BoolVar(&check, "check", false,
+		"Check that the files are formatted without rewriting them")
+
+	return cmd
+}
+
+// pclIndent is the indentation of a nested body. This matches the indentation used by the model printer.
+const pclIndent = "    "
+
+// formatPCL returns the canonically formatted form of the given PCL source. If the source cannot be parsed, the
+// diagnostics are returned instead.
+//
+// The source is parsed with the HCL2 syntax parser and printed with the model printer after normalizing the trivia
+// between the attributes and blocks of the program, so formatted programs use the same layout as the programs that are
+// generated by `pulumi convert` and `pulumi import`. Comments are preserved, as are single blank lines between body
+// items. Expressions are printed as written.
+func formatPCL(src []byte, filename string) ([]byte, hcl.Diagnostics) {
+	src = collapsePCLBlankLines(src, filename)
+	parser := syntax.NewParser()
+	err := parser.ParseFile(bytes.NewReader(src), filename)
+	contract.AssertNoErrorf(err, "reading from a byte slice cannot fail")
+	if parser.Diagnostics.HasErrors() {
+		return nil, parser.Diagnostics
+	}
+	file := parser.Files[0]
+
+	// Formatting only needs the structure of the program, so the body is bound without any definitions and binding
+	// errors (e.g. references to definitions in other files) are ignored.
+	body, _ := model.BindBody(file.Body, model.StaticScope(model.NewRootScope(syntax.None)), file.Tokens,
+		model.AllowMissingVariables)
+
+	var eof *syntax.Token
+	if body.Tokens != nil {
+		eof = body.Tokens.EndOfFile
+	}
+	formatPCLBody(src, body, "", nil, eof)
+	return []byte(fmt.Sprintf("%v", body)), nil
+}
+
+// collapsePCLBlankLines returns the given PCL source with each run of blank lines collapsed into a single blank line.
+// The formatter keeps at most one blank line between body items anyway, but the syntax parser only attaches the
+// comment at the end of a line to the token before it if the comment is followed by at most one blank line.
+func collapsePCLBlankLines(src []byte, filename string) []byte {
+	tokens, diags := hclsyntax.LexConfig(src, filename, hcl.Pos{})
+	if diags.HasErrors() {
+		return src
+	}
+
+	// lineEnds counts the consecutive line breaks before the current token. The start of the file counts as one.
+	var collapsed []byte
+	copied, prevEnd, lineEnds := 0, 0, 1
+	for _, tok := range tokens {
+		switch {
+		case tok.Type == hclsyntax.TokenNewline:
+			lineEnds++
+			if lineEnds > 2 {
+				// Drop the blank line, including any whitespace on it.
+				collapsed = append(collapsed, src[copied:prevEnd]...)
+				copied = tok.Range.End.Byte
+			}
+		case tok.Type == hclsyntax.TokenComment && bytes.HasSuffix(tok.Bytes, []byte("\n")):
+			lineEnds = 1
+		default:
+			lineEnds = 0
+		}
+		prevEnd = tok.Range.End.Byte
+	}
+	return append(collapsed, src[copied:]...)
+}
+
+// formatPCLBody normalizes the trivia of the items in the given body, which is indented by the given indentation.
+// open and close are the tokens that delimit the body, if any: the braces of a block, or the end of the file. src is
+// the source that the body was parsed from.
+func formatPCLBody(src []byte, body *model.Body, indent string, open, close *syntax.Token) {
+	itemIndent := indent
+	if open != nil {
+		itemIndent += pclIndent
+	}
+
+	// The trivia between two items may be split arbitrarily between the trailing trivia of the last token of the
+	// first item and the leading trivia of the first token of the second item, so the two are always rewritten
+	// together.
+	prevTrailing := func() syntax.TriviaList { return nil }
+	setPrevTrailing := func(syntax.TriviaList) {}
+	prevItem := false
+	if open != nil {
+		prevTrailing = func() syntax.TriviaList { return open.TrailingTrivia }
+		setPrevTrailing = func(t syntax.TriviaList) { open.TrailingTrivia = t }
+	}
+
+	for i, item := range body.Items {
+		hasPrev := open != nil || i > 0
+
+		var next syntax.Token
+		switch item := item.(type) {
+		case *model.Attribute:
+			next = item.Tokens.Name
+		case *model.Block:
+			next = item.Tokens.Type
+		}
+		trivia := joinPCLTrivia(prevTrailing(), item.GetLeadingTrivia())
+		lines := splitPCLTrivia(trivia, src, next.Raw.Range.Start.Byte)
+		if hasPrev {
+			setPrevTrailing(lines.trailing(prevItem))
+		}
+		prevItem = true
+		leading := lines.leading(itemIndent, i > 0)
+
+		switch item := item.(type) {
+		case *model.Attribute:
+			if item.Tokens == nil {
+				item.Tokens = syntax.NewAttributeTokens(item.Name)
+			}
+			item.Tokens.Name.LeadingTrivia = leading
+			if isPCLWhitespace(item.Tokens.Name.TrailingTrivia, item.Tokens.Equals.LeadingTrivia,
+				item.Tokens.Equals.TrailingTrivia, item.Value.GetLeadingTrivia()) {
+				item.Tokens.Name.TrailingTrivia = nil
+				item.Tokens.Equals.LeadingTrivia, item.Tokens.Equals.TrailingTrivia = nil, nil
+				item.Value.SetLeadingTrivia(syntax.TriviaList{syntax.NewWhitespace(' ')})
+			}
+
+			value := item.Value
+			prevTrailing = value.GetTrailingTrivia
+			setPrevTrailing = value.SetTrailingTrivia
+		case *model.Block:
+			if item.Tokens == nil {
+				item.Tokens = syntax.NewBlockTokens(item.Type, item.Labels...)
+			}
+			tokens := item.Tokens
+			tokens.Type.LeadingTrivia = leading
+
+			header := []syntax.TriviaList{tokens.Type.TrailingTrivia, tokens.OpenBrace.LeadingTrivia}
+			for _, l := range tokens.Labels {
+				header = append(header, l.LeadingTrivia, l.TrailingTrivia)
+			}
+			if isPCLWhitespace(header...) {
+				tokens.Type.TrailingTrivia, tokens.OpenBrace.LeadingTrivia = nil, nil
+				for i := range tokens.Labels {
+					tokens.Labels[i].LeadingTrivia, tokens.Labels[i].TrailingTrivia = nil, nil
+				}
+			}
+
+			formatPCLBody(src, item.Body, itemIndent, &tokens.OpenBrace, &tokens.CloseBrace)
+
+			prevTrailing = func() syntax.TriviaList { return tokens.CloseBrace.TrailingTrivia }
+			setPrevTrailing = func(t syntax.TriviaList) { tokens.CloseBrace.TrailingTrivia = t }
+		}
+	}
+
+	if close == nil {
+		if len(body.Items) > 0 {
+			setPrevTrailing(splitPCLTrivia(prevTrailing(), src, len(src)).trailing(true))
+		}
+		return
+	}
+
+	hasPrev := open != nil || len(body.Items) > 0
+	lines := splitPCLTrivia(joinPCLTrivia(prevTrailing(), close.LeadingTrivia), src, close.Raw.Range.Start.Byte)
+	if open != nil && len(body.Items) == 0 && len(lines.sameLine) == 0 && len(lines.comments) == 0 {
+		// Print empty blocks as `{}`.
+		open.TrailingTrivia, close.LeadingTrivia = nil, nil
+		return
+	}
+	if hasPrev {
+		setPrevTrailing(lines.trailing(prevItem))
+	}
+	close.LeadingTrivia = lines.closing(itemIndent, indent, len(body.Items) > 0)
+}
+
+// joinPCLTrivia returns a new list that holds the trivia of a followed by the trivia of b.
+func joinPCLTrivia(a, b syntax.TriviaList) syntax.TriviaList {
+	return append(append(syntax.TriviaList{}, a...), b...)
+}
+
+// pclTrivia is the trivia between two tokens, split into lines.
+type pclTrivia struct {
+	// sameLine holds the comments on the line of the first token.
+	sameLine []syntax.Trivia
+	// comments holds the comments on the lines between the two tokens.
+	comments []pclComment
+	// blank is true if there is a blank line between the last comment, if any, and the second token.
+	blank bool
+}
+
+// pclComment is a comment on its own line.
+type pclComment struct {
+	comment syntax.Trivia
+	// blank is true if there is a blank line between the comment and the preceding comment or token.
+	blank bool
+}
+
+// splitPCLTrivia splits the comments in the given trivia, which precedes the token at the byte offset next in src, into
+// lines. The line breaks around the comments are counted in src rather than taken from the whitespace in the trivia,
+// as the syntax parser attributes a line break to the token before it, and may drop it, when several line breaks
+// follow a token.
+func splitPCLTrivia(trivia syntax.TriviaList, src []byte, next int) pclTrivia {
+	var t pclTrivia
+
+	sameLine := true
+	for _, trivia := range trivia {
+		comment, ok := trivia.(syntax.Comment)
+		if !ok {
+			continue
+		}
+		// A comment that follows two or more line breaks is preceded by a blank line.
+		newlines := countPCLNewlines(src, comment.Range().Start.Byte)
+		if sameLine && newlines == 0 {
+			t.sameLine = append(t.sameLine, comment)
+			continue
+		}
+		sameLine = false
+		t.comments = append(t.comments, pclComment{comment: comment, blank: newlines >= 2})
+	}
+	t.blank = countPCLNewlines(src, next) >= 2
+	return t
+}
+
+// countPCLNewlines counts the line breaks in the whitespace that precedes the given byte offset in src, including the
+// line break that ends a preceding line comment. The start of the file counts as a line break.
+func countPCLNewlines(src []byte, offset int) int {
+	newlines := 0
+	for i := offset - 1; ; i-- {
+		if i < 0 {
+			return newlines + 1
+		}
+		switch src[i] {
+		case '\n':
+			newlines++
+		case ' ', '\t', '\r':
+		default:
+			return newlines
+		}
+	}
+}
+
+// trailing returns the trivia that ends the line of the first token. If the token ends a body item, the newline that
+// follows a block comment is left to the printer.
+func (t pclTrivia) trailing(item bool) syntax.TriviaList {
+	var trivia syntax.TriviaList
+	for _, c := range t.sameLine {
+		trivia = append(trivia, syntax.NewWhitespace(' '), c)
+	}
+	if len(trivia) == 0 || !item && !bytes.HasSuffix(trivia[len(trivia)-1].Bytes(), []byte("\n")) {
+		trivia = append(trivia, syntax.NewWhitespace('\n'))
+	}
+	return trivia
+}
+
+// ownLines returns the trivia for the comments on their own lines, indented by the given indentation. If blank is
+// false, blank lines before the first comment are dropped.
+func (t pclTrivia) ownLines(indent string, blank bool) syntax.TriviaList {
+	var trivia syntax.TriviaList
+	for i, c := range t.comments {
+		ws := indent
+		if c.blank && (blank || i > 0) {
+			ws = "\n" + ws
+		}
+		trivia = append(trivia, syntax.NewWhitespace([]byte(ws)...), c.comment)
+		if !bytes.HasSuffix(c.comment.Bytes(), []byte("\n")) {
+			trivia = append(trivia, syntax.NewWhitespace('\n'))
+		}
+	}
+	return trivia
+}
+
+// leading returns the leading trivia of a body item that is indented by the given indentation. If blank is false,
+// blank lines before the item are dropped.
+func (t pclTrivia) leading(indent string, blank bool) syntax.TriviaList {
+	trivia := t.ownLines(indent, blank)
+	if t.blank && (blank || len(t.comments) != 0) {
+		trivia = append(trivia, syntax.NewWhitespace('\n'))
+	}
+	return append(trivia, syntax.NewWhitespace([]byte(indent)...))
+}
+
+// closing returns the leading trivia of the token that closes a body. Comments are indented by the indentation of
+// the body's items, and the token by the indentation of the body itself.
+func (t pclTrivia) closing(itemIndent, indent string, blank bool) syntax.TriviaList {
+	trivia := t.ownLines(itemIndent, blank)
+	if indent != "" {
+		trivia = append(trivia, syntax.NewWhitespace([]byte(indent)...))
+	}
+	return trivia
+}
+
+// isPCLWhitespace returns true if the given trivia lists contain no comments.
+func isPCLWhitespace(lists ...syntax.TriviaList) bool {
+	for _, list := range lists {
+		for _, t := range list {
+			if _, ok := t.(syntax.Comment); ok {
+				return false
+			}
+		}
+	}
+	return true
+}
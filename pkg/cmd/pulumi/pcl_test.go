@@ -0,0 +1,147 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindPCLPrograms(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	write := func(path string) string {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, nil, 0o600))
+		return path
+	}
+	a, b := write("main.pp"), write("other.pp")
+	c := write(filepath.Join("component", "main.pp"))
+	write("README.md")
+
+	programs, err := findPCLPrograms([]string{dir, a})
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		dir:                             {a, b},
+		filepath.Join(dir, "component"): {c},
+	}, programs)
+
+	_, err = findPCLPrograms([]string{filepath.Join(dir, "missing")})
+	assert.Error(t, err)
+}
+
+func TestFormatPCL(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name, src, expected string
+	}{
+		{
+			name:     "indentation",
+			src:      "config foo string {\ndefault=\"bar\"\n}\noutput out {\n  value = foo\n}\n",
+			expected: "config foo string {\n    default = \"bar\"\n}\noutput out {\n    value = foo\n}\n",
+		},
+		{
+			name: "comments",
+			src: "# header\n\n\nresource a \"x:y:Z\" { # open\n\tfoo=1 # trailing\n\n\n\t# own\n\tbar = 2\n" +
+				"  # end\n}\nresource b \"x:y:Z\" {\n}\n\n# eof\n\n",
+			expected: "# header\n\nresource a \"x:y:Z\" { # open\n    foo = 1 # trailing\n\n    # own\n    bar = 2\n" +
+				"    # end\n}\nresource b \"x:y:Z\" {}\n\n# eof\n",
+		},
+		{
+			name: "blank lines",
+			src: "resource a \"x:y:Z\" {\n    foo = <<EOT\nx\n\n\ny\nEOT\n    # own\n\n\n\n    bar = 2\n}\n\n\n" +
+				"# eof\n",
+			expected: "resource a \"x:y:Z\" {\n    foo = <<EOT\nx\n\n\ny\nEOT\n    # own\n\n    bar = 2\n}\n\n# eof\n",
+		},
+		{
+			name:     "nested blocks",
+			src:      "resource r \"x:y:Z\" {\noptions {\nprotect = true\n}\n}",
+			expected: "resource r \"x:y:Z\" {\n    options {\n        protect = true\n    }\n}\n",
+		},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			formatted, diags := formatPCL([]byte(c.src), "main.pp")
+			require.False(t, diags.HasErrors(), "%v", diags)
+			assert.Equal(t, c.expected, string(formatted))
+
+			// Formatting is idempotent.
+			again, diags := formatPCL(formatted, "main.pp")
+			require.False(t, diags.HasErrors(), "%v", diags)
+			assert.Equal(t, c.expected, string(again))
+		})
+	}
+
+	_, diags := formatPCL([]byte("config foo string {"), "main.pp")
+	assert.True(t, diags.HasErrors())
+}
+
+func TestCheckPCLProgram(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.pp")
+
+	require.NoError(t, os.WriteFile(path, []byte("config foo string {\n}\noutput out {\n  value = foo\n}\n"), 0o600))
+	var buf bytes.Buffer
+	diags, err := checkPCLProgram(&buf, dir, []string{path}, nil, true, false)
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors())
+	assert.Empty(t, buf.String())
+
+	require.NoError(t, os.WriteFile(path, []byte("output out {\n  value = bar\n}\n"), 0o600))
+	buf.Reset()
+	diags, err = checkPCLProgram(&buf, dir, []string{path}, nil, true, false)
+	require.NoError(t, err)
+	assert.True(t, diags.HasErrors())
+	// Diagnostics are written with their source ranges.
+	assert.Contains(t, buf.String(), "undefined variable bar")
+	assert.Contains(t, buf.String(), "on main.pp line")
+	assert.Contains(t, buf.String(), "value = bar")
+}
+
+func TestCheckPCLProgramAcrossFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	config, main := filepath.Join(dir, "config.pp"), filepath.Join(dir, "main.pp")
+	require.NoError(t, os.WriteFile(config, []byte("config foo string {\n}\n"), 0o600))
+	require.NoError(t, os.WriteFile(main, []byte("output out {\n  value = foo\n}\n"), 0o600))
+
+	// Naming a single file still checks the whole program, so references to other files resolve.
+	programs, err := findPCLPrograms([]string{main})
+	require.NoError(t, err)
+	require.Equal(t, map[string][]string{dir: {main}}, programs)
+
+	files, err := pclProgramFiles(dir)
+	require.NoError(t, err)
+	assert.Equal(t, []string{config, main}, files)
+
+	var buf bytes.Buffer
+	diags, err := checkPCLProgram(&buf, dir, files, nil, true, false)
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors(), buf.String())
+}
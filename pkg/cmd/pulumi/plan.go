@@ -0,0 +1,71 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPlanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "plan",
+		Hidden: !hasExperimentalCommands(),
+		Short:  "[EXPERIMENTAL] Inspect and compare saved update plans",
+		Long: "[EXPERIMENTAL] Inspect and compare saved update plans.\n" +
+			"\n" +
+			"Update plans are saved by `pulumi preview --save-plan`, and constrain the update run by\n" +
+			"`pulumi up --plan`. The plan family of commands shows what a saved plan would do, and how two\n" +
+			"plans differ, so that plans can be reviewed before they're applied.",
+		Args: cmdutil.NoArgs,
+	}
+
+	cmd.AddCommand(newPlanDiffCmd())
+	cmd.AddCommand(newPlanShowCmd())
+
+	return cmd
+}
+
+// readPlanForDisplay reads the plan file at the given path, without decrypting its secrets, whose values are shown as
+// "[secret]". This avoids needing the secrets provider of the stack the plan was saved for.
+func readPlanForDisplay(path string) (*deploy.Plan, error) {
+	plan, err := readPlan(path, secretPlaceholderCrypter{}, secretPlaceholderCrypter{})
+	if err != nil {
+		return nil, fmt.Errorf("reading plan %s: %w", path, err)
+	}
+	return plan, nil
+}
+
+// secretPlaceholderCrypter is like config.BlindingCrypter, except that it decrypts every secret to a JSON string, as
+// the values of secrets in plans are JSON documents.
+type secretPlaceholderCrypter struct{}
+
+func (c secretPlaceholderCrypter) EncryptValue(ctx context.Context, plaintext string) (string, error) {
+	return "[secret]", nil
+}
+
+func (c secretPlaceholderCrypter) DecryptValue(ctx context.Context, ciphertext string) (string, error) {
+	return `"[secret]"`, nil
+}
+
+func (c secretPlaceholderCrypter) BulkDecrypt(ctx context.Context, ciphertexts []string) (map[string]string, error) {
+	return config.DefaultBulkDecrypt(ctx, c, ciphertexts)
+}
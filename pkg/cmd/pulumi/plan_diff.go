@@ -0,0 +1,61 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPlanDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old-plan> <new-plan>",
+		Args:  cmdutil.ExactArgs(2),
+		Short: "Compare two saved plans",
+		Long: "Compare two saved plans.\n" +
+			"\n" +
+			"Each resource whose plan differs between the two plans is shown, along with how it differs:\n" +
+			"its planned operations, its resource options, and the changes planned to its inputs and\n" +
+			"outputs. Secret values are shown as [secret], and so aren't compared.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			old, err := readPlanForDisplay(args[0])
+			if err != nil {
+				return err
+			}
+			new, err := readPlanForDisplay(args[1])
+			if err != nil {
+				return err
+			}
+
+			changes := deploy.DiffPlans(old, new)
+			if len(changes) == 0 {
+				fmt.Println("The plans are the same.")
+				return nil
+			}
+			display.PrintPlanDiff(os.Stdout, changes, display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			})
+			return nil
+		}),
+	}
+
+	return cmd
+}
@@ -0,0 +1,56 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+func newPlanShowCmd() *cobra.Command {
+	var debug bool
+	cmd := &cobra.Command{
+		Use:   "show <plan>",
+		Args:  cmdutil.ExactArgs(1),
+		Short: "Show the operations planned by a saved plan",
+		Long: "Show the operations planned by a saved plan.\n" +
+			"\n" +
+			"The plan is rendered like the diff of a preview: each resource is shown with the operations\n" +
+			"planned for it and the changes planned to its inputs, followed by a summary. Secret values\n" +
+			"are shown as [secret].",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			plan, err := readPlanForDisplay(args[0])
+			if err != nil {
+				return err
+			}
+
+			display.PrintPlan(os.Stdout, plan, display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+				Debug: debug,
+			})
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().BoolVarP(
+		&debug, "debug", "d", false,
+		"Print detailed debugging output")
+
+	return cmd
+}
@@ -15,6 +15,9 @@
 package main
 
 import (
+	"fmt"
+	"os"
+
 	"github.com/spf13/cobra"
 
 	"github.com/pulumi/pulumi/pkg/v3/engine"
@@ -47,11 +50,37 @@ func newPluginCmd() *cobra.Command {
 
 	cmd.AddCommand(newPluginInstallCmd())
 	cmd.AddCommand(newPluginLsCmd())
+	cmd.AddCommand(newPluginMirrorCmd())
+	cmd.AddCommand(newPluginPruneCmd())
 	cmd.AddCommand(newPluginRmCmd())
 
 	return cmd
 }
 
+// loadPluginLock loads the plugin lock of the project in the given directory. If locked is true, the project must
+// already have a lock file, as there would otherwise be nothing to check its plugins against.
+func loadPluginLock(root string, locked bool) (*workspace.PluginLock, error) {
+	path := workspace.PluginLockPath(root)
+	if locked {
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, fmt.Errorf("--locked requires a %s file; run `pulumi plugin install` to create one",
+				workspace.PluginLockFile)
+		}
+	}
+	return workspace.LoadPluginLock(path)
+}
+
+// savePluginLock writes the plugin lock of the project in the given directory, if plugins have been recorded in it.
+func savePluginLock(root string, lock *workspace.PluginLock) error {
+	if !lock.Changed() {
+		return nil
+	}
+	if err := lock.Save(workspace.PluginLockPath(root)); err != nil {
+		return fmt.Errorf("saving %s: %w", workspace.PluginLockFile, err)
+	}
+	return nil
+}
+
 // getProjectPlugins fetches a list of plugins used by this project.
 func getProjectPlugins() ([]workspace.PluginSpec, error) {
 	proj, root, err := readProject()
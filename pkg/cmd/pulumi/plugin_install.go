@@ -21,10 +21,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/blang/semver"
+	"github.com/go-git/go-git/v5"
 	"github.com/pulumi/pulumi/pkg/v3/util"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
@@ -32,6 +35,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/gitutil"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/spf13/cobra"
@@ -53,7 +57,27 @@ func newPluginInstallCmd() *cobra.Command {
 			"\n" +
 			"If VERSION is specified, it cannot be a range; it must be a specific number.\n" +
 			"If VERSION is unspecified, Pulumi will attempt to look up the latest version of\n" +
-			"the plugin, though the result is not guaranteed.",
+			"the plugin, though the result is not guaranteed.\n" +
+			"\n" +
+			"When installing the current project's plugins, the plugins are recorded, along with\n" +
+			"the checksums of any that are downloaded, in the project's Pulumi.lock file. Later\n" +
+			"installs and deployments use the recorded versions and verify the checksums. Pass\n" +
+			"--locked to fail rather than update Pulumi.lock if a plugin is missing from it or\n" +
+			"differs from it.\n" +
+			"\n" +
+			"Pass --from to build a plugin from source rather than download it. The source is\n" +
+			"either a local directory or a git repository, given as git+<url>[@<revision>], e.g.\n" +
+			"git+https://github.com/example/pulumi-foo.git@v1.2.0. Go modules are built with\n" +
+			"`go build`, and Node.js and Python plugins are installed to be run by their runtime.\n" +
+			"A `build` section in the source's PulumiPlugin.yaml can give the commands to build it:\n" +
+			"\n" +
+			"    runtime: go\n" +
+			"    build:\n" +
+			"      commands:\n" +
+			"        - make provider\n" +
+			"      binary: bin/pulumi-resource-foo\n" +
+			"\n" +
+			"If VERSION is unspecified, it's derived from the git tag of the revision built.",
 		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
 			ctx := commandContext()
 			return picmd.Run(ctx, args)
@@ -70,6 +94,10 @@ func newPluginInstallCmd() *cobra.Command {
 		"reinstall", false, "Reinstall a plugin even if it already exists")
 	cmd.PersistentFlags().StringVar(&picmd.checksum,
 		"checksum", "", "The expected SHA256 checksum for the plugin archive")
+	cmd.PersistentFlags().StringVar(&picmd.from,
+		"from", "", "Build the plugin from source in a local directory or a git repository (git+<url>[@<revision>])")
+	cmd.PersistentFlags().BoolVar(&picmd.locked,
+		"locked", false, "Fail if a project plugin is missing from, or differs from, the project's Pulumi.lock file")
 
 	return cmd
 }
@@ -80,6 +108,8 @@ type pluginInstallCmd struct {
 	file      string
 	reinstall bool
 	checksum  string
+	from      string
+	locked    bool
 
 	diag  diag.Sink
 	env   env.Env
@@ -106,7 +136,13 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 
 	// Parse the kind, name, and version, if specified.
 	var installs []workspace.PluginSpec
+	var lock *workspace.PluginLock
+	var root string
+	var sourceDir string
 	if len(args) > 0 {
+		if cmd.locked {
+			return errors.New("--locked is only valid if the project's plugins are being installed")
+		}
 		if !workspace.IsPluginKind(args[0]) {
 			return fmt.Errorf("unrecognized plugin kind: %s", args[0])
 		} else if len(args) < 2 {
@@ -124,6 +160,9 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 		if len(args) < 3 && cmd.file != "" {
 			return errors.New("missing plugin version argument, this is required if installing from a file")
 		}
+		if cmd.from != "" && (cmd.file != "" || cmd.serverURL != "" || cmd.checksum != "") {
+			return errors.New("--from cannot be combined with --file, --server or --checksum")
+		}
 
 		var checksums map[string][]byte
 		if cmd.checksum != "" {
@@ -158,14 +197,28 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 			)
 		}
 
-		// Try and set known plugin download URLs
-		if urlSet := util.SetKnownPluginDownloadURL(&pluginSpec); urlSet {
+		// Plugins built from source have no download URL; otherwise try and set known plugin download URLs.
+		if cmd.from != "" {
+			dir, tagVersion, cleanup, err := checkoutPluginSource(cmd.from)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			if version == nil {
+				if tagVersion == nil {
+					return fmt.Errorf("could not derive a version for %s from a git tag; "+
+						"pass the VERSION argument", cmd.from)
+				}
+				pluginSpec.Version = tagVersion
+			}
+			sourceDir = dir
+		} else if urlSet := util.SetKnownPluginDownloadURL(&pluginSpec); urlSet {
 			cmd.diag.Infof(
 				diag.Message("", "Plugin download URL set to %s"), pluginSpec.PluginDownloadURL)
 		}
 
 		// If we don't have a version try to look one up
-		if version == nil {
+		if pluginSpec.Version == nil {
 			latestVersion, err := cmd.pluginGetLatestVersion(pluginSpec)
 			if err != nil {
 				return err
@@ -181,18 +234,36 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 		if cmd.checksum != "" {
 			return errors.New("--checksum is only valid if a specific package is being installed")
 		}
+		if cmd.from != "" {
+			return errors.New("--from is only valid if a specific package is being installed")
+		}
 
 		// If a specific plugin wasn't given, compute the set of plugins the current project needs.
 		plugins, err := getProjectPlugins()
 		if err != nil {
 			return err
 		}
+		var proj *workspace.Project
+		if proj, root, err = readProject(); err != nil {
+			return err
+		}
+		if lock, err = loadPluginLock(root, cmd.locked); err != nil {
+			return err
+		}
 		for _, plugin := range plugins {
 			// Skip language plugins; by definition, we already have one installed.
 			// TODO[pulumi/pulumi#956]: eventually we will want to honor and install these in the usual way.
-			if plugin.Kind != workspace.LanguagePlugin {
-				installs = append(installs, plugin)
+			if plugin.Kind == workspace.LanguagePlugin {
+				continue
+			}
+			// Bundled plugins are versioned with the CLI, so aren't locked.
+			if !workspace.IsPluginBundled(plugin.Kind, plugin.Name) {
+				if plugin, err = lock.Apply(plugin, cmd.locked); err != nil {
+					return err
+				}
 			}
+			plugin.Mirror = proj.Plugins.MirrorDir(root)
+			installs = append(installs, plugin)
 		}
 	}
 
@@ -201,16 +272,26 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 		label := fmt.Sprintf("[%s plugin %s]", install.Kind, install)
 
 		// If the plugin already exists, don't download it unless --reinstall was passed.  Note that
-		// by default we accept plugins with >= constraints, unless --exact was passed which requires ==.
+		// by default we accept plugins with >= constraints, unless --exact or --locked was passed which require ==.
 		if !cmd.reinstall {
-			if cmd.exact {
+			if cmd.exact || cmd.locked {
 				if workspace.HasPlugin(install) {
 					logging.V(1).Infof("%s skipping install (existing == match)", label)
+					if lock != nil {
+						if err := lock.RecordInstalled(install, cmd.locked); err != nil {
+							return err
+						}
+					}
 					continue
 				}
 			} else {
 				if has, _ := workspace.HasPluginGTE(install); has {
 					logging.V(1).Infof("%s skipping install (existing >= match)", label)
+					if lock != nil {
+						if err := lock.RecordInstalled(install, cmd.locked); err != nil {
+							return err
+						}
+					}
 					continue
 				}
 			}
@@ -222,8 +303,16 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 		// If we got here, actually try to do the download.
 		var source string
 		var payload workspace.PluginContent
+		var checksum []byte
 		var err error
-		if cmd.file == "" {
+		if sourceDir != "" {
+			source = cmd.from
+			logging.V(1).Infof("%s building from %s", label, cmd.from)
+			payload, err = workspace.BuildPluginFromSource(ctx, sourceDir, install, os.Stderr, os.Stderr)
+			if err != nil {
+				return fmt.Errorf("%s building from %s: %w", label, cmd.from, err)
+			}
+		} else if cmd.file == "" {
 			withProgress := func(stream io.ReadCloser, size int64) io.ReadCloser {
 				return workspace.ReadCloserProgressBar(stream, size, "Downloading plugin", cmd.color)
 			}
@@ -238,6 +327,9 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 			}
 			defer func() { contract.IgnoreError(os.Remove(r.Name())) }()
 
+			if checksum, err = workspace.PluginArchiveChecksum(r.Name()); err != nil {
+				return fmt.Errorf("%s computing checksum: %w", label, err)
+			}
 			payload = workspace.TarPlugin(r)
 		} else {
 			source = cmd.file
@@ -251,8 +343,19 @@ func (cmd *pluginInstallCmd) Run(ctx context.Context, args []string) error {
 		if err = install.InstallWithContext(ctx, payload, cmd.reinstall); err != nil {
 			return fmt.Errorf("installing %s from %s: %w", label, source, err)
 		}
+		if lock != nil {
+			if !cmd.locked {
+				lock.Record(install, checksum)
+			}
+			if err = lock.RecordInstalled(install, cmd.locked); err != nil {
+				return err
+			}
+		}
 	}
 
+	if lock != nil && !cmd.locked {
+		return savePluginLock(root, lock)
+	}
 	return nil
 }
 
@@ -289,3 +392,61 @@ func getFilePayload(file string, spec workspace.PluginSpec) (workspace.PluginCon
 	}
 	return workspace.TarPlugin(f), nil
 }
+
+// checkoutPluginSource returns the directory of the plugin source given to `plugin install --from`, which is either a
+// local directory or a git repository given as git+<url>[@<revision>], and the version given by the source's git tag,
+// if it has one. Git repositories are cloned into a temporary directory, which cleanup removes.
+func checkoutPluginSource(from string) (string, *semver.Version, func(), error) {
+	cleanup := func() {}
+
+	var dir string
+	var repo *git.Repository
+	if rawurl, ok := strings.CutPrefix(from, "git+"); ok {
+		// The revision follows the last "@", unless that is part of the URL's user info.
+		var revision string
+		if i := strings.LastIndex(rawurl, "@"); i > strings.LastIndex(rawurl, "/") {
+			rawurl, revision = rawurl[:i], rawurl[i+1:]
+		}
+
+		tmp, err := os.MkdirTemp("", "pulumi-plugin-source-")
+		if err != nil {
+			return "", nil, nil, err
+		}
+		cleanup = func() { contract.IgnoreError(os.RemoveAll(tmp)) }
+		if repo, err = gitutil.GitCloneAndCheckoutRevision(rawurl, revision, tmp); err != nil {
+			cleanup()
+			return "", nil, nil, fmt.Errorf("cloning %s: %w", from, err)
+		}
+		dir = tmp
+	} else {
+		info, err := os.Stat(from)
+		if err != nil {
+			return "", nil, nil, fmt.Errorf("plugin source: %w", err)
+		}
+		if !info.IsDir() {
+			return "", nil, nil, fmt.Errorf("plugin source %s is not a directory or git+<url>", from)
+		}
+		if dir, err = filepath.Abs(from); err != nil {
+			return "", nil, nil, err
+		}
+		if repo, err = gitutil.GetGitRepository(dir); err != nil {
+			return "", nil, nil, err
+		}
+	}
+
+	if repo == nil {
+		return dir, nil, cleanup, nil
+	}
+	tags, err := gitutil.GitTagsAtHead(repo)
+	if err != nil {
+		cleanup()
+		return "", nil, nil, fmt.Errorf("reading git tags of %s: %w", from, err)
+	}
+	var version *semver.Version
+	for _, tag := range tags {
+		if v, err := semver.ParseTolerant(tag); err == nil && (version == nil || v.GT(*version)) {
+			version = &v
+		}
+	}
+	return dir, version, cleanup, nil
+}
@@ -3,13 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/blang/semver"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/env"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // Test for https://github.com/pulumi/pulumi/issues/11703, check we give an error when trying to install a
@@ -57,3 +63,61 @@ func TestBundledDev(t *testing.T) {
 	err := cmd.Run(context.Background(), []string{"language", "nodejs"})
 	assert.ErrorContains(t, err, "404 HTTP error fetching plugin")
 }
+
+// --locked only applies to the project's plugins, which are recorded in Pulumi.lock.
+func TestLockedRequiresProjectInstall(t *testing.T) {
+	t.Parallel()
+
+	cmd := &pluginInstallCmd{
+		diag:   diagtest.LogSink(t),
+		locked: true,
+	}
+
+	err := cmd.Run(context.Background(), []string{"resource", "aws", "v5.0.0"})
+	assert.EqualError(t, err, "--locked is only valid if the project's plugins are being installed")
+}
+
+func TestCheckoutPluginSource(t *testing.T) {
+	t.Parallel()
+
+	// Create a repository with a tagged commit, and an untagged commit after it.
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	w, err := repo.Worktree()
+	require.NoError(t, err)
+	commit := func(file string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, file), []byte(file), 0o600))
+		_, err := w.Add(file)
+		require.NoError(t, err)
+		_, err = w.Commit(file, &git.CommitOptions{
+			Author: &object.Signature{Name: "test", Email: "test@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+	}
+	commit("tagged")
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.4.0", head.Hash(), nil)
+	require.NoError(t, err)
+	commit("untagged")
+
+	// A local directory is used in place, and has no version as its HEAD isn't tagged.
+	src, version, cleanup, err := checkoutPluginSource(dir)
+	require.NoError(t, err)
+	defer cleanup()
+	assert.Equal(t, dir, src)
+	assert.Nil(t, version)
+
+	// Cloning the tag gives its version.
+	src, version, cleanup, err = checkoutPluginSource("git+file://" + filepath.ToSlash(dir) + "@v1.4.0")
+	require.NoError(t, err)
+	defer cleanup()
+	assert.NotEqual(t, dir, src)
+	assert.Equal(t, "1.4.0", version.String())
+	assert.FileExists(t, filepath.Join(src, "tagged"))
+	assert.NoFileExists(t, filepath.Join(src, "untagged"))
+
+	_, _, _, err = checkoutPluginSource(filepath.Join(dir, "tagged"))
+	assert.ErrorContains(t, err, "is not a directory or git+<url>")
+}
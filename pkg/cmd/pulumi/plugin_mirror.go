@@ -0,0 +1,126 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newPluginMirrorCmd() *cobra.Command {
+	var dir string
+	var platforms []string
+	cmd := &cobra.Command{
+		Use:   "mirror",
+		Args:  cmdutil.NoArgs,
+		Short: "Download the plugins required by the current project into a mirror directory",
+		Long: "Download the plugins required by the current project into a mirror directory.\n" +
+			"\n" +
+			"This command downloads the archives of every plugin the current project requires,\n" +
+			"for the current platform and any others given with --platform, into a directory.\n" +
+			"Plugins already in the directory are not downloaded again. If the project has a\n" +
+			"Pulumi.lock file, the plugins it records are mirrored, and both downloaded archives\n" +
+			"and those already in the directory are verified against its checksums.\n" +
+			"\n" +
+			"Set PULUMI_PLUGIN_MIRROR to the directory, or set `plugins.mirror` in Pulumi.yaml,\n" +
+			"to install plugins from the mirror rather than downloading them. This allows\n" +
+			"deployments from networks without internet access.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			proj, root, err := readProject()
+			if err != nil {
+				return err
+			}
+			if dir == "" {
+				if dir = proj.Plugins.MirrorDir(root); dir == "" {
+					return errors.New("--dir is required if the project doesn't set plugins.mirror")
+				}
+			}
+
+			platforms = append([]string{workspace.CurrentPluginPlatform()}, platforms...)
+			for _, platform := range platforms {
+				if _, _, err := workspace.ParsePluginPlatform(platform); err != nil {
+					return err
+				}
+			}
+
+			lock, err := loadPluginLock(root, false /*locked*/)
+			if err != nil {
+				return err
+			}
+			plugins, err := getProjectPlugins()
+			if err != nil {
+				return err
+			}
+
+			seen := map[string]bool{}
+			for _, plugin := range plugins {
+				// Bundled plugins are distributed with the CLI, and so don't need mirroring.
+				if workspace.IsPluginBundled(plugin.Kind, plugin.Name) ||
+					(plugin.Kind == workspace.ResourcePlugin && plugin.Name == "pulumi") {
+					continue
+				}
+				if plugin, err = lock.Apply(plugin, false /*locked*/); err != nil {
+					return err
+				}
+				if plugin.Version == nil {
+					if plugin.Version, err = plugin.GetLatestRemoteVersion(); err != nil {
+						return fmt.Errorf("could not get latest version for plugin %s: %w", plugin.Name, err)
+					}
+				}
+
+				for _, platform := range platforms {
+					label := fmt.Sprintf("[%s plugin %s %s]", plugin.Kind, plugin, platform)
+					if seen[label] {
+						continue
+					}
+					seen[label] = true
+
+					opSy, arch, err := workspace.ParsePluginPlatform(platform)
+					if err != nil {
+						return err
+					}
+					withProgress := func(stream io.ReadCloser, size int64) io.ReadCloser {
+						return workspace.ReadCloserProgressBar(stream, size, "Downloading plugin",
+							cmdutil.GetGlobalColorization())
+					}
+					path, downloaded, err := workspace.MirrorPlugin(dir, plugin, opSy, arch, withProgress)
+					if err != nil {
+						return fmt.Errorf("%s mirroring: %w", label, err)
+					}
+					if downloaded {
+						cmdutil.Diag().Infoerrf(diag.Message("", "%s downloaded to %s"), label, path)
+					} else {
+						cmdutil.Diag().Infoerrf(diag.Message("", "%s already mirrored"), label)
+					}
+				}
+			}
+			return nil
+		}),
+	}
+
+	cmd.PersistentFlags().StringVar(&dir,
+		"dir", "", "The directory to download plugins into. Defaults to the project's plugins.mirror setting")
+	cmd.PersistentFlags().StringSliceVar(&platforms,
+		"platform", nil, "Additional platforms to download plugins for, e.g. linux-arm64")
+
+	return cmd
+}
@@ -0,0 +1,217 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/hashicorp/go-multierror"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func newPluginPruneCmd() *cobra.Command {
+	var days int
+	var unreferenced bool
+	var lockFiles []string
+	var dryRun bool
+	var yes bool
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Args:  cmdutil.NoArgs,
+		Short: "Remove unused plugins from the plugin cache",
+		Long: "Remove unused plugins from the plugin cache.\n" +
+			"\n" +
+			"This command removes the plugin versions that haven't been used for --days days. With\n" +
+			"--unreferenced, plugin versions that aren't recorded in any Pulumi.lock file are also removed,\n" +
+			"however recently they were used. The Pulumi.lock files considered are the current project's,\n" +
+			"if there is one, and any given with --lock-file.\n" +
+			"\n" +
+			"Plugin versions recorded in a Pulumi.lock file are never removed, and nor is the latest\n" +
+			"installed version of each major version of a plugin.",
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+
+			if days < 0 {
+				return errors.New("--days must not be negative")
+			}
+
+			var locks []*workspace.PluginLock
+			path, err := workspace.DetectProjectPath()
+			if err != nil && !errors.Is(err, workspace.ErrProjectNotFound) {
+				return err
+			} else if err == nil {
+				lockFiles = append(lockFiles, workspace.PluginLockPath(filepath.Dir(path)))
+			}
+			for _, path := range lockFiles {
+				lock, err := workspace.LoadPluginLock(path)
+				if err != nil {
+					return err
+				}
+				locks = append(locks, lock)
+			}
+			if unreferenced && len(locks) == 0 {
+				return errors.New("--unreferenced requires a current project or at least one --lock-file")
+			}
+
+			plugins, err := workspace.GetPluginsWithMetadata()
+			if err != nil {
+				return fmt.Errorf("loading plugins: %w", err)
+			}
+			cutoff := time.Now().Add(-time.Duration(days) * 24 * time.Hour)
+			prunes := selectPluginsToPrune(plugins, locks, cutoff, unreferenced)
+
+			if len(prunes) == 0 {
+				cmdutil.Diag().Infof(
+					diag.Message("", "no plugins found to prune"))
+				return nil
+			}
+
+			var reclaimed uint64
+			for _, plugin := range prunes {
+				reclaimed += uint64(plugin.Size)
+			}
+
+			// List the plugins, and confirm that the user wants to remove them (unless --yes or --dry-run was passed).
+			if dryRun || !yes {
+				var suffix string
+				if len(prunes) != 1 {
+					suffix = "s"
+				}
+				verb := "This will remove"
+				if dryRun {
+					verb = "Pruning would remove"
+				}
+				fmt.Print(
+					opts.Color.Colorize(
+						fmt.Sprintf("%s%s %d plugin%s from the cache, reclaiming %s:%s\n",
+							colors.SpecAttention, verb, len(prunes), suffix, humanize.Bytes(reclaimed), colors.Reset)))
+				for _, plugin := range prunes {
+					fmt.Printf("    %s %s (%s, last used %s)\n", plugin.Kind, plugin.String(),
+						humanize.Bytes(uint64(plugin.Size)), humanize.Time(plugin.LastUsedTime))
+				}
+				if dryRun || !confirmPrompt("", "yes", opts) {
+					return nil
+				}
+			}
+
+			// Run the actual delete operations.
+			var result error
+			reclaimed = 0
+			for _, plugin := range prunes {
+				if err := plugin.Delete(); err == nil {
+					fmt.Printf("removed: %s %v\n", plugin.Kind, plugin)
+					reclaimed += uint64(plugin.Size)
+				} else {
+					result = multierror.Append(
+						result, fmt.Errorf("failed to delete %s plugin %s: %w", plugin.Kind, plugin, err))
+				}
+			}
+			fmt.Printf("reclaimed %s\n", humanize.Bytes(reclaimed))
+			return result
+		}),
+	}
+
+	cmd.PersistentFlags().IntVar(
+		&days, "days", 30,
+		"Remove plugin versions that haven't been used for this many days")
+	cmd.PersistentFlags().BoolVar(
+		&unreferenced, "unreferenced", false,
+		"Also remove plugin versions that aren't recorded in any Pulumi.lock file")
+	cmd.PersistentFlags().StringArrayVar(
+		&lockFiles, "lock-file", nil,
+		"A Pulumi.lock file whose plugins must be kept. May be specified multiple times")
+	cmd.PersistentFlags().BoolVar(
+		&dryRun, "dry-run", false,
+		"List the plugins that would be removed, and the space reclaimed, without removing them")
+	cmd.PersistentFlags().BoolVarP(
+		&yes, "yes", "y", false,
+		"Skip confirmation prompts, and proceed with removal anyway")
+
+	return cmd
+}
+
+// selectPluginsToPrune returns the plugins to remove from the cache. A plugin is removed if it was last used before
+// cutoff, or, if unreferenced is true, if none of the locks record it. Plugins that the locks record are never
+// removed, and nor is the latest version of each major version of a plugin.
+func selectPluginsToPrune(
+	plugins []workspace.PluginInfo, locks []*workspace.PluginLock, cutoff time.Time, unreferenced bool,
+) []workspace.PluginInfo {
+	type major struct {
+		kind  workspace.PluginKind
+		name  string
+		major uint64
+	}
+	latest := map[major]workspace.PluginInfo{}
+	for _, plugin := range plugins {
+		if plugin.Version == nil {
+			continue
+		}
+		key := major{plugin.Kind, plugin.Name, plugin.Version.Major}
+		if l, has := latest[key]; !has || plugin.Version.GT(*l.Version) {
+			latest[key] = plugin
+		}
+	}
+
+	var prunes []workspace.PluginInfo
+	for _, plugin := range plugins {
+		// Plugins without versions can't be compared with their other versions, so leave them alone.
+		if plugin.Version == nil {
+			continue
+		}
+		if l := latest[major{plugin.Kind, plugin.Name, plugin.Version.Major}]; l.Version.EQ(*plugin.Version) {
+			continue
+		}
+
+		referenced := false
+		for _, lock := range locks {
+			if lock.Contains(plugin.Kind, plugin.Name, *plugin.Version) {
+				referenced = true
+				break
+			}
+		}
+		if referenced {
+			continue
+		}
+
+		if unreferenced || plugin.LastUsedTime.Before(cutoff) {
+			prunes = append(prunes, plugin)
+		}
+	}
+
+	sort.Slice(prunes, func(i, j int) bool {
+		pi, pj := prunes[i], prunes[j]
+		if pi.Kind != pj.Kind {
+			return pi.Kind < pj.Kind
+		}
+		if pi.Name != pj.Name {
+			return pi.Name < pj.Name
+		}
+		return pi.Version.LT(*pj.Version)
+	})
+	return prunes
+}
@@ -0,0 +1,70 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func TestSelectPluginsToPrune(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	old := now.Add(-60 * 24 * time.Hour)
+	plugin := func(name, version string, lastUsed time.Time) workspace.PluginInfo {
+		v := semver.MustParse(version)
+		return workspace.PluginInfo{
+			Kind: workspace.ResourcePlugin, Name: name, Version: &v, LastUsedTime: lastUsed,
+		}
+	}
+	plugins := []workspace.PluginInfo{
+		plugin("aws", "5.0.0", old),
+		plugin("aws", "5.1.0", old), // latest 5.x
+		plugin("aws", "6.0.0", old),
+		plugin("aws", "6.1.0", now),
+		plugin("aws", "6.2.0", old), // latest 6.x
+		plugin("gcp", "7.0.0", old),
+		plugin("gcp", "7.1.0", old), // latest 7.x
+		{Kind: workspace.ResourcePlugin, Name: "local", LastUsedTime: old},
+	}
+
+	lock := &workspace.PluginLock{}
+	v600 := semver.MustParse("6.0.0")
+	lock.Record(workspace.PluginSpec{Kind: workspace.ResourcePlugin, Name: "aws", Version: &v600}, nil)
+
+	names := func(plugins []workspace.PluginInfo) []string {
+		var names []string
+		for _, p := range plugins {
+			names = append(names, p.String())
+		}
+		return names
+	}
+	cutoff := now.Add(-30 * 24 * time.Hour)
+
+	prunes := selectPluginsToPrune(plugins, nil, cutoff, false)
+	assert.Equal(t, []string{"aws-5.0.0", "aws-6.0.0", "gcp-7.0.0"}, names(prunes))
+
+	prunes = selectPluginsToPrune(plugins, []*workspace.PluginLock{lock}, cutoff, false)
+	assert.Equal(t, []string{"aws-5.0.0", "gcp-7.0.0"}, names(prunes))
+
+	prunes = selectPluginsToPrune(plugins, []*workspace.PluginLock{lock}, cutoff, true)
+	assert.Equal(t, []string{"aws-5.0.0", "aws-6.1.0", "gcp-7.0.0"}, names(prunes))
+}
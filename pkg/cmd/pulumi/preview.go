@@ -69,6 +69,7 @@ func newPreviewCmd() *cobra.Command {
 	var replaces []string
 	var targetReplaces []string
 	var targetDependents bool
+	var locked bool
 
 	use, cmdArgs := "preview", cmdutil.NoArgs
 	if remoteSupported() {
@@ -232,10 +233,16 @@ func newPreviewCmd() *cobra.Command {
 					// experimental mode to just get more testing of it.
 					GeneratePlan: hasExperimentalCommands() || planFilePath != "",
 					Experimental: hasExperimentalCommands(),
+					Locked:       locked,
 				},
 				Display: displayOpts,
 			}
 
+			// Previews check plugins against the lock, but never update it.
+			if opts.Engine.PluginLock, err = loadPluginLock(root, locked); err != nil {
+				return result.FromError(err)
+			}
+
 			plan, changes, res := s.Preview(ctx, backend.UpdateOperation{
 				Proj:               proj,
 				Root:               root,
@@ -327,6 +334,9 @@ func newPreviewCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&targetDependents, "target-dependents", false,
 		"Allows updating of dependent targets discovered but not specified in --target list")
+	cmd.PersistentFlags().BoolVar(
+		&locked, "locked", false,
+		"Fail if a plugin is missing from, or differs from, the project's Pulumi.lock file")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().StringSliceVar(
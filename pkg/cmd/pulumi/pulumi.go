@@ -305,6 +305,7 @@ func NewPulumiCmd() *cobra.Command {
 				newUpCmd(),
 				newDestroyCmd(),
 				newPreviewCmd(),
+				newPlanCmd(),
 				newCancelCmd(),
 			},
 		},
@@ -335,6 +336,7 @@ func NewPulumiCmd() *cobra.Command {
 				newPluginCmd(),
 				newSchemaCmd(),
 				newPackageCmd(),
+				newPclCmd(),
 			},
 		},
 		{
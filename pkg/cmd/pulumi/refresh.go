@@ -58,10 +58,12 @@ func newRefreshCmd() *cobra.Command {
 	var showReplacementSteps bool
 	var showSames bool
 	var skipPreview bool
+	var previewOnly bool
 	var suppressOutputs bool
 	var suppressPermalink string
 	var yes bool
 	var targets *[]string
+	var locked bool
 
 	// Flags for handling pending creates
 	var skipPendingCreates bool
@@ -89,12 +91,21 @@ func newRefreshCmd() *cobra.Command {
 		Run: cmdutil.RunResultFunc(func(cmd *cobra.Command, args []string) result.Result {
 			ctx := commandContext()
 
+			if previewOnly {
+				if skipPreview {
+					return result.FromError(errors.New("--preview-only and --skip-preview cannot be used together"))
+				}
+				if remoteArgs.remote {
+					return result.FromError(errors.New("--preview-only is not supported for remote operations"))
+				}
+			}
+
 			// Remote implies we're skipping previews.
 			if remoteArgs.remote {
 				skipPreview = true
 			}
 
-			yes = yes || skipPreview || skipConfirmations()
+			yes = yes || skipPreview || previewOnly || skipConfirmations()
 			interactive := cmdutil.Interactive()
 			if !interactive && !yes {
 				return result.FromError(
@@ -105,6 +116,7 @@ func newRefreshCmd() *cobra.Command {
 			if err != nil {
 				return result.FromError(err)
 			}
+			opts.PreviewOnly = previewOnly
 
 			displayType := display.DisplayProgress
 			if diffDisplay {
@@ -204,6 +216,10 @@ func newRefreshCmd() *cobra.Command {
 				return result.FromError(fmt.Errorf(
 					"cannot set both --skip-pending-creates and --clear-pending-creates"))
 			}
+			if previewOnly && (clearPendingCreates || importPendingCreates != nil && len(*importPendingCreates) > 0) {
+				return result.FromError(errors.New(
+					"--preview-only cannot be used with --clear-pending-creates or --import-pending-creates"))
+			}
 
 			// First we handle explicit create->imports we were given
 			if importPendingCreates != nil && len(*importPendingCreates) > 0 {
@@ -231,7 +247,7 @@ func newRefreshCmd() *cobra.Command {
 			}
 
 			// We then allow the user to interactively handle remaining pending creates.
-			if interactive && hasPendingCreates(snap) && !skipPendingCreates {
+			if interactive && !previewOnly && hasPendingCreates(snap) && !skipPendingCreates {
 				if err := filterMapPendingCreates(ctx, s, opts.Display,
 					yes, interactiveFixPendingCreate); err != nil {
 					return result.FromError(err)
@@ -262,6 +278,16 @@ func newRefreshCmd() *cobra.Command {
 				DisableOutputValues:       disableOutputValues(),
 				Targets:                   deploy.NewUrnTargets(targetUrns),
 				Experimental:              hasExperimentalCommands(),
+				Locked:                    locked,
+			}
+
+			// Plugins are checked against the lock, which is only updated by `pulumi up` and `pulumi plugin install`.
+			if root != "" {
+				if opts.Engine.PluginLock, err = loadPluginLock(root, locked); err != nil {
+					return result.FromError(err)
+				}
+			} else if locked {
+				return result.FromError(errors.New("--locked requires a project"))
 			}
 
 			changes, res := s.Refresh(ctx, backend.UpdateOperation{
@@ -308,6 +334,9 @@ func newRefreshCmd() *cobra.Command {
 	targets = cmd.PersistentFlags().StringArrayP(
 		"target", "t", []string{},
 		"Specify a single resource URN to refresh. Multiple resource can be specified using: --target urn1 --target urn2")
+	cmd.PersistentFlags().BoolVar(
+		&locked, "locked", false,
+		"Fail if a plugin is missing from, or differs from, the project's Pulumi.lock file")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().BoolVar(
@@ -328,6 +357,9 @@ func newRefreshCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVarP(
 		&skipPreview, "skip-preview", "f", false,
 		"Do not calculate a preview before performing the refresh")
+	cmd.PersistentFlags().BoolVar(
+		&previewOnly, "preview-only", false,
+		"Only show a preview of the refresh, but don't perform the refresh itself")
 	cmd.PersistentFlags().BoolVar(
 		&suppressOutputs, "suppress-outputs", false,
 		"Suppress display of stack outputs (in case they contain sensitive values)")
@@ -30,6 +30,7 @@ import (
 
 func newStackRenameCmd() *cobra.Command {
 	var stack string
+	var jsonOut bool
 	cmd := &cobra.Command{
 		Use:   "rename <new-stack-name>",
 		Args:  cmdutil.ExactArgs(1),
@@ -64,7 +65,7 @@ func newStackRenameCmd() *cobra.Command {
 			newStackName := args[0]
 			newStackRef, err := s.Rename(ctx, tokens.QName(newStackName))
 			if err != nil {
-				return err
+				return reportCLIError(jsonOut, err)
 			}
 			_, newConfigPath, err := workspace.DetectProjectStackPath(newStackRef.Name().Q())
 			if err != nil {
@@ -97,5 +98,6 @@ func newStackRenameCmd() *cobra.Command {
 	cmd.PersistentFlags().StringVarP(
 		&stack, "stack", "s", "",
 		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Write a structured description of any error to stdout as JSON")
 	return cmd
 }
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strings"
 
 	survey "github.com/AlecAivazis/survey/v2"
 	surveycore "github.com/AlecAivazis/survey/v2/core"
@@ -54,11 +55,63 @@ troubleshooting a stack or when performing specific edits that otherwise would r
 	cmd.AddCommand(newStateEditCommand())
 	cmd.AddCommand(newStateDeleteCommand())
 	cmd.AddCommand(newStateUnprotectCommand())
+	cmd.AddCommand(newStateProtectCommand())
+	cmd.AddCommand(newStateRetainCommand())
+	cmd.AddCommand(newStateTaintCommand())
+	cmd.AddCommand(newStateUntaintCommand())
 	cmd.AddCommand(newStateRenameCommand())
 	cmd.AddCommand(newStateUpgradeCommand())
+	cmd.AddCommand(newStateRepairCommand())
 	return cmd
 }
 
+// resourceNotFoundError is returned by the state commands when the resource they operate on does not exist.
+type resourceNotFoundError struct {
+	urn resource.URN
+}
+
+func (e resourceNotFoundError) Error() string {
+	return fmt.Sprintf("No such resource %q exists in the current state", e.urn)
+}
+
+// newCLIError returns the structured form of an error returned by a command that was run with --json. The kind of the
+// error is determined from cause, and its message is the message of err, which may describe cause in more detail.
+func newCLIError(cause, err error) apitype.CLIError {
+	cliErr := apitype.CLIError{Message: err.Error()}
+
+	var notFound resourceNotFoundError
+	var hasDependencies edit.ResourceHasDependenciesError
+	var protected edit.ResourceProtectedError
+	var alreadyExists *backend.StackAlreadyExistsError
+	switch {
+	case errors.As(cause, &notFound):
+		cliErr.Kind, cliErr.URN = apitype.ResourceNotFoundCLIError, string(notFound.urn)
+	case errors.As(cause, &hasDependencies):
+		cliErr.Kind, cliErr.URN = apitype.ResourceHasDependentsCLIError, string(hasDependencies.Condemned.URN)
+		for _, dep := range hasDependencies.Dependencies {
+			cliErr.Dependents = append(cliErr.Dependents, string(dep.URN))
+		}
+	case errors.As(cause, &protected):
+		cliErr.Kind, cliErr.URN = apitype.ResourceProtectedCLIError, string(protected.Condemned.URN)
+	case errors.As(cause, &alreadyExists):
+		cliErr.Kind, cliErr.StackName = apitype.StackAlreadyExistsCLIError, alreadyExists.StackName
+	}
+	return cliErr
+}
+
+// printCLIError writes the structured form of an error returned by a command that was run with --json to stdout.
+func printCLIError(cause, err error) {
+	contract.IgnoreError(printJSON(newCLIError(cause, err)))
+}
+
+// reportCLIError writes the structured form of err to stdout if jsonOut is true and err is not nil. It returns err.
+func reportCLIError(jsonOut bool, err error) error {
+	if jsonOut && err != nil {
+		printCLIError(err, err)
+	}
+	return err
+}
+
 // locateStackResource attempts to find a unique resource associated with the given URN in the given snapshot. If the
 // given URN is ambiguous and this is an interactive terminal, it prompts the user to select one of the resources in
 // the list of resources with identical URNs to operate upon.
@@ -66,7 +119,7 @@ func locateStackResource(opts display.Options, snap *deploy.Snapshot, urn resour
 	candidateResources := edit.LocateResource(snap, urn)
 	switch {
 	case len(candidateResources) == 0: // resource was not found
-		return nil, fmt.Errorf("No such resource %q exists in the current state", urn)
+		return nil, resourceNotFoundError{urn: urn}
 	case len(candidateResources) == 1: // resource was unambiguously found
 		return candidateResources[0], nil
 	}
@@ -130,6 +183,124 @@ func runStateEdit(
 	})
 }
 
+// runStateEditOnResources runs the given state edit function on every resource in the given stack that matches one of
+// the given URNs or URN globs, or on every resource in the stack if all is true. If filter is non-nil, resources that
+// are matched by a glob or by all are skipped unless filter returns true. It returns the number of resources that were
+// edited.
+func runStateEditOnResources(
+	ctx context.Context, stackName string, showPrompt bool,
+	urnsOrGlobs []string, all bool, filter func(*resource.State) bool, operation edit.OperationFunc,
+) (int, error) {
+	count := 0
+	err := runTotalStateEdit(ctx, stackName, showPrompt, func(opts display.Options, snap *deploy.Snapshot) error {
+		resources, err := selectStackResources(opts, snap, urnsOrGlobs, all, filter)
+		if err != nil {
+			return err
+		}
+
+		for _, res := range resources {
+			if err := operation(snap, res); err != nil {
+				return err
+			}
+		}
+		count = len(resources)
+		return nil
+	})
+	return count, err
+}
+
+// runStateMarkCommand implements the commands that set or clear a flag on the resources in a stack's state, e.g.
+// 'pulumi state protect'. If no URNs are given and all is false, the user is prompted to select a resource. The
+// given description (e.g. "protected") is used to report the number of resources that were edited. If filter is
+// non-nil, only the resources for which it returns true are matched by globs and by all.
+func runStateMarkCommand(
+	ctx context.Context, stackName string, urnsOrGlobs []string, all bool, yes bool,
+	filter func(*resource.State) bool, operation edit.OperationFunc, description string,
+) error {
+	yes = yes || skipConfirmations()
+	// Show the confirmation prompt if the user didn't pass the --yes parameter to skip it.
+	showPrompt := !yes
+
+	if all && len(urnsOrGlobs) != 0 {
+		return errors.New("cannot specify resource URNs together with --all")
+	}
+	if !all && len(urnsOrGlobs) == 0 {
+		if !cmdutil.Interactive() {
+			return missingNonInteractiveArg("resource URN")
+		}
+		urn, err := getURNFromState(ctx, stackName, nil, "Select a resource:")
+		if err != nil {
+			return err
+		}
+		urnsOrGlobs = []string{string(urn)}
+	}
+
+	count, err := runStateEditOnResources(ctx, stackName, showPrompt, urnsOrGlobs, all, filter, operation)
+	if err != nil {
+		return err
+	}
+	if count == 1 {
+		fmt.Printf("1 resource %s\n", description)
+	} else {
+		fmt.Printf("%d resources %s\n", count, description)
+	}
+	return nil
+}
+
+// selectStackResources returns the resources in the given snapshot that match the given URNs or URN globs, or every
+// resource in the snapshot if all is true. Each URN must match a resource and each glob must match at least one
+// resource. URNs that refer to multiple resources are disambiguated as in locateStackResource. If filter is non-nil,
+// globs and all only match the resources for which it returns true; URNs are always selected.
+func selectStackResources(
+	opts display.Options, snap *deploy.Snapshot, urnsOrGlobs []string, all bool, filter func(*resource.State) bool,
+) ([]*resource.State, error) {
+	matches := func(res *resource.State) bool {
+		return filter == nil || filter(res)
+	}
+
+	if all {
+		var selected []*resource.State
+		for _, res := range snap.Resources {
+			if matches(res) {
+				selected = append(selected, res)
+			}
+		}
+		return selected, nil
+	}
+
+	var selected []*resource.State
+	seen := map[*resource.State]bool{}
+	add := func(res *resource.State) {
+		if !seen[res] {
+			seen[res] = true
+			selected = append(selected, res)
+		}
+	}
+	for _, arg := range urnsOrGlobs {
+		if !strings.Contains(arg, "*") {
+			res, err := locateStackResource(opts, snap, resource.URN(arg))
+			if err != nil {
+				return nil, err
+			}
+			add(res)
+			continue
+		}
+
+		targets := deploy.NewUrnTargets([]string{arg})
+		matched := false
+		for _, res := range snap.Resources {
+			if targets.Contains(res.URN) && matches(res) {
+				add(res)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("No resources match %q in the current state", arg)
+		}
+	}
+	return selected, nil
+}
+
 // runTotalStateEdit runs a snapshot-mutating function on the entirety of the given stack's snapshot.
 // Before mutating, the user may be prompted to for confirmation if the current session is interactive.
 func runTotalStateEdit(
@@ -32,6 +32,7 @@ func newStateDeleteCommand() *cobra.Command {
 	var stack string
 	var yes bool
 	var targetDepenedents bool
+	var jsonOut bool
 
 	cmd := &cobra.Command{
 		Use:   "delete [resource URN]",
@@ -84,6 +85,7 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 				return edit.DeleteResource(snap, res, handleProtected, targetDepenedents)
 			})
 			if err != nil {
+				displayErr := err
 				switch e := err.(type) {
 				case edit.ResourceHasDependenciesError:
 					message := string(e.Condemned.URN) + " can't be safely deleted because the following resources depend on it:\n"
@@ -93,14 +95,16 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 					}
 
 					message += "\nDelete those resources first or pass --target-dependents."
-					return errors.New(message)
+					displayErr = errors.New(message)
 				case edit.ResourceProtectedError:
-					return fmt.Errorf(
+					displayErr = fmt.Errorf(
 						"%s can't be safely deleted because it is protected. "+
 							"Re-run this command with --force to force deletion", string(e.Condemned.URN))
-				default:
-					return err
 				}
+				if jsonOut {
+					printCLIError(err, displayErr)
+				}
+				return displayErr
 			}
 			fmt.Println("Resource deleted")
 			return nil
@@ -113,5 +117,6 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 	cmd.Flags().BoolVar(&force, "force", false, "Force deletion of protected resources")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
 	cmd.Flags().BoolVar(&targetDepenedents, "target-dependents", false, "Delete the URN and all its dependents")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Write a structured description of any error to stdout as JSON")
 	return cmd
 }
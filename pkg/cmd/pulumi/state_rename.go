@@ -139,6 +139,9 @@ func stateReurnOperation(
 func stateRenameOperation(
 	urn resource.URN, newResourceName tokens.QName, opts display.Options, snap *deploy.Snapshot,
 ) error {
+	if len(edit.LocateResource(snap, urn)) == 0 {
+		return resourceNotFoundError{urn: urn}
+	}
 	contract.Assertf(tokens.IsQName(string(newResourceName)),
 		"QName must be valid")
 	// update the URN with only the name part changed
@@ -150,6 +153,7 @@ func stateRenameOperation(
 func newStateRenameCommand() *cobra.Command {
 	var stack string
 	var yes bool
+	var jsonOut bool
 
 	cmd := &cobra.Command{
 		Use:   "rename [resource URN] [new name]",
@@ -221,7 +225,7 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 				func(opts display.Options, snap *deploy.Snapshot) error {
 					return stateRenameOperation(urn, newResourceName, opts, snap)
 				})
-			if err != nil {
+			if err := reportCLIError(jsonOut, err); err != nil {
 				// an error occurred
 				// return it
 				return err
@@ -237,5 +241,6 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.
 		"The name of the stack to operate on. Defaults to the current stack")
 
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Write a structured description of any error to stdout as JSON")
 	return cmd
 }
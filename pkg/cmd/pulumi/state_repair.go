@@ -0,0 +1,241 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	survey "github.com/AlecAivazis/survey/v2"
+	surveycore "github.com/AlecAivazis/survey/v2/core"
+	"github.com/spf13/cobra"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/result"
+)
+
+func newStateRepairCommand() *cobra.Command {
+	var stackName string
+	var yes bool
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "repair",
+		Short: "Repair the integrity errors in a stack's state",
+		Long: `Repair the integrity errors in a stack's state
+
+This command fixes the errors that cause a stack's state to fail its integrity checks, such as resources that
+come before their parents or dependencies, and references to resources that do not exist. Resources are sorted so
+that each resource comes after the resources it refers to, references to missing parents, dependencies, and
+deletedWith resources are removed, and references to missing providers are rewritten to refer to the provider
+with the same URN.
+
+Every change is shown before the state is written, and the state is only written after confirmation. Pass
+--dry-run to print the changes as JSON without writing the state.`,
+		Args: cmdutil.NoArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			ctx := commandContext()
+			opts := display.Options{
+				Color: cmdutil.GetGlobalColorization(),
+			}
+			s, err := requireStack(ctx, stackName, stackLoadOnly, opts)
+			if err != nil {
+				return err
+			}
+
+			repair := &stateRepairCmd{
+				Colorizer: opts.Color,
+				Yes:       yes || skipConfirmations(),
+				DryRun:    dryRun,
+			}
+			return repair.Run(ctx, s)
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stackName, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false,
+		"Print the repairs that would be made as JSON without writing the state")
+	return cmd
+}
+
+// stateRepairCmd implements the 'pulumi state repair' command.
+type stateRepairCmd struct {
+	Stdout    io.Writer // defaults to os.Stdout
+	Colorizer colors.Colorization
+	Yes       bool
+	DryRun    bool
+}
+
+// stateRepairReport is the JSON representation of the repairs printed by 'pulumi state repair --dry-run'.
+type stateRepairReport struct {
+	// IntegrityError is the integrity error of the state before it is repaired, if any.
+	IntegrityError string `json:"integrityError,omitempty"`
+	// Repairs lists the changes that would be made to the state.
+	Repairs []edit.Repair `json:"repairs"`
+	// RemainingError is the integrity error of the state after it is repaired, if any.
+	RemainingError string `json:"remainingError,omitempty"`
+}
+
+func (cmd *stateRepairCmd) Run(ctx context.Context, s backend.Stack) error {
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+
+	// Snapshots that fail their integrity checks can't be loaded using Stack.Snapshot, so deserialize the deployment
+	// directly.
+	dep, err := s.ExportDeployment(ctx)
+	if err != nil {
+		return err
+	}
+	snap, err := stack.DeserializeUntypedDeployment(ctx, dep, stack.DefaultSecretsProvider)
+	if err != nil {
+		return err
+	}
+	if snap == nil {
+		fmt.Fprintln(cmd.Stdout, "The stack has no state to repair.")
+		return nil
+	}
+
+	var report stateRepairReport
+	if err := snap.VerifyIntegrity(); err != nil {
+		report.IntegrityError = err.Error()
+	}
+	report.Repairs, err = edit.RepairSnapshot(snap)
+	if err != nil {
+		return fmt.Errorf("repairing state: %w", err)
+	}
+	remainingErr := snap.VerifyIntegrity()
+	if remainingErr != nil {
+		report.RemainingError = remainingErr.Error()
+	}
+
+	if cmd.DryRun {
+		if report.Repairs == nil {
+			report.Repairs = []edit.Repair{}
+		}
+		return fprintJSON(cmd.Stdout, report)
+	}
+
+	if len(report.Repairs) == 0 {
+		if remainingErr != nil {
+			return fmt.Errorf("the state contains errors that cannot be repaired automatically: %w", remainingErr)
+		}
+		fmt.Fprintln(cmd.Stdout, "The state is valid; no repairs are needed.")
+		return nil
+	}
+
+	cmd.printRepairs(s, report.Repairs)
+	if remainingErr != nil {
+		return fmt.Errorf("the state would still contain errors after repair: %w", remainingErr)
+	}
+
+	if !cmd.Yes {
+		if !cmdutil.Interactive() {
+			return errors.New("--yes must be passed in to proceed when running in non-interactive mode")
+		}
+
+		confirm := false
+		surveycore.DisableColor = true
+		prompt := cmd.Colorizer.Colorize(colors.Yellow + "warning" + colors.Reset + ": ")
+		prompt += "This command will edit your stack's state directly. Confirm?"
+		if err = survey.AskOne(&survey.Confirm{
+			Message: prompt,
+		}, &confirm, surveyIcons(cmd.Colorizer)); err != nil || !confirm {
+			return result.FprintBailf(cmd.Stdout, "confirmation declined")
+		}
+	}
+
+	sdep, err := stack.SerializeDeployment(snap, snap.SecretsManager, false /* showSecrets */)
+	if err != nil {
+		return fmt.Errorf("serializing deployment: %w", err)
+	}
+	bytes, err := json.Marshal(sdep)
+	if err != nil {
+		return err
+	}
+	if err := s.ImportDeployment(ctx, &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: bytes,
+	}); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(cmd.Stdout, "State repaired.")
+	return nil
+}
+
+// printRepairs prints the given repairs as a diff, grouped by resource.
+func (cmd *stateRepairCmd) printRepairs(s backend.Stack, repairs []edit.Repair) {
+	fmt.Fprintf(cmd.Stdout, cmd.Colorizer.Colorize(
+		colors.SpecHeadline+"Repairing the state of stack %s:"+colors.Reset+"\n"), s.Ref())
+
+	var urns []resource.URN
+	byURN := map[resource.URN][]edit.Repair{}
+	for _, r := range repairs {
+		if _, has := byURN[r.URN]; !has {
+			urns = append(urns, r.URN)
+		}
+		byURN[r.URN] = append(byURN[r.URN], r)
+	}
+
+	for _, urn := range urns {
+		fmt.Fprint(cmd.Stdout, cmd.Colorizer.Colorize(
+			fmt.Sprintf("%s  ~ %s%s\n", colors.SpecUpdate, urn, colors.Reset)))
+		for _, r := range byURN[urn] {
+			switch r.Kind {
+			case edit.RepairMoveResource:
+				fmt.Fprint(cmd.Stdout, cmd.Colorizer.Colorize(
+					fmt.Sprintf("%s      ~ position: %s => %s%s\n", colors.SpecUpdate, r.Old, r.New, colors.Reset)))
+			case edit.RepairRewriteProvider:
+				fmt.Fprint(cmd.Stdout, cmd.Colorizer.Colorize(
+					fmt.Sprintf("%s      ~ provider: %s => %s%s\n", colors.SpecUpdate, r.Old, r.New, colors.Reset)))
+			default:
+				fmt.Fprint(cmd.Stdout, cmd.Colorizer.Colorize(
+					fmt.Sprintf("%s      - %s: %s%s\n", colors.SpecDelete, repairField(r), r.Old, colors.Reset)))
+			}
+		}
+	}
+	fmt.Fprintln(cmd.Stdout)
+}
+
+// repairField returns the name of the field of a resource's state that is changed by the given repair.
+func repairField(r edit.Repair) string {
+	switch r.Kind {
+	case edit.RepairDropParent:
+		return "parent"
+	case edit.RepairDropDependency:
+		return "dependencies"
+	case edit.RepairDropPropertyDependency:
+		return fmt.Sprintf("propertyDependencies.%s", r.Property)
+	case edit.RepairDropDeletedWith:
+		return "deletedWith"
+	default:
+		return string(r.Kind)
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/pkg/v3/resource/stack"
+	"github.com/pulumi/pulumi/pkg/v3/secrets/b64"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// newRepairTestStack returns a mock stack whose state has a child that comes before its parent and a dependency on
+// a missing resource, along with a pointer to the stack's current deployment.
+func newRepairTestStack(t *testing.T) (backend.Stack, **apitype.UntypedDeployment) {
+	t.Helper()
+
+	stackURN := resource.NewURN("dev", "proj", "", resource.RootStackType, "proj-dev")
+	parentURN := resource.NewURN("dev", "proj", "", "pkg:index:Component", "parent")
+	snap := &deploy.Snapshot{
+		SecretsManager: b64.NewBase64SecretsManager(),
+		Resources: []*resource.State{
+			{URN: stackURN, Type: resource.RootStackType},
+			{
+				URN:          resource.NewURN("dev", "proj", "pkg:index:Component", "pkg:index:Child", "child"),
+				Type:         "pkg:index:Child",
+				Parent:       parentURN,
+				Dependencies: []resource.URN{resource.NewURN("dev", "proj", "", "pkg:index:Gone", "gone")},
+			},
+			{URN: parentURN, Type: "pkg:index:Component", Parent: stackURN},
+		},
+	}
+	sdep, err := stack.SerializeDeployment(snap, nil, false)
+	require.NoError(t, err)
+	data, err := json.Marshal(sdep)
+	require.NoError(t, err)
+
+	deployment := &apitype.UntypedDeployment{
+		Version:    apitype.DeploymentSchemaVersionCurrent,
+		Deployment: data,
+	}
+	s := &backend.MockStack{
+		RefF: func() backend.StackReference {
+			return &backend.MockStackReference{StringV: "dev", NameV: "dev"}
+		},
+		ExportDeploymentF: func(ctx context.Context) (*apitype.UntypedDeployment, error) {
+			return deployment, nil
+		},
+		ImportDeploymentF: func(ctx context.Context, dep *apitype.UntypedDeployment) error {
+			deployment = dep
+			return nil
+		},
+	}
+	return s, &deployment
+}
+
+func TestStateRepairDryRun(t *testing.T) {
+	t.Parallel()
+
+	s, deployment := newRepairTestStack(t)
+	original := *deployment
+
+	var stdout bytes.Buffer
+	cmd := &stateRepairCmd{Stdout: &stdout, Colorizer: colors.Never, DryRun: true}
+	require.NoError(t, cmd.Run(context.Background(), s))
+
+	var report stateRepairReport
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &report))
+	assert.Contains(t, report.IntegrityError, "comes after it")
+	assert.Empty(t, report.RemainingError)
+	assert.Equal(t, []edit.Repair{
+		{
+			Kind: edit.RepairDropDependency,
+			URN:  "urn:pulumi:dev::proj::pkg:index:Component$pkg:index:Child::child",
+			Old:  "urn:pulumi:dev::proj::pkg:index:Gone::gone",
+		},
+		{
+			Kind: edit.RepairMoveResource,
+			URN:  "urn:pulumi:dev::proj::pkg:index:Component::parent",
+			Old:  "2",
+			New:  "1",
+		},
+	}, report.Repairs)
+
+	// A dry run must not write the state.
+	assert.Same(t, original, *deployment)
+}
+
+func TestStateRepair(t *testing.T) {
+	t.Parallel()
+
+	s, deployment := newRepairTestStack(t)
+
+	var stdout bytes.Buffer
+	cmd := &stateRepairCmd{Stdout: &stdout, Colorizer: colors.Never, Yes: true}
+	require.NoError(t, cmd.Run(context.Background(), s))
+	assert.Contains(t, stdout.String(),
+		"  ~ urn:pulumi:dev::proj::pkg:index:Component$pkg:index:Child::child\n"+
+			"      - dependencies: urn:pulumi:dev::proj::pkg:index:Gone::gone\n")
+	assert.Contains(t, stdout.String(), "      ~ position: 2 => 1\n")
+	assert.Contains(t, stdout.String(), "State repaired.")
+
+	snap, err := stack.DeserializeUntypedDeployment(context.Background(), *deployment, stack.DefaultSecretsProvider)
+	require.NoError(t, err)
+	assert.NoError(t, snap.VerifyIntegrity())
+
+	// Running the command again is a no-op.
+	stdout.Reset()
+	require.NoError(t, cmd.Run(context.Background(), s))
+	assert.Equal(t, "The state is valid; no repairs are needed.\n", stdout.String())
+}
@@ -0,0 +1,54 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+func newStateRetainCommand() *cobra.Command {
+	var retainAll bool
+	var stack string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "retain [resource URN or glob...]",
+		Short: "Retain resources in a stack's state on deletion",
+		Long: `Retain resources in a stack's state on deletion
+
+This command sets the 'retainOnDelete' bit on one or more resources. When a retained resource is deleted, it is
+removed from the stack's state without being deleted by its provider.
+Resources are specified by their URNs, which may contain '*' and '**' wildcards.
+
+To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
+		Example: "pulumi state retain 'urn:pulumi:dev::demo::aws:rds/instance:Instance::*'",
+		Args:    cobra.ArbitraryArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runStateMarkCommand(commandContext(), stack, args, retainAll, yes,
+				nil, edit.RetainResource, "retained")
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVar(&retainAll, "all", false, "Retain all resources in the checkpoint")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+
+	return cmd
+}
@@ -0,0 +1,88 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+
+	"github.com/spf13/cobra"
+)
+
+func newStateTaintCommand() *cobra.Command {
+	var taintAll bool
+	var stack string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "taint [resource URN or glob...]",
+		Short: "Mark resources in a stack's state to be replaced on the next update",
+		Long: `Mark resources in a stack's state to be replaced on the next update
+
+This command taints one or more resources. Tainted resources are replaced on the next update, even if their
+inputs have not changed. The taint is cleared once a resource has been replaced successfully, or by running
+` + "`pulumi state untaint`" + `. Resources are specified by their URNs, which may contain '*' and '**' wildcards.
+
+Only custom resources can be tainted. Component resources and providers are never replaced, so naming one
+is an error, and wildcards and --all skip them.
+
+To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
+		Example: "pulumi state taint 'urn:pulumi:dev::demo::aws:ec2/instance:Instance::web-*'",
+		Args:    cobra.ArbitraryArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runStateMarkCommand(commandContext(), stack, args, taintAll, yes,
+				edit.IsTaintable, edit.TaintResource, "tainted")
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVar(&taintAll, "all", false, "Taint all custom resources in the checkpoint")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+
+	return cmd
+}
+
+func newStateUntaintCommand() *cobra.Command {
+	var untaintAll bool
+	var stack string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "untaint [resource URN or glob...]",
+		Short: "Clear the taint of resources in a stack's state",
+		Long: `Clear the taint of resources in a stack's state
+
+This command clears the taint of one or more resources, so that they are no longer replaced on the next update.
+Resources are specified by their URNs, which may contain '*' and '**' wildcards.
+
+To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
+		Example: "pulumi state untaint 'urn:pulumi:dev::demo::aws:ec2/instance:Instance::web-*'",
+		Args:    cobra.ArbitraryArgs,
+		Run: cmdutil.RunFunc(func(cmd *cobra.Command, args []string) error {
+			return runStateMarkCommand(commandContext(), stack, args, untaintAll, yes,
+				nil, edit.UntaintResource, "untainted")
+		}),
+	}
+
+	cmd.PersistentFlags().StringVarP(
+		&stack, "stack", "s", "",
+		"The name of the stack to operate on. Defaults to the current stack")
+	cmd.Flags().BoolVar(&untaintAll, "all", false, "Untaint all resources in the checkpoint")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+
+	return cmd
+}
@@ -0,0 +1,117 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/backend"
+	"github.com/pulumi/pulumi/pkg/v3/backend/display"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/edit"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func TestSelectStackResources(t *testing.T) {
+	t.Parallel()
+
+	newState := func(typ tokens.Type, name tokens.QName) *resource.State {
+		return &resource.State{URN: resource.NewURN("dev", "proj", "", typ, name), Type: typ}
+	}
+	web1 := newState("aws:ec2/instance:Instance", "web-1")
+	web2 := newState("aws:ec2/instance:Instance", "web-2")
+	db := newState("aws:rds/instance:Instance", "db")
+	snap := &deploy.Snapshot{Resources: []*resource.State{web1, web2, db}}
+	opts := display.Options{}
+
+	all, err := selectStackResources(opts, snap, nil, true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{web1, web2, db}, all)
+
+	// Literal URNs and globs may be combined, and each resource is only selected once.
+	selected, err := selectStackResources(opts, snap, []string{
+		string(db.URN),
+		"urn:pulumi:dev::proj::aws:ec2/instance:Instance::web-*",
+		string(web1.URN),
+	}, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{db, web1, web2}, selected)
+
+	selected, err = selectStackResources(opts, snap, []string{"**::db"}, false, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{db}, selected)
+
+	_, err = selectStackResources(opts, snap, []string{"urn:pulumi:dev::proj::*::cache"}, false, nil)
+	assert.ErrorContains(t, err, `No resources match "urn:pulumi:dev::proj::*::cache"`)
+
+	_, err = selectStackResources(opts, snap, []string{"urn:pulumi:dev::proj::aws:s3/bucket:Bucket::logs"}, false, nil)
+	assert.ErrorContains(t, err, "No such resource")
+
+	// A filter applies to globs and all, but not to URNs.
+	isWeb := func(res *resource.State) bool { return res.Type == "aws:ec2/instance:Instance" }
+	selected, err = selectStackResources(opts, snap, nil, true, isWeb)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{web1, web2}, selected)
+
+	selected, err = selectStackResources(opts, snap, []string{"**", string(db.URN)}, false, isWeb)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{web1, web2, db}, selected)
+}
+
+func TestNewCLIError(t *testing.T) {
+	t.Parallel()
+
+	typ := tokens.Type("pkg:index:Comp")
+	parent := &resource.State{URN: resource.NewURN("dev", "proj", "", typ, "parent"), Type: typ}
+	child := &resource.State{URN: resource.NewURN("dev", "proj", typ, typ, "child"), Type: typ}
+
+	cause := edit.ResourceHasDependenciesError{Condemned: parent, Dependencies: []*resource.State{child}}
+	assert.Equal(t, apitype.CLIError{
+		Kind:       apitype.ResourceHasDependentsCLIError,
+		Message:    "can't be safely deleted",
+		URN:        string(parent.URN),
+		Dependents: []string{string(child.URN)},
+	}, newCLIError(cause, errors.New("can't be safely deleted")))
+
+	assert.Equal(t, apitype.CLIError{
+		Kind:    apitype.ResourceProtectedCLIError,
+		Message: "Can't delete protected resource",
+		URN:     string(parent.URN),
+	}, newCLIError(edit.ResourceProtectedError{Condemned: parent}, edit.ResourceProtectedError{Condemned: parent}))
+
+	notFound := fmt.Errorf("editing state: %w", resourceNotFoundError{urn: parent.URN})
+	assert.Equal(t, apitype.CLIError{
+		Kind:    apitype.ResourceNotFoundCLIError,
+		Message: notFound.Error(),
+		URN:     string(parent.URN),
+	}, newCLIError(notFound, notFound))
+
+	alreadyExists := &backend.StackAlreadyExistsError{StackName: "prod"}
+	assert.Equal(t, apitype.CLIError{
+		Kind:      apitype.StackAlreadyExistsCLIError,
+		Message:   "stack 'prod' already exists",
+		StackName: "prod",
+	}, newCLIError(alreadyExists, alreadyExists))
+
+	other := errors.New("something else")
+	assert.Equal(t, apitype.CLIError{Message: "something else"}, newCLIError(other, other))
+}
@@ -32,6 +32,7 @@ func newStateUnprotectCommand() *cobra.Command {
 	var unprotectAll bool
 	var stack string
 	var yes bool
+	var jsonOut bool
 
 	cmd := &cobra.Command{
 		Use:   "unprotect [resource URN]",
@@ -49,7 +50,7 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
 			showPrompt := !yes
 
 			if unprotectAll {
-				return unprotectAllResources(ctx, stack, showPrompt)
+				return reportCLIError(jsonOut, unprotectAllResources(ctx, stack, showPrompt))
 			}
 
 			var urn resource.URN
@@ -66,7 +67,7 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
 			} else {
 				urn = resource.URN(args[0])
 			}
-			return unprotectResource(ctx, stack, urn, showPrompt)
+			return reportCLIError(jsonOut, unprotectResource(ctx, stack, urn, showPrompt))
 		}),
 	}
 
@@ -75,6 +76,7 @@ To see the list of URNs in a stack, use ` + "`pulumi stack --show-urns`" + `.`,
 		"The name of the stack to operate on. Defaults to the current stack")
 	cmd.Flags().BoolVar(&unprotectAll, "all", false, "Unprotect all resources in the checkpoint")
 	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Skip confirmation prompts")
+	cmd.Flags().BoolVarP(&jsonOut, "json", "j", false, "Write a structured description of any error to stdout as JSON")
 
 	return cmd
 }
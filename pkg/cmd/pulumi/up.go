@@ -83,6 +83,8 @@ func newUpCmd() *cobra.Command {
 	var targetReplaces []string
 	var targetDependents bool
 	var planFilePath string
+	var planVerify bool
+	var locked bool
 
 	// up implementation used when the source of the Pulumi program is in the current working directory.
 	upWorkingDirectory := func(ctx context.Context, opts backend.UpdateOptions, cmd *cobra.Command) result.Result {
@@ -161,8 +163,15 @@ func newUpCmd() *cobra.Command {
 			// update phase.
 			GeneratePlan: true,
 			Experimental: hasExperimentalCommands(),
+			Locked:       locked,
 		}
 
+		lock, err := loadPluginLock(root, locked)
+		if err != nil {
+			return result.FromError(err)
+		}
+		opts.Engine.PluginLock = lock
+
 		if planFilePath != "" {
 			dec, err := sm.Decrypter()
 			if err != nil {
@@ -177,6 +186,7 @@ func newUpCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 			opts.Engine.Plan = plan
+			opts.Engine.VerifyPlan = planVerify
 		}
 
 		changes, res := s.Update(ctx, backend.UpdateOperation{
@@ -189,6 +199,12 @@ func newUpCmd() *cobra.Command {
 			SecretsProvider:    stack.DefaultSecretsProvider,
 			Scopes:             backend.CancellationScopes,
 		})
+		if res == nil && !locked {
+			// Record the plugins the update used, so that later deployments use exactly the same ones.
+			if err := savePluginLock(root, lock); err != nil {
+				return result.FromError(err)
+			}
+		}
 		switch {
 		case res != nil && res.Error() == context.Canceled:
 			return result.FromError(errors.New("update cancelled"))
@@ -450,6 +466,10 @@ func newUpCmd() *cobra.Command {
 				return result.FromError(err)
 			}
 
+			if planVerify && planFilePath == "" {
+				return result.FromError(errors.New("--plan-verify requires --plan"))
+			}
+
 			displayType := display.DisplayProgress
 			if diffDisplay {
 				displayType = display.DisplayDiff
@@ -561,6 +581,9 @@ func newUpCmd() *cobra.Command {
 	cmd.PersistentFlags().BoolVar(
 		&targetDependents, "target-dependents", false,
 		"Allows updating of dependent targets discovered but not specified in --target list")
+	cmd.PersistentFlags().BoolVar(
+		&locked, "locked", false,
+		"Fail if a plugin is missing from, or differs from, the project's Pulumi.lock file, rather than updating it")
 
 	// Flags for engine.UpdateOptions.
 	cmd.PersistentFlags().StringSliceVar(
@@ -621,8 +644,14 @@ func newUpCmd() *cobra.Command {
 		"[EXPERIMENTAL] Path to a plan file to use for the update. The update will not "+
 			"perform operations that exceed its plan (e.g. replacements instead of updates, or updates instead"+
 			"of sames).")
+	cmd.PersistentFlags().BoolVar(
+		&planVerify, "plan-verify", false,
+		"[EXPERIMENTAL] Report every deviation of the update from its plan at the end, rather than "+
+			"failing at the first deviation. Requires --plan")
 	if !hasExperimentalCommands() {
 		contract.AssertNoErrorf(cmd.PersistentFlags().MarkHidden("plan"), `Could not mark "plan" as hidden`)
+		contract.AssertNoErrorf(
+			cmd.PersistentFlags().MarkHidden("plan-verify"), `Could not mark "plan-verify" as hidden`)
 	}
 
 	// Remote flags
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/spf13/cobra"
 
@@ -41,6 +42,9 @@ func newWatchCmd() *cobra.Command {
 	var configArray []string
 	var pathArray []string
 	var configPath bool
+	var debounce time.Duration
+	var fullUpdates bool
+	var showLogs bool
 
 	// Flags for engine.UpdateOptions.
 	var policyPackPaths []string
@@ -62,6 +66,11 @@ func newWatchCmd() *cobra.Command {
 			"the active stack whenever the project changes.  In parallel, logs are collected for all resources\n" +
 			"in the stack and displayed along with update progress.\n" +
 			"\n" +
+			"Bursts of changes are coalesced into a single update. Each update is preceded by a preview that\n" +
+			"determines which resources have changed since the last successful update; only those resources\n" +
+			"and their dependents are updated. Pass `--full` to update the entire stack on every change instead.\n" +
+			"Failed updates are reported and watching continues.\n" +
+			"\n" +
 			"The program to watch is loaded from the project in the current directory by default. Use the `-C` or\n" +
 			"`--cwd` flag to use a different directory.",
 		Args: cmdutil.MaximumNArgs(1),
@@ -156,7 +165,12 @@ func newWatchCmd() *cobra.Command {
 				SecretsManager:     sm,
 				SecretsProvider:    stack.DefaultSecretsProvider,
 				Scopes:             backend.CancellationScopes,
-			}, pathArray)
+			}, backend.WatchOptions{
+				Paths:       pathArray,
+				Debounce:    debounce,
+				FullUpdates: fullUpdates,
+				ShowLogs:    showLogs,
+			})
 
 			switch {
 			case res != nil && res.Error() == context.Canceled:
@@ -173,6 +187,15 @@ func newWatchCmd() *cobra.Command {
 		&pathArray, "path", "", []string{""},
 		"Specify one or more relative or absolute paths that need to be watched. "+
 			"A path can point to a folder or a file. Defaults to working directory")
+	cmd.PersistentFlags().DurationVar(
+		&debounce, "debounce", 500*time.Millisecond,
+		"How long to wait for a burst of changes to settle before updating")
+	cmd.PersistentFlags().BoolVar(
+		&fullUpdates, "full", false,
+		"Update the entire stack on every change rather than only the resources that changed and their dependents")
+	cmd.PersistentFlags().BoolVar(
+		&showLogs, "logs", true,
+		"Display the logs of the stack's resources alongside update progress")
 	cmd.PersistentFlags().BoolVarP(
 		&debug, "debug", "d", false,
 		"Print detailed debugging output during resource operations")
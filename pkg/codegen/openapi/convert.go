@@ -0,0 +1,799 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+// Options controls the conversion of a Document into a package schema.
+type Options struct {
+	// Name is the name of the package. If empty, the name is derived from the title of the document.
+	Name string
+	// Version is the version of the package. If empty, the version of the document is used. The version is normalized
+	// to a semantic version, so e.g. "v1.2" becomes "1.2.0".
+	Version string
+	// Module is the module that contains the package's resources and types. Defaults to "index".
+	Module string
+}
+
+// GeneratePackageSpec converts an OpenAPI document into a Pulumi package schema.
+//
+// Each component schema that describes an object or an enum becomes a type. Each group of CRUD endpoints becomes a
+// resource: a collection path with a POST operation (e.g. `/pets`) and an item path with a GET operation (e.g.
+// `/pets/{petId}`) define a resource whose inputs are the properties of the create request body and whose outputs are
+// the properties of the read response body. If the item path has no PUT or PATCH operation, every input of the
+// resource forces a replacement. Path parameters of the collection path become required inputs of the resource.
+func GeneratePackageSpec(doc *Document, opts Options) (schema.PackageSpec, error) {
+	name := opts.Name
+	if name == "" {
+		name = packageName(doc.Info.Title)
+	}
+	if name == "" {
+		return schema.PackageSpec{}, errors.New("a package name is required: the document does not have a title")
+	}
+	version, err := packageVersion(doc, opts)
+	if err != nil {
+		return schema.PackageSpec{}, err
+	}
+	module := opts.Module
+	if module == "" {
+		module = "index"
+	}
+
+	c := &converter{
+		doc:        doc,
+		pkg:        name,
+		module:     module,
+		types:      map[string]schema.ComplexTypeSpec{},
+		resources:  map[string]schema.ResourceSpec{},
+		components: map[string]string{},
+		reserved:   map[string]bool{},
+		inline:     map[*Schema]string{},
+		used:       map[string]bool{},
+		resolving:  map[string]bool{},
+	}
+	if err := c.convert(); err != nil {
+		return schema.PackageSpec{}, err
+	}
+
+	return schema.PackageSpec{
+		Name:        name,
+		DisplayName: doc.Info.Title,
+		Version:     version,
+		Description: doc.Info.Description,
+		Types:       c.types,
+		Resources:   c.resources,
+	}, nil
+}
+
+type converter struct {
+	doc    *Document
+	pkg    string
+	module string
+
+	types     map[string]schema.ComplexTypeSpec
+	resources map[string]schema.ResourceSpec
+
+	// components maps the names of component schemas to the tokens of their types.
+	components map[string]string
+	// reserved records the tokens of resources and component types, which inline types must not use.
+	reserved map[string]bool
+	// inline maps inline object and enum schemas to the tokens of their types.
+	inline map[*Schema]string
+	// used records the names of the component schemas that are referenced by properties.
+	used map[string]bool
+	// resolving records the names of the component schemas whose references are currently being resolved by typeSpec.
+	resolving map[string]bool
+}
+
+// resourceEndpoints groups the operations that manage a single kind of resource.
+type resourceEndpoints struct {
+	collection string
+	create     *Operation
+	read       *Operation
+	update     *Operation
+	params     []*Parameter
+}
+
+func (c *converter) token(name string) string {
+	return fmt.Sprintf("%s:%s:%s", c.pkg, c.module, name)
+}
+
+func (c *converter) convert() error {
+	if err := c.checkRefs(); err != nil {
+		return err
+	}
+
+	endpoints := c.findResources()
+
+	// Name the resources first so that types can avoid their tokens.
+	resourceNames := map[string]*resourceEndpoints{}
+	bodies := map[string]bool{}
+	for _, e := range endpoints {
+		name := ""
+		if ref := refName(responseSchema(e.read)); ref != "" {
+			name = pascal(ref)
+			bodies[ref] = true
+		} else {
+			name = pascal(singular(lastStaticSegment(e.collection)))
+		}
+		if name == "" {
+			return fmt.Errorf("could not determine a resource name for path %q", e.collection)
+		}
+		if _, has := resourceNames[name]; has {
+			return fmt.Errorf("multiple endpoint groups map to the resource %v", c.token(name))
+		}
+		resourceNames[name] = e
+		c.reserved[c.token(name)] = true
+	}
+
+	for _, name := range sortedKeys(c.doc.Components.Schemas) {
+		s := c.resolve(c.doc.Components.Schemas[name])
+		if s == nil || !(isObject(s) || len(s.Enum) != 0) {
+			continue
+		}
+		typeName := pascal(name)
+		if _, isResource := resourceNames[typeName]; isResource {
+			typeName += "Properties"
+		}
+		c.components[name] = c.token(typeName)
+		c.reserved[c.token(typeName)] = true
+	}
+
+	for _, name := range sortedKeys(resourceNames) {
+		res, err := c.convertResource(name, resourceNames[name])
+		if err != nil {
+			return err
+		}
+		c.resources[c.token(name)] = res
+	}
+
+	for _, name := range sortedKeys(c.components) {
+		spec, err := c.convertComplexType(name, c.resolve(c.doc.Components.Schemas[name]))
+		if err != nil {
+			return fmt.Errorf("component %q: %w", name, err)
+		}
+		c.types[c.components[name]] = spec
+	}
+
+	// Drop the types of resource bodies that are not referenced by any property.
+	for name := range bodies {
+		if !c.used[name] {
+			delete(c.types, c.components[name])
+		}
+	}
+	return nil
+}
+
+// findResources finds the groups of CRUD endpoints in the document.
+func (c *converter) findResources() []*resourceEndpoints {
+	var result []*resourceEndpoints
+	for _, path := range sortedKeys(c.doc.Paths) {
+		item := c.doc.Paths[path]
+		slash := strings.LastIndex(path, "/")
+		if item == nil || slash <= 0 || !isPathParameter(path[slash+1:]) || item.Get == nil {
+			continue
+		}
+		collection, ok := c.doc.Paths[path[:slash]]
+		if !ok || collection == nil || collection.Post == nil {
+			continue
+		}
+
+		update := item.Put
+		if item.Patch != nil {
+			update = item.Patch
+		}
+
+		// Parameters of the collection path identify the resource's parent and are required inputs.
+		var params []*Parameter
+		for _, p := range append(append([]*Parameter{}, collection.Parameters...), collection.Post.Parameters...) {
+			if p.In == "path" {
+				params = append(params, p)
+			}
+		}
+
+		result = append(result, &resourceEndpoints{
+			collection: path[:slash],
+			create:     collection.Post,
+			read:       item.Get,
+			update:     update,
+			params:     params,
+		})
+	}
+	return result
+}
+
+func (c *converter) convertResource(name string, e *resourceEndpoints) (schema.ResourceSpec, error) {
+	inputSchema := c.mergeAllOf(c.resolve(requestSchema(e.create)))
+	outputSchema := c.mergeAllOf(c.resolve(responseSchema(e.read)))
+	if outputSchema == nil {
+		outputSchema = inputSchema
+	}
+
+	inputs, requiredInputs, err := c.convertProperties(name, inputSchema, func(s *Schema) bool { return !s.ReadOnly })
+	if err != nil {
+		return schema.ResourceSpec{}, fmt.Errorf("resource %v: %w", c.token(name), err)
+	}
+	outputs, required, err := c.convertProperties(name, outputSchema, func(s *Schema) bool { return !s.WriteOnly })
+	if err != nil {
+		return schema.ResourceSpec{}, fmt.Errorf("resource %v: %w", c.token(name), err)
+	}
+
+	// Every input is also an output.
+	for k, p := range inputs {
+		if _, has := outputs[k]; !has {
+			outputs[k] = p
+		}
+	}
+
+	for _, p := range e.params {
+		prop := schema.PropertySpec{
+			TypeSpec:             schema.TypeSpec{Type: "string"},
+			Description:          p.Description,
+			WillReplaceOnChanges: true,
+		}
+		if p.Schema != nil {
+			t, err := c.typeSpec(name+pascal(p.Name), p.Schema)
+			if err != nil {
+				return schema.ResourceSpec{}, fmt.Errorf("resource %v: parameter %q: %w", c.token(name), p.Name, err)
+			}
+			prop.TypeSpec = t
+		}
+		inputs[p.Name], outputs[p.Name] = prop, prop
+		requiredInputs, required = appendUnique(requiredInputs, p.Name), appendUnique(required, p.Name)
+	}
+
+	if e.update == nil {
+		for k, p := range inputs {
+			p.WillReplaceOnChanges = true
+			inputs[k] = p
+		}
+	}
+
+	description := ""
+	switch {
+	case outputSchema != nil && outputSchema.Description != "":
+		description = outputSchema.Description
+	case e.read.Description != "":
+		description = e.read.Description
+	default:
+		description = e.create.Summary
+	}
+
+	return schema.ResourceSpec{
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: description,
+			Properties:  outputs,
+			Required:    required,
+		},
+		InputProperties: inputs,
+		RequiredInputs:  requiredInputs,
+	}, nil
+}
+
+// convertProperties converts the properties of an object schema that satisfy the given filter. The properties `id`
+// and `urn` are reserved by Pulumi and are always skipped.
+func (c *converter) convertProperties(
+	parent string, s *Schema, include func(s *Schema) bool,
+) (map[string]schema.PropertySpec, []string, error) {
+	properties := map[string]schema.PropertySpec{}
+	if s == nil {
+		return properties, nil, nil
+	}
+
+	for _, k := range sortedKeys(s.Properties) {
+		prop := s.Properties[k]
+		if resolved := c.resolve(prop); k == "id" || k == "urn" || resolved != nil && !include(resolved) {
+			continue
+		}
+		spec, err := c.propertySpec(parent+pascal(k), prop)
+		if err != nil {
+			return nil, nil, fmt.Errorf("property %q: %w", k, err)
+		}
+		properties[k] = spec
+	}
+
+	var required []string
+	for _, k := range s.Required {
+		if _, has := properties[k]; has {
+			required = appendUnique(required, k)
+		}
+	}
+	return properties, required, nil
+}
+
+func (c *converter) propertySpec(name string, s *Schema) (schema.PropertySpec, error) {
+	t, err := c.typeSpec(name, s)
+	if err != nil {
+		return schema.PropertySpec{}, err
+	}
+
+	resolved := c.resolve(s)
+	if resolved == nil {
+		resolved = &Schema{}
+	}
+	spec := schema.PropertySpec{
+		TypeSpec:    t,
+		Description: resolved.Description,
+		Secret:      resolved.Format == "password",
+	}
+	if resolved.Deprecated {
+		spec.DeprecationMessage = "Deprecated"
+	}
+	if t.Ref == "" {
+		spec.Default = constValue(t.Type, resolved.Default)
+	}
+	return spec, nil
+}
+
+// typeSpec returns the type of the given schema. Inline objects and enums are given types named after the given name.
+func (c *converter) typeSpec(name string, s *Schema) (schema.TypeSpec, error) {
+	if s == nil {
+		return schema.TypeSpec{Ref: "pulumi.json#/Any"}, nil
+	}
+
+	if ref := refName(s); ref != "" {
+		if tok, ok := c.components[ref]; ok {
+			c.used[ref] = true
+			return schema.TypeSpec{Ref: "#/types/" + tok}, nil
+		}
+		target, ok := c.doc.Components.Schemas[ref]
+		if !ok {
+			return schema.TypeSpec{}, fmt.Errorf("unknown reference %q", s.Ref)
+		}
+		if c.resolving[ref] {
+			return schema.TypeSpec{}, fmt.Errorf("reference %q refers to itself", s.Ref)
+		}
+		c.resolving[ref] = true
+		defer delete(c.resolving, ref)
+		return c.typeSpec(name, target)
+	}
+
+	switch {
+	case len(s.AllOf) == 1 && len(s.Properties) == 0:
+		return c.typeSpec(name, s.AllOf[0])
+	case len(s.OneOf) != 0 || len(s.AnyOf) != 0:
+		var elements []schema.TypeSpec
+		for i, e := range append(append([]*Schema{}, s.OneOf...), s.AnyOf...) {
+			t, err := c.typeSpec(fmt.Sprintf("%s%d", name, i), e)
+			if err != nil {
+				return schema.TypeSpec{}, err
+			}
+			elements = append(elements, t)
+		}
+		if len(elements) == 1 {
+			return elements[0], nil
+		}
+		return schema.TypeSpec{OneOf: elements}, nil
+	}
+
+	if len(s.Enum) != 0 || isObject(s) {
+		if tok, ok := c.inline[s]; ok {
+			return schema.TypeSpec{Ref: "#/types/" + tok}, nil
+		}
+		tok := c.token(c.uniqueTypeName(name))
+		c.inline[s] = tok
+		// Reserve the token before converting the type so that nested types do not use it.
+		c.types[tok] = schema.ComplexTypeSpec{}
+		spec, err := c.convertComplexType(name, c.resolve(s))
+		if err != nil {
+			return schema.TypeSpec{}, err
+		}
+		c.types[tok] = spec
+		return schema.TypeSpec{Ref: "#/types/" + tok}, nil
+	}
+
+	switch s.Type {
+	case "string", "integer", "number", "boolean":
+		return schema.TypeSpec{Type: string(s.Type)}, nil
+	case "array":
+		items, err := c.typeSpec(name+"Item", s.Items)
+		if err != nil {
+			return schema.TypeSpec{}, err
+		}
+		return schema.TypeSpec{Type: "array", Items: &items}, nil
+	case "object":
+		if s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil {
+			values, err := c.typeSpec(name+"Value", s.AdditionalProperties.Schema)
+			if err != nil {
+				return schema.TypeSpec{}, err
+			}
+			return schema.TypeSpec{Type: "object", AdditionalProperties: &values}, nil
+		}
+	}
+	return schema.TypeSpec{Ref: "pulumi.json#/Any"}, nil
+}
+
+// uniqueTypeName returns a type name based on the given name that is not yet used by any type or resource.
+func (c *converter) uniqueTypeName(name string) string {
+	candidate := name
+	for i := 2; ; i++ {
+		tok := c.token(candidate)
+		if _, isType := c.types[tok]; !isType && !c.reserved[tok] {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%d", name, i)
+	}
+}
+
+// convertComplexType converts an object or enum schema into a type.
+func (c *converter) convertComplexType(name string, s *Schema) (schema.ComplexTypeSpec, error) {
+	if len(s.Enum) != 0 {
+		typ := string(s.Type)
+		switch typ {
+		case "string", "integer", "number", "boolean":
+			// OK
+		default:
+			typ = "string"
+		}
+
+		var values []schema.EnumValueSpec
+		for _, v := range s.Enum {
+			if v == nil {
+				continue
+			}
+			value := constValue(typ, v)
+			if value == nil {
+				return schema.ComplexTypeSpec{}, fmt.Errorf("enum value %v is not a valid %v", v, typ)
+			}
+			spec := schema.EnumValueSpec{Value: value}
+			if typ != "string" {
+				spec.Name = pascal(fmt.Sprintf("value %v", v))
+			}
+			values = append(values, spec)
+		}
+		return schema.ComplexTypeSpec{
+			ObjectTypeSpec: schema.ObjectTypeSpec{Description: s.Description, Type: typ},
+			Enum:           values,
+		}, nil
+	}
+
+	merged := c.mergeAllOf(s)
+	properties, required, err := c.convertProperties(name, merged, func(*Schema) bool { return true })
+	if err != nil {
+		return schema.ComplexTypeSpec{}, err
+	}
+	return schema.ComplexTypeSpec{
+		ObjectTypeSpec: schema.ObjectTypeSpec{
+			Description: s.Description,
+			Type:        "object",
+			Properties:  properties,
+			Required:    required,
+		},
+	}, nil
+}
+
+// mergeAllOf returns a schema that contains the properties of the given schema and each of its allOf elements.
+func (c *converter) mergeAllOf(s *Schema) *Schema {
+	if s == nil || len(s.AllOf) == 0 {
+		return s
+	}
+
+	merged := &Schema{Type: "object", Description: s.Description, Properties: map[string]*Schema{}}
+	for _, e := range append([]*Schema{s}, s.AllOf...) {
+		if e = c.resolve(e); e == nil {
+			continue
+		}
+		if e != s {
+			e = c.mergeAllOf(e)
+		}
+		for k, v := range e.Properties {
+			merged.Properties[k] = v
+		}
+		for _, k := range e.Required {
+			merged.Required = appendUnique(merged.Required, k)
+		}
+	}
+	return merged
+}
+
+// resolve follows references to component schemas.
+func (c *converter) resolve(s *Schema) *Schema {
+	for seen := 0; s != nil && s.Ref != "" && seen < 32; seen++ {
+		s = c.doc.Components.Schemas[refName(s)]
+	}
+	return s
+}
+
+// checkRefs returns an error if any schema in the document refers to a schema other than a component schema of the
+// document itself. External and relative references are not supported.
+func (c *converter) checkRefs() error {
+	seen := map[*Schema]bool{}
+	var check func(s *Schema) error
+	check = func(s *Schema) error {
+		if s == nil || seen[s] {
+			return nil
+		}
+		seen[s] = true
+
+		if s.Ref != "" {
+			name := refName(s)
+			if name == "" {
+				return fmt.Errorf("unsupported reference %q: only references to component schemas are supported", s.Ref)
+			}
+			if _, ok := c.doc.Components.Schemas[name]; !ok {
+				return fmt.Errorf("unknown reference %q: the document does not define the schema %q", s.Ref, name)
+			}
+		}
+
+		children := []*Schema{s.Items}
+		if s.AdditionalProperties != nil {
+			children = append(children, s.AdditionalProperties.Schema)
+		}
+		for _, k := range sortedKeys(s.Properties) {
+			children = append(children, s.Properties[k])
+		}
+		children = append(children, s.AllOf...)
+		children = append(children, s.OneOf...)
+		children = append(children, s.AnyOf...)
+		for _, child := range children {
+			if err := check(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	checkParams := func(params []*Parameter) error {
+		for _, p := range params {
+			if p != nil {
+				if err := check(p.Schema); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	checkContent := func(content map[string]*MediaType) error {
+		for _, k := range sortedKeys(content) {
+			if m := content[k]; m != nil {
+				if err := check(m.Schema); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	for _, name := range sortedKeys(c.doc.Components.Schemas) {
+		if err := check(c.doc.Components.Schemas[name]); err != nil {
+			return fmt.Errorf("schema %q: %w", name, err)
+		}
+	}
+	for _, path := range sortedKeys(c.doc.Paths) {
+		item := c.doc.Paths[path]
+		if item == nil {
+			continue
+		}
+		if err := checkParams(item.Parameters); err != nil {
+			return fmt.Errorf("path %q: %w", path, err)
+		}
+		for _, op := range []*Operation{item.Get, item.Put, item.Post, item.Patch, item.Delete} {
+			if op == nil {
+				continue
+			}
+			if err := checkParams(op.Parameters); err != nil {
+				return fmt.Errorf("path %q: %w", path, err)
+			}
+			if op.RequestBody != nil {
+				if err := checkContent(op.RequestBody.Content); err != nil {
+					return fmt.Errorf("path %q: %w", path, err)
+				}
+			}
+			for _, code := range sortedKeys(op.Responses) {
+				if r := op.Responses[code]; r != nil {
+					if err := checkContent(r.Content); err != nil {
+						return fmt.Errorf("path %q: %w", path, err)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// refName returns the name of the component schema referenced by the given schema, if any.
+func refName(s *Schema) string {
+	if s == nil {
+		return ""
+	}
+	for _, prefix := range []string{"#/components/schemas/", "#/definitions/", "#/$defs/"} {
+		if strings.HasPrefix(s.Ref, prefix) {
+			return s.Ref[len(prefix):]
+		}
+	}
+	return ""
+}
+
+// isObject returns true if the given schema describes an object with a fixed set of properties.
+func isObject(s *Schema) bool {
+	return len(s.Properties) != 0 || len(s.AllOf) > 1
+}
+
+// jsonSchema returns the schema of the JSON content of the given media types, if any.
+func jsonSchema(content map[string]*MediaType) *Schema {
+	for _, k := range sortedKeys(content) {
+		if strings.Contains(k, "json") && content[k] != nil {
+			return content[k].Schema
+		}
+	}
+	return nil
+}
+
+func requestSchema(op *Operation) *Schema {
+	if op == nil || op.RequestBody == nil {
+		return nil
+	}
+	return jsonSchema(op.RequestBody.Content)
+}
+
+func responseSchema(op *Operation) *Schema {
+	if op == nil {
+		return nil
+	}
+	for _, code := range []string{"200", "201", "2XX", "default"} {
+		if r, ok := op.Responses[code]; ok && r != nil {
+			if s := jsonSchema(r.Content); s != nil {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// constValue converts a value decoded from a document into a constant of the given primitive type, or nil if the
+// value is not of that type.
+func constValue(typ string, v interface{}) interface{} {
+	switch typ {
+	case "string":
+		if s, ok := v.(string); ok {
+			return s
+		}
+	case "boolean":
+		if b, ok := v.(bool); ok {
+			return b
+		}
+	case "integer":
+		switch v := v.(type) {
+		case int:
+			return v
+		case float64:
+			if v == float64(int(v)) {
+				return int(v)
+			}
+		}
+	case "number":
+		switch v := v.(type) {
+		case int:
+			return float64(v)
+		case float64:
+			return v
+		}
+	}
+	return nil
+}
+
+func isPathParameter(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
+
+// lastStaticSegment returns the last path segment that is not a parameter.
+func lastStaticSegment(path string) string {
+	segments := strings.Split(path, "/")
+	for i := len(segments) - 1; i >= 0; i-- {
+		if segments[i] != "" && !isPathParameter(segments[i]) {
+			return segments[i]
+		}
+	}
+	return ""
+}
+
+// singular makes a best-effort attempt to convert a plural English noun to its singular form.
+func singular(s string) string {
+	switch {
+	case strings.HasSuffix(s, "ies") && len(s) > 3:
+		return s[:len(s)-3] + "y"
+	case strings.HasSuffix(s, "sses"), strings.HasSuffix(s, "xes"), strings.HasSuffix(s, "ches"),
+		strings.HasSuffix(s, "shes"):
+		return s[:len(s)-2]
+	case strings.HasSuffix(s, "s") && !strings.HasSuffix(s, "ss"):
+		return s[:len(s)-1]
+	}
+	return s
+}
+
+// pascal converts a string to PascalCase, dropping any characters that are not letters or digits.
+func pascal(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	result := b.String()
+	if result != "" && unicode.IsDigit(rune(result[0])) {
+		result = "T" + result
+	}
+	return result
+}
+
+// packageVersion returns the version of the package as a semantic version. Versions that are close to semantic
+// versions (e.g. "v1" or "1.2") are normalized; other versions are rejected.
+func packageVersion(doc *Document, opts Options) (string, error) {
+	if opts.Version != "" {
+		v, err := semver.ParseTolerant(opts.Version)
+		if err != nil {
+			return "", fmt.Errorf("invalid package version %q: %w", opts.Version, err)
+		}
+		return v.String(), nil
+	}
+	if doc.Info.Version == "" {
+		return "", nil
+	}
+	v, err := semver.ParseTolerant(doc.Info.Version)
+	if err != nil {
+		return "", fmt.Errorf("the document's version %q is not a semantic version; specify a package version "+
+			"explicitly: %w", doc.Info.Version, err)
+	}
+	return v.String(), nil
+}
+
+// packageName converts a title into a package name.
+func packageName(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case b.Len() > 0 && !strings.HasSuffix(b.String(), "-"):
+			b.WriteRune('-')
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
+
+func appendUnique(list []string, s string) []string {
+	for _, e := range list {
+		if e == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,371 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openapi
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+)
+
+const petstore = `
+openapi: 3.0.3
+info:
+  title: Pet Store
+  description: A sample API.
+  version: 1.2.3
+paths:
+  /stores/{storeId}/pets:
+    parameters:
+      - name: storeId
+        in: path
+        required: true
+        schema:
+          type: string
+    post:
+      summary: Create a pet
+      requestBody:
+        content:
+          application/json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        "201":
+          description: Created
+  /stores/{storeId}/pets/{petId}:
+    get:
+      responses:
+        "200":
+          description: OK
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/Pet'
+    put:
+      responses:
+        "200":
+          description: OK
+    delete:
+      responses:
+        "204":
+          description: Deleted
+  /owners:
+    post:
+      summary: Create an owner
+      requestBody:
+        content:
+          application/json:
+            schema:
+              type: object
+              required: [name]
+              properties:
+                name:
+                  type: string
+                password:
+                  type: string
+                  format: password
+      responses:
+        "201":
+          description: Created
+  /owners/{ownerId}:
+    get:
+      responses:
+        "200":
+          description: OK
+components:
+  schemas:
+    Pet:
+      description: A pet.
+      type: object
+      required: [name]
+      properties:
+        id:
+          type: string
+          readOnly: true
+        name:
+          type: string
+          description: The pet's name.
+        status:
+          $ref: '#/components/schemas/Status'
+        tags:
+          type: array
+          items:
+            type: object
+            properties:
+              key:
+                type: string
+        labels:
+          type: object
+          additionalProperties:
+            type: string
+        createdAt:
+          type: string
+          readOnly: true
+    Status:
+      type: string
+      enum: [available, sold]
+    Priority:
+      type: integer
+      enum: [1, 2]
+`
+
+func TestGeneratePackageSpec(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load([]byte(petstore))
+	require.NoError(t, err)
+
+	spec, err := GeneratePackageSpec(doc, Options{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "pet-store", spec.Name)
+	assert.Equal(t, "1.2.3", spec.Version)
+	assert.Equal(t, "A sample API.", spec.Description)
+
+	// The Pet component is the body of the Pet resource and is not referenced elsewhere, so it is not a type.
+	assert.ElementsMatch(t, []string{
+		"pet-store:index:Status",
+		"pet-store:index:Priority",
+		"pet-store:index:PetTagsItem",
+	}, keys(spec.Types))
+	assert.Equal(t, []schema.EnumValueSpec{{Value: "available"}, {Value: "sold"}},
+		spec.Types["pet-store:index:Status"].Enum)
+	assert.Equal(t, []schema.EnumValueSpec{{Name: "Value1", Value: 1}, {Name: "Value2", Value: 2}},
+		spec.Types["pet-store:index:Priority"].Enum)
+
+	require.Contains(t, spec.Resources, "pet-store:index:Pet")
+	pet := spec.Resources["pet-store:index:Pet"]
+	assert.Equal(t, "A pet.", pet.Description)
+	assert.ElementsMatch(t, []string{"name", "status", "tags", "labels", "storeId"}, keys(pet.InputProperties))
+	assert.ElementsMatch(t, []string{"name", "storeId"}, pet.RequiredInputs)
+	assert.ElementsMatch(t, []string{"name", "status", "tags", "labels", "createdAt", "storeId"}, keys(pet.Properties))
+	assert.Equal(t, "#/types/pet-store:index:Status", pet.InputProperties["status"].Ref)
+	assert.Equal(t, "#/types/pet-store:index:PetTagsItem", pet.InputProperties["tags"].Items.Ref)
+	assert.Equal(t, "string", pet.InputProperties["labels"].AdditionalProperties.Type)
+	// The pet can be updated, so only the parent's ID forces a replacement.
+	assert.False(t, pet.InputProperties["name"].WillReplaceOnChanges)
+	assert.True(t, pet.InputProperties["storeId"].WillReplaceOnChanges)
+
+	// Resources without named bodies are named after their paths.
+	require.Contains(t, spec.Resources, "pet-store:index:Owner")
+	owner := spec.Resources["pet-store:index:Owner"]
+	assert.Equal(t, "Create an owner", owner.Description)
+	assert.Equal(t, []string{"name"}, owner.RequiredInputs)
+	assert.True(t, owner.InputProperties["password"].Secret)
+	// The owner cannot be updated, so every input forces a replacement.
+	assert.True(t, owner.InputProperties["name"].WillReplaceOnChanges)
+
+	// The generated schema must bind.
+	_, diags, err := schema.BindSpec(spec, nil)
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors(), "%v", diags)
+}
+
+func TestGeneratePackageSpecNameCollision(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load([]byte(`
+openapi: 3.1.0
+info: {title: Things}
+paths:
+  /things:
+    post:
+      requestBody:
+        content:
+          application/json:
+            schema: {$ref: '#/components/schemas/Thing'}
+  /things/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema: {$ref: '#/components/schemas/Thing'}
+components:
+  schemas:
+    Thing:
+      type: object
+      properties:
+        parent: {$ref: '#/components/schemas/Thing'}
+        size: {type: [integer, "null"], default: 3}
+`))
+	require.NoError(t, err)
+
+	spec, err := GeneratePackageSpec(doc, Options{Name: "acme", Module: "things"})
+	require.NoError(t, err)
+
+	// The Thing component is referenced by a property, so it is kept under a name that does not collide with the
+	// resource.
+	require.Contains(t, spec.Resources, "acme:things:Thing")
+	require.Contains(t, spec.Types, "acme:things:ThingProperties")
+	thing := spec.Resources["acme:things:Thing"]
+	assert.Equal(t, "#/types/acme:things:ThingProperties", thing.InputProperties["parent"].Ref)
+	assert.Equal(t, "integer", thing.InputProperties["size"].Type)
+	assert.Equal(t, 3, thing.InputProperties["size"].Default)
+
+	_, diags, err := schema.BindSpec(spec, nil)
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors(), "%v", diags)
+}
+
+func TestLoadJSONSchema(t *testing.T) {
+	t.Parallel()
+
+	doc, err := LoadJSONSchema([]byte(`{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title": "Config",
+		"type": "object",
+		"properties": {
+			"endpoint": {"$ref": "#/$defs/endpoint"},
+			"retries": {"type": "integer"}
+		},
+		"$defs": {
+			"endpoint": {
+				"type": "object",
+				"required": ["url"],
+				"properties": {"url": {"type": "string"}}
+			}
+		}
+	}`))
+	require.NoError(t, err)
+
+	spec, err := GeneratePackageSpec(doc, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "config", spec.Name)
+	assert.Empty(t, spec.Resources)
+	assert.ElementsMatch(t, []string{"config:index:Config", "config:index:Endpoint"}, keys(spec.Types))
+	assert.Equal(t, "#/types/config:index:Endpoint", spec.Types["config:index:Config"].Properties["endpoint"].Ref)
+	assert.Equal(t, []string{"url"}, spec.Types["config:index:Endpoint"].Required)
+
+	_, diags, err := schema.BindSpec(spec, nil)
+	require.NoError(t, err)
+	assert.False(t, diags.HasErrors(), "%v", diags)
+}
+
+func TestLoadErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := Load([]byte(`swagger: "2.0"`))
+	assert.ErrorContains(t, err, "not an OpenAPI document")
+
+	_, err = Load([]byte(`openapi: 2.0.0`))
+	assert.ErrorContains(t, err, "unsupported OpenAPI version")
+
+	_, err = LoadJSONSchema([]byte(`{"type": "string"}`))
+	assert.ErrorContains(t, err, "does not define any types")
+}
+
+func TestGeneratePackageSpecUnsupportedRefs(t *testing.T) {
+	t.Parallel()
+
+	refs := []string{"other.yaml#/Pet", "https://example.com/schemas.json#/Pet", "#/components/schemas/Missing"}
+	for _, ref := range refs {
+		ref := ref
+		t.Run(ref, func(t *testing.T) {
+			t.Parallel()
+
+			doc, err := Load([]byte(`openapi: 3.0.0
+info: {title: test}
+components:
+  schemas:
+    Owner:
+      type: object
+      properties:
+        pet: {$ref: "` + ref + `"}
+`))
+			require.NoError(t, err)
+
+			_, err = GeneratePackageSpec(doc, Options{})
+			assert.ErrorContains(t, err, ref)
+		})
+	}
+}
+
+func TestGeneratePackageSpecNullPathItem(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load([]byte(`openapi: 3.0.0
+info: {title: test}
+paths:
+  /pets:
+  /pets/{petId}:
+components:
+  schemas:
+    Pet: {type: object}
+`))
+	require.NoError(t, err)
+
+	spec, err := GeneratePackageSpec(doc, Options{})
+	require.NoError(t, err)
+	assert.Empty(t, spec.Resources)
+}
+
+func TestGeneratePackageSpecAliasCycle(t *testing.T) {
+	t.Parallel()
+
+	doc, err := Load([]byte(`openapi: 3.0.0
+info: {title: test}
+components:
+  schemas:
+    A: {$ref: "#/components/schemas/B"}
+    B:
+      type: array
+      items: {$ref: "#/components/schemas/A"}
+    Owner:
+      type: object
+      properties:
+        pets: {$ref: "#/components/schemas/A"}
+`))
+	require.NoError(t, err)
+
+	_, err = GeneratePackageSpec(doc, Options{})
+	assert.ErrorContains(t, err, "refers to itself")
+}
+
+func TestGeneratePackageSpecVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		doc, opt, expected, err string
+	}{
+		{doc: "", expected: ""},
+		{doc: "1.2.3", expected: "1.2.3"},
+		{doc: "v2", expected: "2.0.0"},
+		{doc: "1.0", expected: "1.0.0"},
+		{doc: "2021-01-01", err: "not a semantic version"},
+		{doc: "2021-01-01", opt: "0.1", expected: "0.1.0"},
+		{opt: "latest", err: "invalid package version"},
+	}
+	for _, c := range cases {
+		doc := &Document{
+			Info:       Info{Title: "test", Version: c.doc},
+			Components: Components{Schemas: map[string]*Schema{"Pet": {Type: "object"}}},
+		}
+		spec, err := GeneratePackageSpec(doc, Options{Version: c.opt})
+		if c.err != "" {
+			assert.ErrorContains(t, err, c.err)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, c.expected, spec.Version)
+	}
+}
+
+func keys[T any](m map[string]T) []string {
+	return sortedKeys(m)
+}
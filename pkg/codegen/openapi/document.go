@@ -0,0 +1,201 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package openapi converts OpenAPI 3 and JSON Schema documents into Pulumi package schemas.
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is the subset of an OpenAPI 3 document that is used to generate a Pulumi package schema. JSON Schema
+// documents are represented as documents with no paths whose component schemas are the schema's definitions.
+type Document struct {
+	OpenAPI    string               `yaml:"openapi"`
+	Info       Info                 `yaml:"info"`
+	Paths      map[string]*PathItem `yaml:"paths"`
+	Components Components           `yaml:"components"`
+}
+
+// Info describes the API.
+type Info struct {
+	Title       string `yaml:"title"`
+	Description string `yaml:"description"`
+	Version     string `yaml:"version"`
+}
+
+// Components holds the reusable schemas of an API.
+type Components struct {
+	Schemas map[string]*Schema `yaml:"schemas"`
+}
+
+// PathItem describes the operations available on a single path.
+type PathItem struct {
+	Parameters []*Parameter `yaml:"parameters"`
+	Get        *Operation   `yaml:"get"`
+	Put        *Operation   `yaml:"put"`
+	Post       *Operation   `yaml:"post"`
+	Patch      *Operation   `yaml:"patch"`
+	Delete     *Operation   `yaml:"delete"`
+}
+
+// Operation describes a single API operation on a path.
+type Operation struct {
+	OperationID string               `yaml:"operationId"`
+	Summary     string               `yaml:"summary"`
+	Description string               `yaml:"description"`
+	Parameters  []*Parameter         `yaml:"parameters"`
+	RequestBody *RequestBody         `yaml:"requestBody"`
+	Responses   map[string]*Response `yaml:"responses"`
+}
+
+// Parameter describes a single operation parameter.
+type Parameter struct {
+	Name        string  `yaml:"name"`
+	In          string  `yaml:"in"`
+	Description string  `yaml:"description"`
+	Required    bool    `yaml:"required"`
+	Schema      *Schema `yaml:"schema"`
+}
+
+// RequestBody describes the body of a request.
+type RequestBody struct {
+	Description string                `yaml:"description"`
+	Required    bool                  `yaml:"required"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// Response describes a single response from an API operation.
+type Response struct {
+	Description string                `yaml:"description"`
+	Content     map[string]*MediaType `yaml:"content"`
+}
+
+// MediaType describes the schema of a request or response body.
+type MediaType struct {
+	Schema *Schema `yaml:"schema"`
+}
+
+// Schema is the subset of a JSON Schema object that is used to generate Pulumi types.
+type Schema struct {
+	Ref                  string                `yaml:"$ref"`
+	Type                 SchemaType            `yaml:"type"`
+	Format               string                `yaml:"format"`
+	Title                string                `yaml:"title"`
+	Description          string                `yaml:"description"`
+	Properties           map[string]*Schema    `yaml:"properties"`
+	Required             []string              `yaml:"required"`
+	Items                *Schema               `yaml:"items"`
+	AdditionalProperties *AdditionalProperties `yaml:"additionalProperties"`
+	Enum                 []interface{}         `yaml:"enum"`
+	AllOf                []*Schema             `yaml:"allOf"`
+	OneOf                []*Schema             `yaml:"oneOf"`
+	AnyOf                []*Schema             `yaml:"anyOf"`
+	Default              interface{}           `yaml:"default"`
+	ReadOnly             bool                  `yaml:"readOnly"`
+	WriteOnly            bool                  `yaml:"writeOnly"`
+	Deprecated           bool                  `yaml:"deprecated"`
+
+	// Defs and Definitions hold the definitions of a JSON Schema document.
+	Defs        map[string]*Schema `yaml:"$defs"`
+	Definitions map[string]*Schema `yaml:"definitions"`
+}
+
+// SchemaType is the type of a schema. OpenAPI 3.1 and JSON Schema allow a list of types, of which only the first
+// non-null type is used.
+type SchemaType string
+
+func (t *SchemaType) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		*t = SchemaType(node.Value)
+		return nil
+	case yaml.SequenceNode:
+		var types []string
+		if err := node.Decode(&types); err != nil {
+			return err
+		}
+		for _, typ := range types {
+			if typ != "null" {
+				*t = SchemaType(typ)
+				break
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("line %d: expected a string or a list of strings for type", node.Line)
+	}
+}
+
+// AdditionalProperties is the value of a schema's additionalProperties, which may be either a boolean or a schema.
+type AdditionalProperties struct {
+	Allowed bool
+	Schema  *Schema
+}
+
+func (a *AdditionalProperties) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return node.Decode(&a.Allowed)
+	}
+	a.Allowed = true
+	return node.Decode(&a.Schema)
+}
+
+// Load parses an OpenAPI 3 document in either JSON or YAML format.
+func Load(data []byte) (*Document, error) {
+	var doc Document
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.OpenAPI == "" {
+		return nil, errors.New("not an OpenAPI document: missing 'openapi' version")
+	}
+	if !strings.HasPrefix(doc.OpenAPI, "3.") {
+		return nil, fmt.Errorf("unsupported OpenAPI version %q: only OpenAPI 3 documents are supported", doc.OpenAPI)
+	}
+	return &doc, nil
+}
+
+// LoadJSONSchema parses a JSON Schema document in either JSON or YAML format. The schema's definitions become the
+// component schemas of the returned document. If the root schema has a title and describes an object, it is included
+// as a component as well.
+func LoadJSONSchema(data []byte) (*Document, error) {
+	var root Schema
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, err
+	}
+
+	schemas := map[string]*Schema{}
+	for name, s := range root.Definitions {
+		schemas[name] = s
+	}
+	for name, s := range root.Defs {
+		schemas[name] = s
+	}
+	if root.Title != "" && (root.Type == "object" || len(root.Properties) != 0) {
+		schemas[root.Title] = &root
+	}
+	if len(schemas) == 0 {
+		return nil, errors.New("the JSON schema does not define any types")
+	}
+
+	return &Document{
+		Info:       Info{Title: root.Title, Description: root.Description},
+		Components: Components{Schemas: schemas},
+	}, nil
+}
@@ -314,6 +314,7 @@ func (b *binder) declareNodes(file *syntax.File) (hcl.Diagnostics, error) {
 			case "resource":
 				if len(item.Labels) != 2 {
 					diagnostics = append(diagnostics, labelsErrorf(item, "resource variables must have exactly two labels"))
+					continue
 				}
 
 				resource := &Resource{
@@ -342,7 +343,7 @@ func (b *binder) declareNodes(file *syntax.File) (hcl.Diagnostics, error) {
 		case *hclsyntax.Block:
 			switch item.Type {
 			case "config":
-				name, typ := "<unnamed>", model.Type(model.DynamicType)
+				name, typ := "", model.Type(model.DynamicType)
 				switch len(item.Labels) {
 				case 1:
 					name = item.Labels[0]
@@ -373,6 +374,7 @@ func (b *binder) declareNodes(file *syntax.File) (hcl.Diagnostics, error) {
 					}
 				default:
 					diagnostics = append(diagnostics, labelsErrorf(item, "config variables must have exactly one or two labels"))
+					continue
 				}
 
 				// TODO(pdg): check body for valid contents
@@ -388,7 +390,7 @@ func (b *binder) declareNodes(file *syntax.File) (hcl.Diagnostics, error) {
 					return nil, err
 				}
 			case "output":
-				name, typ := "<unnamed>", model.Type(model.DynamicType)
+				name, typ := "", model.Type(model.DynamicType)
 				switch len(item.Labels) {
 				case 1:
 					name = item.Labels[0]
@@ -397,9 +399,13 @@ func (b *binder) declareNodes(file *syntax.File) (hcl.Diagnostics, error) {
 
 					typeExpr, diags := model.BindExpressionText(item.Labels[1], model.TypeScope, item.LabelRanges[1].Start)
 					diagnostics = append(diagnostics, diags...)
+					if typeExpr == nil {
+						return diagnostics, fmt.Errorf("cannot bind expression: %v", diagnostics.Error())
+					}
 					typ = typeExpr.Type()
 				default:
-					diagnostics = append(diagnostics, labelsErrorf(item, "config variables must have exactly one or two labels"))
+					diagnostics = append(diagnostics, labelsErrorf(item, "output variables must have exactly one or two labels"))
+					continue
 				}
 
 				v := &OutputVariable{
@@ -547,3 +547,19 @@ resource randomPet "random:index/randomPet:RandomPet" {
 	assert.Equal(t, 2, len(diags), "There are two diagnostics")
 	assert.Nil(t, strictProgram)
 }
+
+func TestBindingBlocksWithMissingLabels(t *testing.T) {
+	t.Parallel()
+	source := `
+resource onlyName { }
+config { }
+output { }
+`
+	program, diags, err := ParseAndBindProgram(t, source, "prog.pp")
+	assert.Error(t, err)
+	assert.Nil(t, program)
+	require.Len(t, diags, 3)
+	assert.Equal(t, "resource variables must have exactly two labels", diags[0].Summary)
+	assert.Equal(t, "config variables must have exactly one or two labels", diags[1].Summary)
+	assert.Equal(t, "output variables must have exactly one or two labels", diags[2].Summary)
+}
@@ -23,6 +23,10 @@ func diagf(severity hcl.DiagnosticSeverity, subject hcl.Range, f string, args ..
 }
 
 func labelsErrorf(block *hclsyntax.Block, f string, args ...interface{}) *hcl.Diagnostic {
+	if len(block.LabelRanges) == 0 {
+		// Point at the block's type if it has no labels.
+		return errorf(block.TypeRange, f, args...)
+	}
 	startRange := block.LabelRanges[0]
 
 	diagRange := hcl.Range{
@@ -183,6 +183,11 @@ func newDeployment(ctx *Context, info *deploymentContext, opts *deploymentOption
 		return nil, err
 	}
 
+	plugctx.Env = opts.Env
+	if opts.LanguageRuntime != nil {
+		plugctx.Host = &clientLanguageRuntimeHost{Host: plugctx.Host, languageRuntime: opts.LanguageRuntime}
+	}
+
 	// Keep the plugin context open until the context is terminated, to allow for graceful provider cancellation.
 	plugctx = plugctx.WithCancelChannel(ctx.Cancel.Terminated())
 
@@ -214,7 +219,7 @@ func newDeployment(ctx *Context, info *deploymentContext, opts *deploymentOption
 			localPolicyPackPaths, dryRun, ctx.BackendClient)
 	} else {
 		_, defaultProviderInfo, pluginErr := installPlugins(cancelCtx, proj, pwd, main, target, plugctx,
-			false /*returnInstallErrors*/)
+			&opts.Events, opts.PluginLock, opts.Locked, false /*returnInstallErrors*/)
 		if pluginErr != nil {
 			return nil, pluginErr
 		}
@@ -310,6 +315,8 @@ func (deployment *deployment) run(cancelCtx *Context, actions runActions,
 			DisableResourceReferences: deployment.Options.DisableResourceReferences,
 			DisableOutputValues:       deployment.Options.DisableOutputValues,
 			GeneratePlan:              deployment.Options.UpdateOptions.GeneratePlan,
+			VerifyPlan:                deployment.Options.UpdateOptions.VerifyPlan,
+			Retry:                     deployment.Options.Retry,
 		}
 		newPlan, walkError = deployment.Deployment.Execute(ctx, opts, preview)
 		close(done)
@@ -54,6 +54,8 @@ func NewEvent(typ EventType, payload interface{}) Event {
 		_, ok = payload.(SummaryEventPayload)
 	case ResourcePreEvent:
 		_, ok = payload.(ResourcePreEventPayload)
+	case ResourceWaitingEvent:
+		_, ok = payload.(ResourceWaitingEventPayload)
 	case ResourceOutputsEvent:
 		_, ok = payload.(ResourceOutputsEventPayload)
 	case ResourceOperationFailed:
@@ -62,6 +64,8 @@ func NewEvent(typ EventType, payload interface{}) Event {
 		_, ok = payload.(PolicyViolationEventPayload)
 	case PolicyRemediationEvent:
 		_, ok = payload.(PolicyRemediationEventPayload)
+	case ProgressEvent:
+		_, ok = payload.(ProgressEventPayload)
 	default:
 		contract.Failf("unknown event type %v", typ)
 	}
@@ -82,10 +86,12 @@ const (
 	PreludeEvent            EventType = "prelude"
 	SummaryEvent            EventType = "summary"
 	ResourcePreEvent        EventType = "resource-pre"
+	ResourceWaitingEvent    EventType = "resource-waiting"
 	ResourceOutputsEvent    EventType = "resource-outputs"
 	ResourceOperationFailed EventType = "resource-operationfailed"
 	PolicyViolationEvent    EventType = "policy-violation"
 	PolicyRemediationEvent  EventType = "policy-remediation"
+	ProgressEvent           EventType = "progress"
 )
 
 func (e Event) Payload() interface{} {
@@ -130,6 +136,16 @@ type PolicyRemediationEventPayload struct {
 	After             resource.PropertyMap
 }
 
+// ProgressEventPayload is the payload for an event with type `progress`.
+type ProgressEventPayload struct {
+	Type      apitype.ProgressType // the kind of work being reported on
+	ID        string               // identifies the work, so that its progress events can be grouped
+	Message   string               // describes the work, e.g. "Downloading plugin aws-6.0.0"
+	Completed int64                // the amount of work done, e.g. the number of bytes downloaded
+	Total     int64                // the total amount of work, or 0 if it's unknown
+	Done      bool                 // true when the work has finished
+}
+
 type StdoutEventPayload struct {
 	Message string
 	Color   colors.Colorization
@@ -166,6 +182,14 @@ type ResourcePreEventPayload struct {
 	Debug    bool
 }
 
+// ResourceWaitingEventPayload is the payload for an event with type `resource-waiting`, which is sent when a step
+// has to wait for a per-provider or per-type parallelism limit before it can run.
+type ResourceWaitingEventPayload struct {
+	Metadata StepEventMetadata
+	Planning bool
+	Debug    bool
+}
+
 // StepEventMetadata contains the metadata associated with a step the engine is performing.
 type StepEventMetadata struct {
 	Op           display.StepOp                 // the operation performed by this step.
@@ -179,6 +203,8 @@ type StepEventMetadata struct {
 	DetailedDiff map[string]plugin.PropertyDiff // the rich, structured diff
 	Logical      bool                           // true if this step represents a logical operation in the program.
 	Provider     string                         // the provider that performed this step.
+
+	IgnoredChanges []deploy.IgnoredChange // the changes to the resource's inputs suppressed by ignoreChanges rules.
 }
 
 // StepEventStateMetadata contains detailed metadata about a resource's state pertaining to a given step.
@@ -324,6 +350,11 @@ func makeStepEventMetadata(op display.StepOp, step deploy.Step, debug bool) Step
 		detailedDiff = detailedDiffer.DetailedDiff()
 	}
 
+	var ignoredChanges []deploy.IgnoredChange
+	if ignorer, hasIgnoredChanges := step.(interface{ IgnoredChanges() []deploy.IgnoredChange }); hasIgnoredChanges {
+		ignoredChanges = ignorer.IgnoredChanges()
+	}
+
 	return StepEventMetadata{
 		Op:           op,
 		URN:          step.URN(),
@@ -336,6 +367,8 @@ func makeStepEventMetadata(op display.StepOp, step deploy.Step, debug bool) Step
 		Res:          makeStepEventStateMetadata(step.Res(), debug),
 		Logical:      step.Logical(),
 		Provider:     step.Provider(),
+
+		IgnoredChanges: ignoredChanges,
 	}
 }
 
@@ -404,6 +437,18 @@ func (e *eventEmitter) resourcePreEvent(
 	}))
 }
 
+func (e *eventEmitter) resourceWaitingEvent(
+	step deploy.Step, planning bool, debug bool,
+) {
+	contract.Requiref(e != nil, "e", "!= nil")
+
+	e.sendEvent(NewEvent(ResourceWaitingEvent, ResourceWaitingEventPayload{
+		Metadata: makeStepEventMetadata(step.Op(), step, debug),
+		Planning: planning,
+		Debug:    debug,
+	}))
+}
+
 func (e *eventEmitter) preludeEvent(isPreview bool, cfg config.Map) {
 	contract.Requiref(e != nil, "e", "!= nil")
 
@@ -526,6 +571,12 @@ func (e *eventEmitter) diagWarningEvent(d *diag.Diag, prefix, msg string, epheme
 	diagEvent(e, d, prefix, msg, diag.Warning, ephemeral)
 }
 
+func (e *eventEmitter) progressEvent(payload ProgressEventPayload) {
+	contract.Requiref(e != nil, "e", "!= nil")
+
+	e.sendEvent(NewEvent(ProgressEvent, payload))
+}
+
 func filterResourceProperties(m resource.PropertyMap, debug bool) resource.PropertyMap {
 	return filterPropertyValue(resource.NewObjectProperty(m), debug).ObjectValue()
 }
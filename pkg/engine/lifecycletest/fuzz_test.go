@@ -0,0 +1,425 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"pgregory.net/rapid"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+// This file contains a property-based harness for the engine. Each check generates a random program, a sequence of
+// random changes to it, and a random operation to run after each change, injecting provider failures and
+// cancellations along the way. After every operation, the snapshot built from the operation's journal must pass
+// VerifyIntegrity, must match the last snapshot saved by a backend snapshot manager, and must agree with the program
+// where the operation succeeded. rapid shrinks failing checks to a minimal sequence of programs and operations.
+// TestMain lowers the number of checks to keep the test fast; run with -rapid.checks=N to run more.
+
+var errInjected = errors.New("injected failure")
+
+// fuzzResource is a resource registered by a generated program.
+type fuzzResource struct {
+	Name   string
+	Type   tokens.Type
+	Parent string   // The name of an earlier resource that is this resource's parent, if any.
+	Deps   []string // The names of earlier resources that this resource depends on.
+	Value  int      // An input that is updated in place when it changes.
+	Key    int      // An input that causes the resource to be replaced when it changes.
+	DBR    bool     // Whether the resource is deleted before it is replaced.
+}
+
+// fuzzStep is an operation to run against a generated program.
+type fuzzStep struct {
+	Op        string
+	Program   []fuzzResource
+	Failures  map[string]bool // The names of resources whose creates, updates and deletes fail.
+	CancelAt  int             // If non-zero, the operation is cancelled at this provider call.
+	ReadGones map[string]bool // The names of resources that no longer exist when they are refreshed.
+}
+
+func (s fuzzStep) injectsFailures() bool {
+	return len(s.Failures) > 0 || s.CancelAt != 0 || len(s.ReadGones) > 0
+}
+
+var fuzzTypes = []tokens.Type{"pkgA:m:typA", "pkgA:m:typB"}
+
+// fuzzResourceGen generates a new resource named name whose parent and dependencies are drawn from earlier.
+func fuzzResourceGen(name string, earlier []fuzzResource) *rapid.Generator[fuzzResource] {
+	return rapid.Custom(func(t *rapid.T) fuzzResource {
+		res := fuzzResource{
+			Name:  name,
+			Type:  rapid.SampledFrom(fuzzTypes).Draw(t, "type"),
+			Value: rapid.IntRange(0, 2).Draw(t, "value"),
+			Key:   rapid.IntRange(0, 1).Draw(t, "key"),
+			DBR:   rapid.Bool().Draw(t, "dbr"),
+		}
+		if len(earlier) > 0 {
+			names := make([]string, len(earlier))
+			for i, r := range earlier {
+				names[i] = r.Name
+			}
+			if rapid.Bool().Draw(t, "hasParent") {
+				res.Parent = rapid.SampledFrom(names).Draw(t, "parent")
+			}
+			res.Deps = rapid.SliceOfDistinct(rapid.SampledFrom(names), rapid.ID[string]).Draw(t, "deps")
+		}
+		return res
+	})
+}
+
+// fuzzProgramGen generates a program by randomly changing the given one: resources may be removed, have their inputs
+// and options changed, or be added.
+func fuzzProgramGen(prev []fuzzResource, nextName *int) *rapid.Generator[[]fuzzResource] {
+	return rapid.Custom(func(t *rapid.T) []fuzzResource {
+		var program []fuzzResource
+		for _, res := range prev {
+			if rapid.IntRange(0, 4).Draw(t, "remove") == 0 {
+				continue
+			}
+			if rapid.Bool().Draw(t, "change") {
+				res.Value = rapid.IntRange(0, 2).Draw(t, "value")
+				res.Key = rapid.IntRange(0, 1).Draw(t, "key")
+				res.DBR = rapid.Bool().Draw(t, "dbr")
+			}
+			program = append(program, res)
+		}
+		adds := rapid.IntRange(0, 3).Draw(t, "adds")
+		for i := 0; i < adds; i++ {
+			*nextName++
+			program = append(program, fuzzResourceGen(fmt.Sprintf("res%d", *nextName), program).Draw(t, "resource"))
+		}
+
+		// Drop references to resources that are no longer registered before the resources that refer to them.
+		seen := map[string]bool{}
+		for i := range program {
+			res := &program[i]
+			if !seen[res.Parent] {
+				res.Parent = ""
+			}
+			var deps []string
+			for _, dep := range res.Deps {
+				if seen[dep] {
+					deps = append(deps, dep)
+				}
+			}
+			res.Deps = deps
+			seen[res.Name] = true
+		}
+		return program
+	})
+}
+
+func fuzzStepsGen() *rapid.Generator[[]fuzzStep] {
+	return rapid.Custom(func(t *rapid.T) []fuzzStep {
+		var steps []fuzzStep
+		var program []fuzzResource
+		nextName := 0
+		n := rapid.IntRange(1, 5).Draw(t, "steps")
+		for i := 0; i < n; i++ {
+			step := fuzzStep{
+				Op: rapid.SampledFrom([]string{"update", "update", "update", "refresh", "destroy"}).Draw(t, "op"),
+			}
+			if step.Op == "update" {
+				program = fuzzProgramGen(program, &nextName).Draw(t, "program")
+			}
+			step.Program = program
+
+			names := make([]string, len(program))
+			for i, r := range program {
+				names[i] = r.Name
+			}
+			if len(names) > 0 && rapid.IntRange(0, 3).Draw(t, "inject") == 0 {
+				step.Failures = map[string]bool{}
+				for _, name := range rapid.SliceOfNDistinct(rapid.SampledFrom(names), 1, -1, rapid.ID[string]).
+					Draw(t, "failures") {
+					step.Failures[name] = true
+				}
+			}
+			if len(names) > 0 && rapid.IntRange(0, 5).Draw(t, "cancel") == 0 {
+				step.CancelAt = rapid.IntRange(1, len(names)).Draw(t, "cancelAt")
+			}
+			if step.Op == "refresh" && len(names) > 0 && rapid.Bool().Draw(t, "gone") {
+				step.ReadGones = map[string]bool{rapid.SampledFrom(names).Draw(t, "readGone"): true}
+			}
+			steps = append(steps, step)
+		}
+		return steps
+	})
+}
+
+// fuzzHarness runs generated steps against an engine whose provider and program are controlled by the current step.
+type fuzzHarness struct {
+	m      sync.Mutex
+	step   fuzzStep
+	calls  int
+	cancel context.CancelFunc
+}
+
+// call records a call to the provider for the named resource, cancelling the operation if the step says to, and
+// returns an error if the step injects a failure for the resource.
+func (h *fuzzHarness) call(urn resource.URN) error {
+	h.m.Lock()
+	defer h.m.Unlock()
+
+	h.calls++
+	if h.step.CancelAt != 0 && h.calls == h.step.CancelAt {
+		h.cancel()
+	}
+	if h.step.Failures[string(urn.Name())] {
+		return errInjected
+	}
+	return nil
+}
+
+func (h *fuzzHarness) current() fuzzStep {
+	h.m.Lock()
+	defer h.m.Unlock()
+	return h.step
+}
+
+func (h *fuzzHarness) provider() plugin.Provider {
+	return &deploytest.Provider{
+		DiffF: func(urn resource.URN, id resource.ID,
+			oldInputs, oldOutputs, newInputs resource.PropertyMap, ignoreChanges []string,
+		) (plugin.DiffResult, error) {
+			if !oldInputs["key"].DeepEquals(newInputs["key"]) {
+				return plugin.DiffResult{
+					Changes:             plugin.DiffSome,
+					ReplaceKeys:         []resource.PropertyKey{"key"},
+					DeleteBeforeReplace: newInputs["dbr"].IsBool() && newInputs["dbr"].BoolValue(),
+				}, nil
+			}
+			if !oldInputs["value"].DeepEquals(newInputs["value"]) {
+				return plugin.DiffResult{
+					Changes:     plugin.DiffSome,
+					ChangedKeys: []resource.PropertyKey{"value"},
+				}, nil
+			}
+			return plugin.DiffResult{Changes: plugin.DiffNone}, nil
+		},
+		CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+			preview bool,
+		) (resource.ID, resource.PropertyMap, resource.Status, error) {
+			if err := h.call(urn); err != nil {
+				return "", nil, resource.StatusOK, err
+			}
+			return resource.ID("id-" + urn.Name()), news, resource.StatusOK, nil
+		},
+		UpdateF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
+			timeout float64, ignoreChanges []string, preview bool,
+		) (resource.PropertyMap, resource.Status, error) {
+			if err := h.call(urn); err != nil {
+				return nil, resource.StatusOK, err
+			}
+			return newInputs, resource.StatusOK, nil
+		},
+		DeleteF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs resource.PropertyMap,
+			timeout float64,
+		) (resource.Status, error) {
+			if err := h.call(urn); err != nil {
+				return resource.StatusOK, err
+			}
+			return resource.StatusOK, nil
+		},
+		ReadF: func(urn resource.URN, id resource.ID,
+			inputs, state resource.PropertyMap,
+		) (plugin.ReadResult, resource.Status, error) {
+			if err := h.call(urn); err != nil {
+				return plugin.ReadResult{}, resource.StatusUnknown, err
+			}
+			if h.current().ReadGones[string(urn.Name())] {
+				return plugin.ReadResult{}, resource.StatusOK, nil
+			}
+			return plugin.ReadResult{ID: id, Inputs: inputs, Outputs: state}, resource.StatusOK, nil
+		},
+	}
+}
+
+func (h *fuzzHarness) program(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+	urns := map[string]resource.URN{}
+	for _, res := range h.current().Program {
+		var deps []resource.URN
+		for _, dep := range res.Deps {
+			deps = append(deps, urns[dep])
+		}
+		dbr := res.DBR
+		urn, _, _, err := monitor.RegisterResource(res.Type, res.Name, true, deploytest.ResourceOptions{
+			Parent:       urns[res.Parent],
+			Dependencies: deps,
+			Inputs: resource.PropertyMap{
+				"value": resource.NewNumberProperty(float64(res.Value)),
+				"key":   resource.NewNumberProperty(float64(res.Key)),
+				"dbr":   resource.NewBoolProperty(dbr),
+			},
+			DeleteBeforeReplace: &dbr,
+		})
+		if err != nil {
+			return err
+		}
+		urns[res.Name] = urn
+	}
+	return nil
+}
+
+// fuzzPersister is a backend.SnapshotPersister that remembers the last snapshot it was asked to save.
+type fuzzPersister struct {
+	m    sync.Mutex
+	snap *deploy.Snapshot
+}
+
+func (p *fuzzPersister) Save(snap *deploy.Snapshot) error {
+	p.m.Lock()
+	defer p.m.Unlock()
+	p.snap = snap
+	return nil
+}
+
+func (p *fuzzPersister) last() *deploy.Snapshot {
+	p.m.Lock()
+	defer p.m.Unlock()
+	return p.snap
+}
+
+// fuzzSnapshotResource is the part of a resource's state that the journal and the persisted snapshot must agree on.
+type fuzzSnapshotResource struct {
+	URN     resource.URN
+	ID      resource.ID
+	Delete  bool
+	Inputs  resource.PropertyMap
+	Outputs resource.PropertyMap
+}
+
+func fuzzSnapshotResources(snap *deploy.Snapshot) []fuzzSnapshotResource {
+	if snap == nil {
+		return nil
+	}
+	var resources []fuzzSnapshotResource
+	for _, res := range snap.Resources {
+		resources = append(resources, fuzzSnapshotResource{
+			URN:     res.URN,
+			ID:      res.ID,
+			Delete:  res.Delete,
+			Inputs:  res.Inputs,
+			Outputs: res.Outputs,
+		})
+	}
+	return resources
+}
+
+// check verifies the snapshot produced by running step against the snapshot that was persisted while running it.
+// base is the snapshot the step started from, which is all that is persisted if the step made no changes.
+func (h *fuzzHarness) check(
+	t *rapid.T, step fuzzStep, base, snap, persisted *deploy.Snapshot, err error,
+) {
+	if snap == nil {
+		return
+	}
+	require.NoError(t, snap.VerifyIntegrity(), "snapshot integrity after %s", step.Op)
+	if persisted == nil {
+		persisted = base
+	}
+	assert.Equal(t, fuzzSnapshotResources(snap), fuzzSnapshotResources(persisted),
+		"persisted resources after %s", step.Op)
+	if persisted != nil {
+		assert.Empty(t, persisted.PendingOperations, "persisted pending operations after %s", step.Op)
+	}
+	assert.Empty(t, snap.PendingOperations, "pending operations after %s", step.Op)
+	if step.injectsFailures() {
+		return
+	}
+
+	require.NoError(t, err, "%s failed without injected failures", step.Op)
+	resources := map[string]*resource.State{}
+	for _, res := range snap.Resources {
+		if providers.IsProviderType(res.Type) {
+			continue
+		}
+		assert.False(t, res.Delete, "%s is pending deletion after a successful %s", res.URN, step.Op)
+		resources[string(res.URN.Name())] = res
+	}
+	switch step.Op {
+	case "destroy":
+		assert.Empty(t, resources, "resources remain after a successful destroy")
+	case "update":
+		assert.Len(t, resources, len(step.Program))
+		for _, r := range step.Program {
+			res, ok := resources[r.Name]
+			if assert.True(t, ok, "%s is missing after a successful update", r.Name) {
+				assert.Equal(t, r.Type, res.Type)
+				assert.Equal(t, resource.NewNumberProperty(float64(r.Value)), res.Outputs["value"])
+				assert.Equal(t, resource.NewNumberProperty(float64(r.Key)), res.Outputs["key"])
+			}
+		}
+	}
+}
+
+func TestRapidEngine(t *testing.T) {
+	t.Parallel()
+
+	if testing.Short() {
+		t.Skip("skipped in short mode")
+	}
+
+	rapid.Check(t, func(rt *rapid.T) {
+		steps := fuzzStepsGen().Draw(rt, "steps")
+
+		h := &fuzzHarness{}
+		loaders := []*deploytest.ProviderLoader{
+			deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+				return h.provider(), nil
+			}),
+		}
+		hostF := deploytest.NewPluginHostF(nil, nil, deploytest.NewLanguageRuntimeF(h.program), loaders...)
+		p := &TestPlan{
+			Options: TestUpdateOptions{HostF: hostF},
+		}
+		project := p.GetProject()
+
+		ops := map[string]TestOp{"update": Update, "refresh": Refresh, "destroy": Destroy}
+		var snap *deploy.Snapshot
+		for _, step := range steps {
+			ctx, cancel := context.WithCancel(context.Background())
+			h.m.Lock()
+			h.step, h.calls, h.cancel = step, 0, cancel
+			h.m.Unlock()
+
+			persister := &fuzzPersister{}
+			opts := p.Options
+			opts.SnapshotPersister = persister
+
+			next, err := ops[step.Op].RunWithContext(ctx, project, p.GetTarget(t, snap), opts, false, nil, nil)
+			cancel()
+			h.check(rt, step, snap, next, persister.last(), err)
+			if next != nil {
+				snap = next
+			}
+		}
+	})
+}
@@ -0,0 +1,260 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// concurrencyTracker records the maximum number of concurrent creates of each resource type, and the order in which
+// the creates started.
+type concurrencyTracker struct {
+	m       sync.Mutex
+	current map[tokens.Type]int
+	max     map[tokens.Type]int
+	started []string
+}
+
+func newConcurrencyTracker() *concurrencyTracker {
+	return &concurrencyTracker{current: map[tokens.Type]int{}, max: map[tokens.Type]int{}}
+}
+
+func (c *concurrencyTracker) create(urn resource.URN, inputs resource.PropertyMap, timeout float64,
+	preview bool,
+) (resource.ID, resource.PropertyMap, resource.Status, error) {
+	c.m.Lock()
+	c.started = append(c.started, urn.Name().String())
+	c.current[urn.Type()]++
+	c.current[""]++
+	for _, typ := range []tokens.Type{urn.Type(), ""} {
+		if c.current[typ] > c.max[typ] {
+			c.max[typ] = c.current[typ]
+		}
+	}
+	c.m.Unlock()
+
+	// Hold on to the step for long enough that the other registrations catch up with it.
+	time.Sleep(50 * time.Millisecond)
+
+	c.m.Lock()
+	c.current[urn.Type()]--
+	c.current[""]--
+	c.m.Unlock()
+	return resource.ID(urn.Name()), inputs, resource.StatusOK, nil
+}
+
+// registerConcurrently registers count resources of each of the given types at once.
+func registerConcurrently(t *testing.T, monitor *deploytest.ResourceMonitor, count int, provider string,
+	types ...tokens.Type,
+) {
+	var wg sync.WaitGroup
+	for _, typ := range types {
+		for i := 0; i < count; i++ {
+			wg.Add(1)
+			go func(typ tokens.Type, name string) {
+				defer wg.Done()
+				_, _, _, err := monitor.RegisterResource(typ, name, true, deploytest.ResourceOptions{
+					Provider: provider,
+				})
+				assert.NoError(t, err)
+			}(typ, fmt.Sprintf("%s-%d", typ.Name(), i))
+		}
+	}
+	wg.Wait()
+}
+
+// countWaitingEvents returns the number of resource waiting events in the given events.
+func countWaitingEvents(events []Event) int {
+	count := 0
+	for _, e := range events {
+		if e.Type == ResourceWaitingEvent {
+			count++
+		}
+	}
+	return count
+}
+
+// Tests that the stack's parallelism configuration limits the number of concurrent steps on the resources of a
+// provider package and of a resource type.
+func TestStackParallelismLimits(t *testing.T) {
+	t.Parallel()
+
+	tracker := newConcurrencyTracker()
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{CreateF: tracker.create}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		registerConcurrently(t, monitor, 4, "", "pkgA:m:typA", "pkgA:m:typB")
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF:         deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{Parallel: 16},
+		},
+		Parallelism: &workspace.StackParallelism{
+			Providers: map[string]int{"pkgA": 3},
+			Types:     map[string]int{"pkgA:m:typB": 1},
+		},
+	}
+
+	validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		// The steps beyond the limits had to wait for a free slot.
+		assert.NotZero(t, countWaitingEvents(events))
+		return err
+	}
+	snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), p.Options, false, p.BackendClient, validate)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 9)
+
+	assert.LessOrEqual(t, tracker.max[""], 3)
+	assert.LessOrEqual(t, tracker.max["pkgA:m:typB"], 1)
+}
+
+// Tests that the parallelism option of a provider resource limits the number of concurrent steps on the resources that
+// it manages, without limiting those of other providers of the same package.
+func TestProviderParallelismOption(t *testing.T) {
+	t.Parallel()
+
+	tracker := newConcurrencyTracker()
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: tracker.create,
+				// The limit is for the engine, and isn't part of the provider's configuration.
+				ConfigureF: func(news resource.PropertyMap) error {
+					for k := range news {
+						assert.Equal(t, resource.PropertyKey("version"), k)
+					}
+					return nil
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		provURN, provID, _, err := monitor.RegisterResource(providers.MakeProviderType("pkgA"), "provA", true,
+			deploytest.ResourceOptions{Parallelism: 1})
+		assert.NoError(t, err)
+		if provID == "" {
+			provID = providers.UnknownID
+		}
+		provRef, err := providers.NewReference(provURN, provID)
+		assert.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			registerConcurrently(t, monitor, 4, provRef.String(), "pkgA:m:typA")
+		}()
+		go func() {
+			defer wg.Done()
+			registerConcurrently(t, monitor, 4, "", "pkgA:m:typB")
+		}()
+		wg.Wait()
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF:         deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{Parallel: 16},
+		},
+	}
+
+	validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		assert.NotZero(t, countWaitingEvents(events))
+		return err
+	}
+	snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), p.Options, false, p.BackendClient, validate)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 10)
+
+	assert.Equal(t, 1, tracker.max["pkgA:m:typA"])
+	assert.Greater(t, tracker.max["pkgA:m:typB"], 1)
+
+}
+
+// Tests that a step that's waiting on a parallelism limit doesn't hold up one of the deployment's workers, so that the
+// steps behind it that aren't limited can still run.
+func TestParallelismLimitsDontHoldWorkers(t *testing.T) {
+	t.Parallel()
+
+	tracker := newConcurrencyTracker()
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{CreateF: tracker.create}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			registerConcurrently(t, monitor, 2, "", "pkgA:m:typA")
+		}()
+
+		// Give the first typA step time to start, and the second to start waiting for it, before registering typB.
+		time.Sleep(10 * time.Millisecond)
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typB", "typB", true)
+		assert.NoError(t, err)
+		wg.Wait()
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF:         deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{Parallel: 2},
+		},
+		Parallelism: &workspace.StackParallelism{
+			Types: map[string]int{"pkgA:m:typA": 1},
+		},
+	}
+
+	snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 4)
+
+	// typB ran while the second typA step was waiting for the first, rather than after it.
+	assert.Len(t, tracker.started, 3)
+	assert.Equal(t, "typB", tracker.started[1])
+	assert.Equal(t, 1, tracker.max["pkgA:m:typA"])
+}
@@ -127,6 +127,16 @@ func TestMain(m *testing.M) {
 
 	flag.Parse()
 
+	// TestRapidEngine runs a full sequence of operations per check, so run fewer checks than rapid's default unless
+	// a count was given explicitly with -rapid.checks.
+	rapidChecks := false
+	flag.Visit(func(f *flag.Flag) {
+		rapidChecks = rapidChecks || f.Name == "rapid.checks"
+	})
+	if !rapidChecks {
+		contract.AssertNoErrorf(flag.Set("rapid.checks", "10"), "setting -rapid.checks")
+	}
+
 	if *grpcDefault {
 		deploytest.UseGrpcPluginsByDefault = true
 	}
@@ -1352,6 +1362,169 @@ func TestIgnoreChangesInvalidPaths(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestDefaultIgnoreChanges(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+		deploytest.NewProviderLoader("pkgB", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	owner, team, name := "alice", "red", "foo"
+	extra := resource.NewObjectProperty(resource.PropertyMap{"value": resource.NewNumberProperty(1)})
+	program := func(monitor *deploytest.ResourceMonitor) error {
+		tags := resource.NewObjectProperty(resource.PropertyMap{
+			"owner":    resource.NewStringProperty(owner),
+			"teamName": resource.NewStringProperty(team),
+		})
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"tags": tags, "name": resource.NewStringProperty(name)},
+		})
+		assert.NoError(t, err)
+		inputs := resource.PropertyMap{"tags": tags}
+		if extra.HasValue() {
+			inputs["extra"] = extra
+		}
+		_, _, _, err = monitor.RegisterResource("pkgB:m:typB", "resB", true, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		assert.NoError(t, err)
+		return nil
+	}
+
+	runtimeF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return program(monitor)
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, runtimeF, loaders...)
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{HostF: hostF},
+		IgnoreChanges: &workspace.StackIgnoreChanges{
+			All: []string{"tags.owner", "extra.value"},
+			Providers: map[string][]string{
+				"pkgA": {"tags.team*"},
+			},
+		},
+	}
+
+	project := p.GetProject()
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	assert.NoError(t, err)
+
+	// Change everything. The defaults ignore the changes to the tags, except for resB's team, which only pkgA's rules
+	// ignore. The "extra.value" rule doesn't fit resB's new inputs, so it's skipped rather than failing the update.
+	owner, team, name = "bob", "blue", "bar"
+	extra = resource.PropertyValue{}
+	validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		ignored := map[resource.URN][]deploy.IgnoredChange{}
+		for _, e := range events {
+			if e.Type == ResourcePreEvent {
+				md := e.Payload().(ResourcePreEventPayload).Metadata
+				ignored[md.URN] = md.IgnoredChanges
+			}
+			// The ignored changes are reported on the steps rather than as diagnostics.
+			assert.NotEqual(t, DiagEvent, e.Type)
+		}
+		assert.Equal(t, map[resource.URN][]deploy.IgnoredChange{
+			p.NewURN("pkgA:m:typA", "resA", ""): {
+				{Path: resource.PropertyPath{"tags", "owner"}, Rule: "tags.owner", Default: true},
+				{Path: resource.PropertyPath{"tags", "teamName"}, Rule: "tags.team*", Default: true},
+			},
+			p.NewURN("pkgB:m:typB", "resB", ""): {
+				{Path: resource.PropertyPath{"tags", "owner"}, Rule: "tags.owner", Default: true},
+			},
+		}, ignored)
+		return err
+	}
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, validate)
+	assert.NoError(t, err)
+
+	assert.Len(t, snap.Resources, 4)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"owner": "alice", "teamName": "red"},
+		"name": "bar",
+	}), snap.Resources[1].Inputs)
+	assert.Equal(t, resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{"owner": "alice", "teamName": "blue"},
+	}), snap.Resources[3].Inputs)
+}
+
+// TestDefaultIgnoreChangesProviderConfig checks that the stack's default ignoreChanges rules don't apply to the
+// configuration of providers, or to components, only to custom resources.
+func TestDefaultIgnoreChangesProviderConfig(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{}, nil
+		}),
+	}
+
+	region := "us-east-1"
+	program := func(monitor *deploytest.ResourceMonitor) error {
+		inputs := resource.PropertyMap{"region": resource.NewStringProperty(region)}
+		provURN, provID, _, err := monitor.RegisterResource(providers.MakeProviderType("pkgA"), "provA", true,
+			deploytest.ResourceOptions{Inputs: inputs})
+		require.NoError(t, err)
+		if provID == "" {
+			provID = providers.UnknownID
+		}
+		provRef, err := providers.NewReference(provURN, provID)
+		require.NoError(t, err)
+
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs:   inputs,
+			Provider: provRef.String(),
+		})
+		require.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typComponent", "compA", false, deploytest.ResourceOptions{
+			Inputs: inputs,
+		})
+		require.NoError(t, err)
+		return nil
+	}
+
+	runtimeF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		return program(monitor)
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, runtimeF, loaders...)
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{HostF: hostF},
+		IgnoreChanges: &workspace.StackIgnoreChanges{
+			All: []string{"region"},
+			Providers: map[string][]string{
+				"pulumi": {"region"},
+			},
+		},
+	}
+
+	project := p.GetProject()
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	// Change the region. Only the custom resource's change is ignored.
+	region = "us-west-2"
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+
+	inputs := map[resource.URN]resource.PropertyValue{}
+	for _, res := range snap.Resources {
+		inputs[res.URN] = res.Inputs["region"]
+	}
+	assert.Equal(t, map[resource.URN]resource.PropertyValue{
+		p.NewProviderURN("pkgA", "provA", ""):        resource.NewStringProperty("us-west-2"),
+		p.NewURN("pkgA:m:typA", "resA", ""):          resource.NewStringProperty("us-east-1"),
+		p.NewURN("pkgA:m:typComponent", "compA", ""): resource.NewStringProperty("us-west-2"),
+	}, inputs)
+}
+
 type DiffFunc = func(urn resource.URN, id resource.ID,
 	oldInputs, oldOutputs, newInputs resource.PropertyMap, ignoreChanges []string) (plugin.DiffResult, error)
 
@@ -3007,6 +3180,88 @@ func TestRetainOnDelete(t *testing.T) {
 	assert.Len(t, snap.Resources, 0)
 }
 
+func TestTaint(t *testing.T) {
+	t.Parallel()
+
+	idCounter := 0
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					resourceID := resource.ID(fmt.Sprintf("created-id-%d", idCounter))
+					idCounter = idCounter + 1
+					return resourceID, news, resource.StatusOK, nil
+				},
+			}, nil
+		}, deploytest.WithoutGrpc),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "bar"}),
+		})
+		assert.NoError(t, err)
+		_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+			Inputs: resource.NewPropertyMapFromMap(map[string]interface{}{"foo": "baz"}),
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{HostF: hostF},
+	}
+
+	project := p.GetProject()
+	resBURN := p.NewURN("pkgA:m:typA", "resB", "")
+
+	// Run an update to create the resources.
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 3)
+	assert.Equal(t, "created-id-0", snap.Resources[1].ID.String())
+	assert.Equal(t, "created-id-1", snap.Resources[2].ID.String())
+
+	// Taint resA and run an update that only targets resB. resA is not touched, and must remain tainted.
+	snap.Resources[1].Taint = true
+	targeted := *p
+	targeted.Options.Targets = deploy.NewUrnTargetsFromUrns([]resource.URN{resBURN})
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), targeted.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 3)
+	assert.Equal(t, "created-id-0", snap.Resources[1].ID.String())
+	assert.True(t, snap.Resources[1].Taint)
+
+	// The next untargeted update must replace resA even though its inputs have not changed.
+	validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		var ops []display.StepOp
+		for _, entry := range entries {
+			if entry.Kind == JournalEntrySuccess && entry.Step.URN().Name() == "resA" {
+				ops = append(ops, entry.Step.Op())
+			}
+		}
+		assert.Equal(t, []display.StepOp{deploy.OpCreateReplacement, deploy.OpReplace, deploy.OpDeleteReplaced}, ops)
+		return err
+	}
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, validate)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 3)
+	assert.Equal(t, "created-id-2", snap.Resources[1].ID.String())
+
+	// The taint is cleared once the resource has been replaced, so the next update is a no-op.
+	assert.False(t, snap.Resources[1].Taint)
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, nil)
+	require.NoError(t, err)
+	require.Len(t, snap.Resources, 3)
+	assert.Equal(t, "created-id-2", snap.Resources[1].ID.String())
+}
+
 func TestDeletedWith(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,297 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lifecycletest
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+
+	. "github.com/pulumi/pulumi/pkg/v3/engine"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/deploytest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+// retryMessages returns the messages of the warnings that report retries.
+func retryMessages(events []Event) []string {
+	var messages []string
+	for _, e := range events {
+		if e.Type != DiagEvent {
+			continue
+		}
+		payload := e.Payload().(DiagEventPayload)
+		if payload.Severity == diag.Warning && strings.Contains(payload.Message, "retrying") {
+			messages = append(messages, payload.Message)
+		}
+	}
+	return messages
+}
+
+// Tests that creates that fail with errors the provider marks as retryable, and updates that fail with errors that
+// match the deployment's retry patterns, are retried, but that creates aren't retried just because their errors match.
+func TestRetryTransientErrors(t *testing.T) {
+	t.Parallel()
+
+	creates, updates := 0, 0
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, inputs resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					if preview {
+						return "", inputs, resource.StatusOK, nil
+					}
+					creates++
+					switch creates {
+					case 1:
+						return "", nil, resource.StatusOK, errors.New("Throttling: rate exceeded")
+					case 2, 3:
+						return "", nil, resource.StatusOK, &plugin.RetryableError{
+							Reason: "throttled",
+							Err:    errors.New("Throttling: rate exceeded"),
+						}
+					}
+					return "id", inputs, resource.StatusOK, nil
+				},
+				UpdateF: func(urn resource.URN, id resource.ID, oldInputs, oldOutputs, newInputs resource.PropertyMap,
+					timeout float64, ignoreChanges []string, preview bool,
+				) (resource.PropertyMap, resource.Status, error) {
+					if preview {
+						return newInputs, resource.StatusOK, nil
+					}
+					if updates++; updates < 2 {
+						return nil, resource.StatusUnknown, errors.New("unexpected status 503 from service")
+					}
+					return newInputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	value := "foo"
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: resource.PropertyMap{"value": resource.NewStringProperty(value)},
+		})
+		assert.NoError(t, err)
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{
+				Retry: &deploy.RetryPolicy{
+					MaxRetries:   3,
+					InitialDelay: time.Millisecond,
+					Patterns:     []*regexp.Regexp{regexp.MustCompile(`(?i)throttl`), regexp.MustCompile(`\b50[234]\b`)},
+				},
+			},
+		},
+	}
+	project := p.GetProject()
+
+	// The create's error matches a pattern, but the create may have created the resource anyway, so it isn't retried.
+	validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		assert.Empty(t, retryMessages(events))
+		return err
+	}
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, validate)
+	assert.Error(t, err)
+	assert.Equal(t, 1, creates)
+
+	// The create is throttled twice, with errors the provider marks as retryable, before it succeeds.
+	validate = func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		assert.Equal(t, []string{
+			"<{%reset%}>create failed with a transient error, retrying in 1ms (retry 1 of 3): " +
+				"Throttling: rate exceeded<{%reset%}>\n",
+			"<{%reset%}>create failed with a transient error, retrying in 2ms (retry 2 of 3): " +
+				"Throttling: rate exceeded<{%reset%}>\n",
+		}, retryMessages(events))
+		return err
+	}
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, validate)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, creates)
+	assert.Len(t, snap.Resources, 2)
+
+	// The update fails once before it succeeds.
+	value = "bar"
+	validate = func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		assert.Len(t, retryMessages(events), 1)
+		return err
+	}
+	snap, err = TestOp(Update).Run(project, p.GetTarget(t, snap), p.Options, false, p.BackendClient, validate)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, updates)
+	assert.Equal(t, resource.NewStringProperty("bar"), snap.Resources[1].Outputs["value"])
+}
+
+// Tests that a resource's retries option overrides the deployment's retry policy, and that errors that the provider
+// marks as retryable are retried without matching any pattern.
+func TestRetryResourceOption(t *testing.T) {
+	t.Parallel()
+
+	creates := map[string]int{}
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, inputs resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					if preview {
+						return "", inputs, resource.StatusOK, nil
+					}
+					creates[string(urn.Name())]++
+					if urn.Name() == "resC" {
+						return "", nil, resource.StatusOK, errors.New("invalid name")
+					}
+					return "", nil, resource.StatusOK, &plugin.RetryableError{
+						Reason: "throttled",
+						Err:    errors.New("slow down"),
+					}
+				},
+			}, nil
+		}),
+	}
+
+	var name string
+	var retries int32
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", name, true, deploytest.ResourceOptions{
+			Retries: retries,
+		})
+		assert.Error(t, err)
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{
+				Retry: &deploy.RetryPolicy{InitialDelay: time.Millisecond},
+			},
+		},
+	}
+
+	// resA is retried twice, resB's policy doesn't allow retries, and resC's error isn't transient.
+	cases := []struct {
+		name    string
+		retries int32
+		creates int
+	}{
+		{name: "resA", retries: 2, creates: 3},
+		{name: "resB", retries: 0, creates: 1},
+		{name: "resC", retries: 2, creates: 1},
+	}
+	for _, c := range cases {
+		name, retries = c.name, c.retries
+		validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+			events []Event, err error,
+		) error {
+			assert.Len(t, retryMessages(events), c.creates-1)
+			return err
+		}
+		_, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), p.Options, false, p.BackendClient, validate)
+		assert.Error(t, err)
+		assert.Equal(t, c.creates, creates[c.name])
+	}
+}
+
+// Tests that a step that's waiting to be retried holds neither one of the deployment's workers nor its parallelism
+// limits, so that other steps can run in the meantime.
+func TestRetryDoesntHoldWorkers(t *testing.T) {
+	t.Parallel()
+
+	var m sync.Mutex
+	var started []string
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, inputs resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					if preview {
+						return "", inputs, resource.StatusOK, nil
+					}
+					m.Lock()
+					defer m.Unlock()
+					started = append(started, string(urn.Name()))
+					if len(started) == 1 {
+						return "", nil, resource.StatusOK, &plugin.RetryableError{
+							Reason: "throttled",
+							Err:    errors.New("slow down"),
+						}
+					}
+					return "id", inputs, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true)
+			assert.NoError(t, err)
+		}()
+
+		// Give resA's create time to fail before registering resB.
+		time.Sleep(10 * time.Millisecond)
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resB", true)
+		assert.NoError(t, err)
+		wg.Wait()
+		return nil
+	})
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF: deploytest.NewPluginHostF(nil, nil, programF, loaders...),
+			UpdateOptions: UpdateOptions{
+				Parallel: 1,
+				Retry:    &deploy.RetryPolicy{MaxRetries: 1, InitialDelay: 500 * time.Millisecond},
+			},
+		},
+		Parallelism: &workspace.StackParallelism{
+			Types: map[string]int{"pkgA:m:typA": 1},
+		},
+	}
+
+	snap, err := TestOp(Update).Run(p.GetProject(), p.GetTarget(t, nil), p.Options, false, p.BackendClient, nil)
+	assert.NoError(t, err)
+	assert.Len(t, snap.Resources, 3)
+
+	// resB was created while resA was waiting to be retried, rather than after it.
+	assert.Equal(t, []string{"resA", "resB", "resA"}, started)
+}
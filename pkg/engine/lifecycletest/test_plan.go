@@ -3,6 +3,7 @@ package lifecycletest
 
 import (
 	"context"
+	"errors"
 	"reflect"
 	"sync"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/pulumi/pulumi/pkg/v3/backend"
 	"github.com/pulumi/pulumi/pkg/v3/display"
 	. "github.com/pulumi/pulumi/pkg/v3/engine"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
@@ -99,10 +101,20 @@ func (op TestOp) runWithContext(
 	events := make(chan Event)
 	journal := NewJournal()
 
+	// If the test asked for persisted snapshots, run a backend snapshot manager alongside the journal so that the
+	// snapshots it saves can be compared against the journal's view of the world.
+	var snapshotManager SnapshotManager = journal
+	if opts.SnapshotPersister != nil {
+		snapshotManager = snapshotManagers{
+			journal,
+			backend.NewSnapshotManager(opts.SnapshotPersister, nil, target.Snapshot),
+		}
+	}
+
 	ctx := &Context{
 		Cancel:          cancelCtx,
 		Events:          events,
-		SnapshotManager: journal,
+		SnapshotManager: snapshotManager,
 		BackendClient:   backendClient,
 	}
 
@@ -128,7 +140,9 @@ func (op TestOp) runWithContext(
 	plan, _, opErr := op(info, ctx, updateOpts, dryRun)
 	close(events)
 	wg.Wait()
-	contract.IgnoreClose(journal)
+	if err := snapshotManager.Close(); opErr == nil && err != nil {
+		opErr = err
+	}
 
 	if validate != nil {
 		opErr = validate(project, target, journal.Entries(), firedEvents, opErr)
@@ -171,6 +185,54 @@ type TestUpdateOptions struct {
 	UpdateOptions
 	// a factory to produce a plugin host for an update operation.
 	HostF deploytest.PluginHostFactory
+	// an optional persister that receives the snapshots saved by a backend snapshot manager during the update.
+	SnapshotPersister backend.SnapshotPersister
+}
+
+// snapshotManagers is a SnapshotManager that forwards each mutation to a list of snapshot managers in order.
+type snapshotManagers []SnapshotManager
+
+func (sms snapshotManagers) BeginMutation(step deploy.Step) (SnapshotMutation, error) {
+	mutations := make(snapshotMutations, 0, len(sms))
+	for _, sm := range sms {
+		m, err := sm.BeginMutation(step)
+		if err != nil {
+			return nil, err
+		}
+		mutations = append(mutations, m)
+	}
+	return mutations, nil
+}
+
+func (sms snapshotManagers) RegisterResourceOutputs(step deploy.Step) error {
+	for _, sm := range sms {
+		if err := sm.RegisterResourceOutputs(step); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (sms snapshotManagers) Close() error {
+	var errs []error
+	for _, sm := range sms {
+		if err := sm.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type snapshotMutations []SnapshotMutation
+
+func (ms snapshotMutations) End(step deploy.Step, successful bool) error {
+	var errs []error
+	for _, m := range ms {
+		if err := m.End(step, successful); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
 }
 
 // Options produces UpdateOptions for an update operation.
@@ -189,6 +251,8 @@ type TestPlan struct {
 	RuntimeOptions map[string]interface{}
 	Config         config.Map
 	Decrypter      config.Decrypter
+	IgnoreChanges  *workspace.StackIgnoreChanges
+	Parallelism    *workspace.StackParallelism
 	BackendClient  deploy.BackendClient
 	Options        TestUpdateOptions
 	Steps          []TestStep
@@ -241,9 +305,11 @@ func (p *TestPlan) GetTarget(t testing.TB, snapshot *deploy.Snapshot) deploy.Tar
 	}
 
 	return deploy.Target{
-		Name:      stack,
-		Config:    cfg,
-		Decrypter: p.Decrypter,
+		Name:          stack,
+		Config:        cfg,
+		Decrypter:     p.Decrypter,
+		IgnoreChanges: p.IgnoreChanges,
+		Parallelism:   p.Parallelism,
 		// note: it's really important that the preview and update operate on different snapshots.  the engine can and
 		// does mutate the snapshot in-place, even in previews, and sharing a snapshot between preview and update can
 		// cause state changes from the preview to persist even when doing an update.
@@ -1792,3 +1792,84 @@ func TestStackOutputsWithTargetedPlan(t *testing.T) {
 	}, false, p.BackendClient, nil)
 	assert.NoError(t, err)
 }
+
+func TestVerifiedPlan(t *testing.T) {
+	t.Parallel()
+
+	loaders := []*deploytest.ProviderLoader{
+		deploytest.NewProviderLoader("pkgA", semver.MustParse("1.0.0"), func() (plugin.Provider, error) {
+			return &deploytest.Provider{
+				CreateF: func(urn resource.URN, news resource.PropertyMap, timeout float64,
+					preview bool,
+				) (resource.ID, resource.PropertyMap, resource.Status, error) {
+					return "created-id", news, resource.StatusOK, nil
+				},
+			}, nil
+		}),
+	}
+
+	ins := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo": "bar",
+	})
+	createB := false
+	programF := deploytest.NewLanguageRuntimeF(func(_ plugin.RunInfo, monitor *deploytest.ResourceMonitor) error {
+		_, _, _, err := monitor.RegisterResource("pkgA:m:typA", "resA", true, deploytest.ResourceOptions{
+			Inputs: ins,
+		})
+		assert.NoError(t, err)
+		if createB {
+			_, _, _, err = monitor.RegisterResource("pkgA:m:typA", "resB", true, deploytest.ResourceOptions{
+				Inputs: ins,
+			})
+			assert.NoError(t, err)
+		}
+		return nil
+	})
+	hostF := deploytest.NewPluginHostF(nil, nil, programF, loaders...)
+
+	p := &TestPlan{
+		Options: TestUpdateOptions{
+			HostF:         hostF,
+			UpdateOptions: UpdateOptions{GeneratePlan: true, Experimental: true},
+		},
+	}
+
+	project := p.GetProject()
+
+	// Create a plan to create resA.
+	plan, err := TestOp(Update).Plan(project, p.GetTarget(t, nil), p.Options, p.BackendClient, nil)
+	assert.NoError(t, err)
+
+	// Change resA's inputs and create resB as well, and run an update that verifies the plan. Both deviations from the
+	// plan are reported, but the update carries on regardless.
+	ins = resource.NewPropertyMapFromMap(map[string]interface{}{
+		"foo": "baz",
+	})
+	createB = true
+	p.Options.Plan = plan.Clone()
+	p.Options.VerifyPlan = true
+	validate := func(project workspace.Project, target deploy.Target, entries JournalEntries,
+		events []Event, err error,
+	) error {
+		assert.ErrorContains(t, err, "the update deviated from its plan in 2 ways")
+
+		var messages []string
+		for _, e := range events {
+			if e.Type == DiagEvent {
+				messages = append(messages, e.Payload().(DiagEventPayload).Message)
+			}
+		}
+		assert.Equal(t, []string{
+			"<{%reset%}>resource urn:pulumi:test::test::pkgA:m:typA::resA violates plan: " +
+				"properties changed: ++foo[{bar}!={baz}]<{%reset%}>\n",
+			"<{%reset%}>create is not allowed by the plan: no steps were expected for this resource<{%reset%}>\n",
+			"<{%reset%}>the update deviated from its plan in 2 ways<{%reset%}>\n",
+		}, messages)
+		return nil
+	}
+	snap, err := TestOp(Update).Run(project, p.GetTarget(t, nil), p.Options, false, p.BackendClient, validate)
+	assert.NoError(t, err)
+
+	// Check that both resources were created.
+	assert.Len(t, snap.Resources, 3)
+}
@@ -26,7 +26,7 @@ import (
 
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
 	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
-	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
@@ -40,6 +40,11 @@ import (
 const (
 	preparePluginLog        = 7
 	preparePluginVerboseLog = 8
+
+	// maxConcurrentPluginInstalls is the maximum number of plugins that are downloaded and installed at once.
+	maxConcurrentPluginInstalls = 4
+	// pluginProgressInterval is the minimum interval between progress events for a plugin download.
+	pluginProgressInterval = 100 * time.Millisecond
 )
 
 // pluginSet represents a set of plugins.
@@ -126,9 +131,11 @@ func gatherPluginsFromProgram(plugctx *plugin.Context, prog plugin.ProgInfo) (pl
 	if err != nil {
 		return set, err
 	}
+	_, isClient := plugctx.Host.(*clientLanguageRuntimeHost)
 	for _, plug := range langhostPlugins {
-		// Ignore language plugins named "client".
-		if plug.Name == clientRuntimeName && plug.Kind == workspace.LanguagePlugin {
+		// Ignore language plugins named "client", and the language plugin of programs that are run by a client or
+		// in-process, which needs no plugin.
+		if plug.Kind == workspace.LanguagePlugin && (plug.Name == clientRuntimeName || isClient) {
 			continue
 		}
 
@@ -182,24 +189,63 @@ func gatherPluginsFromSnapshot(plugctx *plugin.Context, target *deploy.Target) (
 	return set, nil
 }
 
+// applyPluginLock resolves the plugins in the plugin set against the project's plugin lock, if there is one. Bundled
+// plugins are not locked, as their versions are determined by the CLI.
+func applyPluginLock(plugins pluginSet, lock *workspace.PluginLock, locked bool) (pluginSet, error) {
+	if lock == nil {
+		return plugins, nil
+	}
+
+	resolved := newPluginSet()
+	for _, plug := range plugins.Values() {
+		if isLockablePlugin(plug) {
+			var err error
+			if plug, err = lock.Apply(plug, locked); err != nil {
+				return nil, err
+			}
+		}
+		resolved.Add(plug)
+	}
+	return resolved, nil
+}
+
+// isLockablePlugin returns true if the plugin should be recorded in a project's plugin lock.
+func isLockablePlugin(plug workspace.PluginSpec) bool {
+	if plug.Name == "pulumi" && plug.Kind == workspace.ResourcePlugin {
+		return false
+	}
+	return !workspace.IsPluginBundled(plug.Kind, plug.Name)
+}
+
 // ensurePluginsAreInstalled inspects all plugins in the plugin set and, if any plugins are not currently installed,
-// uses the given backend client to install them. Installations are processed in parallel, though
-// ensurePluginsAreInstalled does not return until all installations are completed.
-func ensurePluginsAreInstalled(ctx context.Context, d diag.Sink,
-	plugins pluginSet, projectPlugins []workspace.ProjectPlugin,
+// uses the given backend client to install them. Up to maxConcurrentPluginInstalls installations are processed in
+// parallel, though ensurePluginsAreInstalled does not return until all installations are completed. If events is not
+// nil, the progress of the installations is reported as progress events. If lock is not nil, the plugins are recorded
+// in it, along with the checksums of the archives of any plugins that are downloaded and of the executables of all
+// plugins, and the executables of plugins that are already installed are verified against it. If locked is true, the
+// lock isn't changed, and installed plugins that can't be verified against it are an error.
+func ensurePluginsAreInstalled(ctx context.Context, plugctx *plugin.Context, events *eventEmitter,
+	plugins pluginSet, lock *workspace.PluginLock, locked bool,
 ) error {
 	logging.V(preparePluginLog).Infof("ensurePluginsAreInstalled(): beginning")
 	var installTasks errgroup.Group
+	installTasks.SetLimit(maxConcurrentPluginInstalls)
 	for _, plug := range plugins.Values() {
 		if plug.Name == "pulumi" && plug.Kind == workspace.ResourcePlugin {
 			logging.V(preparePluginLog).Infof("ensurePluginsAreInstalled(): pulumi is a builtin plugin")
 			continue
 		}
 
-		path, err := workspace.GetPluginPath(d, plug.Kind, plug.Name, plug.Version, projectPlugins)
+		path, err := workspace.GetPluginPath(
+			plugctx.Diag, plug.Kind, plug.Name, plug.Version, plugctx.Host.GetProjectPlugins())
 		if err == nil && path != "" {
 			logging.V(preparePluginLog).Infof(
 				"ensurePluginsAreInstalled(): plugin %s %s already installed", plug.Name, plug.Version)
+			if lock != nil && isLockablePlugin(plug) {
+				if err := lock.RecordInstalled(plug, locked); err != nil {
+					return err
+				}
+			}
 			continue
 		}
 
@@ -213,6 +259,7 @@ func ensurePluginsAreInstalled(ctx context.Context, d diag.Sink,
 		}
 
 		info := plug // don't close over the loop induction variable
+		info.Mirror = plugctx.PluginMirror
 
 		// If DISABLE_AUTOMATIC_PLUGIN_ACQUISITION is set just add an error to the error group and continue.
 		if env.DisableAutomaticPluginAcquisition.Value() {
@@ -226,7 +273,7 @@ func ensurePluginsAreInstalled(ctx context.Context, d diag.Sink,
 		installTasks.Go(func() error {
 			logging.V(preparePluginLog).Infof(
 				"ensurePluginsAreInstalled(): plugin %s %s not installed, doing install", info.Name, info.Version)
-			return installPlugin(ctx, info)
+			return installPlugin(ctx, info, lock, locked, events)
 		})
 	}
 
@@ -241,8 +288,12 @@ func ensurePluginsAreLoaded(plugctx *plugin.Context, plugins pluginSet, kinds pl
 	return plugctx.Host.EnsurePlugins(plugins.Values(), kinds)
 }
 
-// installPlugin installs a plugin from the given backend client.
-func installPlugin(ctx context.Context, plugin workspace.PluginSpec) error {
+// installPlugin installs a plugin from the given backend client, recording it in the given lock if it's not nil. If
+// locked is true, the installed plugin is only verified against the lock, which isn't changed. If events is not nil,
+// the download and installation are reported as progress events, rather than with a progress bar.
+func installPlugin(ctx context.Context,
+	plugin workspace.PluginSpec, lock *workspace.PluginLock, locked bool, events *eventEmitter,
+) error {
 	logging.V(preparePluginLog).Infof("installPlugin(%s, %s): beginning install", plugin.Name, plugin.Version)
 
 	// If we don't have a version yet try and call GetLatestVersion to fill it in
@@ -260,8 +311,21 @@ func installPlugin(ctx context.Context, plugin workspace.PluginSpec) error {
 	logging.V(preparePluginVerboseLog).Infof(
 		"installPlugin(%s, %s): initiating download", plugin.Name, plugin.Version)
 
+	id := fmt.Sprintf("%s:%s", plugin.Kind, plugin)
 	withProgress := func(stream io.ReadCloser, size int64) io.ReadCloser {
-		return workspace.ReadCloserProgressBar(stream, size, "Downloading plugin", cmdutil.GetGlobalColorization())
+		if events == nil {
+			return workspace.ReadCloserProgressBar(stream, size, "Downloading plugin", cmdutil.GetGlobalColorization())
+		}
+		return &pluginProgressReader{
+			ReadCloser: stream,
+			events:     events,
+			payload: ProgressEventPayload{
+				Type:    apitype.PluginDownload,
+				ID:      id,
+				Message: fmt.Sprintf("Downloading %s plugin %s", plugin.Kind, plugin),
+				Total:   size,
+			},
+		}
 	}
 	retry := func(err error, attempt int, limit int, delay time.Duration) {
 		logging.V(preparePluginVerboseLog).Infof(
@@ -274,19 +338,72 @@ func installPlugin(ctx context.Context, plugin workspace.PluginSpec) error {
 	}
 	defer func() { contract.IgnoreError(os.Remove(tarball.Name())) }()
 
-	fmt.Fprintf(os.Stderr, "[%s plugin %s-%s] installing\n", plugin.Kind, plugin.Name, plugin.Version)
+	checksum, err := workspace.PluginArchiveChecksum(tarball.Name())
+	if err != nil {
+		return fmt.Errorf("computing checksum of plugin %s: %w", plugin, err)
+	}
+
+	installing := ProgressEventPayload{
+		Type:    apitype.PluginInstall,
+		ID:      id,
+		Message: fmt.Sprintf("Installing %s plugin %s", plugin.Kind, plugin),
+	}
+	if events == nil {
+		fmt.Fprintf(os.Stderr, "[%s plugin %s-%s] installing\n", plugin.Kind, plugin.Name, plugin.Version)
+	} else {
+		events.progressEvent(installing)
+	}
 
 	logging.V(preparePluginVerboseLog).Infof(
 		"installPlugin(%s, %s): extracting tarball to installation directory", plugin.Name, plugin.Version)
-	if err := plugin.InstallWithContext(ctx, workspace.TarPlugin(tarball), false); err != nil {
+	err = plugin.InstallWithContext(ctx, workspace.TarPlugin(tarball), false)
+	if events != nil {
+		installing.Done = true
+		events.progressEvent(installing)
+	}
+	if err != nil {
 		return fmt.Errorf("installing plugin; run `pulumi plugin install %s %s v%s` to retry manually: %w",
 			plugin.Kind, plugin.Name, plugin.Version, err)
 	}
+	if lock != nil {
+		if !locked {
+			lock.Record(plugin, checksum)
+		}
+		if err := lock.RecordInstalled(plugin, locked); err != nil {
+			return err
+		}
+	}
 
 	logging.V(7).Infof("installPlugin(%s, %s): installation complete", plugin.Name, plugin.Version)
 	return nil
 }
 
+// pluginProgressReader reports the progress of a plugin download as progress events.
+type pluginProgressReader struct {
+	io.ReadCloser
+
+	events  *eventEmitter
+	payload ProgressEventPayload
+	last    time.Time
+}
+
+func (r *pluginProgressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.payload.Completed += int64(n)
+	if now := time.Now(); now.Sub(r.last) >= pluginProgressInterval {
+		r.last = now
+		r.events.progressEvent(r.payload)
+	}
+	return n, err
+}
+
+func (r *pluginProgressReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.payload.Done = true
+	r.events.progressEvent(r.payload)
+	return err
+}
+
 // computeDefaultProviderPlugins computes, for every resource plugin, a mapping from packages to semver versions
 // reflecting the version of a provider that should be used as the "default" resource when registering resources. This
 // function takes two sets of plugins: a set of plugins given to us from the language host and the full set of plugins.
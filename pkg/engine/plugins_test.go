@@ -16,11 +16,15 @@ package engine
 
 import (
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 
 	"github.com/blang/semver"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
@@ -214,3 +218,98 @@ func TestDefaultProviderPluginsSorting(t *testing.T) {
 		"foo": p2,
 	}, result)
 }
+
+func TestApplyPluginLock(t *testing.T) {
+	t.Parallel()
+
+	lock := &workspace.PluginLock{Plugins: []workspace.PluginLockEntry{{
+		Kind:              workspace.ResourcePlugin,
+		Name:              "aws",
+		Version:           "5.4.0",
+		PluginDownloadURL: "https://example.com",
+	}}}
+	plugins := newPluginSet(workspace.PluginSpec{
+		Kind: workspace.ResourcePlugin,
+		Name: "aws",
+	}, workspace.PluginSpec{
+		Kind: workspace.LanguagePlugin,
+		Name: "nodejs",
+	}, workspace.PluginSpec{
+		Kind: workspace.ResourcePlugin,
+		Name: "pulumi",
+	})
+
+	// Without a lock, the plugins are unchanged.
+	resolved, err := applyPluginLock(plugins, nil, true)
+	assert.NoError(t, err)
+	assert.Equal(t, plugins, resolved)
+
+	// Locked plugins are pinned, and bundled and builtin plugins are left alone even when locked.
+	resolved, err = applyPluginLock(plugins, lock, true)
+	assert.NoError(t, err)
+	assert.Equal(t, newPluginSet(workspace.PluginSpec{
+		Kind:              workspace.ResourcePlugin,
+		Name:              "aws",
+		Version:           mustMakeVersion("5.4.0"),
+		PluginDownloadURL: "https://example.com",
+	}, workspace.PluginSpec{
+		Kind: workspace.LanguagePlugin,
+		Name: "nodejs",
+	}, workspace.PluginSpec{
+		Kind: workspace.ResourcePlugin,
+		Name: "pulumi",
+	}), resolved)
+
+	// Plugins missing from the lock are only an error when locked.
+	plugins.Add(workspace.PluginSpec{Kind: workspace.ResourcePlugin, Name: "random"})
+	_, err = applyPluginLock(plugins, lock, false)
+	assert.NoError(t, err)
+	_, err = applyPluginLock(plugins, lock, true)
+	assert.ErrorContains(t, err, "resource plugin random is not recorded in Pulumi.lock")
+}
+
+func TestPluginProgressReader(t *testing.T) {
+	t.Parallel()
+
+	ch := make(chan Event, 10)
+	events, err := makeQueryEventEmitter(ch)
+	require.NoError(t, err)
+
+	reader := &pluginProgressReader{
+		ReadCloser: io.NopCloser(strings.NewReader("plugin")),
+		events:     &events,
+		payload: ProgressEventPayload{
+			Type:    apitype.PluginDownload,
+			ID:      "resource:aws-5.4.0",
+			Message: "Downloading resource plugin aws-5.4.0",
+			Total:   6,
+		},
+	}
+	b, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "plugin", string(b))
+	require.NoError(t, reader.Close())
+	events.Close()
+	close(ch)
+
+	// The first read reports progress immediately, and later reads are throttled, but closing the reader always
+	// reports that the download is done.
+	var payloads []ProgressEventPayload
+	for e := range ch {
+		assert.Equal(t, ProgressEvent, e.Type)
+		payloads = append(payloads, e.Payload().(ProgressEventPayload))
+	}
+	require.GreaterOrEqual(t, len(payloads), 2)
+	assert.Equal(t, int64(6), payloads[0].Completed)
+	for _, payload := range payloads[:len(payloads)-1] {
+		assert.False(t, payload.Done)
+	}
+	assert.Equal(t, ProgressEventPayload{
+		Type:      apitype.PluginDownload,
+		ID:        "resource:aws-5.4.0",
+		Message:   "Downloading resource plugin aws-5.4.0",
+		Completed: 6,
+		Total:     6,
+		Done:      true,
+	}, payloads[len(payloads)-1])
+}
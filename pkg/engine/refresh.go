@@ -79,10 +79,16 @@ func newRefreshSource(
 	if err != nil {
 		return nil, err
 	}
+	if plugins, err = applyPluginLock(plugins, opts.PluginLock, opts.Locked); err != nil {
+		return nil, err
+	}
 
 	// Like Update, if we're missing plugins, attempt to download the missing plugins.
-	if err := ensurePluginsAreInstalled(ctx, plugctx.Diag, plugins.Deduplicate(),
-		plugctx.Host.GetProjectPlugins()); err != nil {
+	if err := ensurePluginsAreInstalled(ctx, plugctx, &opts.Events, plugins.Deduplicate(),
+		opts.PluginLock, opts.Locked); err != nil {
+		if opts.Locked {
+			return nil, err
+		}
 		logging.V(7).Infof("newRefreshSource(): failed to install missing plugins: %v", err)
 	}
 
@@ -153,20 +153,41 @@ type UpdateOptions struct {
 	// true if the engine should disable output value support.
 	DisableOutputValues bool
 
+	// The policy for retrying creates and updates that fail with transient errors, if any.
+	Retry *deploy.RetryPolicy
+
 	// true if we should report events for steps that involve default providers.
 	reportDefaultProviderSteps bool
 
 	// the plugin host to use for this update
 	Host plugin.Host
 
+	// The language runtime to run the program with, in place of the project's runtime, if any. This allows programs
+	// to be run in-process.
+	LanguageRuntime plugin.LanguageRuntime
+
+	// Additional environment variables, of the form KEY=VALUE, with which plugins are run.
+	Env []string
+
 	// The plan to use for the update, if any.
 	Plan *deploy.Plan
 
+	// VerifyPlan when true causes deviations from Plan to be reported at the end of the update, rather than the update
+	// failing at the first deviation.
+	VerifyPlan bool
+
 	// GeneratePlan when true cause plans to be generated, we skip this if we know their not needed (e.g. during up)
 	GeneratePlan bool
 
 	// Experimental is true if the engine is in experimental mode (i.e. PULUMI_EXPERIMENTAL was set)
 	Experimental bool
+
+	// The project's plugin lock, if any. Plugins are resolved against the lock before they are installed, and the
+	// plugins that are installed or already present are recorded in it.
+	PluginLock *workspace.PluginLock
+
+	// true if plugins that are missing from PluginLock, or that differ from it, are an error rather than being recorded.
+	Locked bool
 }
 
 // HasChanges returns true if there are any non-same changes in the resulting summary.
@@ -221,13 +242,14 @@ func Update(u UpdateInfo, ctx *Context, opts UpdateOptions, dryRun bool) (
 func RunInstallPlugins(
 	proj *workspace.Project, pwd, main string, target *deploy.Target, plugctx *plugin.Context,
 ) error {
-	_, _, err := installPlugins(context.Background(), proj, pwd, main, target, plugctx, true /*returnInstallErrors*/)
+	_, _, err := installPlugins(context.Background(), proj, pwd, main, target, plugctx,
+		nil /*events*/, nil /*lock*/, false /*locked*/, true /*returnInstallErrors*/)
 	return err
 }
 
 func installPlugins(ctx context.Context,
 	proj *workspace.Project, pwd, main string, target *deploy.Target,
-	plugctx *plugin.Context, returnInstallErrors bool,
+	plugctx *plugin.Context, events *eventEmitter, lock *workspace.PluginLock, locked, returnInstallErrors bool,
 ) (pluginSet, map[tokens.Package]workspace.PluginSpec, error) {
 	// Before launching the source, ensure that we have all of the plugins that we need in order to proceed.
 	//
@@ -254,16 +276,24 @@ func installPlugins(ctx context.Context,
 		return nil, nil, err
 	}
 
+	// Pin the plugins to the versions in the project's lock, if it has one. Both sets are resolved, rather than just
+	// their union, so that the default providers computed below also use the locked versions.
+	if languagePlugins, err = applyPluginLock(languagePlugins, lock, locked); err != nil {
+		return nil, nil, err
+	}
+	if snapshotPlugins, err = applyPluginLock(snapshotPlugins, lock, locked); err != nil {
+		return nil, nil, err
+	}
+
 	allPlugins := languagePlugins.Union(snapshotPlugins)
 
 	// If there are any plugins that are not available, we can attempt to install them here.
 	//
 	// Note that this is purely a best-effort thing. If we can't install missing plugins, just proceed; we'll fail later
-	// with an error message indicating exactly what plugins are missing. If `returnInstallErrors` is set, then return
-	// the error.
-	if err := ensurePluginsAreInstalled(ctx, plugctx.Diag, allPlugins.Deduplicate(),
-		plugctx.Host.GetProjectPlugins()); err != nil {
-		if returnInstallErrors {
+	// with an error message indicating exactly what plugins are missing. If `returnInstallErrors` is set, or the
+	// plugins are locked (in which case a plugin that fails its checksum must not be used), then return the error.
+	if err := ensurePluginsAreInstalled(ctx, plugctx, events, allPlugins.Deduplicate(), lock, locked); err != nil {
+		if returnInstallErrors || locked {
 			return nil, nil, err
 		}
 		logging.V(7).Infof("newUpdateSource(): failed to install missing plugins: %v", err)
@@ -398,7 +428,7 @@ func newUpdateSource(ctx context.Context,
 	//
 
 	allPlugins, defaultProviderVersions, err := installPlugins(ctx, proj, pwd, main, target,
-		plugctx, false /*returnInstallErrors*/)
+		plugctx, &opts.Events, opts.PluginLock, opts.Locked, false /*returnInstallErrors*/)
 	if err != nil {
 		return nil, err
 	}
@@ -431,9 +461,9 @@ func newUpdateSource(ctx context.Context,
 		return nil, err
 	}
 
-	// If we are connecting to an existing client, stash the address of the engine in its arguments.
+	// If the program is run by an existing client or in-process, stash the address of the engine in its arguments.
 	var args []string
-	if proj.Runtime.Name() == clientRuntimeName {
+	if proj.Runtime.Name() == clientRuntimeName || opts.LanguageRuntime != nil {
 		args = []string{plugctx.Host.ServerAddr()}
 	}
 
@@ -519,6 +549,12 @@ func newUpdateActions(context *Context, u UpdateInfo, opts *deploymentOptions) *
 	}
 }
 
+func (acts *updateActions) OnResourceStepWaiting(step deploy.Step) {
+	if shouldReportStep(step, acts.Opts) {
+		acts.Opts.Events.resourceWaitingEvent(step, false /*planning*/, acts.Opts.Debug)
+	}
+}
+
 func (acts *updateActions) OnResourceStepPre(step deploy.Step) (interface{}, error) {
 	// Ensure we've marked this step as observed.
 	acts.MapLock.Lock()
@@ -691,6 +727,12 @@ func newPreviewActions(opts *deploymentOptions) *previewActions {
 	}
 }
 
+func (acts *previewActions) OnResourceStepWaiting(step deploy.Step) {
+	if shouldReportStep(step, acts.Opts) {
+		acts.Opts.Events.resourceWaitingEvent(step, true /*planning*/, acts.Opts.Debug)
+	}
+}
+
 func (acts *previewActions) OnResourceStepPre(step deploy.Step) (interface{}, error) {
 	acts.MapLock.Lock()
 	acts.Seen[step.URN()] = step
@@ -39,6 +39,15 @@ var Null = &model.Variable{
 
 // GenerateHCL2Definition generates a Pulumi HCL2 definition for a given resource.
 func GenerateHCL2Definition(loader schema.Loader, state *resource.State, names NameTable) (*model.Block, error) {
+	return GenerateHCL2DefinitionWithReferences(loader, state, NewImportState(names, nil, nil))
+}
+
+// GenerateHCL2DefinitionWithReferences generates a Pulumi HCL2 definition for a given resource. Property values that
+// are equal to the IDs or ARNs of other resources known to the import state are generated as references to those
+// resources.
+func GenerateHCL2DefinitionWithReferences(
+	loader schema.Loader, state *resource.State, importState *ImportState,
+) (*model.Block, error) {
 	// TODO: pull the package version from the resource's provider
 	pkg, err := schema.LoadPackageReference(loader, string(state.Type.Package()), nil)
 	if err != nil {
@@ -53,9 +62,11 @@ func GenerateHCL2Definition(loader schema.Loader, state *resource.State, names N
 		return nil, fmt.Errorf("unknown resource type '%v'", r)
 	}
 
+	refs := importState.references(state.URN)
+
 	var items []model.BodyItem
 	for _, p := range r.InputProperties {
-		x, err := generatePropertyValue(p, state.Inputs[resource.PropertyKey(p.Name)])
+		x, err := generatePropertyValue(p, state.Inputs[resource.PropertyKey(p.Name)], refs)
 		if err != nil {
 			return nil, err
 		}
@@ -67,7 +78,7 @@ func GenerateHCL2Definition(loader schema.Loader, state *resource.State, names N
 		}
 	}
 
-	resourceOptions, err := makeResourceOptions(state, names)
+	resourceOptions, err := makeResourceOptions(state, importState.Names)
 	if err != nil {
 		return nil, err
 	}
@@ -332,15 +343,15 @@ func zeroValue(t schema.Type) model.Expression {
 	}
 	switch t {
 	case schema.BoolType:
-		x, err := generateValue(t, resource.NewBoolProperty(false))
+		x, err := generateValue(t, resource.NewBoolProperty(false), nil)
 		contract.IgnoreError(err)
 		return x
 	case schema.IntType, schema.NumberType:
-		x, err := generateValue(t, resource.NewNumberProperty(0))
+		x, err := generateValue(t, resource.NewNumberProperty(0), nil)
 		contract.IgnoreError(err)
 		return x
 	case schema.StringType:
-		x, err := generateValue(t, resource.NewStringProperty(""))
+		x, err := generateValue(t, resource.NewStringProperty(""), nil)
 		contract.IgnoreError(err)
 		return x
 	case schema.ArchiveType, schema.AssetType:
@@ -356,7 +367,9 @@ func zeroValue(t schema.Type) model.Expression {
 // generatePropertyValue generates the value for the given property. If the value is absent and the property is
 // required, a zero value for the property's type is generated. If the value is absent and the property is not
 // required, no value is generated (i.e. this function returns nil).
-func generatePropertyValue(property *schema.Property, value resource.PropertyValue) (model.Expression, error) {
+func generatePropertyValue(
+	property *schema.Property, value resource.PropertyValue, refs referenceFunc,
+) (model.Expression, error) {
 	if !value.HasValue() {
 		if !property.IsRequired() {
 			return nil, nil
@@ -364,7 +377,7 @@ func generatePropertyValue(property *schema.Property, value resource.PropertyVal
 		return zeroValue(property.Type), nil
 	}
 
-	return generateValue(property.Type, value)
+	return generateValue(property.Type, value, refs)
 }
 
 // valueStructurallyTypedAs returns true if the given value is structurally typed as the given schema type.
@@ -575,8 +588,9 @@ func reduceUnionType(schemaUnion *schema.UnionType, value resource.PropertyValue
 }
 
 // generateValue generates a value from the given property value. The given type may or may not match the shape of the
-// given value.
-func generateValue(typ schema.Type, value resource.PropertyValue) (model.Expression, error) {
+// given value. If refs is non-nil, string values that are equal to the outputs of other resources are generated as
+// references to those outputs.
+func generateValue(typ schema.Type, value resource.PropertyValue, refs referenceFunc) (model.Expression, error) {
 	typ = codegen.UnwrapType(typ)
 
 	if unionType, ok := typ.(*schema.UnionType); ok {
@@ -595,7 +609,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 		arr := value.ArrayValue()
 		exprs := make([]model.Expression, len(arr))
 		for i, v := range arr {
-			x, err := generateValue(elementType, v)
+			x, err := generateValue(elementType, v, refs)
 			if err != nil {
 				return nil, err
 			}
@@ -626,7 +640,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 		switch arg := typ.(type) {
 		case *schema.ObjectType:
 			for _, p := range arg.Properties {
-				x, err := generatePropertyValue(p, obj[resource.PropertyKey(p.Name)])
+				x, err := generatePropertyValue(p, obj[resource.PropertyKey(p.Name)], refs)
 				if err != nil {
 					return nil, err
 				}
@@ -652,7 +666,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 					continue
 				}
 
-				x, err := generateValue(elementType, obj[k])
+				x, err := generateValue(elementType, obj[k], refs)
 				if err != nil {
 					return nil, err
 				}
@@ -673,7 +687,7 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 			Items:  items,
 		}, nil
 	case value.IsSecret():
-		arg, err := generateValue(typ, value.SecretValue().Element)
+		arg, err := generateValue(typ, value.SecretValue().Element, refs)
 		if err != nil {
 			return nil, err
 		}
@@ -689,6 +703,12 @@ func generateValue(typ schema.Type, value resource.PropertyValue) (model.Express
 			Args: []model.Expression{arg},
 		}, nil
 	case value.IsString():
+		if refs != nil && typ != schema.ArchiveType && typ != schema.AssetType {
+			if x, ok := refs(value.StringValue()); ok {
+				return x, nil
+			}
+		}
+
 		x := &model.TemplateExpression{
 			Parts: []model.Expression{
 				&model.LiteralValueExpression{
@@ -64,9 +64,20 @@ func (e *DiagnosticsError) String() string {
 func GenerateLanguageDefinitions(w io.Writer, loader schema.Loader, gen LanguageGenerator, states []*resource.State,
 	names NameTable,
 ) error {
+	return GenerateLanguageDefinitionsWithReferences(w, loader, gen, states, nil, names)
+}
+
+// GenerateLanguageDefinitionsWithReferences generates a list of resource definitions from the given resource states.
+// Properties of these resources that refer to the IDs or ARNs of other imported resources, or of resources in the
+// snapshot that have an entry in the name table, are generated as references to those resources.
+func GenerateLanguageDefinitionsWithReferences(w io.Writer, loader schema.Loader, gen LanguageGenerator,
+	states []*resource.State, snapshot []*resource.State, names NameTable,
+) error {
+	importState := NewImportState(names, states, snapshot)
+
 	var hcl2Text bytes.Buffer
 	for i, state := range states {
-		hcl2Def, err := GenerateHCL2Definition(loader, state, names)
+		hcl2Def, err := GenerateHCL2DefinitionWithReferences(loader, state, importState)
 		if err != nil {
 			return err
 		}
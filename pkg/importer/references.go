@@ -0,0 +1,201 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+// An outputReference identifies a single output of a resource that can be referred to by name in generated code.
+type outputReference struct {
+	urn      resource.URN // the URN of the referenced resource.
+	name     string       // the variable name of the referenced resource.
+	property string       // the name of the referenced output.
+}
+
+// expression returns a traversal expression of the form `name.property` that refers to the output.
+func (r outputReference) expression() model.Expression {
+	return &model.ScopeTraversalExpression{
+		RootName: r.name,
+		Traversal: hcl.Traversal{
+			hcl.TraverseRoot{Name: r.name},
+			hcl.TraverseAttr{Name: r.property},
+		},
+		Parts: []model.Traversable{
+			&model.Variable{Name: r.name, VariableType: model.DynamicType},
+			model.DynamicType,
+		},
+	}
+}
+
+// A referenceFunc returns an expression that refers to the output of another resource whose value is equal to the
+// given string, if any such output exists.
+type referenceFunc func(value string) (model.Expression, bool)
+
+// An ImportState records the resources that generated definitions may refer to. When a property of a resource being
+// imported is equal to the ID or an output of another resource that is either part of the same import or named in the
+// NameTable, the generated definition refers to that output rather than repeating its literal value.
+type ImportState struct {
+	// Names maps URNs to the variable names of existing resources that may be referenced by generated definitions.
+	Names NameTable
+
+	// ids maps resource IDs to the resources they identify.
+	ids map[string][]outputReference
+	// values maps the values of other identifying outputs (e.g. ARNs) to the outputs they were read from.
+	values map[string][]outputReference
+	// dependencies records the references that have already been generated, keyed by the referencing resource. It is
+	// used to avoid generating reference cycles between imported resources.
+	dependencies map[resource.URN]map[resource.URN]bool
+}
+
+// NewImportState creates an ImportState for the given set of resources. The imported resources are referred to by the
+// names of their definitions. The resources in the snapshot are only referred to if they have an entry in the name
+// table.
+func NewImportState(names NameTable, imports []*resource.State, snapshot []*resource.State) *ImportState {
+	s := &ImportState{
+		Names:        names,
+		ids:          map[string][]outputReference{},
+		values:       map[string][]outputReference{},
+		dependencies: map[resource.URN]map[resource.URN]bool{},
+	}
+
+	seen := map[resource.URN]bool{}
+	for _, state := range imports {
+		s.addOutputs(state, string(state.URN.Name()))
+		seen[state.URN] = true
+	}
+	for _, state := range snapshot {
+		if seen[state.URN] {
+			continue
+		}
+		if name, ok := names[state.URN]; ok {
+			s.addOutputs(state, name)
+		}
+	}
+	return s
+}
+
+// isIdentifyingOutput returns true if the given output identifies the resource it was read from, in the same way as
+// the resource's ID. Other outputs, such as names or regions, commonly share values with unrelated resources, so
+// referring to them would introduce false dependencies.
+func isIdentifyingOutput(key resource.PropertyKey, value string) bool {
+	switch key {
+	case "arn", "selfLink":
+		return true
+	}
+	return strings.HasPrefix(value, "arn:")
+}
+
+// addOutputs records the ID and the top-level identifying outputs of the given resource as referencable values.
+func (s *ImportState) addOutputs(state *resource.State, name string) {
+	if !state.Custom || state.Delete || providers.IsDefaultProvider(state.URN) {
+		return
+	}
+
+	if state.ID != "" {
+		id := string(state.ID)
+		s.ids[id] = append(s.ids[id], outputReference{urn: state.URN, name: name, property: "id"})
+	}
+	for _, k := range state.Outputs.StableKeys() {
+		v := state.Outputs[k]
+		if k == "id" || !v.IsString() || !isIdentifyingOutput(k, v.StringValue()) {
+			continue
+		}
+		s.values[v.StringValue()] = append(s.values[v.StringValue()],
+			outputReference{urn: state.URN, name: name, property: string(k)})
+	}
+}
+
+// lookup returns the single output of a resource other than the resource with the given URN in the given table that
+// has the given value. If there are no such outputs or the value is ambiguous, lookup returns false.
+func lookup(table map[string][]outputReference, urn resource.URN, value string) (outputReference, bool) {
+	var result outputReference
+	found := false
+	for _, ref := range table[value] {
+		if ref.urn == urn || ref == result {
+			continue
+		}
+		if found {
+			return outputReference{}, false
+		}
+		result, found = ref, true
+	}
+	return result, found
+}
+
+// references returns a referenceFunc for the definition of the resource with the given URN.
+func (s *ImportState) references(urn resource.URN) referenceFunc {
+	if s == nil {
+		return nil
+	}
+
+	return func(value string) (model.Expression, bool) {
+		// Prefer references to resource IDs. The outputs of resources commonly repeat the IDs of the resources they
+		// refer to, so these are only considered if the value is not the ID of any other resource. In either case,
+		// values that are ambiguous are not referenced, as we can't know which resource the value was meant to refer
+		// to.
+		ref, ok := lookup(s.ids, urn, value)
+		if !ok {
+			if _, isID := s.ids[value]; isID {
+				return nil, false
+			}
+			if ref, ok = lookup(s.values, urn, value); !ok {
+				return nil, false
+			}
+		}
+		if s.dependsOn(ref.urn, urn) {
+			return nil, false
+		}
+
+		deps, ok := s.dependencies[urn]
+		if !ok {
+			deps = map[resource.URN]bool{}
+			s.dependencies[urn] = deps
+		}
+		deps[ref.urn] = true
+
+		return ref.expression(), true
+	}
+}
+
+// dependsOn returns true if the resource with URN from transitively refers to the resource with URN to.
+func (s *ImportState) dependsOn(from, to resource.URN) bool {
+	visited := map[resource.URN]bool{}
+
+	var visit func(urn resource.URN) bool
+	visit = func(urn resource.URN) bool {
+		if urn == to {
+			return true
+		}
+		if visited[urn] {
+			return false
+		}
+		visited[urn] = true
+
+		for dep := range s.dependencies[urn] {
+			if visit(dep) {
+				return true
+			}
+		}
+		return false
+	}
+	return visit(from)
+}
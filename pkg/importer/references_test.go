@@ -0,0 +1,123 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package importer
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pulumi/pulumi/pkg/v3/codegen/hcl2/model"
+	"github.com/pulumi/pulumi/pkg/v3/codegen/schema"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func makeState(typ tokens.Type, name tokens.QName, id string, outputs resource.PropertyMap) *resource.State {
+	return &resource.State{
+		URN:     resource.NewURN("stack", "project", "", typ, name),
+		Type:    typ,
+		ID:      resource.ID(id),
+		Custom:  true,
+		Outputs: outputs,
+	}
+}
+
+func TestImportStateReferences(t *testing.T) {
+	t.Parallel()
+
+	vpc := makeState("aws:ec2/vpc:Vpc", "vpc", "vpc-123", resource.PropertyMap{
+		"arn":    resource.NewStringProperty("arn:aws:ec2:vpc/vpc-123"),
+		"region": resource.NewStringProperty("us-west-2"),
+		"name":   resource.NewStringProperty("main"),
+	})
+	subnet := makeState("aws:ec2/subnet:Subnet", "subnet", "subnet-456", resource.PropertyMap{
+		"vpcId": resource.NewStringProperty("vpc-123"),
+	})
+	existing := makeState("aws:ec2/internetGateway:InternetGateway", "gw", "igw-789", resource.PropertyMap{
+		"arn": resource.NewStringProperty("arn:aws:ec2:igw/shared"),
+	})
+	other := makeState("aws:ec2/routeTable:RouteTable", "rt", "rtb-000", resource.PropertyMap{
+		"arn": resource.NewStringProperty("arn:aws:ec2:igw/shared"),
+	})
+	unnamed := makeState("aws:ec2/internetGateway:InternetGateway", "other", "igw-000", nil)
+
+	s := NewImportState(NameTable{existing.URN: "gateway"},
+		[]*resource.State{vpc, subnet, other}, []*resource.State{existing, unnamed})
+
+	render := func(x model.Expression) string {
+		return fmt.Sprintf("%v", x)
+	}
+
+	refs := s.references(subnet.URN)
+
+	// IDs and outputs of other imported resources are referenced.
+	x, ok := refs("vpc-123")
+	require.True(t, ok)
+	assert.Equal(t, "vpc.id", render(x))
+
+	x, ok = refs("arn:aws:ec2:vpc/vpc-123")
+	require.True(t, ok)
+	assert.Equal(t, "vpc.arn", render(x))
+
+	// Named resources in the snapshot are referenced by their names.
+	x, ok = refs("igw-789")
+	require.True(t, ok)
+	assert.Equal(t, "gateway.id", render(x))
+
+	// Resources without a name are not referenced.
+	_, ok = refs("igw-000")
+	assert.False(t, ok)
+
+	// Outputs that don't identify a resource are not referenced, even if they are unique.
+	_, ok = refs("us-west-2")
+	assert.False(t, ok)
+	_, ok = refs("main")
+	assert.False(t, ok)
+
+	// Ambiguous values are not referenced.
+	_, ok = refs("arn:aws:ec2:igw/shared")
+	assert.False(t, ok)
+
+	// A resource never refers to itself.
+	_, ok = refs("subnet-456")
+	assert.False(t, ok)
+
+	// The subnet now refers to the VPC, so the VPC must not refer back to the subnet.
+	_, ok = s.references(vpc.URN)("subnet-456")
+	assert.False(t, ok)
+}
+
+func TestGenerateValueReferences(t *testing.T) {
+	t.Parallel()
+
+	vpc := makeState("aws:ec2/vpc:Vpc", "vpc", "vpc-123", nil)
+	subnet := makeState("aws:ec2/subnet:Subnet", "subnet", "subnet-456", nil)
+	s := NewImportState(nil, []*resource.State{vpc, subnet}, nil)
+
+	value := resource.NewArrayProperty([]resource.PropertyValue{
+		resource.NewStringProperty("vpc-123"),
+		resource.NewStringProperty("sg-123"),
+	})
+
+	x, err := generateValue(schema.AnyType, value, s.references(subnet.URN))
+	require.NoError(t, err)
+	assert.Equal(t, "[\n    vpc.id,\n    \"sg-123\"]", fmt.Sprintf("%v", x))
+
+	x, err = generateValue(schema.AnyType, value, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "[\n    \"vpc-123\",\n    \"sg-123\"]", fmt.Sprintf("%v", x))
+}
@@ -63,6 +63,9 @@ type Options struct {
 	DisableResourceReferences bool       // true to disable resource reference support.
 	DisableOutputValues       bool       // true to disable output value support.
 	GeneratePlan              bool       // true to enable plan generation.
+	VerifyPlan                bool       // true to report deviations from the plan at the end, rather than failing.
+	// an optional policy for retrying creates and updates that fail with transient errors.
+	Retry *RetryPolicy
 }
 
 // DegreeOfParallelism returns the degree of parallelism that should be used during the
@@ -177,6 +180,9 @@ func (t *UrnTargets) addLiteral(urn resource.URN) {
 
 // StepExecutorEvents is an interface that can be used to hook resource lifecycle events.
 type StepExecutorEvents interface {
+	// OnResourceStepWaiting is called when a step has to wait for a per-provider or per-type parallelism limit
+	// before it can run. OnResourceStepPre is called once it does.
+	OnResourceStepWaiting(step Step)
 	OnResourceStepPre(step Step) (interface{}, error)
 	OnResourceStepPost(ctx interface{}, step Step, status resource.Status, err error) error
 	OnResourceOutputs(step Step) error
@@ -286,6 +292,7 @@ type Deployment struct {
 	goals                *goalMap                         // the set of resource goals generated by the deployment.
 	news                 *resourceMap                     // the set of new resources generated by the deployment
 	newPlans             *resourcePlans                   // the set of new resource plans.
+	planViolations       planViolations                   // the deviations from the plan, if it's being verified.
 }
 
 // addDefaultProviders adds any necessary default provider definitions and references to the given snapshot. Version
@@ -294,6 +294,9 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 				}
 
 				rErr := fmt.Errorf("expected resource operations for %v but none were seen", urn)
+				if rErr = ex.deployment.violatePlan(opts, urn, rErr); rErr == nil {
+					continue
+				}
 				logging.V(4).Infof("deploymentExecutor.Execute(...): error handling event: %v", rErr)
 				ex.reportError(urn, rErr)
 				err = errors.Join(err, rErr)
@@ -305,6 +308,27 @@ func (ex *deploymentExecutor) Execute(callerCtx context.Context, opts Options, p
 		}
 	}
 
+	// If the plan is being verified, report every deviation from it now that the deployment is done. Previews only warn
+	// about the deviations, so that the update that follows can still run and report them as errors.
+	if violations := ex.deployment.planViolations.list(); len(violations) > 0 {
+		for _, v := range violations {
+			if preview {
+				ex.deployment.Diag().Warningf(diag.RawMessage(v.urn, v.err.Error()))
+			} else {
+				ex.reportError(v.urn, v.err)
+			}
+		}
+		if !preview && err == nil {
+			var suffix string
+			if len(violations) != 1 {
+				suffix = "s"
+			}
+			vErr := fmt.Errorf("the update deviated from its plan in %d way%s", len(violations), suffix)
+			ex.reportError("", vErr)
+			err = result.BailError(vErr)
+		}
+	}
+
 	if err != nil && result.IsBail(err) {
 		return nil, err
 	}
@@ -70,6 +70,8 @@ type Provider struct {
 
 	GetMappingF  func(key, provider string) ([]byte, string, error)
 	GetMappingsF func(key string) ([]string, error)
+
+	ListResourcesF func(typ tokens.Type, filters resource.PropertyMap) ([]plugin.ListedResource, error)
 }
 
 func (prov *Provider) SignalCancellation() error {
@@ -250,3 +252,12 @@ func (prov *Provider) GetMappings(key string) ([]string, error) {
 	}
 	return prov.GetMappingsF(key)
 }
+
+func (prov *Provider) ListResources(
+	typ tokens.Type, filters resource.PropertyMap,
+) ([]plugin.ListedResource, error) {
+	if prov.ListResourcesF == nil {
+		return nil, plugin.ErrNotYetImplemented
+	}
+	return prov.ListResourcesF(typ, filters)
+}
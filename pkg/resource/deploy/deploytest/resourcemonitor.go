@@ -143,6 +143,8 @@ type ResourceOptions struct {
 	Providers               map[string]string
 	AdditionalSecretOutputs []resource.PropertyKey
 	AliasSpecs              bool
+	Parallelism             int32
+	Retries                 int32
 
 	SourcePosition            string
 	DisableSecrets            bool
@@ -277,6 +279,8 @@ func (rm *ResourceMonitor) RegisterResource(t tokens.Type, name string, custom b
 		DeletedWith:                string(opts.DeletedWith),
 		AliasSpecs:                 opts.AliasSpecs,
 		SourcePosition:             sourcePosition,
+		Parallelism:                opts.Parallelism,
+		Retries:                    opts.Retries,
 	}
 
 	ctx := context.Background()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/mitchellh/copystructure"
@@ -15,6 +16,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
 )
 
 // A Plan is a mapping from URNs to ResourcePlans. The plan defines an expected set of resources and the expected
@@ -50,6 +52,44 @@ func (plan *Plan) Clone() *Plan {
 	return copystructure.Must(copystructure.Copy(plan)).(*Plan)
 }
 
+// planViolation is a deviation of a deployment from its plan.
+type planViolation struct {
+	urn resource.URN
+	err error
+}
+
+// planViolations records the deviations of a deployment from its plan, when the plan is being verified rather than
+// enforced. It is safe for concurrent use.
+type planViolations struct {
+	m          sync.Mutex
+	violations []planViolation
+}
+
+func (v *planViolations) add(urn resource.URN, err error) {
+	v.m.Lock()
+	defer v.m.Unlock()
+	v.violations = append(v.violations, planViolation{urn: urn, err: err})
+}
+
+// list returns the deviations recorded so far, in the order they were recorded.
+func (v *planViolations) list() []planViolation {
+	v.m.Lock()
+	defer v.m.Unlock()
+	return append([]planViolation(nil), v.violations...)
+}
+
+// violatePlan handles a deviation of the given resource from the deployment's plan. If the plan is being verified, the
+// deviation is recorded to be reported once the deployment is done, and nil is returned so that the deployment carries
+// on. Otherwise the deviation is returned as an error.
+func (d *Deployment) violatePlan(opts Options, urn resource.URN, err error) error {
+	if !opts.VerifyPlan {
+		return err
+	}
+	logging.V(4).Infof("resource %v deviates from the plan: %v", urn, err)
+	d.planViolations.add(urn, err)
+	return nil
+}
+
 // PlanDiff holds the results of diffing two object property maps.
 type PlanDiff struct {
 	Adds    resource.PropertyMap   // the resource's properties we expect to add.
@@ -218,7 +258,7 @@ func (rp *ResourcePlan) diffPropertyKeys(a, b []resource.PropertyKey) (message s
 	for i, key := range a {
 		stringsA[i] = string(key)
 	}
-	stringsB := make([]string, len(a))
+	stringsB := make([]string, len(b))
 	for i, key := range b {
 		stringsB[i] = string(key)
 	}
@@ -631,3 +671,149 @@ func (rp *ResourcePlan) checkGoal(
 
 	return nil
 }
+
+// A ResourcePlanChange describes how the plan for a resource differs between two plans.
+type ResourcePlanChange struct {
+	URN resource.URN
+	// The resource's plan in the old plan, or nil if the new plan added the resource.
+	Old *ResourcePlan
+	// The resource's plan in the new plan, or nil if the new plan removed the resource.
+	New *ResourcePlan
+	// Descriptions of the differences between the resource's old and new plans, if it's in both.
+	Differences []string
+}
+
+// DiffPlans compares two plans, and returns the changes to the plans of the resources that differ between them, in
+// URN order.
+func DiffPlans(old, new *Plan) []ResourcePlanChange {
+	urns := map[resource.URN]struct{}{}
+	for urn := range old.ResourcePlans {
+		urns[urn] = struct{}{}
+	}
+	for urn := range new.ResourcePlans {
+		urns[urn] = struct{}{}
+	}
+	sorted := make([]resource.URN, 0, len(urns))
+	for urn := range urns {
+		sorted = append(sorted, urn)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var changes []ResourcePlanChange
+	for _, urn := range sorted {
+		oldPlan, newPlan := old.ResourcePlans[urn], new.ResourcePlans[urn]
+		if oldPlan == nil || newPlan == nil {
+			changes = append(changes, ResourcePlanChange{URN: urn, Old: oldPlan, New: newPlan})
+		} else if differences := oldPlan.diff(newPlan); len(differences) > 0 {
+			changes = append(changes, ResourcePlanChange{
+				URN:         urn,
+				Old:         oldPlan,
+				New:         newPlan,
+				Differences: differences,
+			})
+		}
+	}
+	return changes
+}
+
+// diff returns descriptions of the differences between this resource plan and another plan for the same resource.
+func (rp *ResourcePlan) diff(other *ResourcePlan) []string {
+	var differences []string
+	differ := func(what string, old, new interface{}) {
+		differences = append(differences, fmt.Sprintf("%s changed: %v => %v", what, old, new))
+	}
+
+	if fmt.Sprint(rp.Ops) != fmt.Sprint(other.Ops) {
+		differ("operations", rp.Ops, other.Ops)
+	}
+
+	old, new := rp.Goal, other.Goal
+	switch {
+	case old == nil && new == nil:
+		return differences
+	case old == nil:
+		return append(differences, "goal added")
+	case new == nil:
+		return append(differences, "goal removed")
+	}
+
+	if old.Custom != new.Custom {
+		differ("custom", old.Custom, new.Custom)
+	}
+	if old.Provider != new.Provider {
+		differ("provider", old.Provider, new.Provider)
+	}
+	if old.Parent != new.Parent {
+		differ("parent", old.Parent, new.Parent)
+	}
+	if old.Protect != new.Protect {
+		differ("protect", old.Protect, new.Protect)
+	}
+	describeDBR := func(dbr *bool) string {
+		if dbr == nil {
+			return "no value"
+		}
+		return fmt.Sprint(*dbr)
+	}
+	if oldDBR, newDBR := describeDBR(old.DeleteBeforeReplace), describeDBR(new.DeleteBeforeReplace); oldDBR != newDBR {
+		differ("deleteBeforeReplace", oldDBR, newDBR)
+	}
+	if old.ID != new.ID {
+		differ("importID", old.ID, new.ID)
+	}
+	if old.CustomTimeouts != new.CustomTimeouts {
+		differ("customTimeouts", old.CustomTimeouts, new.CustomTimeouts)
+	}
+	if message, changed := rp.diffStringSets(old.IgnoreChanges, new.IgnoreChanges); changed {
+		differences = append(differences, "ignoreChanges changed: "+message)
+	}
+	if message, changed := rp.diffPropertyKeys(old.AdditionalSecretOutputs, new.AdditionalSecretOutputs); changed {
+		differences = append(differences, "additionalSecretOutputs changed: "+message)
+	}
+	if message, changed := rp.diffURNs(old.Dependencies, new.Dependencies); changed {
+		differences = append(differences, "dependencies changed: "+message)
+	}
+	if message, changed := rp.diffAliases(old.Aliases, new.Aliases); changed {
+		differences = append(differences, "aliases changed: "+message)
+	}
+	differences = append(differences, old.InputDiff.diff(new.InputDiff, "inputs")...)
+	differences = append(differences, old.OutputDiff.diff(new.OutputDiff, "outputs")...)
+	return differences
+}
+
+// describe returns a description of the change planned for the given property, in the same form as MakeError's.
+func (planDiff *PlanDiff) describe(key resource.PropertyKey) string {
+	if value, has := planDiff.Adds[key]; has {
+		return "+" + string(key) + "[" + value.String() + "]"
+	} else if value, has := planDiff.Updates[key]; has {
+		return "~" + string(key) + "[" + value.String() + "]"
+	} else if planDiff.ContainsDelete(key) {
+		return "-" + string(key)
+	}
+	return "=" + string(key)
+}
+
+// diff returns descriptions of the properties whose planned changes differ between this diff and another.
+func (planDiff *PlanDiff) diff(other PlanDiff, what string) []string {
+	keys := map[resource.PropertyKey]struct{}{}
+	for _, d := range []*PlanDiff{planDiff, &other} {
+		for k := range d.Adds {
+			keys[k] = struct{}{}
+		}
+		for k := range d.Updates {
+			keys[k] = struct{}{}
+		}
+		for _, k := range d.Deletes {
+			keys[k] = struct{}{}
+		}
+	}
+
+	var differences []string
+	for k := range keys {
+		if old, new := planDiff.describe(k), other.describe(k); old != new {
+			differences = append(differences, fmt.Sprintf("%s changed: %s => %s", what, old, new))
+		}
+	}
+	sort.Strings(differences)
+	return differences
+}
@@ -0,0 +1,76 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/display"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestDiffPlans(t *testing.T) {
+	t.Parallel()
+
+	const (
+		resA = resource.URN("urn:pulumi:test::test::pkgA:m:typA::resA")
+		resB = resource.URN("urn:pulumi:test::test::pkgA:m:typA::resB")
+		resC = resource.URN("urn:pulumi:test::test::pkgA:m:typA::resC")
+		resD = resource.URN("urn:pulumi:test::test::pkgA:m:typA::resD")
+	)
+	makePlan := func(protect bool, foo string, ops ...display.StepOp) *ResourcePlan {
+		return &ResourcePlan{
+			Goal: &GoalPlan{
+				Type:    "pkgA:m:typA",
+				Custom:  true,
+				Protect: protect,
+				InputDiff: PlanDiff{
+					Updates: resource.PropertyMap{"foo": resource.NewStringProperty(foo)},
+				},
+			},
+			Ops: ops,
+		}
+	}
+
+	old := &Plan{ResourcePlans: map[resource.URN]*ResourcePlan{
+		resA: makePlan(false, "bar", OpUpdate),
+		resB: makePlan(false, "bar", OpUpdate),
+		resC: makePlan(false, "bar", OpSame),
+	}}
+	new := &Plan{ResourcePlans: map[resource.URN]*ResourcePlan{
+		resA: makePlan(true, "baz", OpReplace),
+		resB: makePlan(false, "bar", OpUpdate),
+		resD: makePlan(false, "bar", OpCreate),
+	}}
+
+	assert.Equal(t, []ResourcePlanChange{
+		{
+			URN: resA,
+			Old: old.ResourcePlans[resA],
+			New: new.ResourcePlans[resA],
+			Differences: []string{
+				"operations changed: [update] => [replace]",
+				"protect changed: false => true",
+				"inputs changed: ~foo[{bar}] => ~foo[{baz}]",
+			},
+		},
+		{URN: resC, Old: old.ResourcePlans[resC]},
+		{URN: resD, New: new.ResourcePlans[resD]},
+	}, DiffPlans(old, new))
+
+	assert.Empty(t, DiffPlans(old, old))
+}
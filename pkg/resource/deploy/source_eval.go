@@ -719,6 +719,10 @@ func (rm *resmon) SupportsFeature(ctx context.Context,
 		hasSupport = true
 	case "deletedWith":
 		hasSupport = true
+	case "parallelism":
+		hasSupport = true
+	case "retries":
+		hasSupport = true
 	}
 
 	logging.V(5).Infof("ResourceMonitor.SupportsFeature(id: %s) = %t", req.Id, hasSupport)
@@ -1474,6 +1478,10 @@ func (rm *resmon) RegisterResource(ctx context.Context,
 			additionalSecretKeys, aliases, id, &timeouts, replaceOnChanges, retainOnDelete, deletedWith,
 			sourcePosition,
 		)
+		goal.Retries = int(req.GetRetries())
+		if providers.IsProviderType(t) {
+			goal.Parallelism = int(req.GetParallelism())
+		}
 
 		if goal.Parent != "" {
 			rm.resGoalsLock.Lock()
@@ -67,6 +67,8 @@ type SameStep struct {
 	// If this is a same-step for a resource being created but which was not --target'ed by the user
 	// (and thus was skipped).
 	skippedCreate bool
+
+	ignoredChanges []IgnoredChange // the changes suppressed by ignoreChanges rules.
 }
 
 var _ Step = (*SameStep)(nil)
@@ -127,10 +129,14 @@ func (s *SameStep) New() *resource.State    { return s.new }
 func (s *SameStep) Res() *resource.State    { return s.new }
 func (s *SameStep) Logical() bool           { return true }
 
+func (s *SameStep) IgnoredChanges() []IgnoredChange { return s.ignoredChanges }
+
 func (s *SameStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
-	// Retain the ID and outputs
+	// Retain the ID, outputs and taint. A tainted resource is only left untouched if it was not targeted, in which
+	// case it must still be replaced by the next update that does target it.
 	s.new.ID = s.old.ID
 	s.new.Outputs = s.old.Outputs
+	s.new.Taint = s.old.Taint
 
 	// If the resource is a provider, ensure that it is present in the registry under the appropriate URNs.
 	// We can only do this if the provider is actually a same, not a skipped create.
@@ -163,6 +169,8 @@ type CreateStep struct {
 	detailedDiff  map[string]plugin.PropertyDiff // the structured property diff (only for replacements).
 	replacing     bool                           // true if this is a create due to a replacement.
 	pendingDelete bool                           // true if this replacement should create a pending delete.
+
+	ignoredChanges []IgnoredChange // the changes suppressed by ignoreChanges rules (only for replacements).
 }
 
 var _ Step = (*CreateStep)(nil)
@@ -232,6 +240,7 @@ func (s *CreateStep) Res() *resource.State                         { return s.ne
 func (s *CreateStep) Keys() []resource.PropertyKey                 { return s.keys }
 func (s *CreateStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *CreateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+func (s *CreateStep) IgnoredChanges() []IgnoredChange              { return s.ignoredChanges }
 func (s *CreateStep) Logical() bool                                { return !s.replacing }
 
 func (s *CreateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
@@ -463,6 +472,8 @@ type UpdateStep struct {
 	diffs         []resource.PropertyKey         // the keys causing a diff.
 	detailedDiff  map[string]plugin.PropertyDiff // the structured diff.
 	ignoreChanges []string                       // a list of property paths to ignore when updating.
+
+	ignoredChanges []IgnoredChange // the changes suppressed by ignoreChanges rules.
 }
 
 var _ Step = (*UpdateStep)(nil)
@@ -510,6 +521,7 @@ func (s *UpdateStep) Res() *resource.State                         { return s.ne
 func (s *UpdateStep) Logical() bool                                { return true }
 func (s *UpdateStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *UpdateStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+func (s *UpdateStep) IgnoredChanges() []IgnoredChange              { return s.ignoredChanges }
 
 func (s *UpdateStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
 	// Always propagate the ID and timestamps even in previews and refreshes.
@@ -570,6 +582,8 @@ type ReplaceStep struct {
 	diffs         []resource.PropertyKey         // the keys causing a diff.
 	detailedDiff  map[string]plugin.PropertyDiff // the structured property diff.
 	pendingDelete bool                           // true if a pending deletion should happen.
+
+	ignoredChanges []IgnoredChange // the changes suppressed by ignoreChanges rules.
 }
 
 var _ Step = (*ReplaceStep)(nil)
@@ -608,6 +622,7 @@ func (s *ReplaceStep) Res() *resource.State                         { return s.n
 func (s *ReplaceStep) Keys() []resource.PropertyKey                 { return s.keys }
 func (s *ReplaceStep) Diffs() []resource.PropertyKey                { return s.diffs }
 func (s *ReplaceStep) DetailedDiff() map[string]plugin.PropertyDiff { return s.detailedDiff }
+func (s *ReplaceStep) IgnoredChanges() []IgnoredChange              { return s.ignoredChanges }
 func (s *ReplaceStep) Logical() bool                                { return true }
 
 func (s *ReplaceStep) Apply(preview bool) (resource.Status, StepCompleteFunc, error) {
@@ -873,6 +888,8 @@ func (s *RefreshStep) Apply(preview bool) (resource.Status, StepCompleteFunc, er
 			&s.old.CustomTimeouts, s.old.ImportID, s.old.RetainOnDelete, s.old.DeletedWith, s.old.Created, s.old.Modified,
 			s.old.SourcePosition,
 		)
+		// Refreshing a resource does not replace it, so it remains tainted.
+		s.new.Taint = s.old.Taint
 		var inputsChange, outputsChange bool
 		if s.old != nil {
 			inputsChange = !refreshed.Inputs.DeepEquals(s.old.Inputs)
@@ -1112,7 +1129,7 @@ func (s *ImportStep) Apply(preview bool) (resource.Status, StepCompleteFunc, err
 	}
 
 	// Set inputs back to their old values (if any) for any "ignored" properties
-	processedInputs, err := processIgnoreChanges(s.new.Inputs, s.old.Inputs, s.ignoreChanges)
+	processedInputs, _, err := processIgnoreChanges(s.new.Inputs, s.old.Inputs, s.ignoreChanges)
 	if err != nil {
 		return resource.StatusOK, nil, err
 	}
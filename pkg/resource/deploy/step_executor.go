@@ -20,6 +20,7 @@ import (
 	"fmt"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -71,8 +72,19 @@ func (c completionToken) Wait(ctx context.Context) {
 
 // incomingChain represents a request to the step executor to execute a chain.
 type incomingChain struct {
-	Chain          chain     // The chain we intend to execute
-	CompletionChan chan bool // A completion channel to be closed when the chain has completed execution
+	Chain          chain      // The chain we intend to execute
+	CompletionChan chan bool  // A completion channel to be closed when the chain has completed execution
+	Release        func()     // Returns the parallelism limits taken for the first step while the chain was parked
+	Retry          *stepRetry // The transient failure of the first step, if the chain was parked to retry it
+}
+
+// stepRetry records a step that failed with a transient error, and that is to be retried once its delay has passed.
+type stepRetry struct {
+	retry   int             // The number of this retry, starting at 1.
+	delay   time.Duration   // How long to wait before retrying the step.
+	payload interface{}     // The payload returned by the step's pre-step event.
+	status  resource.Status // The status of the failed attempt.
+	err     error           // The error of the failed attempt.
 }
 
 // stepExecutor is the component of the engine responsible for taking steps and executing
@@ -89,10 +101,15 @@ type stepExecutor struct {
 	pendingNews     sync.Map    // Resources that have been created but are pending a RegisterResourceOutputs.
 	continueOnError bool        // True if we want to continue the deployment after a step error.
 
+	// The per-provider and per-type parallelism limits on steps. A chain whose next step has to wait on these limits
+	// is parked outside of the workers until they're free, and then resubmitted.
+	limiter *stepLimiter
+
 	// Lock protecting the running of workers. This can be used to synchronize with step executor.
 	workerLock sync.RWMutex
 
 	workers        sync.WaitGroup     // WaitGroup tracking the worker goroutines that are owned by this step executor.
+	chains         sync.WaitGroup     // WaitGroup tracking the chains that have been submitted but not completed.
 	incomingChains chan incomingChain // Incoming chains that we are to execute
 
 	ctx    context.Context    // cancellation context for the current deployment.
@@ -115,9 +132,11 @@ func (se *stepExecutor) ExecuteSerial(chain chain) completionToken {
 	// If one is pending, we should exit early - we will shortly be tearing down the engine and exiting.
 
 	completion := make(chan bool)
+	se.chains.Add(1)
 	select {
 	case se.incomingChains <- incomingChain{Chain: chain, CompletionChan: completion}:
 	case <-se.ctx.Done():
+		se.chains.Done()
 		close(completion)
 	}
 
@@ -187,11 +206,13 @@ func (se *stepExecutor) ExecuteRegisterResourceOutputs(e RegisterResourceOutputs
 	if se.deployment.plan != nil {
 		resourcePlan, ok := se.deployment.plan.ResourcePlans[urn]
 		if !ok {
-			return fmt.Errorf("no plan for resource %v", urn)
-		}
-
-		if err := resourcePlan.checkOutputs(oldOuts, outs); err != nil {
-			return fmt.Errorf("resource violates plan: %w", err)
+			if err := se.deployment.violatePlan(se.opts, urn, fmt.Errorf("no plan for resource %v", urn)); err != nil {
+				return err
+			}
+		} else if err := resourcePlan.checkOutputs(oldOuts, outs); err != nil {
+			if err := se.deployment.violatePlan(se.opts, urn, fmt.Errorf("resource violates plan: %w", err)); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -240,7 +261,12 @@ func (se *stepExecutor) Errored() error {
 // SignalCompletion signals to the stepExecutor that there are no more chains left to execute. All worker
 // threads will terminate as soon as they retire all of the work they are currently executing.
 func (se *stepExecutor) SignalCompletion() {
-	close(se.incomingChains)
+	// Parked chains are resubmitted once their parallelism limits are free, so we can only stop taking chains once
+	// every chain that's been submitted has completed.
+	go func() {
+		se.chains.Wait()
+		close(se.incomingChains)
+	}()
 }
 
 // WaitForCompletion blocks the calling goroutine until the step executor completes execution of all in-flight
@@ -258,22 +284,56 @@ func (se *stepExecutor) WaitForCompletion() {
 //
 
 // executeChain executes a chain, one step at a time. If any step in the chain fails to execute, or if the
-// context is canceled, the chain stops execution.
-func (se *stepExecutor) executeChain(workerID int, chain chain) {
-	for _, step := range chain {
+// context is canceled, the chain stops execution. If a step has to wait for its parallelism limits, the rest of the
+// chain is parked until they're free, so that it doesn't hold up the worker.
+func (se *stepExecutor) executeChain(workerID int, request incomingChain) {
+	for i, step := range request.Chain {
 		select {
 		case <-se.ctx.Done():
 			se.log(workerID, "step %v on %v canceled", step.Op(), step.URN())
+			if request.Release != nil {
+				request.Release()
+			}
+			if request.Retry != nil {
+				se.failRetry(workerID, step, request.Retry)
+			}
+			se.completeChain(request)
 			return
 		default:
 		}
 
+		// Take the step's parallelism limits, if any, unless the chain was parked until the first step could take
+		// them.
+		release := request.Release
+		request.Release = nil
+		if release == nil {
+			var ok bool
+			if release, ok = se.limiter.tryAcquire(step); !ok {
+				se.log(workerID, "step %v on %v waiting for parallelism limits", step.Op(), step.URN())
+				if events := se.opts.Events; events != nil {
+					events.OnResourceStepWaiting(step)
+				}
+				request.Chain = request.Chain[i:]
+				se.parkChain(request)
+				return
+			}
+		}
+
 		// Take the work lock before executing the step, this uses the "read" side of the lock because we're ok with as
 		// many workers as possible executing steps in parallel.
 		se.workerLock.RLock()
-		err := se.executeStep(workerID, step)
+		retry, err := se.executeStep(workerID, step, request.Retry)
 		// Regardless of error we need to release the lock here.
 		se.workerLock.RUnlock()
+		release()
+
+		// If the step failed with a transient error, park the chain until it's time to retry the step, so that neither
+		// this worker nor the step's parallelism limits are held while it waits.
+		if request.Retry = retry; retry != nil {
+			request.Chain = request.Chain[i:]
+			se.parkChain(request)
+			return
+		}
 
 		if err != nil {
 			se.log(workerID, "step %v on %v failed, signalling cancellation", step.Op(), step.URN())
@@ -287,9 +347,73 @@ func (se *stepExecutor) executeChain(workerID int, chain chain) {
 				diagMsg := diag.RawMessage(step.URN(), err.Error())
 				se.deployment.Diag().Errorf(diagMsg)
 			}
+			se.completeChain(request)
 			return
 		}
 	}
+	se.completeChain(request)
+}
+
+// parkChain waits, outside of the workers, for the retry delay of the first step of the given chain to pass, if it's
+// being retried, and for the step's parallelism limits to be free, and then resubmits the chain holding them.
+func (se *stepExecutor) parkChain(request incomingChain) {
+	se.workers.Add(1)
+	go func() {
+		defer se.workers.Done()
+
+		step := request.Chain[0]
+		if se.waitToRetry(request.Retry) {
+			if release, ok := se.limiter.acquire(se.ctx, step); ok {
+				request.Release = release
+				select {
+				case se.incomingChains <- request:
+					return
+				case <-se.ctx.Done():
+					release()
+				}
+			}
+		}
+		se.log(synchronousWorkerID, "step %v on %v canceled", step.Op(), step.URN())
+		if request.Retry != nil {
+			se.failRetry(synchronousWorkerID, step, request.Retry)
+		}
+		se.completeChain(request)
+	}()
+}
+
+// waitToRetry waits for the delay of the given retry, if any, to pass. It returns false if the deployment was canceled
+// first.
+func (se *stepExecutor) waitToRetry(retry *stepRetry) bool {
+	if retry == nil {
+		return true
+	}
+	select {
+	case <-time.After(retry.delay):
+		return true
+	case <-se.ctx.Done():
+		return false
+	}
+}
+
+// failRetry fails a step that was waiting to be retried when the deployment was canceled, with the error of its last
+// attempt.
+func (se *stepExecutor) failRetry(workerID int, step Step, retry *stepRetry) {
+	se.workerLock.RLock()
+	defer se.workerLock.RUnlock()
+
+	if events := se.opts.Events; events != nil {
+		if postErr := events.OnResourceStepPost(retry.payload, step, retry.status, retry.err); postErr != nil {
+			se.log(workerID, "step %v on %v failed post-resource step: %v", step.Op(), step.URN(), postErr)
+		}
+	}
+	se.log(workerID, "step %v on %v failed with an error: %v", step.Op(), step.URN(), retry.err)
+	se.cancelDueToError(errStepApplyFailed)
+}
+
+// completeChain signals that the given chain has completed execution, successfully or not.
+func (se *stepExecutor) completeChain(request incomingChain) {
+	close(request.CompletionChan)
+	se.chains.Done()
 }
 
 func (se *stepExecutor) cancelDueToError(err error) {
@@ -310,28 +434,39 @@ func (se *stepExecutor) cancelDueToError(err error) {
 // verbatim to the post-step event.
 //
 
-// executeStep executes a single step, returning true if the step execution was successful and
-// false if it was not.
-func (se *stepExecutor) executeStep(workerID int, step Step) error {
+// executeStep executes a single step, returning an error if the step execution was not successful. If the step
+// failed with a transient error that its retry policy allows retrying, executeStep instead returns the retry, and
+// the step is to be executed again, with that retry, once its delay has passed.
+func (se *stepExecutor) executeStep(workerID int, step Step, retry *stepRetry) (*stepRetry, error) {
 	var payload interface{}
 	events := se.opts.Events
-	if events != nil {
+	if retry != nil {
+		// The pre-step event was raised by the step's first attempt.
+		payload = retry.payload
+	} else if events != nil {
 		var err error
 		payload, err = events.OnResourceStepPre(step)
 		if err != nil {
 			se.log(workerID, "step %v on %v failed pre-resource step: %v", step.Op(), step.URN(), err)
-			return fmt.Errorf("pre-step event returned an error: %w", err)
+			return nil, fmt.Errorf("pre-step event returned an error: %w", err)
 		}
 	}
 
 	se.log(workerID, "applying step %v on %v (preview %v)", step.Op(), step.URN(), se.preview)
 	status, stepComplete, err := step.Apply(se.preview)
+	if err != nil {
+		if retry = se.retryStep(workerID, step, retry, status, err); retry != nil {
+			retry.payload = payload
+			return retry, nil
+		}
+	}
 
 	if err == nil {
 		// If we have a state object, and this is a create or update, remember it, as we may need to update it later.
 		if step.Logical() && step.New() != nil {
 			if prior, has := se.pendingNews.Load(step.URN()); has {
-				return fmt.Errorf("resource '%s' registered twice (%s and %s)", step.URN(), prior.(Step).Op(), step.Op())
+				return nil, fmt.Errorf("resource '%s' registered twice (%s and %s)",
+					step.URN(), prior.(Step).Op(), step.Op())
 			}
 
 			se.pendingNews.Store(step.URN(), step)
@@ -402,7 +537,7 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 	if events != nil {
 		if postErr := events.OnResourceStepPost(payload, step, status, err); postErr != nil {
 			se.log(workerID, "step %v on %v failed post-resource step: %v", step.Op(), step.URN(), postErr)
-			return fmt.Errorf("post-step event returned an error: %w", postErr)
+			return nil, fmt.Errorf("post-step event returned an error: %w", postErr)
 		}
 	}
 
@@ -415,10 +550,35 @@ func (se *stepExecutor) executeStep(workerID int, step Step) error {
 
 	if err != nil {
 		se.log(workerID, "step %v on %v failed with an error: %v", step.Op(), step.URN(), err)
-		return errStepApplyFailed
+		return nil, errStepApplyFailed
 	}
 
-	return nil
+	return nil, nil
+}
+
+// retryStep returns the retry of a step that failed with the given error, if the error is transient and the step's
+// retry policy allows retrying it again, or nil if the step has failed for good. The last retry of the step, if any,
+// is given.
+func (se *stepExecutor) retryStep(workerID int, step Step, last *stepRetry, status resource.Status,
+	err error,
+) *stepRetry {
+	policy := retryPolicyFor(step, se.opts.Retry)
+	retry := 1
+	if last != nil {
+		retry = last.retry + 1
+	}
+	if policy == nil || retry > policy.MaxRetries ||
+		status == resource.StatusPartialFailure || !policy.retryable(step, err) {
+		return nil
+	}
+
+	delay := policy.delay(retry)
+	se.log(workerID, "step %v on %v failed with a transient error, retrying in %v: %v",
+		step.Op(), step.URN(), delay, err)
+	se.deployment.Diag().Warningf(diag.RawMessage(step.URN(), fmt.Sprintf(
+		"%s failed with a transient error, retrying in %v (retry %d of %d): %v",
+		step.Op(), delay, retry, policy.MaxRetries, err)))
+	return &stepRetry{retry: retry, delay: delay, status: status, err: err}
 }
 
 // log is a simple logging helper for the step executor.
@@ -463,8 +623,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 
 			se.log(workerID, "worker received chain for execution")
 			if !launchAsync {
-				se.executeChain(workerID, request.Chain)
-				close(request.CompletionChan)
+				se.executeChain(workerID, request)
 				continue
 			}
 
@@ -475,8 +634,7 @@ func (se *stepExecutor) worker(workerID int, launchAsync bool) {
 			go func() {
 				defer se.workers.Done()
 				se.log(newWorkerID, "launching oneshot worker")
-				se.executeChain(newWorkerID, request.Chain)
-				close(request.CompletionChan)
+				se.executeChain(newWorkerID, request)
 			}()
 
 			oneshotWorkerID++
@@ -495,6 +653,7 @@ func newStepExecutor(ctx context.Context, cancel context.CancelFunc, deployment
 		opts:            opts,
 		preview:         preview,
 		continueOnError: continueOnError,
+		limiter:         newStepLimiter(deployment),
 		incomingChains:  make(chan incomingChain),
 		ctx:             ctx,
 		cancel:          cancel,
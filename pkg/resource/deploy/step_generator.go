@@ -68,6 +68,10 @@ type stepGenerator struct {
 	aliased map[resource.URN]resource.URN
 	// a map from current URN of the resource to the old URN that it was aliased from.
 	aliases map[resource.URN]resource.URN
+
+	// a map from URN to the changes that ignoreChanges rules suppressed for the resource being registered, which are
+	// attached to its steps for display.
+	ignoredChanges map[resource.URN][]IgnoredChange
 }
 
 // isTargetedForUpdate returns if `res` is targeted for update. The function accommodates
@@ -108,6 +112,12 @@ func (sg *stepGenerator) isTargetedReplace(urn resource.URN) bool {
 	return sg.opts.ReplaceTargets.IsConstrained() && sg.opts.ReplaceTargets.Contains(urn)
 }
 
+// isForcedReplace returns true if the given resource must be replaced regardless of its diff, either because it
+// has been targeted for replacement or because its old state has been tainted.
+func (sg *stepGenerator) isForcedReplace(urn resource.URN, old *resource.State) bool {
+	return sg.isTargetedReplace(urn) || old != nil && old.Taint
+}
+
 func (sg *stepGenerator) Errored() bool {
 	return sg.sawError
 }
@@ -266,6 +276,11 @@ func (sg *stepGenerator) GenerateReadSteps(event ReadResourceEvent) ([]Step, err
 // provider associated with that resource. If those fail, an error is returned.
 func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, error) {
 	steps, err := sg.generateSteps(event)
+	defer func() {
+		for urn := range sg.ignoredChanges {
+			delete(sg.ignoredChanges, urn)
+		}
+	}()
 	if err != nil {
 		contract.Assertf(len(steps) == 0, "expected no steps if there is an error")
 		return nil, err
@@ -275,26 +290,24 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 	for _, s := range steps {
 		logging.V(5).Infof("Checking step %s for %s", s.Op(), s.URN())
 
-		if sg.deployment.plan != nil {
-			if resourcePlan, ok := sg.deployment.plan.ResourcePlans[s.URN()]; ok {
-				if len(resourcePlan.Ops) == 0 {
-					return nil, fmt.Errorf("%v is not allowed by the plan: no more steps were expected for this resource", s.Op())
-				}
-				constraint := resourcePlan.Ops[0]
-				// We remove the Op from the list before doing the constraint check.
-				// This is because we look at Ops at the end to see if any expected operations didn't attempt to happen.
-				// This op has been attempted, it just might fail its constraint.
-				resourcePlan.Ops = resourcePlan.Ops[1:]
-				if !ConstrainedTo(s.Op(), constraint) {
-					return nil, fmt.Errorf("%v is not allowed by the plan: this resource is constrained to %v", s.Op(), constraint)
-				}
-			} else {
-				if !ConstrainedTo(s.Op(), OpSame) {
-					return nil, fmt.Errorf("%v is not allowed by the plan: no steps were expected for this resource", s.Op())
-				}
+		// Attach the changes that were ignored to the steps for the resource, so that they can be displayed.
+		if ignored, has := sg.ignoredChanges[s.URN()]; has {
+			switch s := s.(type) {
+			case *SameStep:
+				s.ignoredChanges = ignored
+			case *CreateStep:
+				s.ignoredChanges = ignored
+			case *UpdateStep:
+				s.ignoredChanges = ignored
+			case *ReplaceStep:
+				s.ignoredChanges = ignored
 			}
 		}
 
+		if err := sg.checkPlannedOp(s); err != nil {
+			return nil, err
+		}
+
 		// If we're generating plans add the operation to the plan being generated
 		if sg.opts.GeneratePlan {
 			// Resource plan might be aliased
@@ -357,6 +370,36 @@ func (sg *stepGenerator) GenerateSteps(event RegisterResourceEvent) ([]Step, err
 	return steps, nil
 }
 
+// checkPlannedOp checks the given step against the next operation planned for its resource, if there is a plan. The
+// planned operation is consumed whether or not the step is allowed by it.
+func (sg *stepGenerator) checkPlannedOp(s Step) error {
+	if sg.deployment.plan == nil {
+		return nil
+	}
+
+	var err error
+	if resourcePlan, ok := sg.deployment.plan.ResourcePlans[s.URN()]; ok {
+		if len(resourcePlan.Ops) == 0 {
+			err = fmt.Errorf("%v is not allowed by the plan: no more steps were expected for this resource", s.Op())
+		} else {
+			constraint := resourcePlan.Ops[0]
+			// We remove the Op from the list before doing the constraint check.
+			// This is because we look at Ops at the end to see if any expected operations didn't attempt to happen.
+			// This op has been attempted, it just might fail its constraint.
+			resourcePlan.Ops = resourcePlan.Ops[1:]
+			if !ConstrainedTo(s.Op(), constraint) {
+				err = fmt.Errorf("%v is not allowed by the plan: this resource is constrained to %v", s.Op(), constraint)
+			}
+		}
+	} else if !ConstrainedTo(s.Op(), OpSame) {
+		err = fmt.Errorf("%v is not allowed by the plan: no steps were expected for this resource", s.Op())
+	}
+	if err != nil {
+		return sg.deployment.violatePlan(sg.opts, s.URN(), err)
+	}
+	return nil
+}
+
 func (sg *stepGenerator) collapseAliasToUrn(goal *resource.Goal, alias resource.Alias) resource.URN {
 	if alias.URN != "" {
 		return alias.URN
@@ -538,12 +581,17 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 	// Create the desired inputs from the goal state
 	inputs := goal.Properties
 	if hasOld {
-		// Set inputs back to their old values (if any) for any "ignored" properties
-		processedInputs, err := processIgnoreChanges(inputs, oldInputs, goal.IgnoreChanges)
+		// Set inputs back to their old values (if any) for any "ignored" properties, first for the resource's own
+		// rules and then for the stack's defaults.
+		processedInputs, ignored, err := processIgnoreChanges(inputs, oldInputs, goal.IgnoreChanges)
 		if err != nil {
 			return nil, err
 		}
-		inputs = processedInputs
+		var defaultIgnored []IgnoredChange
+		inputs, defaultIgnored = sg.processDefaultIgnoreChanges(goal, processedInputs, oldInputs)
+		if ignored = append(ignored, defaultIgnored...); len(ignored) != 0 {
+			sg.ignoredChanges[urn] = ignored
+		}
 	}
 
 	aliasUrns := make([]resource.URN, len(alias))
@@ -664,7 +712,7 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 		// invalid (they got deleted) so don't consider them. Similarly, if the old resource was External,
 		// don't consider those inputs since Pulumi does not own them. Finally, if the resource has been
 		// targeted for replacement, ignore its old state.
-		if recreating || wasExternal || sg.isTargetedReplace(urn) || !hasOld {
+		if recreating || wasExternal || sg.isForcedReplace(urn, old) || !hasOld {
 			inputs, failures, err = checkInputs(urn, nil, goal.Properties, allowUnknowns, randomSeed)
 		} else {
 			inputs, failures, err = checkInputs(urn, oldInputs, inputs, allowUnknowns, randomSeed)
@@ -680,7 +728,7 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 
 	// If the resource is valid and we're generating plans then generate a plan
 	if !invalid && sg.opts.GeneratePlan {
-		if recreating || wasExternal || sg.isTargetedReplace(urn) || !hasOld {
+		if recreating || wasExternal || sg.isForcedReplace(urn, old) || !hasOld {
 			oldInputs = nil
 		}
 		inputDiff := oldInputs.Diff(inputs)
@@ -713,11 +761,17 @@ func (sg *stepGenerator) generateSteps(event RegisterResourceEvent) ([]Step, err
 			if old == nil {
 				// We could error here, but we'll trigger an error later on anyway that Create isn't valid here
 			} else if err := checkMissingPlan(old, inputs, goal); err != nil {
-				return nil, fmt.Errorf("resource %s violates plan: %w", urn, err)
+				err = sg.deployment.violatePlan(sg.opts, urn, fmt.Errorf("resource %s violates plan: %w", urn, err))
+				if err != nil {
+					return nil, err
+				}
 			}
 		} else {
 			if err := resourcePlan.checkGoal(oldInputs, inputs, goal); err != nil {
-				return nil, fmt.Errorf("resource %s violates plan: %w", urn, err)
+				err = sg.deployment.violatePlan(sg.opts, urn, fmt.Errorf("resource %s violates plan: %w", urn, err))
+				if err != nil {
+					return nil, err
+				}
 			}
 		}
 	}
@@ -1005,7 +1059,7 @@ func (sg *stepGenerator) generateStepsFromDiff(
 			// had assumed that we were going to carry them over from the old resource, which is no longer true.
 			//
 			// Note that if we're performing a targeted replace, we already have the correct inputs.
-			if prov != nil && !sg.isTargetedReplace(urn) {
+			if prov != nil && !sg.isForcedReplace(urn, old) {
 				var failures []plugin.CheckFailure
 				inputs, failures, err = prov.Check(urn, nil, goal.Properties, allowUnknowns, randomSeed)
 				if err != nil {
@@ -1206,26 +1260,8 @@ func (sg *stepGenerator) GenerateDeletes(targetsOpt UrnTargets) ([]Step, error)
 
 	// Check each proposed delete against the relevant resource plan
 	for _, s := range dels {
-		if sg.deployment.plan != nil {
-			if resourcePlan, ok := sg.deployment.plan.ResourcePlans[s.URN()]; ok {
-				if len(resourcePlan.Ops) == 0 {
-					return nil, fmt.Errorf("%v is not allowed by the plan: no more steps were expected for this resource", s.Op())
-				}
-
-				constraint := resourcePlan.Ops[0]
-				// We remove the Op from the list before doing the constraint check.
-				// This is because we look at Ops at the end to see if any expected operations didn't attempt to happen.
-				// This op has been attempted, it just might fail its constraint.
-				resourcePlan.Ops = resourcePlan.Ops[1:]
-
-				if !ConstrainedTo(s.Op(), constraint) {
-					return nil, fmt.Errorf("%v is not allowed by the plan: this resource is constrained to %v", s.Op(), constraint)
-				}
-			} else {
-				if !ConstrainedTo(s.Op(), OpSame) {
-					return nil, fmt.Errorf("%v is not allowed by the plan: no steps were expected for this resource", s.Op())
-				}
-			}
+		if err := sg.checkPlannedOp(s); err != nil {
+			return nil, err
 		}
 
 		// If we're generating plans add a delete op to the plan for this resource
@@ -1563,8 +1599,8 @@ func (sg *stepGenerator) diff(urn resource.URN, old, new *resource.State, oldInp
 	newInputs resource.PropertyMap, prov plugin.Provider, allowUnknowns bool,
 	ignoreChanges []string,
 ) (plugin.DiffResult, error) {
-	// If this resource is marked for replacement, just return a "replace" diff that blames the id.
-	if sg.isTargetedReplace(urn) {
+	// If this resource is marked for replacement or tainted, just return a "replace" diff that blames the id.
+	if sg.isForcedReplace(urn, old) {
 		return plugin.DiffResult{Changes: plugin.DiffSome, ReplaceKeys: []resource.PropertyKey{"id"}}, nil
 	}
 
@@ -1609,7 +1645,7 @@ func diffResource(urn resource.URN, id resource.ID, oldInputs, oldOutputs,
 		return diff, err
 	}
 	if diff.Changes == plugin.DiffUnknown {
-		new, res := processIgnoreChanges(newInputs, oldInputs, ignoreChanges)
+		new, _, res := processIgnoreChanges(newInputs, oldInputs, ignoreChanges)
 		if res != nil {
 			return plugin.DiffResult{}, err
 		}
@@ -1652,12 +1688,35 @@ func issueCheckFailures(printf func(*diag.Diag, ...interface{}), new *resource.S
 	return true
 }
 
+// IgnoredChange is a change to a resource's inputs that was suppressed by an ignoreChanges rule.
+type IgnoredChange struct {
+	Path    resource.PropertyPath // the path of the property whose change was ignored.
+	Rule    string                // the ignoreChanges rule that matched the path.
+	Default bool                  // true if the rule is one of the stack's defaults rather than the resource's own.
+}
+
 // processIgnoreChanges sets the value for each ignoreChanges property in inputs to the value from oldInputs.  This has
-// the effect of ensuring that no changes will be made for the corresponding property.
+// the effect of ensuring that no changes will be made for the corresponding property. Rules may contain wildcards,
+// e.g. "tags.*" or "rules[*].description", which are expanded against the inputs. The changes that were ignored are
+// returned alongside the inputs.
 func processIgnoreChanges(inputs, oldInputs resource.PropertyMap,
 	ignoreChanges []string,
-) (resource.PropertyMap, error) {
+) (resource.PropertyMap, []IgnoredChange, error) {
+	ignoredInputs, ignored, invalidPaths := resetIgnoredChanges(inputs, oldInputs, ignoreChanges)
+	if len(invalidPaths) != 0 {
+		return nil, nil, fmt.Errorf("cannot ignore changes to the following properties because one or more elements of "+
+			"the path are missing: %q", strings.Join(invalidPaths, ", "))
+	}
+	return ignoredInputs, ignored, nil
+}
+
+// resetIgnoredChanges does the work of processIgnoreChanges, but returns the rules whose paths couldn't be reset
+// rather than failing. The paths that could be reset are reset regardless.
+func resetIgnoredChanges(inputs, oldInputs resource.PropertyMap,
+	ignoreChanges []string,
+) (resource.PropertyMap, []IgnoredChange, []string) {
 	ignoredInputs := inputs.Copy()
+	var ignored []IgnoredChange
 	var invalidPaths []string
 	for _, ignoreChange := range ignoreChanges {
 		path, err := resource.ParsePropertyPath(ignoreChange)
@@ -1665,16 +1724,44 @@ func processIgnoreChanges(inputs, oldInputs resource.PropertyMap,
 			continue
 		}
 
-		ok := path.Reset(oldInputs, ignoredInputs)
-		if !ok {
+		valid := true
+		for _, p := range path.Expand(oldInputs, ignoredInputs) {
+			oldValue, hasOld := p.Get(resource.NewObjectProperty(oldInputs))
+			newValue, hasNew := p.Get(resource.NewObjectProperty(ignoredInputs))
+			if !p.Reset(oldInputs, ignoredInputs) {
+				valid = false
+				continue
+			}
+			if hasOld != hasNew || !oldValue.DeepEquals(newValue) {
+				ignored = append(ignored, IgnoredChange{Path: p, Rule: ignoreChange})
+			}
+		}
+		if !valid {
 			invalidPaths = append(invalidPaths, ignoreChange)
 		}
 	}
-	if len(invalidPaths) != 0 {
-		return nil, fmt.Errorf("cannot ignore changes to the following properties because one or more elements of "+
-			"the path are missing: %q", strings.Join(invalidPaths, ", "))
-	}
-	return ignoredInputs, nil
+	return ignoredInputs, ignored, invalidPaths
+}
+
+// processDefaultIgnoreChanges applies the stack's default ignoreChanges rules for the goal's resource to its inputs, as
+// processIgnoreChanges does for the resource's own rules. The defaults apply to every custom resource other than
+// providers, whose inputs are their configuration, so a rule whose path doesn't fit a resource's inputs is skipped for
+// that resource rather than failing.
+func (sg *stepGenerator) processDefaultIgnoreChanges(goal *resource.Goal,
+	inputs, oldInputs resource.PropertyMap,
+) (resource.PropertyMap, []IgnoredChange) {
+	defaults := sg.deployment.target.IgnoreChanges
+	if defaults == nil || !goal.Custom || providers.IsProviderType(goal.Type) {
+		return inputs, nil
+	}
+
+	inputs, ignored, _ := resetIgnoredChanges(inputs, oldInputs, defaults.All)
+	inputs, providerIgnored, _ := resetIgnoredChanges(inputs, oldInputs, defaults.Providers[string(goal.Type.Package())])
+	ignored = append(ignored, providerIgnored...)
+	for i := range ignored {
+		ignored[i].Default = true
+	}
+	return inputs, ignored
 }
 
 func (sg *stepGenerator) loadResourceProvider(
@@ -2036,5 +2123,6 @@ func newStepGenerator(
 		dependentReplaceKeys: make(map[resource.URN][]resource.PropertyKey),
 		aliased:              make(map[resource.URN]resource.URN),
 		aliases:              make(map[resource.URN]resource.URN),
+		ignoredChanges:       make(map[resource.URN][]IgnoredChange),
 	}
 }
@@ -111,6 +111,65 @@ func TestIgnoreChanges(t *testing.T) {
 			ignoreChanges: []string{"a.b"},
 			expectFailure: true,
 		},
+		{
+			name: "Wildcard resets every key",
+			oldInputs: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"a": "foo",
+					"b": "bar",
+				},
+			},
+			newInputs: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"b": "baz",
+					"c": "qux",
+				},
+			},
+			ignoreChanges: []string{"tags.*"},
+		},
+		{
+			name: "Wildcard resets the elements in old and new",
+			oldInputs: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"description": "foo", "port": 80},
+				},
+			},
+			newInputs: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"description": "bar", "port": 443},
+					map[string]interface{}{"description": "baz", "port": 8080},
+				},
+			},
+			expected: map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{"description": "foo", "port": 443},
+					map[string]interface{}{"description": "baz", "port": 8080},
+				},
+			},
+			ignoreChanges: []string{"rules[*].description"},
+		},
+		{
+			name: "Glob resets the matching keys",
+			oldInputs: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"teamName": "foo",
+					"owner":    "bar",
+				},
+			},
+			newInputs: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"teamId": "baz",
+					"owner":  "qux",
+				},
+			},
+			expected: map[string]interface{}{
+				"tags": map[string]interface{}{
+					"teamName": "foo",
+					"owner":    "qux",
+				},
+			},
+			ignoreChanges: []string{"tags.team*"},
+		},
 	}
 
 	for _, c := range cases {
@@ -125,7 +184,7 @@ func TestIgnoreChanges(t *testing.T) {
 				expected = resource.NewPropertyMapFromMap(c.expected)
 			}
 
-			processed, err := processIgnoreChanges(news, olds, c.ignoreChanges)
+			processed, _, err := processIgnoreChanges(news, olds, c.ignoreChanges)
 			if c.expectFailure {
 				assert.Error(t, err)
 			} else {
@@ -136,6 +195,33 @@ func TestIgnoreChanges(t *testing.T) {
 	}
 }
 
+func TestIgnoreChangesReportsIgnoredChanges(t *testing.T) {
+	t.Parallel()
+
+	olds := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{
+			"a": "foo",
+			"b": "bar",
+		},
+		"name": "foo",
+	})
+	news := resource.NewPropertyMapFromMap(map[string]interface{}{
+		"tags": map[string]interface{}{
+			"a": "foo",
+			"c": "baz",
+		},
+		"name": "bar",
+	})
+
+	_, ignored, err := processIgnoreChanges(news, olds, []string{"tags.*", "name", "missing"})
+	assert.NoError(t, err)
+	assert.Equal(t, []IgnoredChange{
+		{Path: resource.PropertyPath{"tags", "b"}, Rule: "tags.*"},
+		{Path: resource.PropertyPath{"tags", "c"}, Rule: "tags.*"},
+		{Path: resource.PropertyPath{"name"}, Rule: "name"},
+	}, ignored)
+}
+
 func TestApplyReplaceOnChangesEmptyDetailedDiff(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,155 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+)
+
+// stepLimit is a limit on the number of steps that can run concurrently on some set of resources.
+type stepLimit struct {
+	key string // identifies the set of resources that the limit applies to, e.g. "package:aws".
+	n   int    // the maximum number of concurrent steps.
+}
+
+// stepLimiter limits the number of steps that the step executor runs concurrently on the resources of a provider
+// package, a provider instance, or a resource type. These limits apply in addition to the deployment's overall
+// degree of parallelism. The package and type limits come from the target's parallelism configuration, and the
+// provider instance limits from the parallelism option of provider resources.
+//
+// A step takes a slot from each of the limits that apply to it before it runs, and returns them once it's done. A step
+// takes all of its slots at once or none of them, so that steps waiting on each other's slots can't deadlock. Each
+// limit is checked against its current value whenever slots are taken, so a limit that changes during the deployment,
+// e.g. because a provider is updated with a new parallelism option, applies from then on.
+type stepLimiter struct {
+	deployment *Deployment
+
+	m        sync.Mutex
+	running  map[string]int // the number of slots taken from each limit, keyed by the limit's key.
+	released chan struct{}  // closed, and replaced, whenever slots are returned.
+}
+
+func newStepLimiter(deployment *Deployment) *stepLimiter {
+	return &stepLimiter{
+		deployment: deployment,
+		running:    make(map[string]int),
+		released:   make(chan struct{}),
+	}
+}
+
+// limits returns the limits that apply to the given step, in the order that their slots must be taken.
+func (l *stepLimiter) limits(step Step) []stepLimit {
+	switch step.Op() {
+	case OpSame, OpReplace, OpRemovePendingReplace, OpDiscardReplaced, OpReadDiscard:
+		// These steps don't call the resource's provider, so there's no need to limit them.
+		return nil
+	}
+
+	res := step.New()
+	if res == nil {
+		res = step.Old()
+	}
+	if res == nil || !res.Custom || providers.IsProviderType(res.Type) {
+		return nil
+	}
+
+	var limits []stepLimit
+	config := l.deployment.target.Parallelism
+	if config != nil {
+		if n := config.Providers[string(res.Type.Package())]; n > 0 {
+			limits = append(limits, stepLimit{key: "package:" + string(res.Type.Package()), n: n})
+		}
+	}
+	if n := l.providerParallelism(res.Provider); n > 0 {
+		limits = append(limits, stepLimit{key: "provider:" + res.Provider, n: n})
+	}
+	if config != nil {
+		if n := config.Types[string(res.Type)]; n > 0 {
+			limits = append(limits, stepLimit{key: "type:" + string(res.Type), n: n})
+		}
+	}
+	return limits
+}
+
+// providerParallelism returns the parallelism limit of the referenced provider resource, or 0 if it has none. The
+// limit is taken from the provider's registration in this deployment, so providers that are only in the old snapshot,
+// e.g. those of resources that are being deleted, have no limit.
+func (l *stepLimiter) providerParallelism(ref string) int {
+	if ref == "" || l.deployment.goals == nil {
+		return 0
+	}
+	providerRef, err := providers.ParseReference(ref)
+	if err != nil {
+		return 0
+	}
+	goal, ok := l.deployment.goals.get(providerRef.URN())
+	if !ok {
+		return 0
+	}
+	return goal.Parallelism
+}
+
+// tryAcquire takes a slot from each of the limits that apply to the given step without waiting, and returns a function
+// that returns them. If any of the slots isn't free, tryAcquire takes none of them and returns false.
+func (l *stepLimiter) tryAcquire(step Step) (func(), bool) {
+	release, ok, _ := l.take(l.limits(step))
+	return release, ok
+}
+
+// acquire takes a slot from each of the limits that apply to the given step, blocking until they're free, and returns
+// a function that returns them. acquire returns false if the context is canceled while it's blocked.
+func (l *stepLimiter) acquire(ctx context.Context, step Step) (func(), bool) {
+	for {
+		release, ok, released := l.take(l.limits(step))
+		if ok {
+			return release, true
+		}
+		select {
+		case <-released:
+		case <-ctx.Done():
+			return nil, false
+		}
+	}
+}
+
+// take takes a slot from each of the given limits if they are all free, and returns a function that returns them. If
+// any of them isn't free, take takes none of them and returns false, along with a channel that is closed when slots
+// are next returned.
+func (l *stepLimiter) take(limits []stepLimit) (func(), bool, <-chan struct{}) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	for _, limit := range limits {
+		if l.running[limit.key] >= limit.n {
+			return nil, false, l.released
+		}
+	}
+	for _, limit := range limits {
+		l.running[limit.key]++
+	}
+	return func() {
+		l.m.Lock()
+		defer l.m.Unlock()
+
+		for _, limit := range limits {
+			l.running[limit.key]--
+		}
+		close(l.released)
+		l.released = make(chan struct{})
+	}, true, nil
+}
@@ -0,0 +1,117 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
+)
+
+func TestStepLimiter(t *testing.T) {
+	t.Parallel()
+
+	goals := &goalMap{}
+	newProvider := func(name string, parallelism int) string {
+		urn := resource.NewURN("test", "test", "", "pulumi:providers:pkgA", tokens.QName(name))
+		goals.set(urn, &resource.Goal{Type: urn.Type(), Name: urn.Name(), Custom: true, Parallelism: parallelism})
+		ref, err := providers.NewReference(urn, "id")
+		require.NoError(t, err)
+		return ref.String()
+	}
+	limitedRef := newProvider("limited", 1)
+	unlimitedRef := newProvider("unlimited", 0)
+
+	deployment := &Deployment{
+		target: &Target{
+			Parallelism: &workspace.StackParallelism{
+				Providers: map[string]int{"pkgA": 3},
+				Types:     map[string]int{"pkgA:m:typB": 1},
+			},
+		},
+		goals: goals,
+	}
+	newStep := func(typ tokens.Type, name, provider string) Step {
+		return NewDeleteStep(deployment, map[resource.URN]bool{}, &resource.State{
+			Type:     typ,
+			URN:      resource.NewURN("test", "test", "", typ, tokens.QName(name)),
+			Custom:   true,
+			ID:       "id",
+			Provider: provider,
+		})
+	}
+
+	limiter := newStepLimiter(deployment)
+	assert.Equal(t, []stepLimit{
+		{key: "package:pkgA", n: 3},
+	}, limiter.limits(newStep("pkgA:m:typA", "a", unlimitedRef)))
+	assert.Equal(t, []stepLimit{
+		{key: "package:pkgA", n: 3},
+		{key: "provider:" + limitedRef, n: 1},
+		{key: "type:pkgA:m:typB", n: 1},
+	}, limiter.limits(newStep("pkgA:m:typB", "b", limitedRef)))
+	assert.Empty(t, limiter.limits(newStep("pkgB:m:typA", "c", unlimitedRef)))
+	assert.Empty(t, limiter.limits(NewRemovePendingReplaceStep(deployment, &resource.State{
+		Type:               "pkgA:m:typA",
+		URN:                resource.NewURN("test", "test", "", "pkgA:m:typA", "d"),
+		Custom:             true,
+		ID:                 "id",
+		Provider:           unlimitedRef,
+		PendingReplacement: true,
+	})))
+
+	// The first step takes the only slot for typB without waiting.
+	release, ok := limiter.tryAcquire(newStep("pkgA:m:typB", "e", unlimitedRef))
+	require.True(t, ok)
+
+	// The second step can't take it without waiting, and waits until the context is canceled.
+	_, ok = limiter.tryAcquire(newStep("pkgA:m:typB", "f", unlimitedRef))
+	assert.False(t, ok)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, ok = limiter.acquire(ctx, newStep("pkgA:m:typB", "f", unlimitedRef))
+	assert.False(t, ok)
+
+	// Once the first step is done, the slot is free again. The steps that couldn't take it must not have kept their
+	// package slots.
+	release()
+	for i := 0; i < 3; i++ {
+		release, ok = limiter.tryAcquire(newStep("pkgA:m:typA", "g", unlimitedRef))
+		require.True(t, ok)
+	}
+	_, ok = limiter.tryAcquire(newStep("pkgA:m:typA", "h", unlimitedRef))
+	assert.False(t, ok)
+
+	// Limits are checked against their current values, so raising one frees up slots straight away.
+	deployment.target.Parallelism.Providers["pkgA"] = 4
+	_, ok = limiter.tryAcquire(newStep("pkgA:m:typA", "h", unlimitedRef))
+	assert.True(t, ok)
+
+	// A step that's waiting for a slot takes it once it's returned.
+	acquired := make(chan bool)
+	go func() {
+		_, ok := limiter.acquire(context.Background(), newStep("pkgA:m:typA", "i", unlimitedRef))
+		acquired <- ok
+	}()
+	release()
+	assert.True(t, <-acquired)
+}
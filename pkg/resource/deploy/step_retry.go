@@ -0,0 +1,107 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"regexp"
+	"time"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+const (
+	// DefaultRetryInitialDelay is the delay before the first retry of a retry policy that doesn't set one.
+	DefaultRetryInitialDelay = time.Second
+	// DefaultRetryMaxDelay is the maximum delay between retries of a retry policy that doesn't set one.
+	DefaultRetryMaxDelay = 30 * time.Second
+)
+
+// RetryPolicy controls how the step executor retries the creates and updates of resources that fail with transient
+// errors, e.g. because the provider was throttled or the service it manages was unavailable. An error is transient if
+// the provider marked it as retryable, or, for updates, if its message matches one of the policy's patterns. Creates
+// are only retried when the provider marked the error as retryable: a create that failed with any other error may
+// have created the resource anyway, and retrying it could create a duplicate. Operations that partially failed, i.e.
+// that changed the resource before they failed, are never retried.
+//
+// Each retry waits for twice as long as the one before it, starting at the initial delay, up to the maximum delay.
+type RetryPolicy struct {
+	MaxRetries   int              // the maximum number of times to retry an operation; <= 0 disables retries.
+	InitialDelay time.Duration    // the delay before the first retry; defaults to DefaultRetryInitialDelay.
+	MaxDelay     time.Duration    // the maximum delay between retries; defaults to DefaultRetryMaxDelay.
+	Patterns     []*regexp.Regexp // patterns that match the messages of transient errors.
+}
+
+// retryable returns true if the given step's error is transient according to this policy.
+func (p *RetryPolicy) retryable(step Step, err error) bool {
+	var retryErr *plugin.RetryableError
+	if errors.As(err, &retryErr) {
+		return true
+	}
+	if _, isCreate := step.(*CreateStep); isCreate {
+		return false
+	}
+	for _, pattern := range p.Patterns {
+		if pattern.MatchString(err.Error()) {
+			return true
+		}
+	}
+	return false
+}
+
+// delay returns the time to wait before the given retry, counting from 1.
+func (p *RetryPolicy) delay(retry int) time.Duration {
+	delay, maxDelay := p.InitialDelay, p.MaxDelay
+	if delay <= 0 {
+		delay = DefaultRetryInitialDelay
+	}
+	if maxDelay <= 0 {
+		maxDelay = DefaultRetryMaxDelay
+	}
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// retryPolicyFor returns the policy for retrying the given step, or nil if it mustn't be retried. Only creates and
+// updates are retried. A resource's own retries option overrides the number of retries of the deployment's policy.
+func retryPolicyFor(step Step, policy *RetryPolicy) *RetryPolicy {
+	var reg RegisterResourceEvent
+	switch step := step.(type) {
+	case *CreateStep:
+		reg = step.reg
+	case *UpdateStep:
+		reg = step.reg
+	default:
+		return nil
+	}
+
+	if reg != nil && reg.Goal() != nil && reg.Goal().Retries > 0 {
+		override := RetryPolicy{}
+		if policy != nil {
+			override = *policy
+		}
+		override.MaxRetries = reg.Goal().Retries
+		policy = &override
+	}
+	if policy == nil || policy.MaxRetries <= 0 {
+		return nil
+	}
+	return policy
+}
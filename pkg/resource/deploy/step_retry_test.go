@@ -0,0 +1,114 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package deploy
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/plugin"
+)
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{
+		MaxRetries: 3,
+		Patterns:   []*regexp.Regexp{regexp.MustCompile(`(?i)throttl`), regexp.MustCompile(`\b50[234]\b`)},
+	}
+
+	old := &resource.State{
+		Type:     "pkgA:m:typA",
+		URN:      resource.NewURN("test", "test", "", "pkgA:m:typA", "a"),
+		Custom:   true,
+		ID:       "id",
+		Provider: "urn:pulumi:test::test::pulumi:providers:pkgA::default::id",
+	}
+	new := &resource.State{Type: old.Type, URN: old.URN, Custom: true, Provider: old.Provider}
+	update := NewUpdateStep(&Deployment{}, &testRegEvent{}, old, new, nil, nil, nil, nil)
+	create := NewCreateStep(&Deployment{}, &testRegEvent{}, new)
+
+	assert.True(t, policy.retryable(update, errors.New("Throttling: rate exceeded")))
+	assert.True(t, policy.retryable(update, errors.New("unexpected status 503 from service")))
+	assert.False(t, policy.retryable(update, errors.New("invalid bucket name")))
+
+	// A create that failed may have created the resource anyway, so it's only retried if the provider says it's safe.
+	assert.False(t, policy.retryable(create, errors.New("Throttling: rate exceeded")))
+
+	// Errors that the provider marks as retryable are always transient, even when they're wrapped.
+	retryErr := &plugin.RetryableError{Reason: "throttled", Err: errors.New("slow down")}
+	for _, step := range []Step{update, create} {
+		assert.True(t, policy.retryable(step, retryErr))
+		assert.True(t, policy.retryable(step, fmt.Errorf("create failed: %w", retryErr)))
+		assert.True(t, (&RetryPolicy{}).retryable(step, retryErr))
+	}
+}
+
+func TestRetryPolicyDelay(t *testing.T) {
+	t.Parallel()
+
+	policy := &RetryPolicy{InitialDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+	assert.Equal(t, 100*time.Millisecond, policy.delay(1))
+	assert.Equal(t, 200*time.Millisecond, policy.delay(2))
+	assert.Equal(t, 400*time.Millisecond, policy.delay(3))
+	assert.Equal(t, 800*time.Millisecond, policy.delay(4))
+	assert.Equal(t, time.Second, policy.delay(5))
+	assert.Equal(t, time.Second, policy.delay(100))
+
+	defaults := &RetryPolicy{}
+	assert.Equal(t, DefaultRetryInitialDelay, defaults.delay(1))
+	assert.Equal(t, DefaultRetryMaxDelay, defaults.delay(100))
+}
+
+func TestRetryPolicyFor(t *testing.T) {
+	t.Parallel()
+
+	newCreateStep := func(retries int) Step {
+		goal := &resource.Goal{Type: "pkgA:m:typA", Name: "a", Custom: true, Retries: retries}
+		reg := &testRegEvent{goal: goal}
+		urn := resource.NewURN("test", "test", "", goal.Type, goal.Name)
+		return NewCreateStep(&Deployment{}, reg, &resource.State{
+			Type:     goal.Type,
+			URN:      urn,
+			Custom:   true,
+			Provider: "urn:pulumi:test::test::pulumi:providers:pkgA::default::id",
+		})
+	}
+
+	policy := &RetryPolicy{MaxRetries: 2, InitialDelay: time.Millisecond}
+	assert.Same(t, policy, retryPolicyFor(newCreateStep(0), policy))
+	assert.Nil(t, retryPolicyFor(newCreateStep(0), nil))
+
+	// The resource's retries override the deployment's, and enable retries if the deployment has no policy.
+	assert.Equal(t, &RetryPolicy{MaxRetries: 5, InitialDelay: time.Millisecond},
+		retryPolicyFor(newCreateStep(5), policy))
+	assert.Equal(t, &RetryPolicy{MaxRetries: 5}, retryPolicyFor(newCreateStep(5), nil))
+
+	// Only creates and updates are retried.
+	old := &resource.State{
+		Type:     "pkgA:m:typA",
+		URN:      resource.NewURN("test", "test", "", "pkgA:m:typA", "b"),
+		Custom:   true,
+		ID:       "id",
+		Provider: "urn:pulumi:test::test::pulumi:providers:pkgA::default::id",
+	}
+	assert.Nil(t, retryPolicyFor(NewDeleteStep(&Deployment{}, map[resource.URN]bool{}, old), policy))
+}
@@ -18,15 +18,18 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 // Target represents information about a deployment target.
 type Target struct {
-	Name         tokens.Name      // the target stack name.
-	Organization tokens.Name      // the target organization name (if any).
-	Config       config.Map       // optional configuration key/value pairs.
-	Decrypter    config.Decrypter // decrypter for secret configuration values.
-	Snapshot     *Snapshot        // the last snapshot deployed to the target.
+	Name          tokens.Name                   // the target stack name.
+	Organization  tokens.Name                   // the target organization name (if any).
+	Config        config.Map                    // optional configuration key/value pairs.
+	Decrypter     config.Decrypter              // decrypter for secret configuration values.
+	Snapshot      *Snapshot                     // the last snapshot deployed to the target.
+	IgnoreChanges *workspace.StackIgnoreChanges // optional default ignoreChanges rules for the target's resources.
+	Parallelism   *workspace.StackParallelism   // optional per-provider and per-type parallelism limits.
 }
 
 // GetPackageConfig returns the set of configuration parameters for the indicated package, if any.
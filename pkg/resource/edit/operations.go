@@ -136,6 +136,45 @@ func UnprotectResource(_ *deploy.Snapshot, res *resource.State) error {
 	return nil
 }
 
+// ProtectResource protects a resource.
+func ProtectResource(_ *deploy.Snapshot, res *resource.State) error {
+	res.Protect = true
+	return nil
+}
+
+// RetainResource marks a resource to be retained when it is deleted, i.e. it is removed from the stack's state
+// without being deleted by its provider.
+func RetainResource(_ *deploy.Snapshot, res *resource.State) error {
+	res.RetainOnDelete = true
+	return nil
+}
+
+// IsTaintable returns true if the given resource can be tainted. Only custom resources can be replaced: component
+// resources and providers cannot be tainted.
+func IsTaintable(res *resource.State) bool {
+	return res.Custom && !providers.IsProviderType(res.Type)
+}
+
+// TaintResource marks a resource to be replaced on the next update. Component resources and providers cannot be
+// tainted.
+func TaintResource(_ *deploy.Snapshot, res *resource.State) error {
+	if !IsTaintable(res) {
+		kind := "component resource"
+		if providers.IsProviderType(res.Type) {
+			kind = "provider"
+		}
+		return fmt.Errorf("cannot taint %s %s: only custom resources can be replaced", kind, res.URN)
+	}
+	res.Taint = true
+	return nil
+}
+
+// UntaintResource clears a resource's taint marker, so that it will no longer be replaced on the next update.
+func UntaintResource(_ *deploy.Snapshot, res *resource.State) error {
+	res.Taint = false
+	return nil
+}
+
 // LocateResource returns all resources in the given snapshot that have the given URN.
 func LocateResource(snap *deploy.Snapshot, urn resource.URN) []*resource.State {
 	// If there is no snapshot then return no resources
@@ -400,6 +400,35 @@ func TestUnprotectResource(t *testing.T) {
 	assert.False(t, a.Protect)
 }
 
+func TestProtectRetainAndTaintResource(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	a.Custom = true
+	comp := NewResource("comp", nil)
+	snap := NewSnapshot([]*resource.State{pA, a, comp})
+
+	assert.NoError(t, ProtectResource(snap, a))
+	assert.True(t, a.Protect)
+
+	assert.NoError(t, RetainResource(snap, a))
+	assert.True(t, a.RetainOnDelete)
+
+	assert.NoError(t, TaintResource(snap, a))
+	assert.True(t, a.Taint)
+	assert.NoError(t, UntaintResource(snap, a))
+	assert.False(t, a.Taint)
+
+	// Component resources and providers are never replaced, so they cannot be tainted.
+	assert.ErrorContains(t, TaintResource(snap, comp), "cannot taint component resource")
+	assert.False(t, comp.Taint)
+	assert.ErrorContains(t, TaintResource(snap, pA), "cannot taint provider")
+	assert.False(t, pA.Taint)
+
+	assert.Equal(t, []*resource.State{pA, a, comp}, snap.Resources)
+}
+
 func TestLocateResourceNotFound(t *testing.T) {
 	t.Parallel()
 
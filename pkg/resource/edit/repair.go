@@ -0,0 +1,354 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy"
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// RepairKind identifies the kind of change made by RepairSnapshot.
+type RepairKind string
+
+const (
+	// RepairMoveResource moves a resource so that it comes after the resources it refers to.
+	RepairMoveResource RepairKind = "move"
+	// RepairDropParent removes a reference to a parent that does not exist.
+	RepairDropParent RepairKind = "drop-parent"
+	// RepairDropDependency removes a dependency on a resource that does not exist.
+	RepairDropDependency RepairKind = "drop-dependency"
+	// RepairDropPropertyDependency removes a property dependency on a resource that does not exist.
+	RepairDropPropertyDependency RepairKind = "drop-property-dependency"
+	// RepairDropDeletedWith removes a deletedWith reference to a resource that does not exist.
+	RepairDropDeletedWith RepairKind = "drop-deleted-with"
+	// RepairRewriteProvider replaces a reference to a provider that does not exist with a reference to the provider
+	// that has the same URN.
+	RepairRewriteProvider RepairKind = "rewrite-provider"
+)
+
+// Repair describes a single change made by RepairSnapshot.
+type Repair struct {
+	// Kind is the kind of change.
+	Kind RepairKind `json:"kind"`
+	// URN is the URN of the resource that was changed.
+	URN resource.URN `json:"urn"`
+	// Property is the property whose dependencies were changed, if any.
+	Property resource.PropertyKey `json:"property,omitempty"`
+	// Old is the value that was removed or replaced: a URN, a provider reference, or a position in the snapshot.
+	Old string `json:"old,omitempty"`
+	// New is the value that replaced Old, if any.
+	New string `json:"new,omitempty"`
+}
+
+func (r Repair) String() string {
+	switch r.Kind {
+	case RepairMoveResource:
+		return fmt.Sprintf("move from position %s to position %s", r.Old, r.New)
+	case RepairDropParent:
+		return "remove missing parent " + r.Old
+	case RepairDropDependency:
+		return "remove missing dependency " + r.Old
+	case RepairDropPropertyDependency:
+		return fmt.Sprintf("remove missing dependency %s of property %q", r.Old, r.Property)
+	case RepairDropDeletedWith:
+		return "remove missing deletedWith resource " + r.Old
+	case RepairRewriteProvider:
+		return fmt.Sprintf("replace missing provider %s with %s", r.Old, r.New)
+	default:
+		return string(r.Kind)
+	}
+}
+
+// RepairSnapshot fixes the integrity errors in the given snapshot that can be fixed without losing resources. The
+// edits are made in-place, and a description of each edit is returned in the order in which it was made.
+//
+// References to parents, dependencies, and deletedWith resources that are not present in the snapshot are removed.
+// References to providers that are not present in the snapshot are rewritten to refer to the provider with the same
+// URN, if there is exactly one such provider. The resources are then sorted so that each resource comes after the
+// resources that it refers to, preserving the existing order wherever possible.
+//
+// An error is returned if the snapshot cannot be repaired, e.g. because its resources form a cycle. In that case, the
+// snapshot may have been partially modified.
+func RepairSnapshot(snap *deploy.Snapshot) ([]Repair, error) {
+	contract.Requiref(snap != nil, "snap", "must not be nil")
+
+	urns := map[resource.URN][]*resource.State{}
+	provs := map[providers.Reference]*resource.State{}
+	for _, res := range snap.Resources {
+		urns[res.URN] = append(urns[res.URN], res)
+		if providers.IsProviderType(res.Type) {
+			ref, err := providers.NewReference(res.URN, res.ID)
+			if err != nil {
+				return nil, fmt.Errorf("provider %s is not referenceable: %w", res.URN, err)
+			}
+			provs[ref] = res
+		}
+	}
+
+	var repairs []Repair
+	for _, res := range snap.Resources {
+		rs, err := repairReferences(res, urns, provs)
+		if err != nil {
+			return nil, err
+		}
+		repairs = append(repairs, rs...)
+	}
+
+	sorted, err := sortResources(snap.Resources, urns, provs)
+	if err != nil {
+		return nil, err
+	}
+	repairs = append(repairs, movedResources(snap.Resources, sorted)...)
+	snap.Resources = sorted
+
+	return repairs, nil
+}
+
+// repairReferences removes or rewrites the references from the given resource to resources that do not exist.
+func repairReferences(
+	res *resource.State, urns map[resource.URN][]*resource.State, provs map[providers.Reference]*resource.State,
+) ([]Repair, error) {
+	var repairs []Repair
+
+	if res.Parent != "" && len(urns[res.Parent]) == 0 {
+		repairs = append(repairs, Repair{Kind: RepairDropParent, URN: res.URN, Old: string(res.Parent)})
+		res.Parent = ""
+	}
+
+	if len(res.Dependencies) != 0 {
+		deps := slice.Prealloc[resource.URN](len(res.Dependencies))
+		for _, dep := range res.Dependencies {
+			if len(urns[dep]) == 0 {
+				repairs = append(repairs, Repair{Kind: RepairDropDependency, URN: res.URN, Old: string(dep)})
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		res.Dependencies = deps
+	}
+
+	for _, key := range sortedPropertyKeys(res.PropertyDependencies) {
+		propDeps := res.PropertyDependencies[key]
+		deps := slice.Prealloc[resource.URN](len(propDeps))
+		for _, dep := range propDeps {
+			if len(urns[dep]) == 0 {
+				repairs = append(repairs, Repair{
+					Kind:     RepairDropPropertyDependency,
+					URN:      res.URN,
+					Property: key,
+					Old:      string(dep),
+				})
+				continue
+			}
+			deps = append(deps, dep)
+		}
+		res.PropertyDependencies[key] = deps
+	}
+
+	if res.DeletedWith != "" && len(urns[res.DeletedWith]) == 0 {
+		repairs = append(repairs, Repair{Kind: RepairDropDeletedWith, URN: res.URN, Old: string(res.DeletedWith)})
+		res.DeletedWith = ""
+	}
+
+	if res.Provider != "" {
+		ref, err := providers.ParseReference(res.Provider)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse provider reference for resource %s: %w", res.URN, err)
+		}
+		if _, has := provs[ref]; !has {
+			// Look for a single live provider with the same URN. This is typically the result of a provider being
+			// replaced without its dependents being updated.
+			var candidates []providers.Reference
+			for _, prov := range urns[ref.URN()] {
+				if prov.Delete {
+					continue
+				}
+				if candidate, err := providers.NewReference(prov.URN, prov.ID); err == nil {
+					candidates = append(candidates, candidate)
+				}
+			}
+			if len(candidates) != 1 {
+				return nil, fmt.Errorf("resource %s refers to unknown provider %s, which cannot be repaired automatically",
+					res.URN, ref)
+			}
+
+			repairs = append(repairs, Repair{
+				Kind: RepairRewriteProvider,
+				URN:  res.URN,
+				Old:  ref.String(),
+				New:  candidates[0].String(),
+			})
+			res.Provider = candidates[0].String()
+		}
+	}
+
+	return repairs, nil
+}
+
+// sortResources returns the given resources sorted so that each resource comes after its parent, its provider, and
+// its dependencies. Resources that are already in a valid position keep their relative order. As in
+// Snapshot.VerifyIntegrity, a resource that is pending deletion need not come before the resources that refer to its
+// URN.
+func sortResources(
+	resources []*resource.State, urns map[resource.URN][]*resource.State,
+	provs map[providers.Reference]*resource.State,
+) ([]*resource.State, error) {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+
+	sorted := slice.Prealloc[*resource.State](len(resources))
+	state := map[*resource.State]int{}
+	emitted := map[resource.URN]bool{}
+
+	var visit func(res *resource.State) error
+	visitURN := func(res *resource.State, urn resource.URN) error {
+		if emitted[urn] {
+			return nil
+		}
+		// A reference to a URN only needs the live resource with that URN to come first. Copies that are pending
+		// deletion after a create-before-replace are placed by their own references, as they may depend on the
+		// resources that depend on their replacement. If there is no live resource, any copy will do.
+		others := urns[urn]
+		var live []*resource.State
+		for _, other := range others {
+			if !other.Delete {
+				live = append(live, other)
+			}
+		}
+		if len(live) != 0 {
+			others = live
+		}
+		for _, other := range others {
+			if state[other] == 0 {
+				if err := visit(other); err != nil {
+					return err
+				}
+			}
+		}
+		if !emitted[urn] {
+			return fmt.Errorf("resource %s is part of a dependency cycle with %s", res.URN, urn)
+		}
+		return nil
+	}
+	visit = func(res *resource.State) error {
+		state[res] = visiting
+
+		if res.Provider != "" {
+			ref, err := providers.ParseReference(res.Provider)
+			contract.AssertNoErrorf(err, "provider references have been validated")
+			prov := provs[ref]
+			switch state[prov] {
+			case 0:
+				if err := visit(prov); err != nil {
+					return err
+				}
+			case visiting:
+				return fmt.Errorf("resource %s is part of a dependency cycle with %s", res.URN, prov.URN)
+			}
+		}
+
+		refs := slice.Prealloc[resource.URN](len(res.Dependencies) + 1)
+		if res.Parent != "" {
+			refs = append(refs, res.Parent)
+		}
+		refs = append(refs, res.Dependencies...)
+		for _, key := range sortedPropertyKeys(res.PropertyDependencies) {
+			refs = append(refs, res.PropertyDependencies[key]...)
+		}
+		for _, urn := range refs {
+			if err := visitURN(res, urn); err != nil {
+				return err
+			}
+		}
+
+		state[res] = visited
+		emitted[res.URN] = true
+		sorted = append(sorted, res)
+		return nil
+	}
+
+	for _, res := range resources {
+		if state[res] == 0 {
+			if err := visit(res); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return sorted, nil
+}
+
+// movedResources returns a repair for each resource that had to be moved in order to produce the sorted list of
+// resources. The resources that were not moved are the longest run of resources whose relative order is unchanged.
+func movedResources(original, sorted []*resource.State) []Repair {
+	index := make(map[*resource.State]int, len(original))
+	for i, res := range original {
+		index[res] = i
+	}
+
+	// Find the longest increasing subsequence of original indices in the sorted list. tails[k] holds the position in
+	// sorted of the smallest tail of an increasing subsequence of length k+1, and prev links each position to its
+	// predecessor in such a subsequence.
+	tails := []int{}
+	prev := make([]int, len(sorted))
+	for i, res := range sorted {
+		k := sort.Search(len(tails), func(k int) bool { return index[sorted[tails[k]]] >= index[res] })
+		if k > 0 {
+			prev[i] = tails[k-1]
+		} else {
+			prev[i] = -1
+		}
+		if k == len(tails) {
+			tails = append(tails, i)
+		} else {
+			tails[k] = i
+		}
+	}
+	kept := make(map[int]bool, len(tails))
+	if len(tails) != 0 {
+		for i := tails[len(tails)-1]; i != -1; i = prev[i] {
+			kept[i] = true
+		}
+	}
+
+	var repairs []Repair
+	for i, res := range sorted {
+		if !kept[i] {
+			repairs = append(repairs, Repair{
+				Kind: RepairMoveResource,
+				URN:  res.URN,
+				Old:  fmt.Sprint(index[res]),
+				New:  fmt.Sprint(i),
+			})
+		}
+	}
+	return repairs
+}
+
+func sortedPropertyKeys(m map[resource.PropertyKey][]resource.URN) []resource.PropertyKey {
+	keys := slice.Prealloc[resource.PropertyKey](len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	return keys
+}
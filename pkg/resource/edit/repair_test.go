@@ -0,0 +1,160 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package edit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+)
+
+func TestRepairSnapshotValid(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	snap := NewSnapshot([]*resource.State{pA, a, b})
+
+	repairs, err := RepairSnapshot(snap)
+	require.NoError(t, err)
+	assert.Empty(t, repairs)
+	assert.Equal(t, []*resource.State{pA, a, b}, snap.Resources)
+}
+
+func TestRepairSnapshotReorders(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	c := NewResource("c", pA)
+	c.Parent = b.URN
+	d := NewResource("d", pA)
+	// c and b come before their dependencies, and everything comes before the provider.
+	snap := NewSnapshot([]*resource.State{c, d, b, a, pA})
+	require.Error(t, snap.VerifyIntegrity())
+
+	repairs, err := RepairSnapshot(snap)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA, a, b, c, d}, snap.Resources)
+	assert.Equal(t, []Repair{
+		{Kind: RepairMoveResource, URN: pA.URN, Old: "4", New: "0"},
+		{Kind: RepairMoveResource, URN: a.URN, Old: "3", New: "1"},
+		{Kind: RepairMoveResource, URN: b.URN, Old: "2", New: "2"},
+	}, repairs)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
+func TestRepairSnapshotDropsMissingReferences(t *testing.T) {
+	t.Parallel()
+
+	pA := NewProviderResource("a", "p1", "0")
+	missing := NewResource("missing", pA)
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, missing.URN, a.URN)
+	b.Parent = missing.URN
+	b.DeletedWith = missing.URN
+	b.PropertyDependencies = map[resource.PropertyKey][]resource.URN{
+		"foo": {missing.URN},
+		"bar": {a.URN},
+	}
+	snap := NewSnapshot([]*resource.State{pA, a, b})
+	require.Error(t, snap.VerifyIntegrity())
+
+	repairs, err := RepairSnapshot(snap)
+	require.NoError(t, err)
+	assert.Equal(t, []Repair{
+		{Kind: RepairDropParent, URN: b.URN, Old: string(missing.URN)},
+		{Kind: RepairDropDependency, URN: b.URN, Old: string(missing.URN)},
+		{Kind: RepairDropPropertyDependency, URN: b.URN, Property: "foo", Old: string(missing.URN)},
+		{Kind: RepairDropDeletedWith, URN: b.URN, Old: string(missing.URN)},
+	}, repairs)
+	assert.Equal(t, resource.URN(""), b.Parent)
+	assert.Equal(t, []resource.URN{a.URN}, b.Dependencies)
+	assert.Empty(t, b.PropertyDependencies["foo"])
+	assert.Equal(t, []resource.URN{a.URN}, b.PropertyDependencies["bar"])
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
+func TestRepairSnapshotRewritesProviders(t *testing.T) {
+	t.Parallel()
+
+	oldProvider := NewProviderResource("a", "p1", "0")
+	newProvider := NewProviderResource("a", "p1", "1")
+	a := NewResource("a", oldProvider)
+	snap := NewSnapshot([]*resource.State{newProvider, a})
+	require.Error(t, snap.VerifyIntegrity())
+
+	repairs, err := RepairSnapshot(snap)
+	require.NoError(t, err)
+
+	oldRef, err := providers.NewReference(oldProvider.URN, oldProvider.ID)
+	require.NoError(t, err)
+	newRef, err := providers.NewReference(newProvider.URN, newProvider.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []Repair{
+		{Kind: RepairRewriteProvider, URN: a.URN, Old: oldRef.String(), New: newRef.String()},
+	}, repairs)
+	assert.Equal(t, newRef.String(), a.Provider)
+	assert.NoError(t, snap.VerifyIntegrity())
+
+	// Resources whose provider is missing entirely cannot be repaired.
+	b := NewResource("b", NewProviderResource("b", "p2", "0"))
+	_, err = RepairSnapshot(NewSnapshot([]*resource.State{newProvider, b}))
+	assert.ErrorContains(t, err, "cannot be repaired automatically")
+}
+
+func TestRepairSnapshotPendingDelete(t *testing.T) {
+	t.Parallel()
+
+	// a has been replaced with create-before-replace, and the old a, which is pending deletion, depended on b, which
+	// now depends on the new a.
+	pA := NewProviderResource("a", "p1", "0")
+	a := NewResource("a", pA)
+	b := NewResource("b", pA, a.URN)
+	oldA := NewResource("a", pA, b.URN)
+	oldA.Delete = true
+	snap := NewSnapshot([]*resource.State{pA, a, b, oldA})
+	require.NoError(t, snap.VerifyIntegrity())
+
+	repairs, err := RepairSnapshot(snap)
+	require.NoError(t, err)
+	assert.Empty(t, repairs)
+	assert.Equal(t, []*resource.State{pA, a, b, oldA}, snap.Resources)
+
+	// Misplaced resources are still moved after the live a.
+	snap = NewSnapshot([]*resource.State{pA, b, oldA, a})
+	require.Error(t, snap.VerifyIntegrity())
+	_, err = RepairSnapshot(snap)
+	require.NoError(t, err)
+	assert.Equal(t, []*resource.State{pA, a, b, oldA}, snap.Resources)
+	assert.NoError(t, snap.VerifyIntegrity())
+}
+
+func TestRepairSnapshotCycle(t *testing.T) {
+	t.Parallel()
+
+	a := NewResource("a", nil)
+	b := NewResource("b", nil, a.URN)
+	a.Dependencies = []resource.URN{b.URN}
+
+	_, err := RepairSnapshot(NewSnapshot([]*resource.State{a, b}))
+	assert.ErrorContains(t, err, "dependency cycle")
+}
@@ -253,6 +253,26 @@ func NewPromptingPassphraseSecretsManagerFromState(state json.RawMessage) (secre
 	}
 }
 
+// NewPassphraseSecretsManagerFromState returns a new passphrase-based secrets manager, from the given state, using
+// the given passphrase rather than prompting for one. As with NewPromptingPassphraseSecretsManagerFromState, if the
+// passphrase is incorrect the returned secrets manager keeps the state but can not encrypt or decrypt anything.
+func NewPassphraseSecretsManagerFromState(phrase string, state json.RawMessage) (secrets.Manager, error) {
+	var s localSecretsManagerState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return nil, fmt.Errorf("unmarshalling state: %w", err)
+	}
+
+	sm, err := GetPassphraseSecretsManager(phrase, s.Salt)
+	switch {
+	case err == ErrIncorrectPassphrase:
+		return newLockedPasspharseSecretsManager(state), nil
+	case err != nil:
+		return nil, fmt.Errorf("constructing secrets manager: %w", err)
+	default:
+		return sm, nil
+	}
+}
+
 func NewPromptingPassphraseSecretsManager(info *workspace.ProjectStack,
 	rotateSecretsProvider bool,
 ) (secrets.Manager, error) {
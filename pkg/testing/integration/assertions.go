@@ -0,0 +1,329 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/pulumi/pulumi/pkg/v3/resource/deploy/providers"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
+)
+
+// updateGoldens is set by the -update-goldens flag (or PULUMI_ACCEPT=true) to rewrite golden files rather than
+// compare against them.
+var updateGoldens bool
+
+// Placeholders for the values that vary between runs, which are replaced when comparing against golden files.
+const (
+	idPlaceholder        = "[id]"
+	timestampPlaceholder = "[timestamp]"
+	sequencePlaceholder  = "[sequence]"
+	durationPlaceholder  = "[duration]"
+	stackPlaceholder     = "[stack]"
+	secretPlaceholder    = "[secret]"
+)
+
+// ResourcesOfType returns the resources in the deployment that have the given type.
+func (s RuntimeValidationStackInfo) ResourcesOfType(typ tokens.Type) []apitype.ResourceV3 {
+	if s.Deployment == nil {
+		return nil
+	}
+	var resources []apitype.ResourceV3
+	for _, res := range s.Deployment.Resources {
+		if res.Type == typ {
+			resources = append(resources, res)
+		}
+	}
+	return resources
+}
+
+// FindResource returns the resource in the deployment with the given type and name.
+func (s RuntimeValidationStackInfo) FindResource(typ tokens.Type, name string) (apitype.ResourceV3, bool) {
+	for _, res := range s.ResourcesOfType(typ) {
+		if res.URN.Name() == tokens.QName(name) {
+			return res, true
+		}
+	}
+	return apitype.ResourceV3{}, false
+}
+
+// resource returns the resource in the deployment with the given URN.
+func (s RuntimeValidationStackInfo) resource(urn resource.URN) (apitype.ResourceV3, bool) {
+	if s.Deployment != nil {
+		for _, res := range s.Deployment.Resources {
+			if res.URN == urn {
+				return res, true
+			}
+		}
+	}
+	return apitype.ResourceV3{}, false
+}
+
+// AssertResourceCount asserts that the deployment contains the expected number of resources of the given type.
+func (s RuntimeValidationStackInfo) AssertResourceCount(t *testing.T, typ tokens.Type, expected int) bool {
+	return assert.Lenf(t, s.ResourcesOfType(typ), expected, "unexpected number of resources of type %s", typ)
+}
+
+// AssertProperty asserts that the output property at the given path (e.g. `tags["name"]` or `items[0].id`) of the
+// resource with the given URN has the expected value. Values are compared as they are decoded from the deployment's
+// JSON, so numbers are float64s, objects are map[string]interface{}s and arrays are []interface{}s.
+func (s RuntimeValidationStackInfo) AssertProperty(
+	t *testing.T, urn resource.URN, path string, expected interface{},
+) bool {
+	res, ok := s.resource(urn)
+	if !ok {
+		return assert.Failf(t, "resource not found", "no resource with URN %s", urn)
+	}
+	propertyPath, err := resource.ParsePropertyPath(path)
+	if !assert.NoErrorf(t, err, "invalid property path %q", path) {
+		return false
+	}
+
+	var v interface{} = res.Outputs
+	for _, key := range propertyPath {
+		switch key := key.(type) {
+		case string:
+			obj, ok := v.(map[string]interface{})
+			if !ok {
+				return assert.Failf(t, "property not found", "%s of %s is not an object", path, urn)
+			}
+			if v, ok = obj[key]; !ok {
+				return assert.Failf(t, "property not found", "%s of %s does not exist", path, urn)
+			}
+		case int:
+			arr, ok := v.([]interface{})
+			if !ok {
+				return assert.Failf(t, "property not found", "%s of %s is not an array", path, urn)
+			}
+			if key < 0 || key >= len(arr) {
+				return assert.Failf(t, "property not found", "%s of %s is out of range", path, urn)
+			}
+			v = arr[key]
+		}
+	}
+	return assert.Equalf(t, expected, v, "unexpected value for %s of %s", path, urn)
+}
+
+// AssertDependsOn asserts that the resource with the given URN depends on the resource with the dependency URN,
+// either directly or through one of its properties.
+func (s RuntimeValidationStackInfo) AssertDependsOn(t *testing.T, urn, dependency resource.URN) bool {
+	res, ok := s.resource(urn)
+	if !ok {
+		return assert.Failf(t, "resource not found", "no resource with URN %s", urn)
+	}
+	for _, dep := range res.Dependencies {
+		if dep == dependency {
+			return true
+		}
+	}
+	for _, deps := range res.PropertyDependencies {
+		for _, dep := range deps {
+			if dep == dependency {
+				return true
+			}
+		}
+	}
+	return assert.Failf(t, "missing dependency", "%s does not depend on %s", urn, dependency)
+}
+
+// AssertGoldenEvents asserts that the engine events of the last update match those in the golden file at the given
+// path. Values that vary between runs, such as IDs, timestamps and the stack's name, are normalized before
+// comparison. If the test is run with -update-goldens or PULUMI_ACCEPT=true, the golden file is rewritten instead.
+func (s RuntimeValidationStackInfo) AssertGoldenEvents(t *testing.T, path string) bool {
+	return s.assertGolden(t, path, s.Events, normalizeEvents)
+}
+
+// AssertGoldenSnapshot asserts that the stack's deployment matches the golden file at the given path. Values that
+// vary between runs, such as IDs, timestamps, secret ciphertexts and the stack's name, are normalized before
+// comparison. If the test is run with -update-goldens or PULUMI_ACCEPT=true, the golden file is rewritten instead.
+func (s RuntimeValidationStackInfo) AssertGoldenSnapshot(t *testing.T, path string) bool {
+	return s.assertGolden(t, path, s.Deployment, normalizeDeployment)
+}
+
+func (s RuntimeValidationStackInfo) assertGolden(
+	t *testing.T, path string, v interface{}, normalizeFields func(interface{}),
+) bool {
+	actual, err := s.normalizeGolden(v, normalizeFields)
+	if !assert.NoError(t, err) {
+		return false
+	}
+
+	if updateGoldens || cmdutil.IsTruthy(os.Getenv("PULUMI_ACCEPT")) {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); !assert.NoError(t, err) {
+			return false
+		}
+		return assert.NoError(t, os.WriteFile(path, actual, 0o600))
+	}
+
+	expected, err := os.ReadFile(path)
+	if !assert.NoErrorf(t, err, "reading golden file; run with -update-goldens to create it") {
+		return false
+	}
+	return assert.Equalf(t, string(expected), string(actual),
+		"%s does not match; run with -update-goldens to update it", path)
+}
+
+// normalizeGolden returns the golden file form of the given value: indented JSON in which the engine's fields that
+// vary between runs have been replaced by placeholders by normalizeFields, secrets have been hidden, and the stack's
+// name has been replaced in URNs.
+func (s RuntimeValidationStackInfo) normalizeGolden(v interface{}, normalizeFields func(interface{})) ([]byte, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic interface{}
+	if err := json.Unmarshal(bytes, &generic); err != nil {
+		return nil, err
+	}
+	normalizeFields(generic)
+	normalized, err := json.MarshalIndent(s.normalize(generic), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(normalized, '\n'), nil
+}
+
+// normalizeDeployment replaces the fields of a JSON deployment that vary between runs. The manifest and secrets
+// providers are dropped entirely, as they depend on the CLI's version and on the stack's secrets.
+func normalizeDeployment(v interface{}) {
+	deployment, ok := v.(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(deployment, "manifest")
+	delete(deployment, "secrets_providers")
+	for _, res := range objects(deployment["resources"]) {
+		normalizeResource(res, "created", "modified")
+	}
+	for _, op := range objects(deployment["pending_operations"]) {
+		if res, ok := op["resource"].(map[string]interface{}); ok {
+			normalizeResource(res, "created", "modified")
+		}
+	}
+}
+
+// normalizeEvents replaces the fields of JSON engine events that vary between runs.
+func normalizeEvents(v interface{}) {
+	for _, event := range objects(v) {
+		replaceField(event, "sequence", sequencePlaceholder)
+		replaceField(event, "timestamp", timestampPlaceholder)
+		if summary, ok := event["summaryEvent"].(map[string]interface{}); ok {
+			replaceField(summary, "durationSeconds", durationPlaceholder)
+		}
+		for _, key := range []string{"resourcePreEvent", "resOutputsEvent", "resOpFailedEvent"} {
+			e, _ := event[key].(map[string]interface{})
+			metadata, ok := e["metadata"].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			normalizeProvider(metadata)
+			for _, state := range []string{"old", "new"} {
+				if res, ok := metadata[state].(map[string]interface{}); ok {
+					normalizeResource(res)
+				}
+			}
+		}
+	}
+}
+
+// normalizeResource replaces the ID and the given timestamps of a JSON resource state, along with the ID in its
+// provider reference. Its inputs and outputs are left alone.
+func normalizeResource(res map[string]interface{}, timestamps ...string) {
+	replaceField(res, "id", idPlaceholder)
+	for _, key := range timestamps {
+		replaceField(res, key, timestampPlaceholder)
+	}
+	normalizeProvider(res)
+}
+
+// normalizeProvider replaces the provider's ID in the provider reference of a JSON resource state or step.
+func normalizeProvider(obj map[string]interface{}) {
+	provider, _ := obj["provider"].(string)
+	if ref, err := providers.ParseReference(provider); err == nil {
+		obj["provider"] = string(ref.URN()) + resource.URNNameDelimiter + idPlaceholder
+	}
+}
+
+// replaceField replaces the value of the given field of a JSON object with a placeholder, if it has one.
+func replaceField(obj map[string]interface{}, key, placeholder string) {
+	if v, ok := obj[key]; ok && v != nil && v != "" {
+		obj[key] = placeholder
+	}
+}
+
+// objects returns the JSON objects in a JSON array.
+func objects(v interface{}) []map[string]interface{} {
+	arr, _ := v.([]interface{})
+	var objs []map[string]interface{}
+	for _, e := range arr {
+		if obj, ok := e.(map[string]interface{}); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
+
+// normalize hides the secrets in a JSON value and replaces the stack's name in the URNs it contains.
+func (s RuntimeValidationStackInfo) normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if v[resource.SigKey] == resource.SecretSig {
+			return secretPlaceholder
+		}
+		for k, e := range v {
+			v[k] = s.normalize(e)
+		}
+		return v
+	case []interface{}:
+		for i, e := range v {
+			v[i] = s.normalize(e)
+		}
+		return v
+	case string:
+		return s.normalizeURN(v)
+	default:
+		return v
+	}
+}
+
+// normalizeURN replaces the stack's name in the given string if it is a URN or a provider reference in the stack.
+// The root stack resource's name, which is derived from the stack's name, is replaced too.
+func (s RuntimeValidationStackInfo) normalizeURN(v string) string {
+	if s.StackName == "" {
+		return v
+	}
+	urn, id := resource.URN(v), ""
+	if ref, err := providers.ParseReference(v); err == nil {
+		urn, id = ref.URN(), resource.URNNameDelimiter+string(ref.ID())
+	}
+	if !urn.IsValid() || urn.Stack() != s.StackName {
+		return v
+	}
+	rest := strings.TrimPrefix(string(urn), resource.URNPrefix+string(s.StackName))
+	if suffix := "-" + string(s.StackName); urn.Type() == resource.RootStackType &&
+		strings.HasSuffix(string(urn.Name()), suffix) {
+		rest = strings.TrimSuffix(rest, suffix) + "-" + stackPlaceholder
+	}
+	return resource.URNPrefix + stackPlaceholder + rest + id
+}
@@ -0,0 +1,209 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package integration
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
+)
+
+func testStackInfo(stackName string) RuntimeValidationStackInfo {
+	now := time.Now()
+	stack := tokens.QName("dev-" + stackName)
+	bucket := resource.NewURN(stack, "proj", "", "aws:s3/bucket:Bucket", "bucket")
+	object := resource.NewURN(stack, "proj", "", "aws:s3/bucketObject:BucketObject", "object")
+	provider := resource.NewURN(stack, "proj", "", "pulumi:providers:aws", "default")
+	providerID := "provider-" + stackName
+	return RuntimeValidationStackInfo{
+		StackName: stack,
+		Deployment: &apitype.DeploymentV3{
+			Manifest: apitype.ManifestV1{Time: now, Magic: "magic", Version: "v3.0.0"},
+			Resources: []apitype.ResourceV3{
+				{
+					URN:    provider,
+					Type:   "pulumi:providers:aws",
+					Custom: true,
+					ID:     resource.ID(providerID),
+				},
+				{
+					URN:      bucket,
+					Type:     "aws:s3/bucket:Bucket",
+					Custom:   true,
+					ID:       resource.ID("bucket-" + stackName),
+					Created:  &now,
+					Modified: &now,
+					Provider: string(provider) + "::" + providerID,
+					Outputs: map[string]interface{}{
+						"tags":  map[string]interface{}{"name": "my-bucket"},
+						"rules": []interface{}{map[string]interface{}{"days": float64(30)}},
+						"key": map[string]interface{}{
+							resource.SigKey: resource.SecretSig,
+							"ciphertext":    "v1:" + stackName,
+						},
+					},
+				},
+				{
+					URN:    object,
+					Type:   "aws:s3/bucketObject:BucketObject",
+					Custom: true,
+					ID:     resource.ID("object-" + stackName),
+					PropertyDependencies: map[resource.PropertyKey][]resource.URN{
+						"bucket": {bucket},
+					},
+				},
+			},
+		},
+		Events: []apitype.EngineEvent{{
+			Sequence:     1,
+			Timestamp:    int(now.Unix()),
+			SummaryEvent: &apitype.SummaryEvent{DurationSeconds: 3},
+		}},
+	}
+}
+
+func TestStackInfoAssertions(t *testing.T) {
+	t.Parallel()
+
+	info := testStackInfo("abc123")
+	bucket, ok := info.FindResource("aws:s3/bucket:Bucket", "bucket")
+	require.True(t, ok)
+	object, ok := info.FindResource("aws:s3/bucketObject:BucketObject", "object")
+	require.True(t, ok)
+	_, ok = info.FindResource("aws:s3/bucket:Bucket", "missing")
+	assert.False(t, ok)
+
+	assert.True(t, info.AssertResourceCount(t, "aws:s3/bucket:Bucket", 1))
+	assert.True(t, info.AssertProperty(t, bucket.URN, `tags["name"]`, "my-bucket"))
+	assert.True(t, info.AssertProperty(t, bucket.URN, "rules[0].days", float64(30)))
+	assert.True(t, info.AssertDependsOn(t, object.URN, bucket.URN))
+
+	// Failing assertions are reported to the given T.
+	mockT := &testing.T{}
+	assert.False(t, info.AssertResourceCount(mockT, "aws:s3/bucket:Bucket", 2))
+	assert.False(t, info.AssertProperty(mockT, bucket.URN, "tags.missing", "x"))
+	assert.False(t, info.AssertProperty(mockT, bucket.URN, "rules[1].days", float64(30)))
+	assert.False(t, info.AssertDependsOn(mockT, bucket.URN, object.URN))
+}
+
+func TestGoldenFiles(t *testing.T) {
+	t.Parallel()
+
+	// Stacks from different runs have different names, IDs, timestamps and secrets, but match the same goldens.
+	for _, stackName := range []string{"abc123", "def456"} {
+		info := testStackInfo(stackName)
+		assert.True(t, info.AssertGoldenSnapshot(t, "testdata/golden/snapshot.json"))
+		assert.True(t, info.AssertGoldenEvents(t, "testdata/golden/events.json"))
+	}
+}
+
+func TestGoldenNormalization(t *testing.T) {
+	t.Parallel()
+
+	info := testStackInfo("abc123")
+	root := resource.DefaultRootStackURN(info.StackName, "proj")
+	info.Deployment.Resources = append(info.Deployment.Resources, apitype.ResourceV3{
+		URN:  root,
+		Type: resource.RootStackType,
+		Outputs: map[string]interface{}{
+			// Only the engine's fields are normalized, not user outputs that happen to share their names...
+			"id":       "my-id",
+			"time":     "noon",
+			"sequence": float64(7),
+			// ...or that happen to contain the stack's name, unless they are URNs in the stack.
+			"bucketName": "bucket-" + string(info.StackName),
+			"bucketUrn":  string(info.Deployment.Resources[1].URN),
+			"otherUrn":   "urn:pulumi:prod::proj::aws:s3/bucket:Bucket::" + string(info.StackName),
+		},
+	})
+
+	actual, err := info.normalizeGolden(info.Deployment, normalizeDeployment)
+	require.NoError(t, err)
+	var deployment struct {
+		Resources []map[string]interface{} `json:"resources"`
+	}
+	require.NoError(t, json.Unmarshal(actual, &deployment))
+	stack := deployment.Resources[3]
+	assert.Equal(t, "urn:pulumi:[stack]::proj::pulumi:pulumi:Stack::proj-[stack]", stack["urn"])
+	assert.Equal(t, map[string]interface{}{
+		"id":         "my-id",
+		"time":       "noon",
+		"sequence":   float64(7),
+		"bucketName": "bucket-dev-abc123",
+		"bucketUrn":  "urn:pulumi:[stack]::proj::aws:s3/bucket:Bucket::bucket",
+		"otherUrn":   "urn:pulumi:prod::proj::aws:s3/bucket:Bucket::dev-abc123",
+	}, stack["outputs"])
+
+	// The IDs and provider references in step events are normalized too.
+	provider := string(info.Deployment.Resources[0].URN) + "::provider-abc123"
+	events := []apitype.EngineEvent{{
+		Sequence: 2,
+		ResourcePreEvent: &apitype.ResourcePreEvent{Metadata: apitype.StepEventMetadata{
+			URN:      string(info.Deployment.Resources[1].URN),
+			Provider: provider,
+			Old: &apitype.StepEventStateMetadata{
+				ID:       "bucket-abc123",
+				Provider: provider,
+				Outputs:  map[string]interface{}{"id": "my-id"},
+			},
+		}},
+	}}
+	actual, err = info.normalizeGolden(events, normalizeEvents)
+	require.NoError(t, err)
+	var normalized []struct {
+		Sequence         string `json:"sequence"`
+		ResourcePreEvent struct {
+			Metadata apitype.StepEventMetadata `json:"metadata"`
+		} `json:"resourcePreEvent"`
+	}
+	require.NoError(t, json.Unmarshal(actual, &normalized))
+	assert.Equal(t, "[sequence]", normalized[0].Sequence)
+	metadata := normalized[0].ResourcePreEvent.Metadata
+	normalizedProvider := "urn:pulumi:[stack]::proj::pulumi:providers:aws::default::[id]"
+	assert.Equal(t, "urn:pulumi:[stack]::proj::aws:s3/bucket:Bucket::bucket", metadata.URN)
+	assert.Equal(t, normalizedProvider, metadata.Provider)
+	assert.Equal(t, "[id]", metadata.Old.ID)
+	assert.Equal(t, normalizedProvider, metadata.Old.Provider)
+	assert.Equal(t, map[string]interface{}{"id": "my-id"}, metadata.Old.Outputs)
+}
+
+func TestGoldenFileOptions(t *testing.T) {
+	t.Parallel()
+
+	var validated bool
+	pt := newProgramTester(t, &ProgramTestOptions{
+		GoldenEvents:   "testdata/golden/events.json",
+		GoldenSnapshot: "testdata/golden/snapshot.json",
+		ExtraRuntimeValidation: func(t *testing.T, stack RuntimeValidationStackInfo) {
+			validated = true
+		},
+	})
+	pt.runtimeValidation()(t, testStackInfo("abc123"))
+	assert.True(t, validated)
+
+	// A deployment that doesn't match the golden snapshot fails the test.
+	info := testStackInfo("abc123")
+	info.Deployment.Resources = info.Deployment.Resources[:1]
+	mockT := &testing.T{}
+	pt.runtimeValidation()(mockT, info)
+	assert.True(t, mockT.Failed())
+}
@@ -181,6 +181,12 @@ type ProgramTestOptions struct {
 	EditDirs []EditDir
 	// ExtraRuntimeValidation is an optional callback for additional validation, called before applying edits.
 	ExtraRuntimeValidation func(t *testing.T, stack RuntimeValidationStackInfo)
+	// GoldenEvents is an optional path to a golden file that the engine events of the last update before applying
+	// edits are compared against. See RuntimeValidationStackInfo.AssertGoldenEvents.
+	GoldenEvents string
+	// GoldenSnapshot is an optional path to a golden file that the stack's deployment before applying edits is
+	// compared against. See RuntimeValidationStackInfo.AssertGoldenSnapshot.
+	GoldenSnapshot string
 	// RelativeWorkDir is an optional path relative to `Dir` which should be used as working directory during tests.
 	RelativeWorkDir string
 	// AllowEmptyPreviewChanges is true if we expect that this test's no-op preview may propose changes (e.g.
@@ -335,6 +341,19 @@ type ProgramTestOptions struct {
 
 	// Array of provider plugin dependencies which come from local packages.
 	LocalProviders []LocalDependency
+
+	// SharedPulumiHome opts the test out of having its own temporary PULUMI_HOME, so that it shares the plugin cache,
+	// credentials and other state of the current user. By default each test gets its own PULUMI_HOME, seeded with
+	// the plugins in Plugins, so that tests running in parallel can't interfere with each other.
+	SharedPulumiHome bool
+	// Plugins are the pre-installed plugins with which to seed the test's PULUMI_HOME, taken from PluginDir. A plugin
+	// without a version is seeded at the latest version in PluginDir. If Plugins is empty, all of the plugins in
+	// PluginDir are seeded when it is set, and none otherwise.
+	Plugins []workspace.PluginSpec
+	// PluginDir is a directory of pre-installed plugins, laid out as in `~/.pulumi/plugins`, from which to seed the
+	// test's PULUMI_HOME. This may also be set with the environment variable PULUMI_TEST_PLUGIN_DIR. If neither is
+	// set, the plugins in Plugins are taken from the current user's installed plugins.
+	PluginDir string
 }
 
 func (opts *ProgramTestOptions) GetUseSharedVirtualEnv() bool {
@@ -506,6 +525,12 @@ func (opts ProgramTestOptions) With(overrides ProgramTestOptions) ProgramTestOpt
 	if overrides.ExtraRuntimeValidation != nil {
 		opts.ExtraRuntimeValidation = overrides.ExtraRuntimeValidation
 	}
+	if overrides.GoldenEvents != "" {
+		opts.GoldenEvents = overrides.GoldenEvents
+	}
+	if overrides.GoldenSnapshot != "" {
+		opts.GoldenSnapshot = overrides.GoldenSnapshot
+	}
 	if overrides.RelativeWorkDir != "" {
 		opts.RelativeWorkDir = overrides.RelativeWorkDir
 	}
@@ -650,6 +675,15 @@ func (opts ProgramTestOptions) With(overrides ProgramTestOptions) ProgramTestOpt
 	if overrides.LocalProviders != nil {
 		opts.LocalProviders = append(opts.LocalProviders, overrides.LocalProviders...)
 	}
+	if overrides.SharedPulumiHome {
+		opts.SharedPulumiHome = overrides.SharedPulumiHome
+	}
+	if overrides.Plugins != nil {
+		opts.Plugins = append(opts.Plugins, overrides.Plugins...)
+	}
+	if overrides.PluginDir != "" {
+		opts.PluginDir = overrides.PluginDir
+	}
 	return opts
 }
 
@@ -682,6 +716,7 @@ var (
 func init() {
 	flag.Var(&directoryMatcher, "dirs", "optional list of regexes to use to select integration tests to run")
 	flag.BoolVar(&listDirs, "list-dirs", false, "list available integration tests without running them")
+	flag.BoolVar(&updateGoldens, "update-goldens", false, "rewrite golden files rather than comparing against them")
 
 	mutexPath := filepath.Join(os.TempDir(), "pip-mutex.lock")
 	pipMutex = fsutil.NewFileMutex(mutexPath)
@@ -755,6 +790,24 @@ func prepareProgram(t *testing.T, opts *ProgramTestOptions) {
 		opts.CloudURL = MakeTempBackend(t)
 	}
 
+	// Give the test its own PULUMI_HOME unless it has asked to share the current user's, or has set one itself.
+	if !opts.SharedPulumiHome && !hasEnvVar(opts.Env, workspace.PulumiHomeEnvVar) {
+		pluginDir := opts.PluginDir
+		if pluginDir == "" {
+			pluginDir = os.Getenv("PULUMI_TEST_PLUGIN_DIR")
+		}
+		// Only the plugins the test lists are taken from the current user's plugins, never the whole cache.
+		if pluginDir == "" && len(opts.Plugins) > 0 {
+			dir, err := workspace.GetPluginDir()
+			if err != nil {
+				t.Fatalf("getting plugin directory: %v", err)
+			}
+			pluginDir = dir
+		}
+		home := MakeTempPulumiHome(t, pluginDir, opts.Plugins...)
+		opts.Env = append(opts.Env, fmt.Sprintf("%s=%s", workspace.PulumiHomeEnvVar, home))
+	}
+
 	// If the test panics, recover and log instead of letting the panic escape the test. Even though *this* test will
 	// have run deferred functions and cleaned up, if the panic reaches toplevel it will kill the process and prevent
 	// other tests running in parallel from cleaning up.
@@ -829,6 +882,10 @@ func prepareProgram(t *testing.T, opts *ProgramTestOptions) {
 //	(*) Only if PULUMI_ACCESS_TOKEN is set.
 //	(+) Only if `opts.RunBuild` is true.
 //
+// Unless RequireService or CloudURL is set, the stack is created in a temporary `file://` backend, and unless
+// SharedPulumiHome is set, commands run with a temporary PULUMI_HOME seeded with pre-installed plugins. Tests are
+// therefore hermetic and run in parallel by default.
+//
 // All commands must return success return codes for the test to succeed, unless ExpectFailure is true.
 func ProgramTest(t *testing.T, opts *ProgramTestOptions) {
 	prepareProgram(t, opts)
@@ -883,6 +940,18 @@ func MakeTempBackend(t *testing.T) string {
 	return fmt.Sprintf("file://%s", filepath.ToSlash(tempDir))
 }
 
+// MakeTempPulumiHome creates a temporary PULUMI_HOME directory which will clean up on test exit. If pluginDir is not
+// empty, the given plugins installed in it, or all of them if none are given, are installed in the new directory too.
+func MakeTempPulumiHome(t *testing.T, pluginDir string, plugins ...workspace.PluginSpec) string {
+	home := t.TempDir()
+	if pluginDir != "" {
+		if err := seedPlugins(filepath.Join(home, workspace.PluginDir), pluginDir, plugins); err != nil {
+			t.Fatalf("seeding plugins from %s: %v", pluginDir, err)
+		}
+	}
+	return home
+}
+
 func (pt *ProgramTester) getBin() (string, error) {
 	return getCmdBin(&pt.bin, "pulumi", pt.opts.Bin)
 }
@@ -1512,7 +1581,7 @@ func (pt *ProgramTester) TestPreviewUpdateAndEdits() error {
 	}
 
 	// Run additional validation provided by the test options, passing in the checkpoint info.
-	if err := pt.performExtraRuntimeValidation(pt.opts.ExtraRuntimeValidation, dir); err != nil {
+	if err := pt.performExtraRuntimeValidation(pt.runtimeValidation(), dir); err != nil {
 		return err
 	}
 
@@ -1770,6 +1839,25 @@ func (pt *ProgramTester) testEdit(dir string, i int, edit EditDir) error {
 	return pt.performExtraRuntimeValidation(edit.ExtraRuntimeValidation, dir)
 }
 
+// runtimeValidation returns the validation to run before applying edits: the comparisons against the test's golden
+// files, if any, followed by its ExtraRuntimeValidation.
+func (pt *ProgramTester) runtimeValidation() func(t *testing.T, stack RuntimeValidationStackInfo) {
+	if pt.opts.GoldenEvents == "" && pt.opts.GoldenSnapshot == "" {
+		return pt.opts.ExtraRuntimeValidation
+	}
+	return func(t *testing.T, stack RuntimeValidationStackInfo) {
+		if pt.opts.GoldenEvents != "" {
+			stack.AssertGoldenEvents(t, pt.opts.GoldenEvents)
+		}
+		if pt.opts.GoldenSnapshot != "" {
+			stack.AssertGoldenSnapshot(t, pt.opts.GoldenSnapshot)
+		}
+		if pt.opts.ExtraRuntimeValidation != nil {
+			pt.opts.ExtraRuntimeValidation(t, stack)
+		}
+	}
+}
+
 func (pt *ProgramTester) performExtraRuntimeValidation(
 	extraRuntimeValidation func(t *testing.T, stack RuntimeValidationStackInfo), dir string,
 ) error {
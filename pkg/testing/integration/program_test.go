@@ -19,9 +19,12 @@ import (
 	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"testing"
 
+	"github.com/blang/semver"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/iotest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -187,3 +190,117 @@ func TestGoModEdits(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeTempPulumiHome(t *testing.T) {
+	t.Parallel()
+
+	pluginDir := t.TempDir()
+	binary := filepath.Join(pluginDir, "resource-random-v4.13.0", "pulumi-resource-random")
+	require.NoError(t, os.MkdirAll(filepath.Dir(binary), 0o700))
+	require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\n"), 0o700))
+	// Mutable metadata is not seeded, so that the test can't write through to the source directory.
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(binary), ".pulumi-last-used"), nil, 0o600))
+	// Partially installed plugins and other files are not copied.
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "resource-aws-v6.0.0"), 0o700))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "resource-aws-v6.0.0.partial"), nil, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(pluginDir, "resource-random-v4.13.0.lock"), nil, 0o600))
+
+	home := MakeTempPulumiHome(t, pluginDir)
+	entries, err := os.ReadDir(filepath.Join(home, "plugins"))
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "resource-random-v4.13.0", entries[0].Name())
+
+	seeded := filepath.Join(home, "plugins", "resource-random-v4.13.0", "pulumi-resource-random")
+	contents, err := os.ReadFile(seeded)
+	require.NoError(t, err)
+	assert.Equal(t, "#!/bin/sh\n", string(contents))
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(seeded)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o700), info.Mode().Perm())
+	}
+	assert.NoFileExists(t, filepath.Join(filepath.Dir(seeded), ".pulumi-last-used"))
+
+	// Each test gets its own directory.
+	assert.NotEqual(t, home, MakeTempPulumiHome(t, pluginDir))
+}
+
+func TestMakeTempPulumiHomePlugins(t *testing.T) {
+	t.Parallel()
+
+	pluginDir := t.TempDir()
+	for _, dir := range []string{
+		"resource-random-v4.13.0", "resource-random-v4.9.0", "resource-aws-v6.0.0", "language-nodejs-v3.0.0",
+	} {
+		require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, dir), 0o700))
+	}
+
+	// Only the listed plugins are seeded, at the latest version if none is given.
+	v6 := semver.MustParse("6.0.0")
+	home := MakeTempPulumiHome(t, pluginDir,
+		workspace.PluginSpec{Kind: workspace.ResourcePlugin, Name: "random"},
+		workspace.PluginSpec{Kind: workspace.ResourcePlugin, Name: "aws", Version: &v6})
+	entries, err := os.ReadDir(filepath.Join(home, "plugins"))
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"resource-random-v4.13.0", "resource-aws-v6.0.0"}, names)
+
+	// A listed plugin must be installed.
+	v7 := semver.MustParse("7.0.0")
+	err = seedPlugins(t.TempDir(), pluginDir,
+		[]workspace.PluginSpec{{Kind: workspace.ResourcePlugin, Name: "aws", Version: &v7}})
+	assert.ErrorContains(t, err, "resource plugin aws-7.0.0 is not installed")
+}
+
+// Test that the commands run by a test see the test's own PULUMI_HOME, seeded with plugins, unless it opts out.
+func TestIsolatedPulumiHome(t *testing.T) {
+	t.Parallel()
+
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("Couldn't find sh on PATH")
+	}
+
+	pluginDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(pluginDir, "resource-random-v4.13.0"), 0o700))
+
+	run := func(opts *ProgramTestOptions, script string) []string {
+		prepareProgram(t, opts)
+
+		wd := t.TempDir()
+		args := []string{sh, "-c", script}
+		require.NoError(t, RunCommand(t, "sh", args, wd, opts))
+
+		matches, err := filepath.Glob(filepath.Join(wd, commandOutputFolderName, "sh.*"))
+		require.NoError(t, err)
+		require.Len(t, matches, 1)
+		output, err := os.ReadFile(matches[0])
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(output)), "\n")
+	}
+
+	opts := &ProgramTestOptions{
+		NoParallel: true,
+		PluginDir:  pluginDir,
+	}
+	lines := run(opts, `echo "$PULUMI_HOME"; ls "$PULUMI_HOME/plugins"`)
+	require.Len(t, lines, 2)
+	assert.Contains(t, opts.Env, "PULUMI_HOME="+lines[0])
+	assert.NotEqual(t, os.Getenv("PULUMI_HOME"), lines[0])
+	assert.Equal(t, "resource-random-v4.13.0", lines[1])
+
+	opts = &ProgramTestOptions{
+		NoParallel:       true,
+		SharedPulumiHome: true,
+		PluginDir:        pluginDir,
+	}
+	lines = run(opts, `echo "$PULUMI_HOME"`)
+	assert.Equal(t, []string{os.Getenv("PULUMI_HOME")}, lines)
+	for _, env := range opts.Env {
+		assert.False(t, strings.HasPrefix(env, "PULUMI_HOME="), "unexpected %s", env)
+	}
+}
@@ -17,6 +17,7 @@ package integration
 import (
 	"fmt"
 	"io"
+	"io/fs"
 	"net/http"
 	"os"
 	"os/exec"
@@ -26,10 +27,13 @@ import (
 	"testing"
 	"time"
 
+	"github.com/blang/semver"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/fsutil"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 )
 
 // DecodeMapString takes a string of the form key1=value1:key2=value2 and returns a go map.
@@ -242,3 +246,114 @@ func AssertHTTPResultWithRetry(
 	// Verify it matches expectations
 	return check(string(body))
 }
+
+// hasEnvVar returns true if the given list of `KEY=value` environment variables sets the named variable.
+func hasEnvVar(env []string, name string) bool {
+	for _, v := range env {
+		if strings.HasPrefix(v, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// mutablePluginFiles are the files in a plugin's directory that the CLI updates after the plugin is installed, such
+// as the file whose modification time workspace.RecordPluginUse bumps each time the plugin is loaded. These are never
+// seeded into a test's PULUMI_HOME, as a hard link to them would let the test write through to the source directory.
+var mutablePluginFiles = map[string]bool{
+	".pulumi-last-used": true,
+}
+
+// seedPlugins installs the given plugins from the plugin directory src into the plugin directory dst, or every plugin
+// in src if none are given. A plugin without a version is taken at the latest version in src, and it is an error for
+// a plugin to be missing. Files are hard linked where possible, as plugins are not modified once installed, and
+// copied otherwise. Partially installed plugins and mutable plugin metadata are skipped.
+func seedPlugins(dst, src string, plugins []workspace.PluginSpec) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		if os.IsNotExist(err) && len(plugins) == 0 {
+			return nil
+		}
+		return err
+	}
+	installed := map[string]bool{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(src, entry.Name()+".partial")); err == nil {
+			continue
+		}
+		installed[entry.Name()] = true
+	}
+
+	var dirs []string
+	if len(plugins) == 0 {
+		for dir := range installed {
+			dirs = append(dirs, dir)
+		}
+	}
+	for _, spec := range plugins {
+		dir, ok := spec.Dir(), false
+		if spec.Version != nil {
+			ok = installed[dir]
+		} else {
+			// Pick the latest installed version of the plugin.
+			var latest *semver.Version
+			for name := range installed {
+				v, err := semver.ParseTolerant(strings.TrimPrefix(name, dir+"-v"))
+				if !strings.HasPrefix(name, dir+"-v") || err != nil {
+					continue
+				}
+				if latest == nil || v.GT(*latest) {
+					latest, dir, ok = &v, name, true
+				}
+			}
+		}
+		if !ok {
+			return fmt.Errorf("%s plugin %s is not installed in %s", spec.Kind, spec, src)
+		}
+		dirs = append(dirs, dir)
+	}
+
+	if err := os.MkdirAll(dst, 0o700); err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := seedPlugin(filepath.Join(dst, dir), filepath.Join(src, dir)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// seedPlugin installs the single plugin directory src as dst.
+func seedPlugin(dst, src string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o700)
+		}
+		if rel == d.Name() && mutablePluginFiles[d.Name()] {
+			return nil
+		}
+		if d.Type()&fs.ModeSymlink != 0 {
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		}
+		if err := os.Link(path, target); err == nil {
+			return nil
+		}
+		return fsutil.CopyFile(target, path, nil)
+	})
+}
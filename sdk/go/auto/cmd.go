@@ -22,10 +22,64 @@ import (
 	"os/exec"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/slice"
+	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 )
 
 const unknownErrorCode = -2
 
+// PulumiCommand runs Pulumi CLI commands on behalf of a LocalWorkspace and its Stacks. By default commands are run
+// by executing the `pulumi` binary found on the PATH; the Pulumi LocalWorkspaceOption can be used to run them some
+// other way, for example in-process with the engine.
+type PulumiCommand interface {
+	// Run runs the Pulumi command given by args from workdir, returning its standard output, standard error and exit
+	// code. Output is also written to additionalOutput and additionalErrorOutput as it is produced. additionalEnv
+	// holds extra environment variables, of the form KEY=VALUE, to run the command with.
+	Run(ctx context.Context,
+		workdir string,
+		additionalOutput []io.Writer,
+		additionalErrorOutput []io.Writer,
+		additionalEnv []string,
+		args ...string,
+	) (string, string, int, error)
+}
+
+// InlinePulumiCommand is a PulumiCommand that can run inline programs itself. Stacks whose workspace has an inline
+// program pass the program to RunInline, rather than serving it to the command over gRPC.
+type InlinePulumiCommand interface {
+	PulumiCommand
+
+	// RunInline is like Run, but runs program as the Pulumi program of the command.
+	RunInline(ctx context.Context,
+		workdir string,
+		additionalOutput []io.Writer,
+		additionalErrorOutput []io.Writer,
+		additionalEnv []string,
+		program pulumi.RunFunc,
+		args ...string,
+	) (string, string, int, error)
+}
+
+// pulumiCLI is the default PulumiCommand, which executes the `pulumi` binary.
+type pulumiCLI struct{}
+
+func (pulumiCLI) Run(ctx context.Context,
+	workdir string,
+	additionalOutput []io.Writer,
+	additionalErrorOutput []io.Writer,
+	additionalEnv []string,
+	args ...string,
+) (string, string, int, error) {
+	return runPulumiCommandSync(ctx, workdir, additionalOutput, additionalErrorOutput, additionalEnv, args...)
+}
+
+// pulumiCommandFor returns the PulumiCommand used to run commands for the given workspace.
+func pulumiCommandFor(w Workspace) PulumiCommand {
+	if lw, ok := w.(*LocalWorkspace); ok && lw.pulumiCommand != nil {
+		return lw.pulumiCommand
+	}
+	return pulumiCLI{}
+}
+
 func runPulumiCommandSync(
 	ctx context.Context,
 	workdir string,
@@ -15,9 +15,12 @@
 package auto
 
 import (
+	"encoding/json"
 	"fmt"
 	"regexp"
 	"strings"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 )
 
 type autoError struct {
@@ -145,3 +148,105 @@ func IsUnexpectedEngineError(e error) bool {
 
 	return strings.Contains(as.stdout, "The Pulumi CLI encountered a fatal error. This is a bug!")
 }
+
+// StackAlreadyExistsError is returned when a stack is renamed to the name of a stack that already exists.
+type StackAlreadyExistsError struct {
+	// StackName is the name of the existing stack.
+	StackName string
+
+	err autoError
+}
+
+func (e *StackAlreadyExistsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *StackAlreadyExistsError) Unwrap() error {
+	return e.err
+}
+
+// ResourceNotFoundError is returned by state operations when the resource does not exist in the stack's state.
+type ResourceNotFoundError struct {
+	// URN is the URN of the missing resource.
+	URN string
+
+	err autoError
+}
+
+func (e *ResourceNotFoundError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ResourceNotFoundError) Unwrap() error {
+	return e.err
+}
+
+// ResourceProtectedError is returned by Stack.StateDelete when the resource is protected and
+// optstatedelete.Force is not given.
+type ResourceProtectedError struct {
+	// URN is the URN of the protected resource.
+	URN string
+
+	err autoError
+}
+
+func (e *ResourceProtectedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ResourceProtectedError) Unwrap() error {
+	return e.err
+}
+
+// ResourceHasDependentsError is returned by Stack.StateDelete when other resources depend on the resource and
+// optstatedelete.TargetDependents is not given.
+type ResourceHasDependentsError struct {
+	// URN is the URN of the resource.
+	URN string
+	// Dependents are the URNs of the resources that depend on the resource.
+	Dependents []string
+
+	err autoError
+}
+
+func (e *ResourceHasDependentsError) Error() string {
+	return e.err.Error()
+}
+
+func (e *ResourceHasDependentsError) Unwrap() error {
+	return e.err
+}
+
+// UnsupportedFlagError is returned when an operation needs a flag that the workspace's Pulumi CLI doesn't support.
+type UnsupportedFlagError struct {
+	// Command is the Pulumi command, e.g. "refresh".
+	Command string
+	// Flag is the unsupported flag, e.g. "--preview-only".
+	Flag string
+}
+
+func (e *UnsupportedFlagError) Error() string {
+	return fmt.Sprintf("the Pulumi CLI does not support `pulumi %s %s`; please upgrade", e.Command, e.Flag)
+}
+
+// newCLIError returns the structured error that a failed command that was run with --json described on stdout, or
+// the given error if the command did not describe the failure or its kind is not known.
+func newCLIError(ae autoError) error {
+	var cliErr apitype.CLIError
+	if err := json.Unmarshal([]byte(ae.stdout), &cliErr); err != nil {
+		return ae
+	}
+
+	switch cliErr.Kind {
+	case apitype.ResourceNotFoundCLIError:
+		return &ResourceNotFoundError{URN: cliErr.URN, err: ae}
+	case apitype.ResourceProtectedCLIError:
+		return &ResourceProtectedError{URN: cliErr.URN, err: ae}
+	case apitype.ResourceHasDependentsCLIError:
+		return &ResourceHasDependentsError{URN: cliErr.URN, Dependents: cliErr.Dependents, err: ae}
+	case apitype.StackAlreadyExistsCLIError:
+		return &StackAlreadyExistsError{StackName: cliErr.StackName, err: ae}
+	default:
+		return ae
+	}
+}
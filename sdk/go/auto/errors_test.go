@@ -17,15 +17,18 @@ package auto
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os/exec"
 	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/pulumi/pulumi/sdk/v3/python"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConcurrentUpdateError(t *testing.T) {
@@ -578,3 +581,66 @@ func TestRuntimeErrorDotnet(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+func TestNewCLIError(t *testing.T) {
+	t.Parallel()
+
+	urn := "urn:pulumi:dev::proj::pkg:index:Comp::parent"
+	cliError := func(e apitype.CLIError) autoError {
+		stdout, err := json.Marshal(e)
+		require.NoError(t, err)
+		return newAutoError(fmt.Errorf("failed"), string(stdout), "error: "+e.Message+"\n", 255)
+	}
+
+	err := newCLIError(cliError(apitype.CLIError{
+		Kind:    apitype.ResourceNotFoundCLIError,
+		Message: "No such resource exists",
+		URN:     urn,
+	}))
+	var notFound *ResourceNotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, urn, notFound.URN)
+
+	err = newCLIError(cliError(apitype.CLIError{
+		Kind:    apitype.ResourceProtectedCLIError,
+		Message: "can't be safely deleted because it is protected",
+		URN:     urn,
+	}))
+	var protected *ResourceProtectedError
+	assert.ErrorAs(t, err, &protected)
+	assert.Equal(t, urn, protected.URN)
+
+	err = newCLIError(cliError(apitype.CLIError{
+		Kind:    apitype.ResourceHasDependentsCLIError,
+		Message: "can't be safely deleted because the following resources depend on it",
+		URN:     urn,
+		Dependents: []string{
+			"urn:pulumi:dev::proj::pkg:index:Comp$pkg:index:Child::child",
+			"urn:pulumi:dev::proj::pkg:index:Other::other",
+		},
+	}))
+	var hasDependents *ResourceHasDependentsError
+	assert.ErrorAs(t, err, &hasDependents)
+	assert.Equal(t, urn, hasDependents.URN)
+	assert.Equal(t, []string{
+		"urn:pulumi:dev::proj::pkg:index:Comp$pkg:index:Child::child",
+		"urn:pulumi:dev::proj::pkg:index:Other::other",
+	}, hasDependents.Dependents)
+
+	err = newCLIError(cliError(apitype.CLIError{
+		Kind:      apitype.StackAlreadyExistsCLIError,
+		Message:   "stack 'dev' already exists",
+		StackName: "dev",
+	}))
+	var alreadyExists *StackAlreadyExistsError
+	assert.ErrorAs(t, err, &alreadyExists)
+	assert.Equal(t, "dev", alreadyExists.StackName)
+
+	// Failures without a known kind, or that are not described on stdout, are returned as-is.
+	err = newCLIError(cliError(apitype.CLIError{Message: "something else"}))
+	assert.IsType(t, autoError{}, err)
+	assert.Contains(t, err.Error(), "something else")
+
+	err = newCLIError(newAutoError(fmt.Errorf("failed"), "", "error: No such resource exists\n", 255))
+	assert.IsType(t, autoError{}, err)
+}
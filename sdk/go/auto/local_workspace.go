@@ -26,6 +26,7 @@ import (
 	"github.com/blang/semver"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optremove"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstatedelete"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/cmdutil"
@@ -55,6 +56,7 @@ type LocalWorkspace struct {
 	remoteEnvVars                 map[string]EnvVarValue
 	preRunCommands                []string
 	remoteSkipInstallDependencies bool
+	pulumiCommand                 PulumiCommand
 }
 
 var settingsExtensions = []string{".yaml", ".yml", ".json"}
@@ -635,6 +637,97 @@ func (l *LocalWorkspace) ImportStack(ctx context.Context, stackName string, stat
 	return nil
 }
 
+// ChangeStackSecretsProvider changes the secrets provider of the stack matching the given name and re-encrypts its
+// configuration and state with the new provider. Valid secrets providers are `default`, `passphrase`, and the URLs of
+// cloud secrets providers, e.g. `awskms://alias/ExampleAlias?region=us-east-1`. When changing to the `passphrase`
+// provider, the new passphrase is read from the PULUMI_CONFIG_PASSPHRASE environment variable of the Workspace.
+// https://www.pulumi.com/docs/cli/commands/pulumi_stack_change-secrets-provider/
+func (l *LocalWorkspace) ChangeStackSecretsProvider(
+	ctx context.Context, stackName, newSecretsProvider string,
+) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx,
+		"stack", "change-secrets-provider", "--stack", stackName, newSecretsProvider)
+	if err != nil {
+		return newAutoError(fmt.Errorf("failed to change secrets provider: %w", err), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// RenameStack renames the stack matching the given name. The stack's configuration file, if any, is renamed along
+// with the stack. Returns a StackAlreadyExistsError if a stack with the new name already exists.
+// https://www.pulumi.com/docs/cli/commands/pulumi_stack_rename/
+func (l *LocalWorkspace) RenameStack(ctx context.Context, stackName, newStackName string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx,
+		"stack", "rename", "--json", "--stack", stackName, newStackName)
+	if err != nil {
+		return newCLIError(newAutoError(fmt.Errorf("failed to rename stack: %w", err), stdout, stderr, errCode))
+	}
+	return nil
+}
+
+// StateDelete deletes the resource with the given URN from the state of the stack matching the given name. Resources
+// that other resources depend on can only be deleted with optstatedelete.TargetDependents, and protected resources can
+// only be deleted with optstatedelete.Force. Returns a ResourceNotFoundError, ResourceHasDependentsError, or
+// ResourceProtectedError if the resource can't be deleted.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_delete/
+func (l *LocalWorkspace) StateDelete(
+	ctx context.Context, stackName, urn string, opts ...optstatedelete.Option,
+) error {
+	deleteOpts := &optstatedelete.Options{}
+	for _, o := range opts {
+		o.ApplyOption(deleteOpts)
+	}
+
+	args := []string{"state", "delete", "--yes", "--json", "--stack", stackName, urn}
+	if deleteOpts.Force {
+		args = append(args, "--force")
+	}
+	if deleteOpts.TargetDependents {
+		args = append(args, "--target-dependents")
+	}
+
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx, args...)
+	if err != nil {
+		return newCLIError(newAutoError(fmt.Errorf("failed to delete resource: %w", err), stdout, stderr, errCode))
+	}
+	return nil
+}
+
+// StateUnprotect clears the protect bit of the resource with the given URN in the state of the stack matching the
+// given name. Returns a ResourceNotFoundError if the resource does not exist.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_unprotect/
+func (l *LocalWorkspace) StateUnprotect(ctx context.Context, stackName, urn string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx,
+		"state", "unprotect", "--yes", "--json", "--stack", stackName, urn)
+	if err != nil {
+		return newCLIError(newAutoError(fmt.Errorf("failed to unprotect resource: %w", err), stdout, stderr, errCode))
+	}
+	return nil
+}
+
+// StateUnprotectAll clears the protect bit of every resource in the state of the stack matching the given name.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_unprotect/
+func (l *LocalWorkspace) StateUnprotectAll(ctx context.Context, stackName string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx,
+		"state", "unprotect", "--yes", "--all", "--stack", stackName)
+	if err != nil {
+		return newAutoError(fmt.Errorf("failed to unprotect resources: %w", err), stdout, stderr, errCode)
+	}
+	return nil
+}
+
+// StateRename renames the resource with the given URN in the state of the stack matching the given name. Returns a
+// ResourceNotFoundError if the resource does not exist.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_rename/
+func (l *LocalWorkspace) StateRename(ctx context.Context, stackName, urn, newName string) error {
+	stdout, stderr, errCode, err := l.runPulumiCmdSync(ctx,
+		"state", "rename", "--yes", "--json", "--stack", stackName, urn, newName)
+	if err != nil {
+		return newCLIError(newAutoError(fmt.Errorf("failed to rename resource: %w", err), stdout, stderr, errCode))
+	}
+	return nil
+}
+
 // StackOutputs gets the current set of Stack outputs from the last Stack.Up().
 func (l *LocalWorkspace) StackOutputs(ctx context.Context, stackName string) (OutputMap, error) {
 	// standard outputs
@@ -720,7 +813,7 @@ func (l *LocalWorkspace) runPulumiCmdSync(
 			env = append(env, strings.Join(e, "="))
 		}
 	}
-	return runPulumiCommandSync(ctx,
+	return pulumiCommandFor(l).Run(ctx,
 		l.WorkDir(),
 		nil, /* additionalOutputs */
 		nil, /* additionalErrorOutputs */
@@ -738,7 +831,7 @@ func (l *LocalWorkspace) supportsPulumiCmdFlag(ctx context.Context, flag string,
 	}
 
 	// Run the command with `--help`, and then we'll look for the flag in the output.
-	stdout, _, _, err := runPulumiCommandSync(ctx, l.WorkDir(), nil, nil, env, append(args, "--help")...)
+	stdout, _, _, err := pulumiCommandFor(l).Run(ctx, l.WorkDir(), nil, nil, env, append(args, "--help")...)
 	if err != nil {
 		return false, err
 	}
@@ -796,6 +889,7 @@ func NewLocalWorkspace(ctx context.Context, opts ...LocalWorkspaceOption) (Works
 		remoteEnvVars:                 lwOpts.RemoteEnvVars,
 		remoteSkipInstallDependencies: lwOpts.RemoteSkipInstallDependencies,
 		repo:                          lwOpts.Repo,
+		pulumiCommand:                 lwOpts.PulumiCommand,
 	}
 
 	// optOut indicates we should skip the version check.
@@ -897,6 +991,8 @@ type localWorkspaceOptions struct {
 	PreRunCommands []string
 	// RemoteSkipInstallDependencies sets whether to skip the default dependency installation step
 	RemoteSkipInstallDependencies bool
+	// PulumiCommand runs Pulumi commands for the workspace. Defaults to running the `pulumi` binary.
+	PulumiCommand PulumiCommand
 }
 
 // LocalWorkspaceOption is used to customize and configure a LocalWorkspace at initialization time.
@@ -1016,6 +1112,14 @@ func EnvVars(envvars map[string]string) LocalWorkspaceOption {
 	})
 }
 
+// Pulumi sets the PulumiCommand used to run Pulumi commands for the workspace and its stacks, in place of
+// executing the `pulumi` binary.
+func Pulumi(pulumi PulumiCommand) LocalWorkspaceOption {
+	return localWorkspaceOption(func(lo *localWorkspaceOptions) {
+		lo.PulumiCommand = pulumi
+	})
+}
+
 // remoteEnvVars is a map of environment values scoped to the workspace.
 // These values will be passed to the remote Pulumi operation.
 func remoteEnvVars(envvars map[string]EnvVarValue) LocalWorkspaceOption {
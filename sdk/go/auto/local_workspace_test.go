@@ -39,8 +39,10 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optremove"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstatedelete"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	resourceConfig "github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -1238,6 +1240,89 @@ func TestProgressStreams(t *testing.T) {
 	assert.Equal(t, desOut.String(), dRes.StdOut, "expected stdout writers to contain same contents")
 }
 
+func TestStateOperations(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	sName := randomStackName()
+	stackName := FullyQualifiedStackName(pulumiOrg, pName, sName)
+
+	// initialize
+	s, err := NewStackInlineSource(ctx, stackName, pName, func(ctx *pulumi.Context) error {
+		var parent, child, other pulumi.ResourceState
+		if err := ctx.RegisterComponentResource("test:index:Component", "parent", &parent,
+			pulumi.Protect(true)); err != nil {
+			return err
+		}
+		if err := ctx.RegisterComponentResource("test:index:Component", "child", &child,
+			pulumi.Parent(&parent)); err != nil {
+			return err
+		}
+		return ctx.RegisterComponentResource("test:index:Component", "other", &other)
+	})
+	require.NoError(t, err, "failed to initialize stack")
+
+	defer func() {
+		// -- pulumi stack rm --
+		err = s.Workspace().RemoveStack(ctx, s.Name(), optremove.Force())
+		assert.Nil(t, err, "failed to remove stack. Resources have leaked.")
+	}()
+
+	// -- pulumi up --
+	_, err = s.Up(ctx)
+	require.NoError(t, err, "up failed")
+
+	urn := func(name string) string {
+		return string(resource.NewURN(tokens.QName(sName), tokens.PackageName(pName), "",
+			"test:index:Component", tokens.QName(name)))
+	}
+	parentURN := urn("parent")
+	childURN := string(resource.NewURN(tokens.QName(sName), tokens.PackageName(pName), "test:index:Component",
+		"test:index:Component", tokens.QName("child")))
+
+	// -- pulumi state delete --
+	var notFound *ResourceNotFoundError
+	err = s.StateDelete(ctx, urn("missing"))
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, urn("missing"), notFound.URN)
+
+	var protected *ResourceProtectedError
+	err = s.StateDelete(ctx, parentURN)
+	require.ErrorAs(t, err, &protected)
+	assert.Equal(t, parentURN, protected.URN)
+
+	// -- pulumi state unprotect --
+	err = s.StateUnprotect(ctx, parentURN)
+	require.NoError(t, err, "unprotect failed")
+
+	var hasDependents *ResourceHasDependentsError
+	err = s.StateDelete(ctx, parentURN)
+	require.ErrorAs(t, err, &hasDependents)
+	assert.Equal(t, []string{childURN}, hasDependents.Dependents)
+
+	err = s.StateDelete(ctx, parentURN, optstatedelete.TargetDependents())
+	require.NoError(t, err, "delete failed")
+
+	// -- pulumi state rename --
+	err = s.StateRename(ctx, urn("other"), "renamed")
+	require.NoError(t, err, "rename failed")
+
+	state, err := s.Export(ctx)
+	require.NoError(t, err, "export failed")
+	assert.Contains(t, string(state.Deployment), urn("renamed"))
+	assert.NotContains(t, string(state.Deployment), parentURN)
+
+	// -- pulumi stack rename --
+	newName := randomStackName()
+	err = s.Rename(ctx, FullyQualifiedStackName(pulumiOrg, pName, newName))
+	require.NoError(t, err, "stack rename failed")
+	assert.Equal(t, FullyQualifiedStackName(pulumiOrg, pName, newName), s.Name())
+
+	// -- pulumi stack change-secrets-provider --
+	err = s.ChangeSecretsProvider(ctx, "default")
+	require.NoError(t, err, "change secrets provider failed")
+}
+
 func TestImportExportStack(t *testing.T) {
 	t.Parallel()
 
@@ -25,6 +25,7 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optremotepreview"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optremoterefresh"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optremoteup"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstatedelete"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 )
@@ -162,3 +163,26 @@ func (s *RemoteStack) Export(ctx context.Context) (apitype.UntypedDeployment, er
 func (s *RemoteStack) Import(ctx context.Context, state apitype.UntypedDeployment) error {
 	return s.stack.Workspace().ImportStack(ctx, s.Name(), state)
 }
+
+// StateDelete deletes the resource with the given URN from the stack's state.
+// See Stack.StateDelete for details.
+func (s *RemoteStack) StateDelete(ctx context.Context, urn string, opts ...optstatedelete.Option) error {
+	return s.stack.StateDelete(ctx, urn, opts...)
+}
+
+// StateUnprotect clears the protect bit of the resource with the given URN in the stack's state.
+// See Stack.StateUnprotect for details.
+func (s *RemoteStack) StateUnprotect(ctx context.Context, urn string) error {
+	return s.stack.StateUnprotect(ctx, urn)
+}
+
+// StateUnprotectAll clears the protect bit of every resource in the stack's state.
+func (s *RemoteStack) StateUnprotectAll(ctx context.Context) error {
+	return s.stack.StateUnprotectAll(ctx)
+}
+
+// StateRename renames the resource with the given URN in the stack's state.
+// See Stack.StateRename for details.
+func (s *RemoteStack) StateRename(ctx context.Context, urn string, newName string) error {
+	return s.stack.StateRename(ctx, urn, newName)
+}
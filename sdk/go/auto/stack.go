@@ -107,6 +107,7 @@ import (
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	pbempty "github.com/golang/protobuf/ptypes/empty"
 	"github.com/nxadm/tail"
@@ -116,8 +117,10 @@ import (
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/events"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/opthistory"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optimport"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstatedelete"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/constant"
@@ -207,8 +210,6 @@ func (s *Stack) Workspace() Workspace {
 // Preview preforms a dry-run update to a stack, returning pending changes.
 // https://www.pulumi.com/docs/cli/commands/pulumi_preview/
 func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (PreviewResult, error) {
-	var res PreviewResult
-
 	preOpts := &optpreview.Options{}
 	for _, o := range opts {
 		o.ApplyOption(preOpts)
@@ -260,19 +261,31 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 	sharedArgs = append(sharedArgs, s.remoteArgs()...)
 
 	kind, args := constant.ExecKindAutoLocal, []string{"preview"}
-	if program := s.Workspace().Program(); program != nil {
-		server, err := startLanguageRuntimeServer(program)
-		if err != nil {
-			return res, err
-		}
-		defer contract.IgnoreClose(server)
-
-		kind, args = constant.ExecKindAutoInline, append(args, "--client="+server.address)
+	program := s.Workspace().Program()
+	if program != nil {
+		kind = constant.ExecKindAutoInline
 	}
 
 	args = append(args, fmt.Sprintf("--exec-kind=%s", kind))
 	args = append(args, sharedArgs...)
 
+	return s.runPreview(ctx, "preview", program, args,
+		preOpts.ProgressStreams, preOpts.ErrorProgressStreams, preOpts.EventStreams)
+}
+
+// runPreview runs the given preview command, collecting the summary of the changes it would make from the engine
+// events. program is the inline program to run, if any.
+func (s *Stack) runPreview(
+	ctx context.Context,
+	command string,
+	program pulumi.RunFunc,
+	args []string,
+	progressStreams []io.Writer,
+	errorProgressStreams []io.Writer,
+	eventStreams []chan<- events.EngineEvent,
+) (PreviewResult, error) {
+	var res PreviewResult
+
 	var summaryEvents []apitype.SummaryEvent
 	eventChannel := make(chan events.EngineEvent)
 	eventsDone := make(chan bool)
@@ -290,23 +303,24 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 	}()
 
 	eventChannels := []chan<- events.EngineEvent{eventChannel}
-	eventChannels = append(eventChannels, preOpts.EventStreams...)
+	eventChannels = append(eventChannels, eventStreams...)
 
-	t, err := tailLogs("preview", eventChannels)
+	t, err := tailLogs(command, eventChannels)
 	if err != nil {
 		return res, fmt.Errorf("failed to tail logs: %w", err)
 	}
 	defer t.Close()
 	args = append(args, "--event-log", t.Filename)
 
-	stdout, stderr, code, err := s.runPulumiCmdSync(
+	stdout, stderr, code, err := s.runPulumiProgramCmdSync(
 		ctx,
-		preOpts.ProgressStreams,      /* additionalOutput */
-		preOpts.ErrorProgressStreams, /* additionalErrorOutput */
+		program,
+		progressStreams,      /* additionalOutput */
+		errorProgressStreams, /* additionalErrorOutput */
 		args...,
 	)
 	if err != nil {
-		return res, newAutoError(fmt.Errorf("failed to run preview: %w", err), stdout, stderr, code)
+		return res, newAutoError(fmt.Errorf("failed to run %s: %w", command, err), stdout, stderr, code)
 	}
 
 	// Close the file watcher wait for all events to send
@@ -314,10 +328,10 @@ func (s *Stack) Preview(ctx context.Context, opts ...optpreview.Option) (Preview
 	<-eventsDone
 
 	if len(summaryEvents) == 0 {
-		return res, newAutoError(errors.New("failed to get preview summary"), stdout, stderr, code)
+		return res, newAutoError(fmt.Errorf("failed to get %s summary", command), stdout, stderr, code)
 	}
 	if len(summaryEvents) > 1 {
-		return res, newAutoError(errors.New("got multiple preview summaries"), stdout, stderr, code)
+		return res, newAutoError(fmt.Errorf("got multiple %s summaries", command), stdout, stderr, code)
 	}
 
 	res.StdOut = stdout
@@ -382,14 +396,9 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 	sharedArgs = append(sharedArgs, s.remoteArgs()...)
 
 	kind, args := constant.ExecKindAutoLocal, []string{"up", "--yes", "--skip-preview"}
-	if program := s.Workspace().Program(); program != nil {
-		server, err := startLanguageRuntimeServer(program)
-		if err != nil {
-			return res, err
-		}
-		defer contract.IgnoreClose(server)
-
-		kind, args = constant.ExecKindAutoInline, append(args, "--client="+server.address)
+	program := s.Workspace().Program()
+	if program != nil {
+		kind = constant.ExecKindAutoInline
 	}
 	args = append(args, fmt.Sprintf("--exec-kind=%s", kind))
 
@@ -404,7 +413,8 @@ func (s *Stack) Up(ctx context.Context, opts ...optup.Option) (UpResult, error)
 	}
 
 	args = append(args, sharedArgs...)
-	stdout, stderr, code, err := s.runPulumiCmdSync(ctx, upOpts.ProgressStreams, upOpts.ErrorProgressStreams, args...)
+	stdout, stderr, code, err := s.runPulumiProgramCmdSync(
+		ctx, program, upOpts.ProgressStreams, upOpts.ErrorProgressStreams, args...)
 	if err != nil {
 		return res, newAutoError(fmt.Errorf("failed to run update: %w", err), stdout, stderr, code)
 	}
@@ -451,33 +461,7 @@ func (s *Stack) Refresh(ctx context.Context, opts ...optrefresh.Option) (Refresh
 		o.ApplyOption(refreshOpts)
 	}
 
-	args := slice.Prealloc[string](len(refreshOpts.Target))
-
-	args = debug.AddArgs(&refreshOpts.DebugLogOpts, args)
-	args = append(args, "refresh", "--yes", "--skip-preview")
-	if refreshOpts.Message != "" {
-		args = append(args, fmt.Sprintf("--message=%q", refreshOpts.Message))
-	}
-	if refreshOpts.ExpectNoChanges {
-		args = append(args, "--expect-no-changes")
-	}
-	for _, tURN := range refreshOpts.Target {
-		args = append(args, fmt.Sprintf("--target=%s", tURN))
-	}
-	if refreshOpts.Parallel > 0 {
-		args = append(args, fmt.Sprintf("--parallel=%d", refreshOpts.Parallel))
-	}
-	if refreshOpts.UserAgent != "" {
-		args = append(args, fmt.Sprintf("--exec-agent=%s", refreshOpts.UserAgent))
-	}
-	if refreshOpts.Color != "" {
-		args = append(args, fmt.Sprintf("--color=%s", refreshOpts.Color))
-	}
-	execKind := constant.ExecKindAutoLocal
-	if s.Workspace().Program() != nil {
-		execKind = constant.ExecKindAutoInline
-	}
-	args = append(args, fmt.Sprintf("--exec-kind=%s", execKind))
+	args := refreshOptsToCmd(refreshOpts, s, false /*isPreview*/)
 
 	if len(refreshOpts.EventStreams) > 0 {
 		eventChannels := refreshOpts.EventStreams
@@ -489,9 +473,6 @@ func (s *Stack) Refresh(ctx context.Context, opts ...optrefresh.Option) (Refresh
 		args = append(args, "--event-log", t.Filename)
 	}
 
-	// Apply the remote args, if needed.
-	args = append(args, s.remoteArgs()...)
-
 	stdout, stderr, code, err := s.runPulumiCmdSync(
 		ctx,
 		refreshOpts.ProgressStreams,      /* additionalOutputs */
@@ -539,10 +520,159 @@ func (s *Stack) Destroy(ctx context.Context, opts ...optdestroy.Option) (Destroy
 		o.ApplyOption(destroyOpts)
 	}
 
+	args := destroyOptsToCmd(destroyOpts, s, false /*isPreview*/)
+
+	if len(destroyOpts.EventStreams) > 0 {
+		eventChannels := destroyOpts.EventStreams
+		t, err := tailLogs("destroy", eventChannels)
+		if err != nil {
+			return res, fmt.Errorf("failed to tail logs: %w", err)
+		}
+		defer t.Close()
+		args = append(args, "--event-log", t.Filename)
+	}
+
+	stdout, stderr, code, err := s.runPulumiCmdSync(
+		ctx,
+		destroyOpts.ProgressStreams,      /* additionalOutputs */
+		destroyOpts.ErrorProgressStreams, /* additionalErrorOutputs */
+		args...,
+	)
+	if err != nil {
+		return res, newAutoError(fmt.Errorf("failed to destroy stack: %w", err), stdout, stderr, code)
+	}
+
+	historyOpts := []opthistory.Option{}
+	if showSecrets := destroyOpts.ShowSecrets; showSecrets != nil {
+		historyOpts = append(historyOpts, opthistory.ShowSecrets(*showSecrets))
+	}
+	// If it's a remote workspace, explicitly set ShowSecrets to false to prevent attempting to
+	// load the project file.
+	if s.isRemote() {
+		historyOpts = append(historyOpts, opthistory.ShowSecrets(false))
+	}
+	history, err := s.History(ctx, 1 /*pageSize*/, 1 /*page*/, historyOpts...)
+	if err != nil {
+		return res, fmt.Errorf("failed to destroy stack: %w", err)
+	}
+
+	var summary UpdateSummary
+	if len(history) > 0 {
+		summary = history[0]
+	}
+
+	res = DestroyResult{
+		Summary: summary,
+		StdOut:  stdout,
+		StdErr:  stderr,
+	}
+
+	return res, nil
+}
+
+// PreviewRefresh performs a dry-run refresh of the stack, returning the changes that a refresh would make to the
+// stack's state without making them. Returns an UnsupportedFlagError if the Pulumi CLI is too old to preview refreshes.
+// https://www.pulumi.com/docs/cli/commands/pulumi_refresh/
+func (s *Stack) PreviewRefresh(ctx context.Context, opts ...optrefresh.Option) (PreviewResult, error) {
+	refreshOpts := &optrefresh.Options{}
+	for _, o := range opts {
+		o.ApplyOption(refreshOpts)
+	}
+
+	if err := s.checkPreviewOnly(ctx, "refresh"); err != nil {
+		return PreviewResult{}, err
+	}
+
+	args := refreshOptsToCmd(refreshOpts, s, true /*isPreview*/)
+	return s.runPreview(ctx, "refresh", nil /* program */, args,
+		refreshOpts.ProgressStreams, refreshOpts.ErrorProgressStreams, refreshOpts.EventStreams)
+}
+
+// PreviewDestroy performs a dry-run destroy of the stack, returning the changes that a destroy would make without
+// making them. Returns an UnsupportedFlagError if the Pulumi CLI is too old to preview destroys.
+// https://www.pulumi.com/docs/cli/commands/pulumi_destroy/
+func (s *Stack) PreviewDestroy(ctx context.Context, opts ...optdestroy.Option) (PreviewResult, error) {
+	destroyOpts := &optdestroy.Options{}
+	for _, o := range opts {
+		o.ApplyOption(destroyOpts)
+	}
+
+	if err := s.checkPreviewOnly(ctx, "destroy"); err != nil {
+		return PreviewResult{}, err
+	}
+
+	args := destroyOptsToCmd(destroyOpts, s, true /*isPreview*/)
+	return s.runPreview(ctx, "destroy", nil /* program */, args,
+		destroyOpts.ProgressStreams, destroyOpts.ErrorProgressStreams, destroyOpts.EventStreams)
+}
+
+// checkPreviewOnly returns an UnsupportedFlagError if the workspace's Pulumi CLI can't preview the given command with
+// `--preview-only`.
+func (s *Stack) checkPreviewOnly(ctx context.Context, command string) error {
+	lws, isLocalWorkspace := s.Workspace().(*LocalWorkspace)
+	if !isLocalWorkspace {
+		return nil
+	}
+	supported, err := lws.supportsPulumiCmdFlag(ctx, "--preview-only", command)
+	if err != nil {
+		return err
+	}
+	if !supported {
+		return &UnsupportedFlagError{Command: command, Flag: "--preview-only"}
+	}
+	return nil
+}
+
+func refreshOptsToCmd(refreshOpts *optrefresh.Options, s *Stack, isPreview bool) []string {
+	args := slice.Prealloc[string](len(refreshOpts.Target))
+
+	args = debug.AddArgs(&refreshOpts.DebugLogOpts, args)
+	args = append(args, "refresh")
+	if isPreview {
+		args = append(args, "--preview-only")
+	} else {
+		args = append(args, "--yes", "--skip-preview")
+	}
+	if refreshOpts.Message != "" {
+		args = append(args, fmt.Sprintf("--message=%q", refreshOpts.Message))
+	}
+	if refreshOpts.ExpectNoChanges {
+		args = append(args, "--expect-no-changes")
+	}
+	for _, tURN := range refreshOpts.Target {
+		args = append(args, fmt.Sprintf("--target=%s", tURN))
+	}
+	if refreshOpts.Parallel > 0 {
+		args = append(args, fmt.Sprintf("--parallel=%d", refreshOpts.Parallel))
+	}
+	if refreshOpts.UserAgent != "" {
+		args = append(args, fmt.Sprintf("--exec-agent=%s", refreshOpts.UserAgent))
+	}
+	if refreshOpts.Color != "" {
+		args = append(args, fmt.Sprintf("--color=%s", refreshOpts.Color))
+	}
+	execKind := constant.ExecKindAutoLocal
+	if s.Workspace().Program() != nil {
+		execKind = constant.ExecKindAutoInline
+	}
+	args = append(args, fmt.Sprintf("--exec-kind=%s", execKind))
+
+	// Apply the remote args, if needed.
+	args = append(args, s.remoteArgs()...)
+
+	return args
+}
+
+func destroyOptsToCmd(destroyOpts *optdestroy.Options, s *Stack, isPreview bool) []string {
 	args := slice.Prealloc[string](len(destroyOpts.Target))
 
 	args = debug.AddArgs(&destroyOpts.DebugLogOpts, args)
-	args = append(args, "destroy", "--yes", "--skip-preview")
+	args = append(args, "destroy")
+	if isPreview {
+		args = append(args, "--preview-only")
+	} else {
+		args = append(args, "--yes", "--skip-preview")
+	}
 	if destroyOpts.Message != "" {
 		args = append(args, fmt.Sprintf("--message=%q", destroyOpts.Message))
 	}
@@ -567,9 +697,67 @@ func (s *Stack) Destroy(ctx context.Context, opts ...optdestroy.Option) (Destroy
 	}
 	args = append(args, fmt.Sprintf("--exec-kind=%s", execKind))
 
-	if len(destroyOpts.EventStreams) > 0 {
-		eventChannels := destroyOpts.EventStreams
-		t, err := tailLogs("destroy", eventChannels)
+	// Apply the remote args, if needed.
+	args = append(args, s.remoteArgs()...)
+
+	return args
+}
+
+// ImportResources imports existing resources into the stack, optionally generating code that declares them.
+// Resources are specified using optimport.Resources, and may refer to existing resources in the stack as their
+// parents and providers using optimport.NameTable.
+// https://www.pulumi.com/docs/cli/commands/pulumi_import/
+func (s *Stack) ImportResources(ctx context.Context, opts ...optimport.Option) (ImportResult, error) {
+	var res ImportResult
+
+	importOpts := &optimport.Options{}
+	for _, o := range opts {
+		o.ApplyOption(importOpts)
+	}
+
+	tempDir, err := os.MkdirTemp("", "pulumi-import-")
+	if err != nil {
+		return res, fmt.Errorf("failed to create temporary directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	importFile, err := writeImportFile(tempDir, importOpts)
+	if err != nil {
+		return res, err
+	}
+
+	args := []string{"import", "--yes", "--skip-preview", "--file", importFile}
+	args = debug.AddArgs(&importOpts.DebugLogOpts, args)
+	generateCode := importOpts.GenerateCode == nil || *importOpts.GenerateCode
+	codeFile := filepath.Join(tempDir, "generated")
+	if generateCode {
+		args = append(args, "--out", codeFile)
+	} else {
+		args = append(args, "--generate-code=false")
+	}
+	if importOpts.Protect != nil {
+		args = append(args, fmt.Sprintf("--protect=%t", *importOpts.Protect))
+	}
+	if importOpts.Message != "" {
+		args = append(args, fmt.Sprintf("--message=%q", importOpts.Message))
+	}
+	if importOpts.Diff {
+		args = append(args, "--diff")
+	}
+	if importOpts.Parallel > 0 {
+		args = append(args, fmt.Sprintf("--parallel=%d", importOpts.Parallel))
+	}
+	if importOpts.UserAgent != "" {
+		args = append(args, fmt.Sprintf("--exec-agent=%s", importOpts.UserAgent))
+	}
+	if importOpts.Color != "" {
+		args = append(args, fmt.Sprintf("--color=%s", importOpts.Color))
+	}
+	args = append(args, fmt.Sprintf("--exec-kind=%s", constant.ExecKindAutoLocal))
+
+	if len(importOpts.EventStreams) > 0 {
+		eventChannels := importOpts.EventStreams
+		t, err := tailLogs("import", eventChannels)
 		if err != nil {
 			return res, fmt.Errorf("failed to tail logs: %w", err)
 		}
@@ -577,47 +765,69 @@ func (s *Stack) Destroy(ctx context.Context, opts ...optdestroy.Option) (Destroy
 		args = append(args, "--event-log", t.Filename)
 	}
 
-	// Apply the remote args, if needed.
-	args = append(args, s.remoteArgs()...)
-
 	stdout, stderr, code, err := s.runPulumiCmdSync(
-		ctx,
-		destroyOpts.ProgressStreams,      /* additionalOutputs */
-		destroyOpts.ErrorProgressStreams, /* additionalErrorOutputs */
-		args...,
-	)
+		ctx, importOpts.ProgressStreams, importOpts.ErrorProgressStreams, args...)
 	if err != nil {
-		return res, newAutoError(fmt.Errorf("failed to destroy stack: %w", err), stdout, stderr, code)
+		return res, newAutoError(fmt.Errorf("failed to import resources: %w", err), stdout, stderr, code)
 	}
 
-	historyOpts := []opthistory.Option{}
-	if showSecrets := destroyOpts.ShowSecrets; showSecrets != nil {
-		historyOpts = append(historyOpts, opthistory.ShowSecrets(*showSecrets))
+	var generatedCode string
+	if generateCode {
+		bytes, err := os.ReadFile(codeFile)
+		if err != nil && !os.IsNotExist(err) {
+			return res, fmt.Errorf("failed to read generated code: %w", err)
+		}
+		generatedCode = string(bytes)
 	}
-	// If it's a remote workspace, explicitly set ShowSecrets to false to prevent attempting to
-	// load the project file.
-	if s.isRemote() {
-		historyOpts = append(historyOpts, opthistory.ShowSecrets(false))
+
+	historyOpts := []opthistory.Option{}
+	if importOpts.ShowSecrets != nil {
+		historyOpts = append(historyOpts, opthistory.ShowSecrets(*importOpts.ShowSecrets))
 	}
 	history, err := s.History(ctx, 1 /*pageSize*/, 1 /*page*/, historyOpts...)
 	if err != nil {
-		return res, fmt.Errorf("failed to destroy stack: %w", err)
+		return res, err
 	}
 
-	var summary UpdateSummary
-	if len(history) > 0 {
-		summary = history[0]
+	res = ImportResult{
+		StdOut:        stdout,
+		StdErr:        stderr,
+		GeneratedCode: generatedCode,
 	}
 
-	res = DestroyResult{
-		Summary: summary,
-		StdOut:  stdout,
-		StdErr:  stderr,
+	if len(history) > 0 {
+		res.Summary = history[0]
 	}
 
 	return res, nil
 }
 
+// writeImportFile writes the resources to import to a file in the given directory in the format expected by
+// `pulumi import --file`, and returns the path to the file.
+func writeImportFile(dir string, opts *optimport.Options) (string, error) {
+	if len(opts.Resources) == 0 {
+		return "", errors.New("no resources to import")
+	}
+
+	nameTable := opts.NameTable
+	if nameTable == nil {
+		nameTable = map[string]string{}
+	}
+	bytes, err := json.Marshal(struct {
+		NameTable map[string]string          `json:"nameTable"`
+		Resources []optimport.ImportResource `json:"resources"`
+	}{nameTable, opts.Resources})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal import file: %w", err)
+	}
+
+	path := filepath.Join(dir, "import.json")
+	if err := os.WriteFile(path, bytes, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write import file: %w", err)
+	}
+	return path, nil
+}
+
 // Outputs get the current set of Stack outputs from the last Stack.Up().
 func (s *Stack) Outputs(ctx context.Context) (OutputMap, error) {
 	return s.Workspace().StackOutputs(ctx, s.Name())
@@ -796,6 +1006,55 @@ func (s *Stack) Import(ctx context.Context, state apitype.UntypedDeployment) err
 	return s.Workspace().ImportStack(ctx, s.Name(), state)
 }
 
+// ChangeSecretsProvider changes the secrets provider of the stack and re-encrypts its configuration and state with
+// the new provider. Valid secrets providers are `default`, `passphrase`, and the URLs of cloud secrets providers, e.g.
+// `awskms://alias/ExampleAlias?region=us-east-1`. When changing to the `passphrase` provider, the new passphrase is
+// read from the PULUMI_CONFIG_PASSPHRASE environment variable of the Workspace.
+// https://www.pulumi.com/docs/cli/commands/pulumi_stack_change-secrets-provider/
+func (s *Stack) ChangeSecretsProvider(ctx context.Context, newSecretsProvider string) error {
+	return s.Workspace().ChangeStackSecretsProvider(ctx, s.Name(), newSecretsProvider)
+}
+
+// Rename renames the stack. The stack's configuration file, if any, is renamed along with the stack. Returns a
+// StackAlreadyExistsError if a stack with the new name already exists.
+// https://www.pulumi.com/docs/cli/commands/pulumi_stack_rename/
+func (s *Stack) Rename(ctx context.Context, newStackName string) error {
+	if err := s.Workspace().RenameStack(ctx, s.Name(), newStackName); err != nil {
+		return err
+	}
+	s.stackName = newStackName
+	return nil
+}
+
+// StateDelete deletes the resource with the given URN from the stack's state. Resources that other resources depend
+// on can only be deleted with optstatedelete.TargetDependents, and protected resources can only be deleted with
+// optstatedelete.Force. Returns a ResourceNotFoundError, ResourceHasDependentsError, or ResourceProtectedError if
+// the resource can't be deleted.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_delete/
+func (s *Stack) StateDelete(ctx context.Context, urn string, opts ...optstatedelete.Option) error {
+	return s.Workspace().StateDelete(ctx, s.Name(), urn, opts...)
+}
+
+// StateUnprotect clears the protect bit of the resource with the given URN in the stack's state. Returns a
+// ResourceNotFoundError if the resource does not exist.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_unprotect/
+func (s *Stack) StateUnprotect(ctx context.Context, urn string) error {
+	return s.Workspace().StateUnprotect(ctx, s.Name(), urn)
+}
+
+// StateUnprotectAll clears the protect bit of every resource in the stack's state.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_unprotect/
+func (s *Stack) StateUnprotectAll(ctx context.Context) error {
+	return s.Workspace().StateUnprotectAll(ctx, s.Name())
+}
+
+// StateRename renames the resource with the given URN in the stack's state. Returns a ResourceNotFoundError if the
+// resource does not exist.
+// https://www.pulumi.com/docs/cli/commands/pulumi_state_rename/
+func (s *Stack) StateRename(ctx context.Context, urn string, newName string) error {
+	return s.Workspace().StateRename(ctx, s.Name(), urn, newName)
+}
+
 // UpdateSummary provides a summary of a Stack lifecycle operation (up/preview/refresh/destroy).
 type UpdateSummary struct {
 	Version     int               `json:"version"`
@@ -912,6 +1171,20 @@ func (rr *RefreshResult) GetPermalink() (string, error) {
 	return GetPermalink(rr.StdOut)
 }
 
+// ImportResult is the output of a successful Stack.ImportResources operation
+type ImportResult struct {
+	StdOut string
+	StdErr string
+	// GeneratedCode is the code that declares the imported resources, if code generation was enabled.
+	GeneratedCode string
+	Summary       UpdateSummary
+}
+
+// GetPermalink returns the permalink URL in the Pulumi Console for the import operation.
+func (ir *ImportResult) GetPermalink() (string, error) {
+	return GetPermalink(ir.StdOut)
+}
+
 // DestroyResult is the output of a successful Stack.Destroy operation
 type DestroyResult struct {
 	StdOut  string
@@ -932,6 +1205,18 @@ func (s *Stack) runPulumiCmdSync(
 	additionalOutput []io.Writer,
 	additionalErrorOutput []io.Writer,
 	args ...string,
+) (string, string, int, error) {
+	return s.runPulumiProgramCmdSync(ctx, nil /* program */, additionalOutput, additionalErrorOutput, args...)
+}
+
+// runPulumiProgramCmdSync runs a Pulumi command that runs the given inline program, if any. The program is passed to
+// commands that can run inline programs themselves, and is otherwise served to the command over gRPC.
+func (s *Stack) runPulumiProgramCmdSync(
+	ctx context.Context,
+	program pulumi.RunFunc,
+	additionalOutput []io.Writer,
+	additionalErrorOutput []io.Writer,
+	args ...string,
 ) (string, string, int, error) {
 	var env []string
 	debugEnv := fmt.Sprintf("%s=%s", "PULUMI_DEBUG_COMMANDS", "true")
@@ -963,14 +1248,28 @@ func (s *Stack) runPulumiCmdSync(
 	args = append(args, additionalArgs...)
 	args = append(args, "--stack", s.Name())
 
-	stdout, stderr, errCode, err := runPulumiCommandSync(
-		ctx,
-		s.Workspace().WorkDir(),
-		additionalOutput,
-		additionalErrorOutput,
-		env,
-		args...,
-	)
+	cmd := pulumiCommandFor(s.Workspace())
+	var stdout, stderr string
+	var errCode int
+	if inline, ok := cmd.(InlinePulumiCommand); ok && program != nil {
+		if isNestedInvocation() {
+			return "", "", -1, errNestedInvocation
+		}
+		stdout, stderr, errCode, err = inline.RunInline(
+			ctx, s.Workspace().WorkDir(), additionalOutput, additionalErrorOutput, env, program, args...)
+	} else {
+		if program != nil {
+			server, err := startLanguageRuntimeServer(program)
+			if err != nil {
+				return "", "", -1, err
+			}
+			defer contract.IgnoreClose(server)
+
+			args = append(args, "--client="+server.address)
+		}
+		stdout, stderr, errCode, err = cmd.Run(
+			ctx, s.Workspace().WorkDir(), additionalOutput, additionalErrorOutput, env, args...)
+	}
 	if err != nil {
 		return stdout, stderr, errCode, err
 	}
@@ -1081,6 +1380,11 @@ type languageRuntimeServer struct {
 	done   <-chan error
 }
 
+// errNestedInvocation is returned when a stack operation that runs an inline program is started from within an inline
+// program.
+var errNestedInvocation = errors.New(
+	"nested stack operations are not supported https://github.com/pulumi/pulumi/issues/5058")
+
 // isNestedInvocation returns true if pulumi.RunWithContext is on the stack.
 func isNestedInvocation() bool {
 	depth, callers := 0, make([]uintptr, 32)
@@ -1102,7 +1406,7 @@ func isNestedInvocation() bool {
 
 func startLanguageRuntimeServer(fn pulumi.RunFunc) (*languageRuntimeServer, error) {
 	if isNestedInvocation() {
-		return nil, errors.New("nested stack operations are not supported https://github.com/pulumi/pulumi/issues/5058")
+		return nil, errNestedInvocation
 	}
 
 	s := &languageRuntimeServer{
@@ -1230,6 +1534,25 @@ type fileWatcher struct {
 	done      chan bool
 }
 
+// waitForEOF waits until the watcher has read to the end of the log, or has stopped.
+func (fw *fileWatcher) waitForEOF() {
+	for {
+		info, err := os.Stat(fw.tail.Filename)
+		if err != nil {
+			return
+		}
+		offset, err := fw.tail.Tell()
+		if err != nil || offset >= info.Size() {
+			return
+		}
+		select {
+		case <-fw.done:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
 func watchFile(path string, receivers []chan<- events.EngineEvent) (*fileWatcher, error) {
 	t, err := tail.TailFile(path, tail.Config{
 		Follow: true,
@@ -1279,6 +1602,12 @@ func tailLogs(command string, receivers []chan<- events.EngineEvent) (*fileWatch
 	}
 	logFile := filepath.Join(logDir, "eventlog.txt")
 
+	// Create the log up front so the watcher opens it straight away. Otherwise a command that finishes quickly can
+	// stop the watcher before it notices the log has been created, losing every event.
+	if err := os.WriteFile(logFile, nil, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to create log file: %w", err)
+	}
+
 	t, err := watchFile(logFile, receivers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to watch file: %w", err)
@@ -1292,6 +1621,10 @@ func (fw *fileWatcher) Close() {
 		return
 	}
 
+	// StopAtEOF takes effect the next time the watcher waits for changes, which can be before it has been notified
+	// of the last writes to the log, so first wait for it to read everything that has been written.
+	fw.waitForEOF()
+
 	// Tell the watcher to end on next EoF, wait for the done event, then cleanup.
 
 	//nolint:errcheck
@@ -17,10 +17,15 @@ package auto
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optdestroy"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optimport"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optpreview"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optrefresh"
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optup"
 	"github.com/pulumi/pulumi/sdk/v3/go/pulumi"
 	"github.com/stretchr/testify/assert"
@@ -135,3 +140,189 @@ func TestUpdatePlans(t *testing.T) {
 	assert.Equal(t, "destroy", dRes.Summary.Kind)
 	assert.Equal(t, "succeeded", dRes.Summary.Result)
 }
+
+func TestWriteImportFile(t *testing.T) {
+	t.Parallel()
+
+	var opts optimport.Options
+	optimport.Resources(optimport.ImportResource{
+		Type: "aws:s3/bucket:Bucket",
+		Name: "bucket",
+		ID:   "my-bucket",
+	}).ApplyOption(&opts)
+	optimport.Resources(optimport.ImportResource{
+		Type:       "aws:s3/bucketObject:BucketObject",
+		Name:       "object",
+		ID:         "my-bucket/object",
+		Parent:     "parent",
+		Provider:   "provider",
+		Properties: []string{"bucket", "key"},
+	}).ApplyOption(&opts)
+	optimport.NameTable(map[string]string{
+		"parent":   "urn:pulumi:dev::proj::pkg:index:Component::parent",
+		"provider": "urn:pulumi:dev::proj::pulumi:providers:aws::provider",
+	}).ApplyOption(&opts)
+
+	dir := t.TempDir()
+	path, err := writeImportFile(dir, &opts)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "import.json"), path)
+
+	bytes, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"nameTable": {
+			"parent": "urn:pulumi:dev::proj::pkg:index:Component::parent",
+			"provider": "urn:pulumi:dev::proj::pulumi:providers:aws::provider"
+		},
+		"resources": [
+			{"type": "aws:s3/bucket:Bucket", "name": "bucket", "id": "my-bucket"},
+			{
+				"type": "aws:s3/bucketObject:BucketObject",
+				"name": "object",
+				"id": "my-bucket/object",
+				"parent": "parent",
+				"provider": "provider",
+				"properties": ["bucket", "key"]
+			}
+		]
+	}`, string(bytes))
+
+	_, err = writeImportFile(dir, &optimport.Options{})
+	assert.ErrorContains(t, err, "no resources to import")
+}
+
+func TestPreviewRefreshAndDestroyArgs(t *testing.T) {
+	t.Parallel()
+
+	s := &Stack{workspace: &LocalWorkspace{}, stackName: "dev"}
+
+	var refreshOpts optrefresh.Options
+	optrefresh.Target([]string{"urn:a"}).ApplyOption(&refreshOpts)
+	assert.Equal(t, []string{"refresh", "--preview-only", "--target=urn:a", "--exec-kind=auto.local"},
+		refreshOptsToCmd(&refreshOpts, s, true /*isPreview*/))
+	assert.Equal(t, []string{"refresh", "--yes", "--skip-preview", "--target=urn:a", "--exec-kind=auto.local"},
+		refreshOptsToCmd(&refreshOpts, s, false /*isPreview*/))
+
+	var destroyOpts optdestroy.Options
+	optdestroy.TargetDependents().ApplyOption(&destroyOpts)
+	assert.Equal(t, []string{"destroy", "--preview-only", "--target-dependents", "--exec-kind=auto.local"},
+		destroyOptsToCmd(&destroyOpts, s, true /*isPreview*/))
+	assert.Equal(t, []string{"destroy", "--yes", "--skip-preview", "--target-dependents", "--exec-kind=auto.local"},
+		destroyOptsToCmd(&destroyOpts, s, false /*isPreview*/))
+}
+
+func TestPreviewRefreshAndDestroyUnsupported(t *testing.T) {
+	t.Parallel()
+
+	// A CLI whose help doesn't mention --preview-only can't preview refreshes and destroys.
+	cmd := &recordingPulumiCommand{run: func(args []string) (string, error) {
+		if args[len(args)-1] != "--help" {
+			return "", fmt.Errorf("unexpected command %v", args)
+		}
+		return "Usage:\n  pulumi " + args[0] + " [flags]\n", nil
+	}}
+	s := &Stack{workspace: &LocalWorkspace{workDir: t.TempDir(), pulumiCommand: cmd}, stackName: "dev"}
+
+	var unsupported *UnsupportedFlagError
+	_, err := s.PreviewRefresh(context.Background())
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, UnsupportedFlagError{Command: "refresh", Flag: "--preview-only"}, *unsupported)
+	_, err = s.PreviewDestroy(context.Background())
+	require.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, UnsupportedFlagError{Command: "destroy", Flag: "--preview-only"}, *unsupported)
+	assert.Equal(t, [][]string{{"refresh", "--help"}, {"destroy", "--help"}}, cmd.commands)
+}
+
+// recordingPulumiCommand is a PulumiCommand that records the commands it runs instead of running them.
+type recordingPulumiCommand struct {
+	commands [][]string
+	run      func(args []string) (string, error)
+}
+
+func (c *recordingPulumiCommand) Run(ctx context.Context,
+	workdir string,
+	additionalOutput []io.Writer,
+	additionalErrorOutput []io.Writer,
+	additionalEnv []string,
+	args ...string,
+) (string, string, int, error) {
+	c.commands = append(c.commands, args)
+	stdout, err := c.run(args)
+	if err != nil {
+		return stdout, "", 1, err
+	}
+	return stdout, "", 0, nil
+}
+
+// flagValue returns the value of the given flag in args.
+func flagValue(args []string, flag string) string {
+	for i, arg := range args {
+		if arg == flag && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+func TestImportResources(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	var importFile []byte
+	cmd := &recordingPulumiCommand{run: func(args []string) (string, error) {
+		switch args[0] {
+		case "import":
+			var err error
+			importFile, err = os.ReadFile(flagValue(args, "--file"))
+			if err != nil {
+				return "", err
+			}
+			if out := flagValue(args, "--out"); out != "" {
+				err = os.WriteFile(out, []byte("const bucket = new aws.s3.Bucket(\"bucket\");\n"), 0o600)
+			}
+			return "Resources:\n    + 1 imported\n", err
+		case "stack":
+			return `[{"kind": "import", "result": "succeeded", "version": 2}]`, nil
+		}
+		return "", fmt.Errorf("unexpected command %v", args)
+	}}
+	s := Stack{workspace: &LocalWorkspace{workDir: t.TempDir(), pulumiCommand: cmd}, stackName: "dev"}
+
+	bucket := optimport.ImportResource{Type: "aws:s3/bucket:Bucket", Name: "bucket", ID: "my-bucket"}
+	res, err := s.ImportResources(ctx,
+		optimport.Resources(bucket),
+		optimport.Protect(false),
+		optimport.Color("never"),
+		optimport.Message("import bucket"))
+	require.NoError(t, err)
+	assert.Equal(t, "Resources:\n    + 1 imported\n", res.StdOut)
+	assert.Equal(t, "const bucket = new aws.s3.Bucket(\"bucket\");\n", res.GeneratedCode)
+	assert.Equal(t, "import", res.Summary.Kind)
+	assert.Equal(t, "succeeded", res.Summary.Result)
+	assert.JSONEq(t, `{
+		"nameTable": {},
+		"resources": [{"type": "aws:s3/bucket:Bucket", "name": "bucket", "id": "my-bucket"}]
+	}`, string(importFile))
+
+	require.Len(t, cmd.commands, 2)
+	importArgs := cmd.commands[0]
+	assert.Contains(t, importArgs, "--protect=false")
+	assert.Contains(t, importArgs, "--color=never")
+	assert.Contains(t, importArgs, `--message="import bucket"`)
+	assert.Equal(t, "dev", flagValue(importArgs, "--stack"))
+	assert.Equal(t, []string{"stack", "history", "--json"}, cmd.commands[1][:3])
+
+	// Without code generation, no output file is requested.
+	cmd.commands = nil
+	res, err = s.ImportResources(ctx, optimport.Resources(bucket), optimport.GenerateCode(false))
+	require.NoError(t, err)
+	assert.Empty(t, res.GeneratedCode)
+	assert.Contains(t, cmd.commands[0], "--generate-code=false")
+	assert.Empty(t, flagValue(cmd.commands[0], "--out"))
+
+	// Failures of the import are returned.
+	cmd.run = func(args []string) (string, error) { return "", fmt.Errorf("exit status 255") }
+	_, err = s.ImportResources(ctx, optimport.Resources(bucket))
+	assert.ErrorContains(t, err, "failed to import resources")
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/auto/optremove"
+	"github.com/pulumi/pulumi/sdk/v3/go/auto/optstatedelete"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/apitype"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 
@@ -138,6 +139,21 @@ type Workspace interface {
 	ImportStack(context.Context, string, apitype.UntypedDeployment) error
 	// StackOutputs gets the current set of Stack outputs from the last Stack.Up().
 	StackOutputs(context.Context, string) (OutputMap, error)
+	// ChangeStackSecretsProvider changes the secrets provider of the stack matching the given name and re-encrypts its
+	// configuration and state with the new provider.
+	ChangeStackSecretsProvider(context.Context, string, string) error
+	// RenameStack renames the stack matching the given name. Returns a StackAlreadyExistsError if a stack with the new
+	// name already exists.
+	RenameStack(context.Context, string, string) error
+	// StateDelete deletes the resource with the given URN from the state of the stack matching the given name.
+	StateDelete(context.Context, string, string, ...optstatedelete.Option) error
+	// StateUnprotect clears the protect bit of the resource with the given URN in the state of the stack matching the
+	// given name.
+	StateUnprotect(context.Context, string, string) error
+	// StateUnprotectAll clears the protect bit of every resource in the state of the stack matching the given name.
+	StateUnprotectAll(context.Context, string) error
+	// StateRename renames the resource with the given URN in the state of the stack matching the given name.
+	StateRename(context.Context, string, string, string) error
 }
 
 // ConfigValue is a configuration value used by a Pulumi program.
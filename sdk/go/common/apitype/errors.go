@@ -72,3 +72,35 @@ type ErrorResponse struct {
 func (err ErrorResponse) Error() string {
 	return fmt.Sprintf("[%d] %s", err.Code, err.Message)
 }
+
+// CLIErrorKind identifies the kind of a structured error reported by a CLI command.
+type CLIErrorKind string
+
+const (
+	// ResourceNotFoundCLIError is reported when a resource does not exist in the stack's state.
+	ResourceNotFoundCLIError CLIErrorKind = "resource_not_found"
+	// ResourceProtectedCLIError is reported when a protected resource can't be deleted from the stack's state.
+	ResourceProtectedCLIError CLIErrorKind = "resource_protected"
+	// ResourceHasDependentsCLIError is reported when a resource can't be deleted from the stack's state because other
+	// resources depend on it.
+	ResourceHasDependentsCLIError CLIErrorKind = "resource_has_dependents"
+	// StackAlreadyExistsCLIError is reported when a stack can't be created or renamed because a stack with the same
+	// name already exists.
+	StackAlreadyExistsCLIError CLIErrorKind = "stack_already_exists"
+)
+
+// CLIError is written to stdout by CLI commands that support structured errors (e.g. `pulumi state delete --json`)
+// when they fail. It allows programs such as the Automation API to tell failures apart without parsing the error
+// messages that are written to stderr.
+type CLIError struct {
+	// Kind is the kind of the error. It is empty for errors without a more specific kind.
+	Kind CLIErrorKind `json:"kind,omitempty"`
+	// Message is the user-facing message describing the error.
+	Message string `json:"message"`
+	// URN is the URN of the resource that the error refers to, if any.
+	URN string `json:"urn,omitempty"`
+	// Dependents are the URNs of the resources that depend on the resource, for ResourceHasDependentsCLIError.
+	Dependents []string `json:"dependents,omitempty"`
+	// StackName is the name of the stack that the error refers to, if any.
+	StackName string `json:"stackName,omitempty"`
+}
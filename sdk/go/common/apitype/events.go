@@ -71,6 +71,33 @@ type PolicyRemediationEvent struct {
 	After                map[string]interface{} `json:"after,omitempty"`
 }
 
+// ProgressType is the kind of work a ProgressEvent reports on.
+type ProgressType string
+
+const (
+	// PluginDownload is the download of a plugin's archive.
+	PluginDownload ProgressType = "plugin-download"
+	// PluginInstall is the installation of a downloaded plugin.
+	PluginInstall ProgressType = "plugin-install"
+)
+
+// ProgressEvent is emitted as long running work, such as downloading a plugin, progresses. Progress events are
+// ephemeral, and aren't persisted with an update's other events.
+type ProgressEvent struct {
+	// Type is the kind of work being reported on.
+	Type ProgressType `json:"type"`
+	// ID identifies the work, so that its progress events can be grouped.
+	ID string `json:"id"`
+	// Message describes the work, e.g. "Downloading plugin aws-6.0.0".
+	Message string `json:"message"`
+	// Completed is the amount of work done, e.g. the number of bytes downloaded.
+	Completed int64 `json:"completed"`
+	// Total is the total amount of work, or 0 if it's unknown.
+	Total int64 `json:"total"`
+	// Done is true when the work has finished.
+	Done bool `json:"done"`
+}
+
 // PreludeEvent is emitted at the start of an update.
 type PreludeEvent struct {
 	// Config contains the keys and values for the update.
@@ -144,6 +171,18 @@ type StepEventMetadata struct {
 	Logical bool `json:"logical,omitempty"`
 	// Provider actually performing the step.
 	Provider string `json:"provider"`
+	// IgnoredChanges are the changes to the resource's inputs that were suppressed by ignoreChanges rules.
+	IgnoredChanges []IgnoredChange `json:"ignoredChanges,omitempty"`
+}
+
+// IgnoredChange describes a change to a resource's inputs that was suppressed by an ignoreChanges rule.
+type IgnoredChange struct {
+	// Path is the property path whose change was ignored.
+	Path string `json:"path"`
+	// Rule is the ignoreChanges rule that matched the path.
+	Rule string `json:"rule"`
+	// Default is true if the rule is one of the stack's default ignoreChanges rules.
+	Default bool `json:"default,omitempty"`
 }
 
 // StepEventStateMetadata is the more detailed state information for a resource as it relates to
@@ -181,6 +220,14 @@ type ResourcePreEvent struct {
 	Planning bool              `json:"planning,omitempty"`
 }
 
+// ResourceWaitingEvent is emitted when an operation on a resource has to wait for a per-provider or per-type
+// parallelism limit before it can start. A ResourcePreEvent is emitted once it does. Waiting events are ephemeral, and
+// aren't persisted with an update's other events.
+type ResourceWaitingEvent struct {
+	Metadata StepEventMetadata `json:"metadata"`
+	Planning bool              `json:"planning,omitempty"`
+}
+
 // ResOutputsEvent is emitted when a resource is finished being provisioned.
 type ResOutputsEvent struct {
 	Metadata StepEventMetadata `json:"metadata"`
@@ -221,6 +268,8 @@ type EngineEvent struct {
 	ResOpFailedEvent       *ResOpFailedEvent       `json:"resOpFailedEvent,omitempty"`
 	PolicyEvent            *PolicyEvent            `json:"policyEvent,omitempty"`
 	PolicyRemediationEvent *PolicyRemediationEvent `json:"policyRemediationEvent,omitempty"`
+	ProgressEvent          *ProgressEvent          `json:"progressEvent,omitempty"`
+	ResourceWaitingEvent   *ResourceWaitingEvent   `json:"resourceWaitingEvent,omitempty"`
 }
 
 // EngineEventBatch is a group of engine events.
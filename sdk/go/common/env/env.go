@@ -62,6 +62,10 @@ var IgnoreAmbientPlugins = env.Bool("IGNORE_AMBIENT_PLUGINS",
 var DisableAutomaticPluginAcquisition = env.Bool("DISABLE_AUTOMATIC_PLUGIN_ACQUISITION",
 	"Disables the automatic installation of missing plugins.")
 
+var PluginMirror = env.String("PLUGIN_MIRROR",
+	"A directory of plugin archives, as created by `pulumi plugin mirror`, to install plugins from. "+
+		"When set, plugins are never downloaded.")
+
 var SkipConfirmations = env.Bool("SKIP_CONFIRMATIONS",
 	`Whether or not confirmation prompts should be skipped. This should be used by pass any requirement
 that a --yes parameter has been set for non-interactive scenarios.
@@ -91,6 +95,9 @@ var (
 
 	SelfManagedDisableCheckpointBackups = env.Bool("DISABLE_CHECKPOINT_BACKUPS",
 		"If set checkpoint backups will not be written the to the backup folder.")
+
+	SelfManagedEnvironmentsDir = env.String("SELF_MANAGED_ENVIRONMENTS_DIR",
+		"If set, environments are stored as YAML files in this local directory instead of in the state bucket.")
 )
 
 // Environment variables which affect Pulumi AI integrations
@@ -17,6 +17,7 @@ package plugin
 import (
 	"context"
 	"io"
+	"path/filepath"
 	"sync"
 
 	"github.com/opentracing/opentracing-go"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/diag/colors"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/env"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/rpcutil"
@@ -40,6 +42,14 @@ type Context struct {
 	Pwd        string    // the working directory to spawn all plugins in.
 	Root       string    // the root directory of the context.
 
+	// Env holds additional environment variables, of the form KEY=VALUE, with which plugins are run. They are added to
+	// the environment of the current process.
+	Env []string
+
+	// PluginMirror is the directory of plugin archives to install plugins from rather than downloading them, if the
+	// project has one. Plugins are run with PULUMI_PLUGIN_MIRROR set to it, unless that is already set.
+	PluginMirror string
+
 	// If non-nil, configures custom gRPC client options. Receives pluginInfo which is a JSON-serializable bit of
 	// metadata describing the plugin.
 	DialOptions func(pluginInfo interface{}) []grpc.DialOption
@@ -74,8 +84,13 @@ func NewContext(d, statusD diag.Sink, host Host, _ ConfigSource,
 	}
 
 	root := ""
-	return NewContextWithRoot(d, statusD, host, pwd, root, runtimeOptions,
+	ctx, err := NewContextWithRoot(d, statusD, host, pwd, root, runtimeOptions,
 		disableProviderPreview, parentSpan, plugins, nil)
+	if err != nil {
+		return nil, err
+	}
+	ctx.PluginMirror = plugins.MirrorDir(filepath.Dir(projPath))
+	return ctx, nil
 }
 
 // NewContextWithRoot is a variation of NewContext that also sets known project Root. Additionally accepts Plugins
@@ -107,6 +122,7 @@ func NewContextWithContext(
 		StatusDiag:      statusD,
 		Host:            host,
 		Pwd:             pwd,
+		PluginMirror:    plugins.MirrorDir(root),
 		tracingSpan:     parentSpan,
 		DebugTraceMutex: &sync.Mutex{},
 		cancelLock:      &sync.Mutex{},
@@ -122,6 +138,19 @@ func NewContextWithContext(
 	return pctx, nil
 }
 
+// pluginEnv returns the environment variables to add to those of the current process when running a plugin: the
+// context's Env, and PULUMI_PLUGIN_MIRROR if the context has a plugin mirror and it isn't already set, so that the
+// plugin installs any plugins it needs from the mirror.
+func (ctx *Context) pluginEnv() []string {
+	if ctx == nil {
+		return nil
+	}
+	if ctx.PluginMirror == "" || env.PluginMirror.Value() != "" {
+		return ctx.Env
+	}
+	return append(ctx.Env[:len(ctx.Env):len(ctx.Env)], env.PluginMirror.Var().Name()+"="+ctx.PluginMirror)
+}
+
 // Request allocates a request sub-context.
 func (ctx *Context) Request() context.Context {
 	c := ctx.baseContext
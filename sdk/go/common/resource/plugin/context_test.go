@@ -15,11 +15,13 @@
 package plugin
 
 import (
+	"path/filepath"
 	"sync"
 	"testing"
 
 	"github.com/opentracing/opentracing-go/mocktracer"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/testing/diagtest"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/workspace"
 	"github.com/stretchr/testify/require"
 )
 
@@ -49,3 +51,24 @@ func TestContextRequest_race(t *testing.T) {
 	}
 	wg.Wait()
 }
+
+//nolint:paralleltest // mutates environment variables
+func TestContextPluginMirror(t *testing.T) {
+	t.Setenv("PULUMI_PLUGIN_MIRROR", "")
+
+	root := t.TempDir()
+	ctx, err := NewContextWithRoot(nil, nil, nil, root, root, nil, false, nil,
+		&workspace.Plugins{Mirror: "mirror"}, nil)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, ctx.Close()) }()
+
+	// The project's mirror is resolved against its root, and plugins are run with it.
+	mirror := filepath.Join(root, "mirror")
+	require.Equal(t, mirror, ctx.PluginMirror)
+	ctx.Env = []string{"FOO=bar"}
+	require.Equal(t, []string{"FOO=bar", "PULUMI_PLUGIN_MIRROR=" + mirror}, ctx.pluginEnv())
+
+	// PULUMI_PLUGIN_MIRROR takes precedence over the project's mirror.
+	t.Setenv("PULUMI_PLUGIN_MIRROR", t.TempDir())
+	require.Equal(t, []string{"FOO=bar"}, ctx.pluginEnv())
+}
@@ -196,6 +196,14 @@ func newPlugin(ctx *Context, pwd, bin, prefix string, kind workspace.PluginKind,
 	tracingSpan := opentracing.StartSpan("newPlugin", opts...)
 	defer tracingSpan.Finish()
 
+	// Add the context's environment variables to those of the plugin.
+	if extra := ctx.pluginEnv(); len(extra) > 0 {
+		if env == nil {
+			env = os.Environ()
+		}
+		env = append(env[:len(env):len(env)], extra...)
+	}
+
 	// Try to execute the binary.
 	plug, err := execPlugin(ctx, bin, prefix, kind, args, pwd, env)
 	if err != nil {
@@ -203,6 +211,11 @@ func newPlugin(ctx *Context, pwd, bin, prefix string, kind workspace.PluginKind,
 	}
 	contract.Assertf(plug != nil, "plugin %v canot be nil", bin)
 
+	// Record that the plugin was used, so that unused plugins can be pruned from the cache.
+	if err := workspace.RecordPluginUse(bin); err != nil {
+		logging.V(7).Infof("newPlugin(): failed to record use of plugin %s: %v", bin, err)
+	}
+
 	// If we did not successfully launch the plugin, we still need to wait for stderr and stdout to drain.
 	defer func() {
 		if plug.Conn == nil {
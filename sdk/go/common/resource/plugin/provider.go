@@ -113,6 +113,15 @@ type Provider interface {
 	GetMappings(key string) ([]string, error)
 }
 
+// ResourceLister is an optional interface implemented by providers that support resource discovery. It is kept
+// separate from Provider so that existing Provider implementations are not required to implement it.
+type ResourceLister interface {
+	// ListResources enumerates the existing resources of the given type that are visible to the provider, optionally
+	// restricted by a set of provider-specific filters. Providers whose plugins do not support discovery return
+	// ErrNotYetImplemented.
+	ListResources(typ tokens.Type, filters resource.PropertyMap) ([]ListedResource, error)
+}
+
 type GrpcProvider interface {
 	Provider
 
@@ -339,6 +348,16 @@ type ReadResult struct {
 	Outputs resource.PropertyMap
 }
 
+// ListedResource describes an existing resource returned by a call to ListResources.
+type ListedResource struct {
+	// ID is the provider-assigned ID of the resource. This is the ID that can be used to import the resource.
+	ID resource.ID
+	// Name is the provider's suggested name for the resource, if any.
+	Name string
+	// Properties contains a summary of the resource's state, if any.
+	Properties resource.PropertyMap
+}
+
 // ConstructInfo contains all of the information required to register resources as part of a call to Construct.
 type ConstructInfo struct {
 	Project          string                // the project name housing the program being run.
@@ -1889,9 +1889,14 @@ func parseError(err error) (
 	contract.Assertf(responseErr != nil, "resourceStateAndError must never return a nil error")
 
 	// If resource was successfully created but failed to initialize, the error will be packed
-	// with the live properties of the object.
+	// with the live properties of the object. Otherwise, the provider may have marked the error as
+	// transient, in which case the operation may succeed if it's retried.
 	resourceErr = responseErr
 	for _, detail := range responseErr.Details() {
+		if retryErr, ok := detail.(*pulumirpc.ErrorRetryable); ok {
+			resourceErr = &RetryableError{Reason: retryErr.GetReason(), Err: responseErr}
+			continue
+		}
 		if initErr, ok := detail.(*pulumirpc.ErrorResourceInitFailed); ok {
 			id = resource.ID(initErr.GetId())
 			liveObject = initErr.GetProperties()
@@ -1921,6 +1926,23 @@ func (ie *InitError) Error() string {
 	return err.Error()
 }
 
+// RetryableError represents a failure that the provider expects to be transient, e.g. because it was throttled or the
+// service it manages was unavailable, and so an operation that failed with it may succeed if it's retried.
+type RetryableError struct {
+	Reason string // a short description of why the operation may succeed if it's retried.
+	Err    error  // the underlying error.
+}
+
+var _ error = (*RetryableError)(nil)
+
+func (re *RetryableError) Error() string {
+	return re.Err.Error()
+}
+
+func (re *RetryableError) Unwrap() error {
+	return re.Err
+}
+
 func decorateSpanWithType(span opentracing.Span, urn string) {
 	if urn := resource.URN(urn); urn.IsValid() {
 		span.SetTag("pulumi-decorator", urn.Type())
@@ -2000,3 +2022,80 @@ func (p *provider) GetMappings(key string) ([]string, error) {
 	}
 	return resp.Providers, nil
 }
+
+func (p *provider) ListResources(typ tokens.Type, filters resource.PropertyMap) ([]ListedResource, error) {
+	label := fmt.Sprintf("%s.ListResources(%s)", p.label(), typ)
+	logging.V(7).Infof("%s executing (#filters=%d)", label, len(filters))
+
+	// Ensure that the plugin is configured.
+	client := p.clientRaw
+	pcfg, err := p.awaitConfig(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	// If the provider is not fully configured, we can't discover anything.
+	if !pcfg.known {
+		return nil, fmt.Errorf("cannot list resources of type %v: the provider's configuration is not fully known", typ)
+	}
+
+	mfilters, err := MarshalProperties(filters, MarshalOptions{
+		Label:         fmt.Sprintf("%s.filters", label),
+		KeepSecrets:   pcfg.acceptSecrets,
+		KeepResources: pcfg.acceptResources,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Issue requests until the provider runs out of pages. A provider that hands out a page token that it's already
+	// handed out would make us loop forever, so we treat that as an error.
+	var results []ListedResource
+	pageToken := ""
+	seenTokens := map[string]bool{}
+	for {
+		resp, err := client.ListResources(p.requestContext(), &pulumirpc.ListResourcesRequest{
+			Type:      string(typ),
+			Filters:   mfilters,
+			PageToken: pageToken,
+		})
+		if err != nil {
+			rpcError := rpcerror.Convert(err)
+			if rpcError.Code() == codes.Unimplemented {
+				logging.V(7).Infof("%s unimplemented", label)
+				return nil, ErrNotYetImplemented
+			}
+			logging.V(7).Infof("%s failed: %v", label, rpcError)
+			return nil, rpcError
+		}
+
+		for _, r := range resp.GetResources() {
+			props, err := UnmarshalProperties(r.GetProperties(), MarshalOptions{
+				Label:          fmt.Sprintf("%s.properties", label),
+				RejectUnknowns: true,
+				KeepSecrets:    true,
+				KeepResources:  true,
+			})
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, ListedResource{
+				ID:         resource.ID(r.GetId()),
+				Name:       r.GetName(),
+				Properties: props,
+			})
+		}
+
+		pageToken = resp.GetNextPageToken()
+		if pageToken == "" {
+			break
+		}
+		if seenTokens[pageToken] {
+			return nil, fmt.Errorf("%s: provider returned the page token %q more than once", label, pageToken)
+		}
+		seenTokens[pageToken] = true
+	}
+
+	logging.V(7).Infof("%s success: #resources=%d", label, len(results))
+	return results, nil
+}
@@ -3,6 +3,7 @@ package plugin
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"reflect"
 	"sync"
@@ -12,6 +13,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
@@ -677,6 +680,121 @@ func TestProvider_ConfigureDeleteRace(t *testing.T) {
 	}, s.StructValue.Fields["value"].GetKind())
 }
 
+func TestProvider_ListResources(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		ConfigureF: func(req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+			return &pulumirpc.ConfigureResponse{}, nil
+		},
+		ListResourcesF: func(req *pulumirpc.ListResourcesRequest) (*pulumirpc.ListResourcesResponse, error) {
+			assert.Equal(t, "pkg:index:Thing", req.Type)
+			assert.Equal(t, "us-west-2", req.Filters.Fields["region"].GetStringValue())
+
+			// Return one resource per page, continuing until the third page.
+			switch req.PageToken {
+			case "":
+				return &pulumirpc.ListResourcesResponse{
+					Resources:     []*pulumirpc.ListedResource{{Id: "id-1", Name: "one"}},
+					NextPageToken: "page-2",
+				}, nil
+			case "page-2":
+				return &pulumirpc.ListResourcesResponse{
+					Resources: []*pulumirpc.ListedResource{{
+						Id: "id-2",
+						Properties: &structpb.Struct{Fields: map[string]*structpb.Value{
+							"size": {Kind: &structpb.Value_NumberValue{NumberValue: 3}},
+						}},
+					}},
+				}, nil
+			default:
+				return nil, fmt.Errorf("unexpected page token %q", req.PageToken)
+			}
+		},
+	}
+
+	p := NewProviderWithClient(newTestContext(t), "pkg", client, false /* disablePreview */)
+	require.NoError(t, p.Configure(resource.PropertyMap{}))
+
+	resources, err := p.(ResourceLister).ListResources("pkg:index:Thing", resource.PropertyMap{
+		"region": resource.NewStringProperty("us-west-2"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []ListedResource{
+		{ID: "id-1", Name: "one", Properties: resource.PropertyMap{}},
+		{ID: "id-2", Properties: resource.PropertyMap{"size": resource.NewNumberProperty(3)}},
+	}, resources)
+}
+
+func TestProvider_ListResourcesUnimplemented(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		ConfigureF: func(req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+			return &pulumirpc.ConfigureResponse{}, nil
+		},
+		ListResourcesF: func(req *pulumirpc.ListResourcesRequest) (*pulumirpc.ListResourcesResponse, error) {
+			return nil, status.Error(codes.Unimplemented, "ListResources is not yet implemented")
+		},
+	}
+
+	p := NewProviderWithClient(newTestContext(t), "pkg", client, false /* disablePreview */)
+	require.NoError(t, p.Configure(resource.PropertyMap{}))
+
+	_, err := p.(ResourceLister).ListResources("pkg:index:Thing", nil)
+	assert.ErrorIs(t, err, ErrNotYetImplemented)
+}
+
+func TestProvider_CreateRetryableError(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		ConfigureF: func(req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+			return &pulumirpc.ConfigureResponse{}, nil
+		},
+		CreateF: func(req *pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error) {
+			st, err := status.New(codes.Unavailable, "rate exceeded").WithDetails(&pulumirpc.ErrorRetryable{
+				Reason: "throttled",
+			})
+			require.NoError(t, err)
+			return nil, st.Err()
+		},
+	}
+
+	p := NewProviderWithClient(newTestContext(t), "pkg", client, false /* disablePreview */)
+	require.NoError(t, p.Configure(resource.PropertyMap{}))
+
+	_, _, rst, err := p.Create("urn:pulumi:stack::project::pkg:index:Thing::thing", resource.PropertyMap{}, 0, false)
+	assert.Equal(t, resource.StatusOK, rst)
+	var retryErr *RetryableError
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, "throttled", retryErr.Reason)
+	assert.Contains(t, err.Error(), "rate exceeded")
+}
+
+func TestProvider_ListResourcesRepeatedPageToken(t *testing.T) {
+	t.Parallel()
+
+	client := &stubClient{
+		ConfigureF: func(req *pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error) {
+			return &pulumirpc.ConfigureResponse{}, nil
+		},
+		ListResourcesF: func(req *pulumirpc.ListResourcesRequest) (*pulumirpc.ListResourcesResponse, error) {
+			// Always hand out the same next page, which would never terminate.
+			return &pulumirpc.ListResourcesResponse{
+				Resources:     []*pulumirpc.ListedResource{{Id: "id"}},
+				NextPageToken: "page-2",
+			}, nil
+		},
+	}
+
+	p := NewProviderWithClient(newTestContext(t), "pkg", client, false /* disablePreview */)
+	require.NoError(t, p.Configure(resource.PropertyMap{}))
+
+	_, err := p.(ResourceLister).ListResources("pkg:index:Thing", nil)
+	assert.ErrorContains(t, err, `returned the page token "page-2" more than once`)
+}
+
 // newTestContext builds a *Context for use in tests.
 func newTestContext(t testing.TB) *Context {
 	t.Helper()
@@ -698,7 +816,10 @@ type stubClient struct {
 
 	ConstructF func(*pulumirpc.ConstructRequest) (*pulumirpc.ConstructResponse, error)
 	ConfigureF func(*pulumirpc.ConfigureRequest) (*pulumirpc.ConfigureResponse, error)
+	CreateF    func(*pulumirpc.CreateRequest) (*pulumirpc.CreateResponse, error)
 	DeleteF    func(*pulumirpc.DeleteRequest) error
+
+	ListResourcesF func(*pulumirpc.ListResourcesRequest) (*pulumirpc.ListResourcesResponse, error)
 }
 
 func (c *stubClient) Construct(
@@ -723,6 +844,17 @@ func (c *stubClient) Configure(
 	return c.ResourceProviderClient.Configure(ctx, req, opts...)
 }
 
+func (c *stubClient) Create(
+	ctx context.Context,
+	req *pulumirpc.CreateRequest,
+	opts ...grpc.CallOption,
+) (*pulumirpc.CreateResponse, error) {
+	if f := c.CreateF; f != nil {
+		return f(req)
+	}
+	return c.ResourceProviderClient.Create(ctx, req, opts...)
+}
+
 func (c *stubClient) Delete(
 	ctx context.Context,
 	req *pulumirpc.DeleteRequest,
@@ -734,3 +866,14 @@ func (c *stubClient) Delete(
 	}
 	return c.ResourceProviderClient.Delete(ctx, req, opts...)
 }
+
+func (c *stubClient) ListResources(
+	ctx context.Context,
+	req *pulumirpc.ListResourcesRequest,
+	opts ...grpc.CallOption,
+) (*pulumirpc.ListResourcesResponse, error) {
+	if f := c.ListResourcesF; f != nil {
+		return f(req)
+	}
+	return c.ResourceProviderClient.ListResources(ctx, req, opts...)
+}
@@ -664,3 +664,36 @@ func (p *providerServer) GetMappings(ctx context.Context,
 	}
 	return &pulumirpc.GetMappingsResponse{Providers: providers}, nil
 }
+
+func (p *providerServer) ListResources(ctx context.Context,
+	req *pulumirpc.ListResourcesRequest,
+) (*pulumirpc.ListResourcesResponse, error) {
+	lister, ok := p.provider.(ResourceLister)
+	if !ok {
+		return nil, status.Error(codes.Unimplemented, "ListResources is not yet implemented")
+	}
+
+	filters, err := UnmarshalProperties(req.GetFilters(), p.unmarshalOptions("filters"))
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := lister.ListResources(tokens.Type(req.GetType()), filters)
+	if err != nil {
+		return nil, p.checkNYI("ListResources", err)
+	}
+
+	rpcResources := make([]*pulumirpc.ListedResource, len(resources))
+	for i, r := range resources {
+		props, err := MarshalProperties(r.Properties, p.marshalOptions("properties"))
+		if err != nil {
+			return nil, err
+		}
+		rpcResources[i] = &pulumirpc.ListedResource{
+			Id:         string(r.ID),
+			Name:       r.Name,
+			Properties: props,
+		}
+	}
+	return &pulumirpc.ListResourcesResponse{Resources: rpcResources}, nil
+}
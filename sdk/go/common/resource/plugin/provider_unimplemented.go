@@ -105,3 +105,9 @@ func (p *UnimplementedProvider) GetMapping(key, provider string) ([]byte, string
 func (p *UnimplementedProvider) GetMappings(key string) ([]string, error) {
 	return nil, status.Error(codes.Unimplemented, "GetMappings is not yet implemented")
 }
+
+func (p *UnimplementedProvider) ListResources(
+	typ tokens.Type, filters resource.PropertyMap,
+) ([]ListedResource, error) {
+	return nil, status.Error(codes.Unimplemented, "ListResources is not yet implemented")
+}
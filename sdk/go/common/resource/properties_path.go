@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -14,6 +15,11 @@ import (
 // in ObjectProperty values) and integers (which access elements of ArrayProperty values).
 type PropertyPath []interface{}
 
+// propertyGlob is a component of a PropertyPath that matches the keys of an object as a glob, in which "*" matches any
+// sequence of characters. ParsePropertyPath parses property names that contain "*" as globs, but not quoted property
+// names, so that keys containing "*" can still be accessed.
+type propertyGlob string
+
 // ParsePropertyPath parses a property path into a PropertyPath value.
 //
 // A property path string is essentially a Javascript property access expression in which all elements are literals.
@@ -46,6 +52,7 @@ type PropertyPath []interface{}
 // - ["root key with a ."][100]
 // - root.array[*].field
 // - root.array["*"].field
+// - root.tags.team*
 func ParsePropertyPath(path string) (PropertyPath, error) {
 	// We interpret the grammar above a little loosely in order to keep things simple. Specifically, we will accept
 	// something close to the following:
@@ -109,7 +116,11 @@ func ParsePropertyPath(path string) (PropertyPath, error) {
 		default:
 			for i := 0; ; i++ {
 				if i == len(path) || path[i] == '.' || path[i] == '[' {
-					elements, path = append(elements, path[:i]), path[i:]
+					var pathElement interface{} = path[:i]
+					if name := path[:i]; name != "*" && strings.Contains(name, "*") {
+						pathElement = propertyGlob(name)
+					}
+					elements, path = append(elements, pathElement), path[i:]
 					break
 				}
 			}
@@ -383,7 +394,11 @@ func (p PropertyPath) reset(old, new PropertyValue) bool {
 					return true
 				} else if new.IsArray() {
 					if old.IsArray() {
+						// Only reset the elements that both arrays have, as we can't change the size of new.
 						for i := range old.ArrayValue() {
+							if i >= len(new.ArrayValue()) {
+								break
+							}
 							v := old.ArrayValue()[i]
 							new.ArrayValue()[i] = v
 						}
@@ -416,6 +431,9 @@ func (p PropertyPath) reset(old, new PropertyValue) bool {
 				newArray := new.ArrayValue()
 
 				for i := range oldArray {
+					if i >= len(newArray) {
+						break
+					}
 					if !p[1:].reset(oldArray[i], newArray[i]) {
 						return false
 					}
@@ -492,6 +510,105 @@ func (p PropertyPath) Reset(old, new PropertyMap) bool {
 	return p.reset(NewObjectProperty(old), NewObjectProperty(new))
 }
 
+// Expand returns the paths without wildcards that the PropertyPath matches in the given old and new PropertyMaps,
+// such that resetting each of the returned paths resets everything that the PropertyPath matches. A "*" component
+// matches every key of an object or every index of an array, and any other unquoted property name that contains "*",
+// e.g. "team*", matches the keys of an object that it matches as a glob, where "*" matches any sequence of
+// characters. A quoted property name other than "*", e.g. ["team*"], only matches that exact key.
+//
+// Array indices are only matched if both old and new have them, as Reset can't change the size of an array. Object
+// keys are matched in both old and new by the last component of the path, and only in new by earlier components, as
+// an intermediate location that's only in old can't be reset. A wildcard that matches nothing expands to no paths,
+// and a path without wildcards expands to itself.
+func (p PropertyPath) Expand(old, new PropertyMap) []PropertyPath {
+	var paths []PropertyPath
+	var expand func(prefix, rest PropertyPath, old, new PropertyValue)
+	expand = func(prefix, rest PropertyPath, old, new PropertyValue) {
+		if len(rest) == 0 {
+			paths = append(paths, prefix)
+			return
+		}
+
+		descend := func(key interface{}) {
+			next := make(PropertyPath, len(prefix), len(prefix)+1)
+			copy(next, prefix)
+			next = append(next, key)
+			child := PropertyPath{key}
+			oldChild, _ := child.Get(old)
+			newChild, _ := child.Get(new)
+			expand(next, rest[1:], oldChild, newChild)
+		}
+
+		var key string
+		switch k := rest[0].(type) {
+		case propertyGlob:
+			key = string(k)
+		case string:
+			if k != "*" {
+				descend(k)
+				return
+			}
+			key = k
+		default:
+			descend(k)
+			return
+		}
+
+		if key == "*" && old.IsArray() && new.IsArray() {
+			for i := 0; i < len(old.ArrayValue()) && i < len(new.ArrayValue()); i++ {
+				descend(i)
+			}
+			return
+		}
+
+		matches := map[string]bool{}
+		if new.IsObject() {
+			for k := range new.ObjectValue() {
+				if matchGlob(key, string(k)) {
+					matches[string(k)] = true
+				}
+			}
+		}
+		if len(rest) == 1 && old.IsObject() {
+			for k := range old.ObjectValue() {
+				if matchGlob(key, string(k)) {
+					matches[string(k)] = true
+				}
+			}
+		}
+		keys := make([]string, 0, len(matches))
+		for k := range matches {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			descend(k)
+		}
+	}
+	expand(nil, p, NewObjectProperty(old), NewObjectProperty(new))
+	return paths
+}
+
+// matchGlob returns true if s matches the pattern, in which "*" matches any sequence of characters.
+func matchGlob(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	if len(parts) == 1 {
+		return s == ""
+	}
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i == -1 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, parts[len(parts)-1])
+}
+
 func requiresQuote(c rune) bool {
 	return !(c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_')
 }
@@ -521,6 +638,12 @@ func (p PropertyPath) String() string {
 			} else {
 				fmt.Fprintf(&buf, `["%s"]`, keyBuf.String())
 			}
+		case propertyGlob:
+			if i == 0 {
+				fmt.Fprintf(&buf, "%s", k)
+			} else {
+				fmt.Fprintf(&buf, ".%s", k)
+			}
 		case int:
 			fmt.Fprintf(&buf, "[%d]", k)
 		}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/deepcopy"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestPropertyPath(t *testing.T) {
@@ -740,3 +741,103 @@ func TestReset(t *testing.T) {
 		})
 	}
 }
+
+func TestExpand(t *testing.T) {
+	t.Parallel()
+
+	tags := func(keys ...string) PropertyValue {
+		m := PropertyMap{}
+		for _, k := range keys {
+			m[PropertyKey(k)] = NewStringProperty(k)
+		}
+		return NewObjectProperty(m)
+	}
+	rules := func(n int) PropertyValue {
+		a := make([]PropertyValue, n)
+		for i := range a {
+			a[i] = NewObjectProperty(PropertyMap{"description": NewStringProperty("rule")})
+		}
+		return NewArrayProperty(a)
+	}
+
+	cases := []struct {
+		name     string
+		path     string
+		old      PropertyMap
+		new      PropertyMap
+		expected []string
+	}{
+		{
+			"Literal path",
+			"tags.owner",
+			PropertyMap{},
+			PropertyMap{},
+			[]string{"tags.owner"},
+		},
+		{
+			"Object wildcard matches keys in old and new",
+			"tags.*",
+			PropertyMap{"tags": tags("a", "b")},
+			PropertyMap{"tags": tags("b", "c")},
+			[]string{"tags.a", "tags.b", "tags.c"},
+		},
+		{
+			"Glob matches keys",
+			"tags.team*",
+			PropertyMap{"tags": tags("teamName", "owner")},
+			PropertyMap{"tags": tags("team", "teamId", "myteam")},
+			[]string{"tags.team", "tags.teamId", "tags.teamName"},
+		},
+		{
+			"Glob with several wildcards",
+			"tags.a*b*c",
+			PropertyMap{"tags": tags("abc", "aXbYc", "abXc", "acb", "abcd")},
+			PropertyMap{},
+			[]string{"tags.aXbYc", "tags.abXc", "tags.abc"},
+		},
+		{
+			"Quoted key with a wildcard is literal",
+			`tags["a*b"]`,
+			PropertyMap{"tags": tags("a*b", "aXb")},
+			PropertyMap{"tags": tags("ab")},
+			[]string{`tags["a*b"]`},
+		},
+		{
+			"Array wildcard matches indices in both",
+			"rules[*].description",
+			PropertyMap{"rules": rules(3)},
+			PropertyMap{"rules": rules(2)},
+			[]string{"rules[0].description", "rules[1].description"},
+		},
+		{
+			"Intermediate wildcard matches keys in new",
+			"tags.*.value",
+			PropertyMap{"tags": NewObjectProperty(PropertyMap{"a": tags("value")})},
+			PropertyMap{"tags": NewObjectProperty(PropertyMap{"b": tags("value")})},
+			[]string{"tags.b.value"},
+		},
+		{
+			"Wildcard matches nothing",
+			"tags.*",
+			PropertyMap{"tags": NewStringProperty("a")},
+			PropertyMap{},
+			nil,
+		},
+	}
+
+	for _, tt := range cases {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, err := ParsePropertyPath(tt.path)
+			require.NoError(t, err)
+
+			var actual []string
+			for _, p := range path.Expand(tt.old, tt.new) {
+				actual = append(actual, p.String())
+			}
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -44,6 +44,11 @@ type Goal struct {
 	// if specified resource is being deleted as well.
 	DeletedWith    URN
 	SourcePosition string // If set, the source location of the resource registration
+	// if positive, the maximum number of times to retry a create or update of this resource that fails with a transient
+	// error.
+	Retries int
+	// if positive, the maximum number of concurrent operations on the resources of this provider resource.
+	Parallelism int
 }
 
 // NewGoal allocates a new resource goal state.
@@ -51,6 +51,7 @@ type State struct {
 	Created                 *time.Time            // If set, the time when the state was initially added to the state file. (i.e. Create, Import)
 	Modified                *time.Time            // If set, the time when the state was last modified in the state file.
 	SourcePosition          string                // If set, the source location of the resource registration
+	Taint                   bool                  // true if this resource should be replaced on the next update.
 }
 
 func (s *State) GetAliasURNs() []URN {
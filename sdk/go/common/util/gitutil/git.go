@@ -449,6 +449,79 @@ func GitCloneAndCheckoutCommit(url string, commit plumbing.Hash, path string) er
 	})
 }
 
+// GitCloneAndCheckoutRevision clones the Git repository and checks out the given revision, which may be a tag, a
+// branch or a commit. If the revision is empty, the repository's default branch is checked out.
+func GitCloneAndCheckoutRevision(url, revision, path string) (*git.Repository, error) {
+	logging.V(10).Infof("Attempting to clone from %s at revision %s and path %s", url, revision, path)
+
+	u, auth, err := parseAuthURL(url)
+	if err != nil {
+		return nil, err
+	}
+	repo, err := git.PlainClone(path, false, &git.CloneOptions{
+		URL:  u,
+		Auth: auth,
+		Tags: git.AllTags,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if revision == "" {
+		return repo, nil
+	}
+
+	// Branches other than the default are only known as remote branches after cloning.
+	hash, err := repo.ResolveRevision(plumbing.Revision(revision))
+	if err != nil {
+		if hash, err = repo.ResolveRevision(plumbing.Revision("origin/" + revision)); err != nil {
+			return nil, fmt.Errorf("resolving revision %s: %w", revision, err)
+		}
+	}
+
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	if err := w.Checkout(&git.CheckoutOptions{Hash: *hash, Force: true}); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// GitTagsAtHead returns the names of the tags, lightweight or annotated, that point at the repository's HEAD commit.
+func GitTagsAtHead(repo *git.Repository) ([]string, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, err
+	}
+	tags, err := repo.Tags()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	err = tags.ForEach(func(ref *plumbing.Reference) error {
+		hash := ref.Hash()
+		if tag, err := repo.TagObject(hash); err == nil {
+			commit, err := tag.Commit()
+			if err != nil {
+				// The tag doesn't point at a commit.
+				return nil
+			}
+			hash = commit.Hash
+		}
+		if hash == head.Hash() {
+			names = append(names, ref.Name().Short())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
 func GitCloneOrPull(rawurl string, referenceName plumbing.ReferenceName, path string, shallow bool) error {
 	logging.V(10).Infof("Attempting to clone from %s at ref %s", rawurl, referenceName)
 
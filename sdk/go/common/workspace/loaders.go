@@ -197,6 +197,12 @@ func LoadProjectStack(project *Project, path string) (*ProjectStack, error) {
 	if projectStack.Config == nil {
 		projectStack.Config = make(config.Map)
 	}
+	if err := projectStack.IgnoreChanges.validate(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	if err := projectStack.Parallelism.validate(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
 
 	projectStack.raw = b
 	return &projectStack, nil
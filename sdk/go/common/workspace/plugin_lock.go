@@ -0,0 +1,341 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+)
+
+// PluginLockFile is the name of the file, next to a project's Pulumi.yaml, that records the exact plugins the project
+// uses.
+const PluginLockFile = "Pulumi.lock"
+
+// PluginLockEntry records a plugin resolved for a project.
+type PluginLockEntry struct {
+	Kind              PluginKind `json:"kind" yaml:"kind"`
+	Name              string     `json:"name" yaml:"name"`
+	Version           string     `json:"version" yaml:"version"`
+	PluginDownloadURL string     `json:"pluginDownloadURL,omitempty" yaml:"pluginDownloadURL,omitempty"`
+	// Checksums are the hex encoded SHA-256 checksums of the plugin's archive, keyed by "$os-$arch", e.g.
+	// "linux-amd64".
+	Checksums map[string]string `json:"checksums,omitempty" yaml:"checksums,omitempty"`
+	// BinaryChecksums are the hex encoded SHA-256 checksums of the plugin's executable once installed, keyed like
+	// Checksums. Archives aren't kept once installed, so these are what installed plugins are verified against.
+	BinaryChecksums map[string]string `json:"binaryChecksums,omitempty" yaml:"binaryChecksums,omitempty"`
+}
+
+func (entry PluginLockEntry) version() semver.Version {
+	version, err := semver.ParseTolerant(entry.Version)
+	contract.AssertNoErrorf(err, "versions are validated when the lock is loaded")
+	return version
+}
+
+// PluginLock is the contents of a project's Pulumi.lock file. It is safe for concurrent use.
+type PluginLock struct {
+	Plugins []PluginLockEntry `json:"plugins" yaml:"plugins"`
+
+	m       sync.Mutex
+	changed bool
+}
+
+// PluginLockPath returns the path of the lock file for the project in the given directory.
+func PluginLockPath(root string) string {
+	return filepath.Join(root, PluginLockFile)
+}
+
+// LoadPluginLock reads the lock file at the given path. If the file doesn't exist, an empty lock is returned.
+func LoadPluginLock(path string) (*PluginLock, error) {
+	b, err := readFileStripUTF8BOM(path)
+	if os.IsNotExist(err) {
+		return &PluginLock{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var lock PluginLock
+	if err := encoding.YAML.Unmarshal(b, &lock); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", path, err)
+	}
+	for _, entry := range lock.Plugins {
+		if _, err := semver.ParseTolerant(entry.Version); err != nil {
+			return nil, fmt.Errorf("could not read %s: invalid version for %s plugin %s: %w",
+				path, entry.Kind, entry.Name, err)
+		}
+		for platform, checksum := range entry.Checksums {
+			if _, err := hex.DecodeString(checksum); err != nil {
+				return nil, fmt.Errorf("could not read %s: invalid %s checksum for %s plugin %s: %w",
+					path, platform, entry.Kind, entry.Name, err)
+			}
+		}
+		for platform, checksum := range entry.BinaryChecksums {
+			if _, err := hex.DecodeString(checksum); err != nil {
+				return nil, fmt.Errorf("could not read %s: invalid %s binary checksum for %s plugin %s: %w",
+					path, platform, entry.Kind, entry.Name, err)
+			}
+		}
+	}
+	return &lock, nil
+}
+
+// Save writes the lock to the given path, with its plugins in a stable order.
+func (l *PluginLock) Save(path string) error {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	sort.Slice(l.Plugins, func(i, j int) bool {
+		a, b := l.Plugins[i], l.Plugins[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.version().LT(b.version())
+	})
+	b, err := encoding.YAML.Marshal(l)
+	if err != nil {
+		return err
+	}
+	//nolint:gosec // The lock file is meant to be committed alongside the project, like Pulumi.yaml.
+	if err := os.WriteFile(path, b, 0o644); err != nil {
+		return err
+	}
+	l.changed = false
+	return nil
+}
+
+// Changed returns true if plugins have been recorded in the lock since it was loaded or saved.
+func (l *PluginLock) Changed() bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+	return l.changed
+}
+
+// Contains returns true if the lock records the given version of a plugin.
+func (l *PluginLock) Contains(kind PluginKind, name string, version semver.Version) bool {
+	l.m.Lock()
+	defer l.m.Unlock()
+	_, match := l.lookup(kind, name, &version)
+	return match != -1
+}
+
+// lookup returns the entries for the given plugin, and the entry for its version if it has one. The caller must hold
+// the lock's mutex.
+func (l *PluginLock) lookup(kind PluginKind, name string, version *semver.Version) ([]int, int) {
+	var entries []int
+	match := -1
+	for i, entry := range l.Plugins {
+		if entry.Kind != kind || entry.Name != name {
+			continue
+		}
+		entries = append(entries, i)
+		if version != nil && entry.version().EQ(*version) {
+			match = i
+		}
+	}
+	return entries, match
+}
+
+// Apply resolves the given plugin against the lock. A plugin without a version is pinned to the locked version, and a
+// locked plugin's download URL and checksums are filled in if the plugin doesn't specify them, so that downloads are
+// verified against the recorded checksums.
+//
+// If locked is true, an error is returned if the plugin isn't in the lock, or if it specifies a download URL or
+// checksum that differs from the lock's.
+func (l *PluginLock) Apply(spec PluginSpec, locked bool) (PluginSpec, error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	entries, match := l.lookup(spec.Kind, spec.Name, spec.Version)
+	if spec.Version == nil && len(entries) == 1 {
+		match = entries[0]
+		version := l.Plugins[match].version()
+		spec.Version = &version
+	}
+	if match == -1 {
+		if locked {
+			return spec, fmt.Errorf("%s plugin %s is not recorded in %s; run without --locked to update it",
+				spec.Kind, spec, PluginLockFile)
+		}
+		return spec, nil
+	}
+	entry := l.Plugins[match]
+
+	if spec.PluginDownloadURL == "" {
+		spec.PluginDownloadURL = entry.PluginDownloadURL
+	} else if locked && spec.PluginDownloadURL != entry.PluginDownloadURL {
+		return spec, fmt.Errorf("%s plugin %s is downloaded from %q, but %s records %q",
+			spec.Kind, spec, spec.PluginDownloadURL, PluginLockFile, entry.PluginDownloadURL)
+	}
+
+	checksums := make(map[string][]byte, len(entry.Checksums))
+	for platform, checksum := range entry.Checksums {
+		b, err := hex.DecodeString(checksum)
+		contract.AssertNoErrorf(err, "checksums are validated when the lock is loaded")
+		checksums[platform] = b
+	}
+	for platform, checksum := range spec.Checksums {
+		if locked {
+			if expected, ok := checksums[platform]; ok && !bytes.Equal(expected, checksum) {
+				return spec, fmt.Errorf("%s plugin %s has %s checksum %x, but %s records %x",
+					spec.Kind, spec, platform, checksum, PluginLockFile, expected)
+			}
+		}
+		checksums[platform] = checksum
+	}
+	if len(checksums) > 0 {
+		spec.Checksums = checksums
+	}
+	return spec, nil
+}
+
+// Record adds the given plugin to the lock, or updates its entry. If checksum is not nil, it is recorded as the
+// checksum of the plugin's archive for the current platform. Plugins without a version are not recorded.
+func (l *PluginLock) Record(spec PluginSpec, checksum []byte) {
+	l.record(spec, checksum, nil)
+}
+
+// RecordInstalled adds a plugin that is installed to the lock, like Record, along with the checksum of its executable.
+// If the lock records a checksum of the executable for the current platform, the installed executable must match it.
+// If locked is true, the lock must record a checksum of the executable for the current platform, the plugin must have
+// an executable to verify against it, and the lock is never changed.
+func (l *PluginLock) RecordInstalled(spec PluginSpec, locked bool) error {
+	checksum, err := spec.InstalledChecksum()
+	if err != nil {
+		return fmt.Errorf("computing checksum of installed %s plugin %s: %w", spec.Kind, spec, err)
+	}
+
+	var expected string
+	if spec.Version != nil {
+		l.m.Lock()
+		if _, match := l.lookup(spec.Kind, spec.Name, spec.Version); match != -1 {
+			expected = l.Plugins[match].BinaryChecksums[CurrentPluginPlatform()]
+		}
+		l.m.Unlock()
+	}
+
+	switch {
+	case expected == "" && locked:
+		return fmt.Errorf("%s does not record a %s binary checksum of %s plugin %s; "+
+			"run `pulumi plugin install` without --locked to record it",
+			PluginLockFile, CurrentPluginPlatform(), spec.Kind, spec)
+	case checksum == nil && locked:
+		return fmt.Errorf("installed %s plugin %s has no executable to verify against %s; "+
+			"run `pulumi plugin install --reinstall` to reinstall it", spec.Kind, spec, PluginLockFile)
+	case expected != "" && checksum != nil && hex.EncodeToString(checksum) != expected:
+		return fmt.Errorf("installed %s plugin %s has %s binary checksum %x, but %s records %s; "+
+			"run `pulumi plugin install --reinstall` to reinstall it",
+			spec.Kind, spec, CurrentPluginPlatform(), checksum, PluginLockFile, expected)
+	}
+
+	if !locked {
+		l.record(spec, nil, checksum)
+	}
+	return nil
+}
+
+// record adds the given plugin to the lock, or updates its entry, with the given checksums of its archive and
+// executable for the current platform, if they are not nil.
+func (l *PluginLock) record(spec PluginSpec, checksum, binaryChecksum []byte) {
+	if spec.Version == nil {
+		return
+	}
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	_, match := l.lookup(spec.Kind, spec.Name, spec.Version)
+	if match == -1 {
+		l.Plugins = append(l.Plugins, PluginLockEntry{
+			Kind:    spec.Kind,
+			Name:    spec.Name,
+			Version: spec.Version.String(),
+		})
+		match = len(l.Plugins) - 1
+		l.changed = true
+	}
+	entry := &l.Plugins[match]
+	if entry.PluginDownloadURL != spec.PluginDownloadURL {
+		entry.PluginDownloadURL = spec.PluginDownloadURL
+		l.changed = true
+	}
+
+	checksums := map[string]string{}
+	for platform, b := range spec.Checksums {
+		checksums[platform] = hex.EncodeToString(b)
+	}
+	if checksum != nil {
+		checksums[CurrentPluginPlatform()] = hex.EncodeToString(checksum)
+	}
+	l.changed = updateChecksums(&entry.Checksums, checksums) || l.changed
+	if binaryChecksum != nil {
+		binaryChecksums := map[string]string{CurrentPluginPlatform(): hex.EncodeToString(binaryChecksum)}
+		l.changed = updateChecksums(&entry.BinaryChecksums, binaryChecksums) || l.changed
+	}
+}
+
+// updateChecksums sets the given checksums in the map m, creating it if needed, and returns true if any changed.
+func updateChecksums(m *map[string]string, checksums map[string]string) bool {
+	changed := false
+	for platform, checksum := range checksums {
+		if (*m)[platform] != checksum {
+			if *m == nil {
+				*m = map[string]string{}
+			}
+			(*m)[platform] = checksum
+			changed = true
+		}
+	}
+	return changed
+}
+
+// CurrentPluginPlatform returns the key of the current platform in plugin checksums, e.g. "linux-amd64".
+func CurrentPluginPlatform() string {
+	return runtime.GOOS + "-" + runtime.GOARCH
+}
+
+// PluginArchiveChecksum returns the SHA-256 checksum of the plugin archive at the given path, as recorded by Record.
+func PluginArchiveChecksum(path string) ([]byte, error) {
+	return fileChecksum(path)
+}
+
+// fileChecksum returns the SHA-256 checksum of the file at the given path.
+func fileChecksum(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer contract.IgnoreClose(f)
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, f); err != nil {
+		return nil, err
+	}
+	return hasher.Sum(nil), nil
+}
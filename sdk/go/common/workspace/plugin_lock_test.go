@@ -0,0 +1,203 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPluginLockRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	path := PluginLockPath(t.TempDir())
+
+	// A missing lock file is an empty lock.
+	lock, err := LoadPluginLock(path)
+	require.NoError(t, err)
+	assert.Empty(t, lock.Plugins)
+	assert.False(t, lock.Changed())
+
+	v2 := semver.MustParse("2.0.0")
+	v1 := semver.MustParse("1.0.0")
+	lock.Record(PluginSpec{Kind: ResourcePlugin, Name: "random", Version: &v2}, []byte{0xab, 0xcd})
+	lock.Record(PluginSpec{
+		Kind:              ResourcePlugin,
+		Name:              "random",
+		Version:           &v1,
+		PluginDownloadURL: "https://example.com",
+	}, nil)
+	lock.Record(PluginSpec{Kind: LanguagePlugin, Name: "nodejs"}, nil)
+	assert.True(t, lock.Changed())
+
+	require.NoError(t, lock.Save(path))
+	assert.False(t, lock.Changed())
+
+	// Recording a plugin that's already in the lock doesn't change it.
+	lock.Record(PluginSpec{Kind: ResourcePlugin, Name: "random", Version: &v2}, []byte{0xab, 0xcd})
+	assert.False(t, lock.Changed())
+
+	loaded, err := LoadPluginLock(path)
+	require.NoError(t, err)
+	assert.Equal(t, []PluginLockEntry{
+		{Kind: ResourcePlugin, Name: "random", Version: "1.0.0", PluginDownloadURL: "https://example.com"},
+		{
+			Kind:      ResourcePlugin,
+			Name:      "random",
+			Version:   "2.0.0",
+			Checksums: map[string]string{CurrentPluginPlatform(): "abcd"},
+		},
+	}, loaded.Plugins)
+}
+
+func TestPluginLockInvalid(t *testing.T) {
+	t.Parallel()
+
+	path := PluginLockPath(t.TempDir())
+	err := os.WriteFile(path, []byte("plugins:\n- kind: resource\n  name: random\n  version: latest\n"), 0o600)
+	require.NoError(t, err)
+	_, err = LoadPluginLock(path)
+	assert.ErrorContains(t, err, "invalid version for resource plugin random")
+
+	err = os.WriteFile(path, []byte(
+		"plugins:\n- kind: resource\n  name: random\n  version: 1.0.0\n  checksums:\n    linux-amd64: xyz\n"), 0o600)
+	require.NoError(t, err)
+	_, err = LoadPluginLock(path)
+	assert.ErrorContains(t, err, "invalid linux-amd64 checksum for resource plugin random")
+}
+
+func TestPluginLockApply(t *testing.T) {
+	t.Parallel()
+
+	lock := &PluginLock{Plugins: []PluginLockEntry{
+		{
+			Kind:              ResourcePlugin,
+			Name:              "random",
+			Version:           "4.2.0",
+			PluginDownloadURL: "https://example.com",
+			Checksums:         map[string]string{"linux-amd64": "abcd"},
+		},
+		{Kind: ResourcePlugin, Name: "aws", Version: "5.0.0"},
+		{Kind: ResourcePlugin, Name: "aws", Version: "6.0.0"},
+	}}
+
+	// An unversioned plugin is pinned to its only locked version, and picks up the lock's URL and checksums.
+	spec, err := lock.Apply(PluginSpec{Kind: ResourcePlugin, Name: "random"}, true)
+	require.NoError(t, err)
+	require.NotNil(t, spec.Version)
+	assert.Equal(t, "4.2.0", spec.Version.String())
+	assert.Equal(t, "https://example.com", spec.PluginDownloadURL)
+	assert.Equal(t, map[string][]byte{"linux-amd64": {0xab, 0xcd}}, spec.Checksums)
+
+	// A plugin with several locked versions can't be pinned.
+	spec, err = lock.Apply(PluginSpec{Kind: ResourcePlugin, Name: "aws"}, false)
+	require.NoError(t, err)
+	assert.Nil(t, spec.Version)
+	_, err = lock.Apply(PluginSpec{Kind: ResourcePlugin, Name: "aws"}, true)
+	assert.ErrorContains(t, err, "is not recorded in Pulumi.lock")
+
+	v6 := semver.MustParse("6.0.0")
+	spec, err = lock.Apply(PluginSpec{Kind: ResourcePlugin, Name: "aws", Version: &v6}, true)
+	require.NoError(t, err)
+	assert.Equal(t, &v6, spec.Version)
+
+	// Drift is only an error when locked.
+	v43 := semver.MustParse("4.3.0")
+	_, err = lock.Apply(PluginSpec{Kind: ResourcePlugin, Name: "random", Version: &v43}, false)
+	assert.NoError(t, err)
+	_, err = lock.Apply(PluginSpec{Kind: ResourcePlugin, Name: "random", Version: &v43}, true)
+	assert.ErrorContains(t, err, "is not recorded in Pulumi.lock")
+
+	drifted := PluginSpec{Kind: ResourcePlugin, Name: "random", PluginDownloadURL: "https://other.example.com"}
+	_, err = lock.Apply(drifted, false)
+	assert.NoError(t, err)
+	_, err = lock.Apply(drifted, true)
+	assert.ErrorContains(t, err, `is downloaded from "https://other.example.com"`)
+
+	drifted = PluginSpec{
+		Kind:      ResourcePlugin,
+		Name:      "random",
+		Checksums: map[string][]byte{"linux-amd64": {0x12, 0x34}},
+	}
+	_, err = lock.Apply(drifted, false)
+	assert.NoError(t, err)
+	_, err = lock.Apply(drifted, true)
+	assert.ErrorContains(t, err, "has linux-amd64 checksum 1234, but Pulumi.lock records abcd")
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestPluginLockRecordInstalled(t *testing.T) {
+	t.Setenv("PULUMI_HOME", t.TempDir())
+
+	// installPlugin writes the executable of the given plugin and returns its checksum.
+	installPlugin := func(spec PluginSpec, contents string) string {
+		dir, err := spec.DirPath()
+		require.NoError(t, err)
+		require.NoError(t, os.MkdirAll(dir, 0o700))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, spec.File()+getCandidateExtensions()[0]), []byte(contents), 0o700))
+		checksum := sha256.Sum256([]byte(contents))
+		return hex.EncodeToString(checksum[:])
+	}
+
+	v5 := semver.MustParse("5.4.0")
+	spec := PluginSpec{Kind: ResourcePlugin, Name: "aws", Version: &v5}
+	sum := sha256.Sum256([]byte("expected"))
+	expected := hex.EncodeToString(sum[:])
+	lock := &PluginLock{Plugins: []PluginLockEntry{{
+		Kind:            ResourcePlugin,
+		Name:            "aws",
+		Version:         "5.4.0",
+		BinaryChecksums: map[string]string{CurrentPluginPlatform(): expected},
+	}}}
+
+	// A plugin without an executable can only be recorded when not locked.
+	assert.NoError(t, lock.RecordInstalled(spec, false))
+	assert.ErrorContains(t, lock.RecordInstalled(spec, true), "has no executable to verify against Pulumi.lock")
+
+	// An executable with a different checksum is always an error.
+	actual := installPlugin(spec, "modified")
+	msg := fmt.Sprintf("binary checksum %s, but Pulumi.lock records %s", actual, expected)
+	assert.ErrorContains(t, lock.RecordInstalled(spec, false), msg)
+	assert.ErrorContains(t, lock.RecordInstalled(spec, true), msg)
+	assert.False(t, lock.Changed())
+
+	// A matching executable is recorded.
+	lock.Plugins[0].BinaryChecksums[CurrentPluginPlatform()] = actual
+	assert.NoError(t, lock.RecordInstalled(spec, true))
+	assert.False(t, lock.Changed())
+
+	// When locked, a plugin the lock has no binary checksum for is an error, and isn't recorded.
+	v6 := semver.MustParse("6.0.0")
+	spec = PluginSpec{Kind: ResourcePlugin, Name: "aws", Version: &v6}
+	actual = installPlugin(spec, "v6")
+	assert.ErrorContains(t, lock.RecordInstalled(spec, true), "Pulumi.lock does not record a "+
+		CurrentPluginPlatform()+" binary checksum of resource plugin aws-6.0.0")
+	assert.False(t, lock.Changed())
+	assert.Len(t, lock.Plugins, 1)
+
+	// Otherwise, it records the checksum of its executable, and not of an archive.
+	assert.NoError(t, lock.RecordInstalled(spec, false))
+	assert.True(t, lock.Changed())
+	assert.Equal(t, map[string]string{CurrentPluginPlatform(): actual}, lock.Plugins[1].BinaryChecksums)
+	assert.Empty(t, lock.Plugins[1].Checksums)
+}
@@ -657,22 +657,20 @@ type checksumReader struct {
 
 func (reader *checksumReader) Read(p []byte) (int, error) {
 	n, err := reader.io.Read(p)
-	if err != nil {
-		if err == io.EOF {
-			// Check the checksum matches
-			actualChecksum := reader.hasher.Sum(nil)
-			if !bytes.Equal(reader.checksum, actualChecksum) {
-				return n, &checksumError{expected: reader.checksum, actual: actualChecksum}
-			}
-		}
-		return n, err
-	}
 
-	m, err := reader.hasher.Write(p[0:n])
-	contract.AssertNoErrorf(err, "error hashing input")
+	// Readers may return the last of their input along with io.EOF, so hash what was read first.
+	m, hashErr := reader.hasher.Write(p[0:n])
+	contract.AssertNoErrorf(hashErr, "error hashing input")
 	contract.Assertf(m == n, "wrote %d bytes, expected %d", m, n)
 
-	return n, nil
+	if err == io.EOF {
+		// Check the checksum matches
+		actualChecksum := reader.hasher.Sum(nil)
+		if !bytes.Equal(reader.checksum, actualChecksum) {
+			return n, &checksumError{expected: reader.checksum, actual: actualChecksum}
+		}
+	}
+	return n, err
 }
 
 func (reader *checksumReader) Close() error {
@@ -727,6 +725,10 @@ type PluginSpec struct {
 
 	// if set will be used to validate the plugin downloaded matches. This is keyed by "$os-$arch", e.g. "linux-x64".
 	Checksums map[string][]byte
+
+	// if set, a directory of plugin archives to install the plugin from rather than downloading it. This is
+	// typically a project's plugin mirror. PULUMI_PLUGIN_MIRROR takes precedence over it.
+	Mirror string
 }
 
 // Dir gets the expected plugin directory for this plugin.
@@ -778,6 +780,24 @@ func (spec PluginSpec) PartialFilePath() (string, error) {
 	return fmt.Sprintf("%s.partial", dir), nil
 }
 
+// InstalledChecksum returns the SHA-256 checksum of the installed plugin's executable, or nil if there is no executable
+// where the plugin is expected to be installed.
+func (spec PluginSpec) InstalledChecksum() ([]byte, error) {
+	dir, err := spec.DirPath()
+	if err != nil {
+		return nil, err
+	}
+	path := getPluginPath(&PluginInfo{Name: spec.Name, Kind: spec.Kind, Version: spec.Version, Path: dir})
+	checksum, err := fileChecksum(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return checksum, nil
+}
+
 func (spec PluginSpec) String() string {
 	var version string
 	if v := spec.Version; v != nil {
@@ -828,6 +848,39 @@ func (info *PluginInfo) Delete() error {
 	return nil
 }
 
+// pluginLastUsedFile is the file in a plugin's directory whose modification time records when the plugin was last
+// used.
+const pluginLastUsedFile = ".pulumi-last-used"
+
+// RecordPluginUse records that the plugin with the given binary has been loaded, if it is installed in the plugin
+// cache. This is the plugin's LastUsedTime, which `pulumi plugin prune` uses to find plugins that are no longer used.
+func RecordPluginUse(bin string) error {
+	pluginDir, err := GetPluginDir()
+	if err != nil {
+		return err
+	}
+	bin, err = filepath.Abs(bin)
+	if err != nil {
+		return err
+	}
+	// Plugins from the cache are at <plugin dir>/<kind>-<name>-v<version>/<binary>.
+	dir := filepath.Dir(bin)
+	if filepath.Dir(dir) != filepath.Clean(pluginDir) {
+		return nil
+	}
+
+	path := filepath.Join(dir, pluginLastUsedFile)
+	now := time.Now()
+	err = os.Chtimes(path, now, now)
+	if os.IsNotExist(err) {
+		var f *os.File
+		if f, err = os.Create(path); err == nil {
+			err = f.Close()
+		}
+	}
+	return err
+}
+
 // SetFileMetadata adds extra metadata from the given file, representing this plugin's directory.
 func (info *PluginInfo) SetFileMetadata(path string) error {
 	// Get the file info.
@@ -854,6 +907,12 @@ func (info *PluginInfo) SetFileMetadata(path string) error {
 	}
 
 	info.LastUsedTime = tinfo.AccessTime()
+	// Prefer the time recorded by RecordPluginUse, as many filesystems don't maintain access times.
+	if file.IsDir() {
+		if used, err := os.Stat(filepath.Join(path, pluginLastUsedFile)); err == nil {
+			info.LastUsedTime = used.ModTime()
+		}
+	}
 
 	if info.Kind == ResourcePlugin {
 		var v string
@@ -867,8 +926,27 @@ func (info *PluginInfo) SetFileMetadata(path string) error {
 	return nil
 }
 
+// GetSource returns the source to download the plugin from. If PULUMI_PLUGIN_MIRROR or the spec's Mirror is set,
+// this is the mirror directory rather than the plugin's usual source, so that plugins are never downloaded.
 func (spec PluginSpec) GetSource() (PluginSource, error) {
+	mirror := env.PluginMirror.Value()
+	if mirror == "" {
+		mirror = spec.Mirror
+	}
+	return spec.getSource(mirror)
+}
+
+// GetRemoteSource returns the plugin's usual source, ignoring any plugin mirror.
+func (spec PluginSpec) GetRemoteSource() (PluginSource, error) {
+	return spec.getSource("")
+}
+
+func (spec PluginSpec) getSource(mirror string) (PluginSource, error) {
 	baseSource, err := func() (PluginSource, error) {
+		if mirror != "" {
+			return newMirrorSource(mirror, spec.Name, spec.Kind), nil
+		}
+
 		// The plugin has a set URL use that.
 		if spec.PluginDownloadURL != "" {
 			// Support schematised URLS if the URL has a "schema" part we recognize
@@ -884,6 +962,8 @@ func (spec PluginSpec) GetSource() (PluginSource, error) {
 				return newGitlabSource(url, spec.Name, spec.Kind)
 			case "http", "https":
 				return newHTTPSource(spec.Name, spec.Kind, url), nil
+			case "oci":
+				return newOCISource(url, spec.Name, spec.Kind)
 			default:
 				return nil, fmt.Errorf("unknown plugin source scheme: %s", url.Scheme)
 			}
@@ -917,6 +997,15 @@ func (spec PluginSpec) GetLatestVersion() (*semver.Version, error) {
 	return source.GetLatestVersion(getHTTPResponseWithRetry)
 }
 
+// GetLatestRemoteVersion is like GetLatestVersion, but ignores any plugin mirror.
+func (spec PluginSpec) GetLatestRemoteVersion() (*semver.Version, error) {
+	source, err := spec.GetRemoteSource()
+	if err != nil {
+		return nil, err
+	}
+	return source.GetLatestVersion(getHTTPResponseWithRetry)
+}
+
 // Download fetches an io.ReadCloser for this plugin and also returns the size of the response (if known).
 func (spec PluginSpec) Download() (io.ReadCloser, int64, error) {
 	// Figure out the OS/ARCH pair for the download URL.
@@ -983,7 +1072,7 @@ func getHTTPResponse(req *http.Request) (io.ReadCloser, int64, error) {
 		return nil, -1, newDownloadError(resp.StatusCode, req.URL, resp.Header)
 	}
 
-	return resp.Body, resp.ContentLength, nil
+	return &httpResponseBody{resp.Body, resp.Header}, resp.ContentLength, nil
 }
 
 func getHTTPResponseWithRetry(req *http.Request) (io.ReadCloser, int64, error) {
@@ -1006,7 +1095,19 @@ func getHTTPResponseWithRetry(req *http.Request) (io.ReadCloser, int64, error) {
 		return nil, -1, newDownloadError(resp.StatusCode, req.URL, resp.Header)
 	}
 
-	return resp.Body, resp.ContentLength, nil
+	return &httpResponseBody{resp.Body, resp.Header}, resp.ContentLength, nil
+}
+
+// httpResponseBody is the body of a successful HTTP response, which also carries the response's headers for sources
+// that need them, e.g. to follow pagination links.
+type httpResponseBody struct {
+	io.ReadCloser
+	header http.Header
+}
+
+// Header returns the headers of the response.
+func (body *httpResponseBody) Header() http.Header {
+	return body.header
 }
 
 // downloadError is an error that happened during the HTTP download of a plugin.
@@ -1314,6 +1415,9 @@ func DirPlugin(rootPath string) PluginContent {
 
 type dirPlugin struct {
 	Root string
+
+	// skipGit omits .git directories, which are not part of a plugin built from source.
+	skipGit bool
 }
 
 func (p dirPlugin) Close() error {
@@ -1332,13 +1436,26 @@ func (p dirPlugin) writeToDir(dstRoot string) error {
 			return nil
 		}
 		if d.IsDir() {
+			if p.skipGit && d.Name() == ".git" {
+				return filepath.SkipDir
+			}
 			return os.Mkdir(dstPath, 0o700)
 		}
 
+		// Symlinks are copied as they are, rather than copying what they point to.
+		if d.Type()&fs.ModeSymlink != 0 {
+			target, err := os.Readlink(srcPath)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(target, dstPath)
+		}
+
 		src, err := os.Open(srcPath)
 		if err != nil {
 			return err
 		}
+		defer contract.IgnoreClose(src)
 
 		info, err := d.Info()
 		if err != nil {
@@ -1444,7 +1561,7 @@ func (spec PluginSpec) InstallWithContext(ctx context.Context, content PluginCon
 	if err != nil && !os.IsNotExist(err) {
 		return fmt.Errorf("loading PulumiPlugin.yaml: %w", err)
 	}
-	if proj != nil {
+	if proj != nil && !dependenciesInstalled(content) {
 		runtime := strings.ToLower(proj.Runtime.Name())
 		// For now, we only do this for Node.js and Python. For Go, the expectation is the binary is
 		// already built. For .NET, similarly, a single self-contained binary could be used, but
@@ -0,0 +1,201 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// PluginPlatforms are the "$os-$arch" platforms that plugins are published for.
+var PluginPlatforms = []string{
+	"darwin-amd64", "darwin-arm64",
+	"linux-amd64", "linux-arm64",
+	"windows-amd64", "windows-arm64",
+}
+
+// ParsePluginPlatform splits a platform such as "linux-amd64" into its OS and architecture.
+func ParsePluginPlatform(platform string) (string, string, error) {
+	for _, p := range PluginPlatforms {
+		if p == platform {
+			opSy, arch, _ := strings.Cut(platform, "-")
+			return opSy, arch, nil
+		}
+	}
+	return "", "", fmt.Errorf("unsupported plugin platform %q, must be one of %s",
+		platform, strings.Join(PluginPlatforms, ", "))
+}
+
+// PluginMirrorPath returns the path of the plugin's archive for the given platform in a plugin mirror directory. The
+// plugin must have a version.
+//
+// A mirror directory holds the archives of plugins under the same names they have on get.pulumi.com, e.g.
+// pulumi-resource-aws-v6.0.0-linux-amd64.tar.gz, so a mirror can also be served over HTTP and used with
+// PULUMI_PLUGIN_DOWNLOAD_URL_OVERRIDES.
+func PluginMirrorPath(dir string, spec PluginSpec, opSy, arch string) string {
+	contract.Requiref(spec.Version != nil, "spec", "must have a version")
+	return filepath.Join(dir, standardAssetName(spec.Name, spec.Kind, *spec.Version, opSy, arch))
+}
+
+// MirrorPlugin downloads the plugin's archive for the given platform from its usual source into a plugin mirror
+// directory, unless the mirror already has it. The plugin must have a version. If the plugin has a checksum for the
+// platform, as it does once resolved against a Pulumi.lock, the archive is verified against it, whether it is
+// downloaded or already in the mirror. Returns the path of the archive and whether it was downloaded.
+func MirrorPlugin(
+	dir string, spec PluginSpec, opSy, arch string,
+	wrapper func(stream io.ReadCloser, size int64) io.ReadCloser,
+) (string, bool, error) {
+	path := PluginMirrorPath(dir, spec, opSy, arch)
+	if _, err := os.Stat(path); err == nil {
+		if err := verifyMirroredPlugin(path, spec, opSy, arch); err != nil {
+			return "", false, err
+		}
+		return path, false, nil
+	}
+
+	source, err := spec.GetRemoteSource()
+	if err != nil {
+		return "", false, err
+	}
+	stream, size, err := source.Download(*spec.Version, opSy, arch, getHTTPResponseWithRetry)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to download plugin: %s: %w", spec, err)
+	}
+	if wrapper != nil {
+		stream = wrapper(stream, size)
+	}
+	defer contract.IgnoreClose(stream)
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", false, err
+	}
+	// Download to a temporary file and then rename it, so that the mirror never has a partial archive.
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.partial")
+	if err != nil {
+		return "", false, err
+	}
+	defer func() { contract.IgnoreError(os.Remove(tmp.Name())) }()
+
+	// The download is verified against the plugin's checksum by the source as it is read.
+	_, err = io.Copy(tmp, stream)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("failed to download plugin: %s: %w", spec, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", false, err
+	}
+	return path, true, nil
+}
+
+// verifyMirroredPlugin checks the plugin archive at the given path against the plugin's checksum for the platform, if
+// it has one.
+func verifyMirroredPlugin(path string, spec PluginSpec, opSy, arch string) error {
+	expected, ok := spec.Checksums[opSy+"-"+arch]
+	if !ok {
+		return nil
+	}
+	actual, err := fileChecksum(path)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(expected, actual) {
+		return fmt.Errorf("%s in plugin mirror: %w; delete it to download it again",
+			filepath.Base(path), &checksumError{expected: expected, actual: actual})
+	}
+	return nil
+}
+
+// mirrorSource installs plugins from a plugin mirror directory, as created by `pulumi plugin mirror`.
+type mirrorSource struct {
+	dir  string
+	name string
+	kind PluginKind
+}
+
+func newMirrorSource(dir, name string, kind PluginKind) *mirrorSource {
+	return &mirrorSource{dir: dir, name: name, kind: kind}
+}
+
+func (source *mirrorSource) GetLatestVersion(
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+) (*semver.Version, error) {
+	entries, err := os.ReadDir(source.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading plugin mirror: %w", err)
+	}
+
+	// Archives are named pulumi-<kind>-<name>-v<version>-<os>-<arch>.tar.gz.
+	prefix := fmt.Sprintf("pulumi-%s-%s-v", source.kind, source.name)
+	suffix := fmt.Sprintf("-%s.tar.gz", CurrentPluginPlatform())
+	var latest *semver.Version
+	for _, entry := range entries {
+		name := entry.Name()
+		if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		version, err := semver.Parse(strings.TrimSuffix(strings.TrimPrefix(name, prefix), suffix))
+		if err != nil {
+			continue
+		}
+		if latest == nil || version.GT(*latest) {
+			latest = &version
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no versions of %s plugin %s found in plugin mirror %s",
+			source.kind, source.name, source.dir)
+	}
+	return latest, nil
+}
+
+func (source *mirrorSource) Download(
+	version semver.Version, opSy string, arch string,
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+) (io.ReadCloser, int64, error) {
+	path := filepath.Join(source.dir, standardAssetName(source.name, source.kind, version, opSy, arch))
+	logging.V(1).Infof("%s installing from plugin mirror %s", source.name, path)
+
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		// Report this like a 404, so that the download isn't retried.
+		return nil, -1, &downloadError{
+			code: http.StatusNotFound,
+			msg: fmt.Sprintf("%s plugin %s v%s for %s-%s is not in plugin mirror %s; "+
+				"run `pulumi plugin mirror` to add it", source.kind, source.name, version, opSy, arch, source.dir),
+		}
+	} else if err != nil {
+		return nil, -1, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		contract.IgnoreClose(f)
+		return nil, -1, err
+	}
+	return f, info.Size(), nil
+}
@@ -0,0 +1,161 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePluginPlatform(t *testing.T) {
+	t.Parallel()
+
+	opSy, arch, err := ParsePluginPlatform("linux-arm64")
+	require.NoError(t, err)
+	assert.Equal(t, "linux", opSy)
+	assert.Equal(t, "arm64", arch)
+
+	_, _, err = ParsePluginPlatform("plan9-386")
+	assert.ErrorContains(t, err, `unsupported plugin platform "plan9-386"`)
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestPluginMirror(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		_, err := w.Write([]byte(strings.TrimPrefix(r.URL.Path, "/")))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	v1 := semver.MustParse("1.0.0")
+	v2 := semver.MustParse("2.0.0")
+	spec := PluginSpec{Kind: ResourcePlugin, Name: "mock", Version: &v1, PluginDownloadURL: server.URL}
+
+	// Mirror the plugin for the current platform and another.
+	opSy, arch, err := ParsePluginPlatform(CurrentPluginPlatform())
+	require.NoError(t, err)
+	path, downloaded, err := MirrorPlugin(dir, spec, opSy, arch, nil)
+	require.NoError(t, err)
+	assert.True(t, downloaded)
+	assert.Equal(t, PluginMirrorPath(dir, spec, opSy, arch), path)
+	_, downloaded, err = MirrorPlugin(dir, spec, "windows", "arm64", nil)
+	require.NoError(t, err)
+	assert.True(t, downloaded)
+	spec.Version = &v2
+	_, _, err = MirrorPlugin(dir, spec, opSy, arch, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// Plugins already in the mirror aren't downloaded again.
+	_, downloaded, err = MirrorPlugin(dir, spec, opSy, arch, nil)
+	require.NoError(t, err)
+	assert.False(t, downloaded)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+	matches, err := filepath.Glob(filepath.Join(dir, "*.partial"))
+	require.NoError(t, err)
+	assert.Empty(t, matches)
+
+	// With the spec's mirror set, such as a project's, plugins come from it without any requests.
+	mirrored := spec
+	mirrored.Version, mirrored.Mirror = nil, dir
+	version, err := mirrored.GetLatestVersion()
+	require.NoError(t, err)
+	assert.Equal(t, &v2, version)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+
+	// PULUMI_PLUGIN_MIRROR takes precedence over the spec's mirror.
+	mirrored.Mirror = t.TempDir()
+	t.Setenv("PULUMI_PLUGIN_MIRROR", dir)
+	version, err = mirrored.GetLatestVersion()
+	require.NoError(t, err)
+	assert.Equal(t, &v2, version)
+
+	// With the mirror set, plugins come from it without any requests.
+	spec.Version = nil
+	version, err = spec.GetLatestVersion()
+	require.NoError(t, err)
+	assert.Equal(t, &v2, version)
+
+	spec.Version = &v1
+	r, size, err := spec.Download()
+	require.NoError(t, err)
+	b, err := io.ReadAll(r)
+	require.NoError(t, err)
+	require.NoError(t, r.Close())
+	assert.Equal(t, "pulumi-resource-mock-v1.0.0-"+CurrentPluginPlatform()+".tar.gz", string(b))
+	assert.Equal(t, int64(len(b)), size)
+
+	v3 := semver.MustParse("3.0.0")
+	spec.Version = &v3
+	_, err = DownloadToFile(spec, nil, nil)
+	assert.ErrorContains(t, err,
+		"resource plugin mock v3.0.0 for "+CurrentPluginPlatform()+" is not in plugin mirror")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&requests))
+}
+
+func TestPluginMirrorChecksums(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, err := w.Write([]byte("archive"))
+		assert.NoError(t, err)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	v1 := semver.MustParse("1.0.0")
+	checksum := sha256.Sum256([]byte("archive"))
+	spec := PluginSpec{
+		Kind:              ResourcePlugin,
+		Name:              "mock",
+		Version:           &v1,
+		PluginDownloadURL: server.URL,
+		Checksums:         map[string][]byte{"linux-amd64": {0x12, 0x34}, "linux-arm64": checksum[:]},
+	}
+
+	// Downloads that don't match the checksum aren't added to the mirror.
+	_, _, err := MirrorPlugin(dir, spec, "linux", "amd64", nil)
+	assert.ErrorContains(t, err, "invalid checksum")
+	_, err = os.Stat(PluginMirrorPath(dir, spec, "linux", "amd64"))
+	assert.True(t, os.IsNotExist(err))
+
+	_, downloaded, err := MirrorPlugin(dir, spec, "linux", "arm64", nil)
+	require.NoError(t, err)
+	assert.True(t, downloaded)
+
+	// Archives already in the mirror are verified too.
+	_, downloaded, err = MirrorPlugin(dir, spec, "linux", "arm64", nil)
+	require.NoError(t, err)
+	assert.False(t, downloaded)
+
+	spec.Checksums["linux-arm64"] = []byte{0xab, 0xcd}
+	_, _, err = MirrorPlugin(dir, spec, "linux", "arm64", nil)
+	assert.ErrorContains(t, err, "pulumi-resource-mock-v1.0.0-linux-arm64.tar.gz in plugin mirror: "+
+		"invalid checksum, expected abcd")
+}
@@ -0,0 +1,503 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/blang/semver"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+const (
+	ociManifestMediaType       = "application/vnd.oci.image.manifest.v1+json"
+	dockerManifestMediaType    = "application/vnd.docker.distribution.manifest.v2+json"
+	ociTitleAnnotation         = "org.opencontainers.image.title"
+	ociCredentialsNotFoundText = "credentials not found in native keychain"
+)
+
+// ociSource can download a plugin that is stored as an artifact in an OCI registry, such as Harbor, Zot or ECR.
+//
+// The plugin is expected in the repository <namespace>/pulumi-<kind>-<name> of an oci://<registry>/<namespace> url,
+// tagged with each of its versions (e.g. v1.2.3 or 1.2.3). The artifact has a layer for each platform's archive,
+// named by its org.opencontainers.image.title annotation as on get.pulumi.com, e.g.
+// pulumi-resource-aws-v1.2.3-linux-amd64.tar.gz. This is the layout that `oras push` produces when given the
+// archives.
+//
+// Registries are authenticated with the credentials that the Docker CLI would use, from its config file or
+// credential helpers. Registries on localhost are accessed over plain HTTP, as Docker does.
+type ociSource struct {
+	scheme     string
+	registry   string
+	repository string
+	name       string
+	kind       PluginKind
+
+	// credentials returns the username and password for a registry, or empty strings if there are none.
+	credentials func(registry string) (string, string, error)
+
+	m             sync.Mutex
+	authorization string
+}
+
+// Creates a new OCI source from an oci://<registry>[/<namespace>] url.
+func newOCISource(url *url.URL, name string, kind PluginKind) (*ociSource, error) {
+	contract.Requiref(url.Scheme == "oci", "url", `scheme must be "oci", was %q`, url.Scheme)
+
+	if url.Host == "" {
+		return nil, fmt.Errorf("oci:// url must have the format <registry>[/<namespace>], was: %s", url)
+	}
+
+	scheme := "https"
+	if host := url.Hostname(); host == "localhost" || net.ParseIP(host).IsLoopback() {
+		scheme = "http"
+	}
+
+	return &ociSource{
+		scheme:      scheme,
+		registry:    url.Host,
+		repository:  path.Join(strings.Trim(url.Path, "/"), fmt.Sprintf("pulumi-%s-%s", kind, name)),
+		name:        name,
+		kind:        kind,
+		credentials: dockerCredentials,
+	}, nil
+}
+
+// get issues a GET request against the registry's API, authenticating if the registry asks for it.
+func (source *ociSource) get(
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+	apiPath, accept string,
+) (io.ReadCloser, int64, error) {
+	return source.getURL(getHTTPResponse, source.apiURL(apiPath), accept)
+}
+
+// apiURL returns the URL of the given path in the registry's API for the plugin's repository.
+func (source *ociSource) apiURL(apiPath string) string {
+	return fmt.Sprintf("%s://%s/v2/%s/%s", source.scheme, source.registry, source.repository, apiPath)
+}
+
+// getURL issues a GET request for the given URL in the registry's API, authenticating if the registry asks for it.
+func (source *ociSource) getURL(
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+	endpoint, accept string,
+) (io.ReadCloser, int64, error) {
+	logging.V(9).Infof("plugin OCI registry url: %s", endpoint)
+
+	do := func() (io.ReadCloser, int64, error) {
+		source.m.Lock()
+		authorization := source.authorization
+		source.m.Unlock()
+
+		req, err := buildHTTPRequest(endpoint, authorization)
+		if err != nil {
+			return nil, -1, err
+		}
+		req.Header.Set("Accept", accept)
+		return getHTTPResponse(req)
+	}
+
+	resp, length, err := do()
+	var downErr *downloadError
+	if err == nil || !errors.As(err, &downErr) || downErr.code != http.StatusUnauthorized {
+		return resp, length, err
+	}
+
+	// The registry wants us to authenticate. Do so as it asks and try again.
+	challenge := downErr.header.Get("WWW-Authenticate")
+	if authErr := source.authenticate(getHTTPResponse, challenge); authErr != nil {
+		return nil, -1, fmt.Errorf("authenticating with %s: %w", source.registry, authErr)
+	}
+	return do()
+}
+
+// authenticate computes the Authorization header to use with the registry, given the challenge from its
+// WWW-Authenticate header.
+func (source *ociSource) authenticate(
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+	challenge string,
+) error {
+	scheme, params := parseAuthChallenge(challenge)
+
+	username, password, err := source.credentials(source.registry)
+	if err != nil {
+		return err
+	}
+	var basic string
+	if username != "" || password != "" {
+		basic = "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password))
+	}
+
+	var authorization string
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if basic == "" {
+			return fmt.Errorf("no credentials found; run `docker login %s`", source.registry)
+		}
+		authorization = basic
+	case "bearer":
+		// Exchange our credentials, if we have any, for a token from the registry's token service.
+		realm, err := url.Parse(params["realm"])
+		if err != nil || realm.Host == "" {
+			return fmt.Errorf("invalid token realm %q", params["realm"])
+		}
+		query := realm.Query()
+		if service, ok := params["service"]; ok {
+			query.Set("service", service)
+		}
+		scope := params["scope"]
+		if scope == "" {
+			scope = fmt.Sprintf("repository:%s:pull", source.repository)
+		}
+		query.Set("scope", scope)
+		realm.RawQuery = query.Encode()
+
+		req, err := buildHTTPRequest(realm.String(), basic)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, _, err := getHTTPResponse(req)
+		if err != nil {
+			return err
+		}
+		defer contract.IgnoreClose(resp)
+
+		var token struct {
+			Token       string `json:"token"`
+			AccessToken string `json:"access_token"`
+		}
+		if err := json.NewDecoder(resp).Decode(&token); err != nil {
+			return fmt.Errorf("cannot decode token response: %w", err)
+		}
+		if token.Token == "" {
+			token.Token = token.AccessToken
+		}
+		if token.Token == "" {
+			return errors.New("token response did not contain a token")
+		}
+		authorization = "Bearer " + token.Token
+	default:
+		return fmt.Errorf("unsupported authentication scheme %q", scheme)
+	}
+
+	source.m.Lock()
+	defer source.m.Unlock()
+	source.authorization = authorization
+	return nil
+}
+
+// parseAuthChallenge parses a WWW-Authenticate header such as
+// `Bearer realm="https://auth.example.com/token",service="registry.example.com"` into its scheme and parameters.
+func parseAuthChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := map[string]string{}
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimLeft(rest, ", ") {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		if strings.HasPrefix(value, `"`) {
+			// Quoted values may contain commas, so read up to the closing quote.
+			end := strings.Index(value[1:], `"`)
+			if end == -1 {
+				params[key] = value[1:]
+				break
+			}
+			params[key], rest = value[1:end+1], value[end+2:]
+		} else {
+			params[key], rest, _ = strings.Cut(value, ",")
+		}
+	}
+	return scheme, params
+}
+
+func (source *ociSource) GetLatestVersion(
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+) (*semver.Version, error) {
+	// Registries may paginate the tag list, in which case each page links to the next with a Link header.
+	var allTags []string
+	for next := source.apiURL("tags/list"); next != ""; {
+		tags, link, err := source.getTags(getHTTPResponse, next)
+		if err != nil {
+			return nil, err
+		}
+		allTags = append(allTags, tags...)
+
+		if next, err = ociNextPage(next, link); err != nil {
+			return nil, err
+		}
+	}
+
+	// Like GitHub's latest release, the latest version is the greatest that isn't a pre-release.
+	var latest *semver.Version
+	for _, tag := range allTags {
+		version, err := semver.ParseTolerant(tag)
+		if err != nil || len(version.Pre) > 0 {
+			continue
+		}
+		if latest == nil || version.GT(*latest) {
+			latest = &version
+		}
+	}
+	if latest == nil {
+		return nil, fmt.Errorf("no versions of %s found in %s/%s", source.name, source.registry, source.repository)
+	}
+	return latest, nil
+}
+
+// getTags fetches a page of the repository's tags, returning the tags and the page's Link header.
+func (source *ociSource) getTags(
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+	endpoint string,
+) ([]string, string, error) {
+	resp, length, err := source.getURL(getHTTPResponse, endpoint, "application/json")
+	if err != nil {
+		return nil, "", err
+	}
+	defer contract.IgnoreClose(resp)
+
+	var link string
+	if withHeader, ok := resp.(interface{ Header() http.Header }); ok {
+		link = withHeader.Header().Get("Link")
+	}
+
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+	if err = json.NewDecoder(resp).Decode(&tags); err != nil {
+		return nil, "", fmt.Errorf("cannot decode OCI tags response len(%d): %w", length, err)
+	}
+	return tags.Tags, link, nil
+}
+
+// ociNextPage returns the URL of the next page given the URL of the current page and its Link header, such as
+// `</v2/pulumi-resource-aws/tags/list?n=100&last=v1.2.3>; rel="next"`, or "" if there is no next page. Relative
+// links are resolved against the current page.
+func ociNextPage(current, link string) (string, error) {
+	for _, value := range strings.Split(link, ",") {
+		target, params, ok := strings.Cut(strings.TrimSpace(value), ";")
+		if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+			continue
+		}
+		for _, param := range strings.Split(params, ";") {
+			key, rel, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.ToLower(key) != "rel" || strings.Trim(rel, `"`) != "next" {
+				continue
+			}
+			base, err := url.Parse(current)
+			if err != nil {
+				return "", err
+			}
+			ref, err := url.Parse(target[1 : len(target)-1])
+			if err != nil {
+				return "", fmt.Errorf("invalid Link header %q: %w", link, err)
+			}
+			next := base.ResolveReference(ref).String()
+			if next == current {
+				return "", fmt.Errorf("invalid Link header %q: the next page is the current page", link)
+			}
+			return next, nil
+		}
+	}
+	return "", nil
+}
+
+// ociDescriptor describes content in an OCI registry.
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+func (source *ociSource) Download(
+	version semver.Version, opSy string, arch string,
+	getHTTPResponse func(*http.Request) (io.ReadCloser, int64, error),
+) (io.ReadCloser, int64, error) {
+	assetName := standardAssetName(source.name, source.kind, version, opSy, arch)
+	logging.V(1).Infof("%s downloading %s from %s/%s", source.name, assetName, source.registry, source.repository)
+
+	// Versions may be tagged with or without a leading "v".
+	accept := ociManifestMediaType + ", " + dockerManifestMediaType
+	resp, length, err := source.get(getHTTPResponse, "manifests/v"+version.String(), accept)
+	var downErr *downloadError
+	if errors.As(err, &downErr) && downErr.code == http.StatusNotFound {
+		resp, length, err = source.get(getHTTPResponse, "manifests/"+version.String(), accept)
+	}
+	if err != nil {
+		return nil, -1, err
+	}
+	defer contract.IgnoreClose(resp)
+
+	var manifest struct {
+		Layers []ociDescriptor `json:"layers"`
+	}
+	if err = json.NewDecoder(resp).Decode(&manifest); err != nil {
+		return nil, -1, fmt.Errorf("cannot decode OCI manifest len(%d): %w", length, err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[ociTitleAnnotation] != assetName {
+			continue
+		}
+
+		algorithm, encoded, _ := strings.Cut(layer.Digest, ":")
+		if algorithm != "sha256" {
+			return nil, -1, fmt.Errorf("unsupported digest %q for %s", layer.Digest, assetName)
+		}
+		digest, err := hex.DecodeString(encoded)
+		if err != nil || len(digest) != sha256.Size {
+			return nil, -1, fmt.Errorf("invalid digest %q for %s", layer.Digest, assetName)
+		}
+
+		blob, _, err := source.get(getHTTPResponse, "blobs/"+layer.Digest, "application/octet-stream")
+		if err != nil {
+			return nil, -1, err
+		}
+		return &ociBlobReader{
+			digest: digest,
+			reader: blob,
+			hasher: sha256.New(),
+		}, layer.Size, nil
+	}
+	return nil, -1, fmt.Errorf("%s/%s:v%s has no layer named %s", source.registry, source.repository, version, assetName)
+}
+
+// ociBlobReader verifies that a blob read from a registry matches its digest.
+type ociBlobReader struct {
+	digest []byte
+	reader io.ReadCloser
+	hasher hash.Hash
+}
+
+func (reader *ociBlobReader) Read(p []byte) (int, error) {
+	n, err := reader.reader.Read(p)
+	m, hashErr := reader.hasher.Write(p[0:n])
+	contract.AssertNoErrorf(hashErr, "error hashing input")
+	contract.Assertf(m == n, "wrote %d bytes, expected %d", m, n)
+
+	if err == io.EOF {
+		if actual := reader.hasher.Sum(nil); !bytes.Equal(reader.digest, actual) {
+			return n, fmt.Errorf("OCI blob digest mismatch, expected sha256:%x, got sha256:%x", reader.digest, actual)
+		}
+	}
+	return n, err
+}
+
+func (reader *ociBlobReader) Close() error {
+	return reader.reader.Close()
+}
+
+// dockerConfig is the subset of the Docker CLI's config.json that holds registry credentials.
+type dockerConfig struct {
+	Auths map[string]struct {
+		Auth string `json:"auth"`
+	} `json:"auths"`
+	CredsStore  string            `json:"credsStore"`
+	CredHelpers map[string]string `json:"credHelpers"`
+}
+
+// dockerCredentials returns the username and password that the Docker CLI would use for the given registry, from
+// its config file ($DOCKER_CONFIG/config.json or ~/.docker/config.json) or the credential helpers it configures. If
+// there are no credentials for the registry, empty strings are returned.
+func dockerCredentials(registry string) (string, string, error) {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", "", nil
+		}
+		configDir = filepath.Join(home, ".docker")
+	}
+
+	b, err := os.ReadFile(filepath.Join(configDir, "config.json"))
+	if os.IsNotExist(err) {
+		return "", "", nil
+	} else if err != nil {
+		return "", "", err
+	}
+	var config dockerConfig
+	if err := json.Unmarshal(b, &config); err != nil {
+		return "", "", fmt.Errorf("could not read Docker config: %w", err)
+	}
+
+	if helper, ok := config.CredHelpers[registry]; ok {
+		return dockerCredentialHelper(helper, registry)
+	}
+	for key, auth := range config.Auths {
+		// Keys may be URLs, e.g. https://registry.example.com/v1/, rather than plain hosts.
+		host := strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")
+		host, _, _ = strings.Cut(host, "/")
+		if host != registry || auth.Auth == "" {
+			continue
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return "", "", fmt.Errorf("could not read Docker credentials for %s: %w", registry, err)
+		}
+		username, password, _ := strings.Cut(string(decoded), ":")
+		return username, password, nil
+	}
+	if config.CredsStore != "" {
+		return dockerCredentialHelper(config.CredsStore, registry)
+	}
+	return "", "", nil
+}
+
+// dockerCredentialHelper gets the credentials for the given registry from a Docker credential helper, e.g.
+// docker-credential-ecr-login.
+func dockerCredentialHelper(helper, registry string) (string, string, error) {
+	var stdout, stderr bytes.Buffer
+	//nolint:gosec // The helper comes from the user's Docker config.
+	cmd := exec.Command("docker-credential-"+helper, "get")
+	cmd.Stdin = strings.NewReader(registry)
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	if err := cmd.Run(); err != nil {
+		output := strings.TrimSpace(stdout.String() + stderr.String())
+		if strings.Contains(output, ociCredentialsNotFoundText) {
+			return "", "", nil
+		}
+		return "", "", fmt.Errorf("docker-credential-%s: %w: %s", helper, err, output)
+	}
+
+	var credentials struct {
+		Username string `json:"Username"`
+		Secret   string `json:"Secret"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &credentials); err != nil {
+		return "", "", fmt.Errorf("could not read output of docker-credential-%s: %w", helper, err)
+	}
+	return credentials.Username, credentials.Secret, nil
+}
@@ -0,0 +1,287 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRegistry starts an in-process OCI registry holding the mock resource plugin in the "plugins" namespace.
+// Clients must get a token from the registry's token service with the username "user" and password "pass".
+func newTestRegistry(t *testing.T) *httptest.Server {
+	const repository = "plugins/pulumi-resource-mock"
+
+	blobs := map[string][]byte{}
+	layer := func(name string, content []byte) map[string]interface{} {
+		digest := fmt.Sprintf("sha256:%x", sha256.Sum256(content))
+		blobs[digest] = content
+		return map[string]interface{}{
+			"mediaType":   "application/vnd.oci.image.layer.v1.tar+gzip",
+			"digest":      digest,
+			"size":        len(content),
+			"annotations": map[string]string{"org.opencontainers.image.title": name},
+		}
+	}
+	manifest := func(layers ...map[string]interface{}) []byte {
+		b, err := json.Marshal(map[string]interface{}{
+			"schemaVersion": 2,
+			"mediaType":     ociManifestMediaType,
+			"layers":        layers,
+		})
+		require.NoError(t, err)
+		return b
+	}
+
+	// Tampering with the darwin-arm64 blob after computing its digest makes it fail verification.
+	tampered := layer("pulumi-resource-mock-v1.2.0-darwin-arm64.tar.gz", []byte("darwin"))
+	manifests := map[string][]byte{
+		"1.0.0": manifest(layer("pulumi-resource-mock-v1.0.0-linux-amd64.tar.gz", []byte("v1.0.0"))),
+		"v1.2.0": manifest(
+			layer("pulumi-resource-mock-v1.2.0-linux-amd64.tar.gz", []byte("linux")),
+			tampered,
+		),
+	}
+	blobs[tampered["digest"].(string)] = []byte("tampered")
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			assert.Equal(t, "test-registry", r.URL.Query().Get("service"))
+			assert.Equal(t, "repository:"+repository+":pull", r.URL.Query().Get("scope"))
+			_, err := w.Write([]byte(`{"token": "test-token"}`))
+			assert.NoError(t, err)
+			return
+		}
+
+		if r.Header.Get("Authorization") != "Bearer test-token" {
+			w.Header().Set("WWW-Authenticate",
+				fmt.Sprintf(`Bearer realm="%s/token",service="test-registry"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var content []byte
+		var ok bool
+		switch p := strings.TrimPrefix(r.URL.Path, "/v2/"+repository+"/"); {
+		case p == "tags/list" && r.URL.Query().Get("last") == "":
+			// The registry paginates tags, so the latest version is only found by following the Link header.
+			w.Header().Set("Link", fmt.Sprintf(`</v2/%s/tags/list?n=2&last=1.0.0>; rel="next"`, repository))
+			content, ok = []byte(`{"tags": ["0.1.0", "1.0.0"]}`), true
+		case p == "tags/list":
+			assert.Equal(t, "1.0.0", r.URL.Query().Get("last"))
+			content, ok = []byte(`{"tags": ["v1.2.0", "v2.0.0-alpha", "latest"]}`), true
+		case strings.HasPrefix(p, "manifests/"):
+			assert.Contains(t, r.Header.Get("Accept"), ociManifestMediaType)
+			content, ok = manifests[strings.TrimPrefix(p, "manifests/")]
+		case strings.HasPrefix(p, "blobs/"):
+			content, ok = blobs[strings.TrimPrefix(p, "blobs/")]
+		}
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, err := w.Write(content)
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestOCISource(t *testing.T, server *httptest.Server, username, password string) *ociSource {
+	spec := PluginSpec{
+		Kind:              ResourcePlugin,
+		Name:              "mock",
+		PluginDownloadURL: "oci://" + strings.TrimPrefix(server.URL, "http://") + "/plugins",
+	}
+	source, err := spec.GetSource()
+	require.NoError(t, err)
+	require.IsType(t, &ociSource{}, source)
+
+	oci := source.(*ociSource)
+	assert.Equal(t, "http", oci.scheme)
+	oci.credentials = func(registry string) (string, string, error) {
+		assert.Equal(t, strings.TrimPrefix(server.URL, "http://"), registry)
+		return username, password, nil
+	}
+	return oci
+}
+
+func TestOCISource(t *testing.T) {
+	t.Parallel()
+
+	server := newTestRegistry(t)
+
+	t.Run("GetLatestVersion", func(t *testing.T) {
+		t.Parallel()
+
+		source := newTestOCISource(t, server, "user", "pass")
+		version, err := source.GetLatestVersion(getHTTPResponse)
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.0", version.String())
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		t.Parallel()
+
+		source := newTestOCISource(t, server, "user", "pass")
+		for version, expected := range map[string]string{"1.2.0": "linux", "1.0.0": "v1.0.0"} {
+			r, length, err := source.Download(semver.MustParse(version), "linux", "amd64", getHTTPResponse)
+			require.NoError(t, err)
+			b, err := io.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, expected, string(b))
+			assert.Equal(t, int64(len(expected)), length)
+		}
+	})
+
+	t.Run("DigestMismatch", func(t *testing.T) {
+		t.Parallel()
+
+		source := newTestOCISource(t, server, "user", "pass")
+		r, _, err := source.Download(semver.MustParse("1.2.0"), "darwin", "arm64", getHTTPResponse)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.ErrorContains(t, err, "OCI blob digest mismatch")
+	})
+
+	t.Run("MissingPlatform", func(t *testing.T) {
+		t.Parallel()
+
+		source := newTestOCISource(t, server, "user", "pass")
+		_, _, err := source.Download(semver.MustParse("1.2.0"), "windows", "amd64", getHTTPResponse)
+		assert.ErrorContains(t, err, "has no layer named pulumi-resource-mock-v1.2.0-windows-amd64.tar.gz")
+	})
+
+	t.Run("BadCredentials", func(t *testing.T) {
+		t.Parallel()
+
+		source := newTestOCISource(t, server, "user", "wrong")
+		_, err := source.GetLatestVersion(getHTTPResponse)
+		assert.ErrorContains(t, err, "authenticating with")
+		assert.ErrorContains(t, err, "401 HTTP error")
+	})
+}
+
+func TestOCISourceURL(t *testing.T) {
+	t.Parallel()
+
+	source, err := newOCISource(urlMustParse("oci://registry.example.com/team/plugins/"), "aws", ResourcePlugin)
+	require.NoError(t, err)
+	assert.Equal(t, "https", source.scheme)
+	assert.Equal(t, "registry.example.com", source.registry)
+	assert.Equal(t, "team/plugins/pulumi-resource-aws", source.repository)
+
+	source, err = newOCISource(urlMustParse("oci://localhost:5000"), "aws", ResourcePlugin)
+	require.NoError(t, err)
+	assert.Equal(t, "http", source.scheme)
+	assert.Equal(t, "pulumi-resource-aws", source.repository)
+
+	_, err = newOCISource(urlMustParse("oci:///plugins"), "aws", ResourcePlugin)
+	assert.ErrorContains(t, err, "oci:// url must have the format <registry>[/<namespace>]")
+}
+
+func TestParseAuthChallenge(t *testing.T) {
+	t.Parallel()
+
+	scheme, params := parseAuthChallenge(
+		`Bearer realm="https://auth.example.com/token",service="registry.example.com",` +
+			`scope="repository:a:pull,push"`)
+	assert.Equal(t, "Bearer", scheme)
+	assert.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a:pull,push",
+	}, params)
+
+	scheme, params = parseAuthChallenge(`Basic realm=registry`)
+	assert.Equal(t, "Basic", scheme)
+	assert.Equal(t, map[string]string{"realm": "registry"}, params)
+}
+
+func TestOCINextPage(t *testing.T) {
+	t.Parallel()
+
+	current := "https://registry.example.com/v2/a/tags/list"
+	next, err := ociNextPage(current, `<https://cdn.example.com/v2/a/tags/list?last=b>; rel="prev", `+
+		`</v2/a/tags/list?n=2&last=c>; rel="next"`)
+	require.NoError(t, err)
+	assert.Equal(t, "https://registry.example.com/v2/a/tags/list?n=2&last=c", next)
+
+	next, err = ociNextPage(current, "")
+	require.NoError(t, err)
+	assert.Empty(t, next)
+
+	_, err = ociNextPage(current, `</v2/a/tags/list>; rel=next`)
+	assert.ErrorContains(t, err, "the next page is the current page")
+}
+
+//nolint:paralleltest // mutates environment variables
+func TestDockerCredentials(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("DOCKER_CONFIG", dir)
+
+	// Without a config file there are no credentials.
+	username, password, err := dockerCredentials("registry.example.com")
+	require.NoError(t, err)
+	assert.Empty(t, username)
+	assert.Empty(t, password)
+
+	config := `{
+		"auths": {"https://registry.example.com/v1/": {"auth": "dXNlcjpwYXNz"}},
+		"credHelpers": {"helped.example.com": "test"}
+	}`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "config.json"), []byte(config), 0o600))
+
+	username, password, err = dockerCredentials("registry.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "user", username)
+	assert.Equal(t, "pass", password)
+
+	username, password, err = dockerCredentials("other.example.com")
+	require.NoError(t, err)
+	assert.Empty(t, username)
+	assert.Empty(t, password)
+
+	if runtime.GOOS == "windows" {
+		t.Skip("credential helper script requires a POSIX shell")
+	}
+	helper := "#!/bin/sh\nread registry\n" +
+		`echo "{\"ServerURL\": \"$registry\", \"Username\": \"helper\", \"Secret\": \"$registry\"}"` + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "docker-credential-test"), []byte(helper), 0o700)) //nolint:gosec
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+
+	username, password, err = dockerCredentials("helped.example.com")
+	require.NoError(t, err)
+	assert.Equal(t, "helper", username)
+	assert.Equal(t, "helped.example.com", password)
+}
@@ -0,0 +1,199 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/util/logging"
+)
+
+// BuildPluginFromSource builds the plugin whose source is in the given directory, and returns the content to install
+// into the plugin cache. The spec must have a version, which the build is given as $PULUMI_PLUGIN_VERSION. The source
+// is copied to a temporary directory and built there, so the build doesn't modify it.
+//
+// If the directory has a PulumiPlugin.yaml with build instructions, they're followed. Otherwise the language of the
+// source is detected:
+//
+//   - Go modules, at the root or in a provider directory, are built with `go build`. The main package is
+//     cmd/pulumi-<kind>-<name> if there is one, and the module's root otherwise.
+//   - Node.js packages have their dependencies installed and their build script, if any, run. The package is installed
+//     and run by the nodejs runtime.
+//   - Python projects with a requirements.txt are installed and run by the python runtime.
+//
+// The output of the build is written to stdout and stderr.
+func BuildPluginFromSource(
+	ctx context.Context, dir string, spec PluginSpec, stdout, stderr io.Writer,
+) (PluginContent, error) {
+	contract.Requiref(spec.Version != nil, "spec", "must have a version")
+
+	tmp, err := os.MkdirTemp("", "pulumi-plugin-build-")
+	if err != nil {
+		return nil, err
+	}
+	content, err := buildPluginFromSource(ctx, dir, tmp, spec, stdout, stderr)
+	if err != nil {
+		contract.IgnoreError(os.RemoveAll(tmp))
+		return nil, err
+	}
+	return content, nil
+}
+
+// buildPluginFromSource builds the plugin whose source is in dir in the temporary directory tmp. The returned
+// content removes tmp when it's closed.
+func buildPluginFromSource(
+	ctx context.Context, dir, tmp string, spec PluginSpec, stdout, stderr io.Writer,
+) (PluginContent, error) {
+	build := func(command string, args ...string) func(dir string) error {
+		return func(dir string) error {
+			logging.V(5).Infof("building %s plugin %s: %s %v", spec.Kind, spec.Name, command, args)
+			cmd := exec.CommandContext(ctx, command, args...)
+			cmd.Dir = dir
+			cmd.Env = append(os.Environ(), "PULUMI_PLUGIN_VERSION="+spec.Version.String())
+			cmd.Stdout, cmd.Stderr = stdout, stderr
+			if err := cmd.Run(); err != nil {
+				return fmt.Errorf("running %s %v: %w", command, args, err)
+			}
+			return nil
+		}
+	}
+
+	// Copy the source, without its .git directory, to build it.
+	src := filepath.Join(tmp, "src")
+	if err := os.Mkdir(src, 0o700); err != nil {
+		return nil, err
+	}
+	if err := (dirPlugin{Root: dir, skipGit: true}).writeToDir(src); err != nil {
+		return nil, fmt.Errorf("copying plugin source: %w", err)
+	}
+
+	proj, err := LoadPluginProject(filepath.Join(src, "PulumiPlugin.yaml"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("loading PulumiPlugin.yaml: %w", err)
+	}
+
+	if proj != nil && proj.Build != nil {
+		for _, command := range proj.Build.Commands {
+			run := build("sh", "-c", command)
+			if runtime.GOOS == windowsGOOS {
+				run = build("cmd", "/C", command)
+			}
+			if err := run(src); err != nil {
+				return nil, err
+			}
+		}
+		if proj.Build.Binary == "" {
+			return builtPlugin{PluginContent: DirPlugin(src), tmp: tmp}, nil
+		}
+		f, err := os.Open(filepath.Join(src, proj.Build.Binary))
+		if err != nil {
+			return nil, fmt.Errorf("opening built plugin: %w", err)
+		}
+		return builtPlugin{PluginContent: SingleFilePlugin(f, spec), tmp: tmp}, nil
+	}
+
+	exists := func(path string) bool {
+		_, err := os.Stat(path)
+		return err == nil
+	}
+
+	// Go plugins are built from their module, unless a PulumiPlugin.yaml says to run them with the go runtime.
+	for _, root := range []string{src, filepath.Join(src, "provider")} {
+		if proj != nil || !exists(filepath.Join(root, "go.mod")) {
+			continue
+		}
+
+		main := "."
+		binary := fmt.Sprintf("pulumi-%s-%s", spec.Kind, spec.Name)
+		if exists(filepath.Join(root, "cmd", binary)) {
+			main = "./" + filepath.ToSlash(filepath.Join("cmd", binary))
+		}
+
+		out := filepath.Join(tmp, binary)
+		if runtime.GOOS == windowsGOOS {
+			out += ".exe"
+		}
+		if err := build("go", "build", "-o", out, main)(root); err != nil {
+			return nil, err
+		}
+		f, err := os.Open(out)
+		if err != nil {
+			return nil, err
+		}
+		return builtPlugin{PluginContent: SingleFilePlugin(f, spec), tmp: tmp}, nil
+	}
+
+	runtimeName := ""
+	if proj != nil {
+		runtimeName = proj.Runtime.Name()
+	} else if exists(filepath.Join(src, "package.json")) {
+		runtimeName = "nodejs"
+	} else if exists(filepath.Join(src, "requirements.txt")) {
+		runtimeName = "python"
+	}
+	plugin := builtPlugin{PluginContent: DirPlugin(src), tmp: tmp}
+	switch runtimeName {
+	case "nodejs":
+		// The build needs the package's development dependencies, which are then pruned. Installing the plugin
+		// doesn't install its dependencies again.
+		for _, args := range [][]string{{"install"}, {"run", "build", "--if-present"}, {"prune", "--production"}} {
+			if err := build("npm", args...)(src); err != nil {
+				return nil, err
+			}
+		}
+		plugin.installedDependencies = true
+	case "":
+		return nil, fmt.Errorf("could not detect how to build the plugin in %s; "+
+			"add build instructions to its PulumiPlugin.yaml", dir)
+	}
+
+	if proj == nil {
+		proj := &PluginProject{Runtime: NewProjectRuntimeInfo(runtimeName, nil)}
+		if err := save(filepath.Join(src, "PulumiPlugin.yaml"), proj, false /*mkDirAll*/); err != nil {
+			return nil, err
+		}
+	}
+	return plugin, nil
+}
+
+// builtPlugin is a plugin built in a temporary directory, which is removed when the plugin is closed.
+type builtPlugin struct {
+	PluginContent
+
+	tmp string
+	// installedDependencies is true if the plugin's dependencies were installed when it was built.
+	installedDependencies bool
+}
+
+func (p builtPlugin) Close() error {
+	err := p.PluginContent.Close()
+	if rmErr := os.RemoveAll(p.tmp); err == nil {
+		err = rmErr
+	}
+	return err
+}
+
+// dependenciesInstalled returns true if the given plugin content already has its dependencies installed.
+func dependenciesInstalled(content PluginContent) bool {
+	p, ok := content.(builtPlugin)
+	return ok && p.installedDependencies
+}
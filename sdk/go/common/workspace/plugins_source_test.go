@@ -0,0 +1,149 @@
+// Copyright 2016-2023, Pulumi Corporation.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package workspace
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/blang/semver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for path, content := range files {
+		path = filepath.Join(dir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestBuildPluginFromSource(t *testing.T) {
+	t.Parallel()
+
+	version := semver.MustParse("1.2.3")
+	spec := PluginSpec{Kind: ResourcePlugin, Name: "mock", Version: &version}
+
+	buildDir := func(t *testing.T, src string) (string, error) {
+		content, err := BuildPluginFromSource(context.Background(), src, spec, io.Discard, io.Discard)
+		if err != nil {
+			return "", err
+		}
+		defer func() { assert.NoError(t, content.Close()) }()
+		dst := t.TempDir()
+		require.NoError(t, content.writeToDir(dst))
+		return dst, nil
+	}
+	build := func(t *testing.T, files map[string]string) (string, error) {
+		src := t.TempDir()
+		writeTestFiles(t, src, files)
+		return buildDir(t, src)
+	}
+
+	t.Run("BuildCommands", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == windowsGOOS {
+			t.Skip("build commands are run by cmd on Windows")
+		}
+
+		dst, err := build(t, map[string]string{
+			"PulumiPlugin.yaml": "runtime: go\n" +
+				"build:\n" +
+				"  commands:\n" +
+				"    - mkdir bin\n" +
+				"    - echo \"$PULUMI_PLUGIN_VERSION\" > bin/provider\n" +
+				"  binary: bin/provider\n",
+		})
+		require.NoError(t, err)
+		b, err := os.ReadFile(filepath.Join(dst, "pulumi-resource-mock"))
+		require.NoError(t, err)
+		assert.Equal(t, "1.2.3\n", string(b))
+	})
+
+	t.Run("BuildInCopy", func(t *testing.T) {
+		t.Parallel()
+		if runtime.GOOS == windowsGOOS {
+			t.Skip("build commands are run by cmd on Windows")
+		}
+
+		src := t.TempDir()
+		writeTestFiles(t, src, map[string]string{
+			"PulumiPlugin.yaml": "runtime: nodejs\n" +
+				"build:\n" +
+				"  commands:\n" +
+				"    - echo built > lib/index.js\n",
+			"lib/.keep":  "",
+			".git/HEAD":  "ref: refs/heads/main\n",
+			"index.d.ts": "",
+		})
+		require.NoError(t, os.Symlink("index.d.ts", filepath.Join(src, "types.d.ts")))
+
+		dst, err := buildDir(t, src)
+		require.NoError(t, err)
+
+		// The build's output is installed, but the source isn't changed.
+		b, err := os.ReadFile(filepath.Join(dst, "lib", "index.js"))
+		require.NoError(t, err)
+		assert.Equal(t, "built\n", string(b))
+		assert.NoFileExists(t, filepath.Join(src, "lib", "index.js"))
+		assert.NoDirExists(t, filepath.Join(dst, ".git"))
+
+		// Symlinks are preserved.
+		target, err := os.Readlink(filepath.Join(dst, "types.d.ts"))
+		require.NoError(t, err)
+		assert.Equal(t, "index.d.ts", target)
+	})
+
+	t.Run("Go", func(t *testing.T) {
+		t.Parallel()
+
+		dst, err := build(t, map[string]string{
+			"provider/go.mod": "module example.com/mock\n\ngo 1.18\n",
+			"provider/cmd/pulumi-resource-mock/main.go": "package main\n\nfunc main() {}\n",
+		})
+		require.NoError(t, err)
+		info, err := os.Stat(filepath.Join(dst, "pulumi-resource-mock"))
+		require.NoError(t, err)
+		assert.NotZero(t, info.Size())
+	})
+
+	t.Run("Python", func(t *testing.T) {
+		t.Parallel()
+
+		dst, err := build(t, map[string]string{
+			"requirements.txt": "",
+			"__main__.py":      "print('mock')\n",
+			".git/HEAD":        "ref: refs/heads/main\n",
+		})
+		require.NoError(t, err)
+		proj, err := LoadPluginProject(filepath.Join(dst, "PulumiPlugin.yaml"))
+		require.NoError(t, err)
+		assert.Equal(t, "python", proj.Runtime.Name())
+		assert.FileExists(t, filepath.Join(dst, "__main__.py"))
+		assert.NoDirExists(t, filepath.Join(dst, ".git"))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := build(t, map[string]string{"README.md": ""})
+		assert.ErrorContains(t, err, "could not detect how to build the plugin")
+	})
+}
@@ -1226,3 +1226,35 @@ func TestSymlinkPathPluginsDoNotWarn(t *testing.T) {
 	assert.Equal(t, ambientPath, path)
 	assert.Empty(t, stderr.String())
 }
+
+//nolint:paralleltest // mutates environment variables
+func TestRecordPluginUse(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("PULUMI_HOME", home)
+
+	dir := filepath.Join(home, "plugins", "resource-mock-v1.0.0")
+	require.NoError(t, os.MkdirAll(dir, 0o700))
+	bin := filepath.Join(dir, "pulumi-resource-mock")
+	require.NoError(t, os.WriteFile(bin, nil, 0o600))
+
+	// Binaries outside of the plugin cache aren't recorded.
+	other := filepath.Join(t.TempDir(), "pulumi-resource-mock")
+	require.NoError(t, RecordPluginUse(other))
+	_, err := os.Stat(filepath.Join(filepath.Dir(other), pluginLastUsedFile))
+	assert.True(t, os.IsNotExist(err))
+
+	// Recording a use creates the marker, and then updates it.
+	require.NoError(t, RecordPluginUse(bin))
+	past := time.Now().Add(-48 * time.Hour)
+	require.NoError(t, os.Chtimes(filepath.Join(dir, pluginLastUsedFile), past, past))
+	plugins, err := GetPluginsWithMetadata()
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.WithinDuration(t, past, plugins[0].LastUsedTime, time.Second)
+
+	require.NoError(t, RecordPluginUse(bin))
+	plugins, err = GetPluginsWithMetadata()
+	require.NoError(t, err)
+	require.Len(t, plugins, 1)
+	assert.WithinDuration(t, time.Now(), plugins[0].LastUsedTime, time.Minute)
+}
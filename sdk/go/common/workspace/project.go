@@ -28,6 +28,7 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/encoding"
+	"github.com/pulumi/pulumi/sdk/v3/go/common/resource"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/resource/config"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/tokens"
 	"github.com/pulumi/pulumi/sdk/v3/go/common/util/contract"
@@ -104,6 +105,21 @@ type Plugins struct {
 	Providers []PluginOptions `json:"providers,omitempty" yaml:"providers,omitempty"`
 	Languages []PluginOptions `json:"languages,omitempty" yaml:"languages,omitempty"`
 	Analyzers []PluginOptions `json:"analyzers,omitempty" yaml:"analyzers,omitempty"`
+	// Mirror is a directory of plugin archives, relative to the project, to install plugins from rather than
+	// downloading them. It is used unless PULUMI_PLUGIN_MIRROR is set. See MirrorDir.
+	Mirror string `json:"mirror,omitempty" yaml:"mirror,omitempty"`
+}
+
+// MirrorDir returns the absolute path of the project's plugin mirror, given the project's root directory, or "" if
+// the project doesn't have one.
+func (plugins *Plugins) MirrorDir(root string) string {
+	if plugins == nil || plugins.Mirror == "" {
+		return ""
+	}
+	if filepath.IsAbs(plugins.Mirror) {
+		return plugins.Mirror
+	}
+	return filepath.Join(root, plugins.Mirror)
 }
 
 type ProjectConfigItemsType struct {
@@ -564,6 +580,17 @@ func (proj *PolicyPackProject) Save(path string) error {
 type PluginProject struct {
 	// Runtime is a required runtime that executes code.
 	Runtime ProjectRuntimeInfo `json:"runtime" yaml:"runtime"`
+	// Build is an optional set of instructions to build the plugin from source.
+	Build *PluginBuild `json:"build,omitempty" yaml:"build,omitempty"`
+}
+
+// PluginBuild describes how to build a plugin from source, for `pulumi plugin install --from`.
+type PluginBuild struct {
+	// Commands are run in order, by the shell, in the plugin's directory.
+	Commands []string `json:"commands,omitempty" yaml:"commands,omitempty"`
+	// Binary is the path, relative to the plugin's directory, of the plugin binary that the commands build. If it's
+	// empty, the whole directory is installed, and the plugin is run by its runtime.
+	Binary string `json:"binary,omitempty" yaml:"binary,omitempty"`
 }
 
 func (proj *PluginProject) Validate() error {
@@ -625,11 +652,69 @@ type ProjectStack struct {
 	Config config.Map `json:"config,omitempty" yaml:"config,omitempty"`
 	// Environment is an optional environment definition or list of environments.
 	Environment *Environment `json:"environment,omitempty" yaml:"environment,omitempty"`
+	// IgnoreChanges are optional default ignoreChanges rules for the stack's resources.
+	IgnoreChanges *StackIgnoreChanges `json:"ignoreChanges,omitempty" yaml:"ignoreChanges,omitempty"`
+	// Parallelism optionally limits the number of concurrent operations on particular providers' resources, and on
+	// resources of particular types.
+	Parallelism *StackParallelism `json:"parallelism,omitempty" yaml:"parallelism,omitempty"`
 
 	// The original byte representation of the file, used to attempt trivia-preserving edits
 	raw []byte
 }
 
+// StackIgnoreChanges are the default ignoreChanges rules for a stack's resources. They apply in addition to the rules
+// in each resource's ignoreChanges option, and are property paths in the same form, e.g. "tags.*".
+type StackIgnoreChanges struct {
+	// All are the rules that apply to every custom resource in the stack, other than providers.
+	All []string `json:"all,omitempty" yaml:"all,omitempty"`
+	// Providers are the rules that apply to the resources of each provider, keyed by the provider's package name, e.g.
+	// "aws".
+	Providers map[string][]string `json:"providers,omitempty" yaml:"providers,omitempty"`
+}
+
+func (ic *StackIgnoreChanges) validate() error {
+	if ic == nil {
+		return nil
+	}
+	rules := append([]string(nil), ic.All...)
+	for _, providerRules := range ic.Providers {
+		rules = append(rules, providerRules...)
+	}
+	for _, rule := range rules {
+		if _, err := resource.ParsePropertyPath(rule); err != nil {
+			return fmt.Errorf("invalid ignoreChanges rule %q: %w", rule, err)
+		}
+	}
+	return nil
+}
+
+// StackParallelism limits the number of operations that run concurrently on a stack's resources, per provider package
+// and per resource type. These limits apply in addition to the limit on all operations, so that a rate-limited
+// provider can be given a low limit without slowing down the rest of the stack.
+type StackParallelism struct {
+	// Providers are the limits for the resources of each provider, keyed by the provider's package name, e.g. "aws".
+	Providers map[string]int `json:"providers,omitempty" yaml:"providers,omitempty"`
+	// Types are the limits for the resources of each type, keyed by the type's token, e.g. "aws:s3/bucket:Bucket".
+	Types map[string]int `json:"types,omitempty" yaml:"types,omitempty"`
+}
+
+func (p *StackParallelism) validate() error {
+	if p == nil {
+		return nil
+	}
+	for pkg, limit := range p.Providers {
+		if limit <= 0 {
+			return fmt.Errorf("invalid parallelism for provider %q: must be a positive number, got %d", pkg, limit)
+		}
+	}
+	for typ, limit := range p.Types {
+		if limit <= 0 {
+			return fmt.Errorf("invalid parallelism for type %q: must be a positive number, got %d", typ, limit)
+		}
+	}
+	return nil
+}
+
 func (ps ProjectStack) EnvironmentBytes() []byte {
 	switch {
 	case ps.Environment == nil:
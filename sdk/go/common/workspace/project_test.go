@@ -573,6 +573,73 @@ config:
 	assert.Equal(t, "true", getConfigValue(t, stack.Config, "test:createVpc"))
 }
 
+func TestStackIgnoreChanges(t *testing.T) {
+	t.Parallel()
+	projectYaml := `
+name: test
+runtime: dotnet`
+
+	projectStackYaml := `
+ignoreChanges:
+  all:
+    - tags.lastModified
+  providers:
+    aws:
+      - tags.*
+      - rules[*].description`
+
+	project, projectError := loadProjectFromText(t, projectYaml)
+	assert.NoError(t, projectError, "Should be able to load the project")
+	stack, stackError := loadProjectStackFromText(t, project, projectStackYaml)
+	assert.NoError(t, stackError, "Should be able to read the stack")
+	assert.Equal(t, &StackIgnoreChanges{
+		All: []string{"tags.lastModified"},
+		Providers: map[string][]string{
+			"aws": {"tags.*", "rules[*].description"},
+		},
+	}, stack.IgnoreChanges)
+
+	invalidStackYaml := `
+ignoreChanges:
+  providers:
+    aws:
+      - tags[`
+
+	_, stackError = loadProjectStackFromText(t, project, invalidStackYaml)
+	assert.ErrorContains(t, stackError, `invalid ignoreChanges rule "tags["`)
+}
+
+func TestStackParallelism(t *testing.T) {
+	t.Parallel()
+	projectYaml := `
+name: test
+runtime: dotnet`
+
+	projectStackYaml := `
+parallelism:
+  providers:
+    aws: 2
+  types:
+    aws:s3/bucket:Bucket: 1`
+
+	project, projectError := loadProjectFromText(t, projectYaml)
+	assert.NoError(t, projectError, "Should be able to load the project")
+	stack, stackError := loadProjectStackFromText(t, project, projectStackYaml)
+	assert.NoError(t, stackError, "Should be able to read the stack")
+	assert.Equal(t, &StackParallelism{
+		Providers: map[string]int{"aws": 2},
+		Types:     map[string]int{"aws:s3/bucket:Bucket": 1},
+	}, stack.Parallelism)
+
+	invalidStackYaml := `
+parallelism:
+  providers:
+    aws: 0`
+
+	_, stackError = loadProjectStackFromText(t, project, invalidStackYaml)
+	assert.ErrorContains(t, stackError, `invalid parallelism for provider "aws"`)
+}
+
 func TestNamespacedProjectConfigShouldNotBeExplicitlyTyped(t *testing.T) {
 	t.Parallel()
 	projectYaml := `
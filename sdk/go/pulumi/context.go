@@ -64,6 +64,8 @@ type Context struct {
 	keepOutputValues    bool       // true if outputs should be marshaled as strongly-type output values.
 	supportsDeletedWith bool       // true if deletedWith supported by pulumi
 	supportsAliasSpecs  bool       // true if full alias specification is supported by pulumi
+	supportsParallelism bool       // true if the parallelism option is supported by pulumi
+	supportsRetries     bool       // true if the retries option is supported by pulumi
 	rpcs                int        // the number of outstanding RPC requests.
 	rpcsDone            *sync.Cond // an event signaling completion of RPCs.
 	rpcsLock            sync.Mutex // a lock protecting the RPC count and event.
@@ -150,6 +152,16 @@ func NewContext(ctx context.Context, info RunInfo) (*Context, error) {
 		return nil, err
 	}
 
+	supportsParallelism, err := supportsFeature("parallelism")
+	if err != nil {
+		return nil, err
+	}
+
+	supportsRetries, err := supportsFeature("retries")
+	if err != nil {
+		return nil, err
+	}
+
 	context := &Context{
 		ctx:                 ctx,
 		info:                info,
@@ -162,6 +174,8 @@ func NewContext(ctx context.Context, info RunInfo) (*Context, error) {
 		keepOutputValues:    keepOutputValues,
 		supportsDeletedWith: supportsDeletedWith,
 		supportsAliasSpecs:  supportsAliasSpecs,
+		supportsParallelism: supportsParallelism,
+		supportsRetries:     supportsRetries,
 	}
 	context.rpcsDone = sync.NewCond(&context.rpcsLock)
 	context.Log = &logState{
@@ -860,6 +874,14 @@ func (ctx *Context) registerResource(
 		}
 	}
 
+	if options.Parallelism > 0 && !ctx.supportsParallelism {
+		return errors.New("the Pulumi CLI does not support the Parallelism option. Please update the Pulumi CLI")
+	}
+
+	if options.Retries > 0 && !ctx.supportsRetries {
+		return errors.New("the Pulumi CLI does not support the Retries option. Please update the Pulumi CLI")
+	}
+
 	// Note that we're about to make an outstanding RPC request, so that we can rendezvous during shutdown.
 	if err := ctx.beginRPC(); err != nil {
 		return err
@@ -956,6 +978,8 @@ func (ctx *Context) registerResource(
 				RetainOnDelete:          inputs.retainOnDelete,
 				DeletedWith:             inputs.deletedWith,
 				SourcePosition:          sourcePosition,
+				Parallelism:             inputs.parallelism,
+				Retries:                 inputs.retries,
 			})
 			if err != nil {
 				logging.V(9).Infof("RegisterResource(%s, %s): error: %v", t, name, err)
@@ -1355,6 +1379,8 @@ type resourceInputs struct {
 	replaceOnChanges        []string
 	retainOnDelete          bool
 	deletedWith             string
+	parallelism             int32
+	retries                 int32
 }
 
 func (ctx *Context) resolveAliasParent(alias Alias, spec *pulumirpc.Alias_Spec) error {
@@ -1620,6 +1646,8 @@ func (ctx *Context) prepareResourceInputs(res Resource, props Input, t string, o
 		replaceOnChanges:        resOpts.replaceOnChanges,
 		retainOnDelete:          opts.RetainOnDelete,
 		deletedWith:             string(deletedWithURN),
+		parallelism:             int32(opts.Parallelism),
+		retries:                 int32(opts.Retries),
 	}, nil
 }
 
@@ -562,6 +562,41 @@ func (c *resmonClientWithFeatures) SupportsFeature(
 	return c.ResourceMonitorClient.SupportsFeature(ctx, req, opts...)
 }
 
+func TestUnsupportedResourceOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		feature string
+		opt     ResourceOption
+	}{
+		{"parallelism", Parallelism(2)},
+		{"retries", Retries(3)},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.feature, func(t *testing.T) {
+			t.Parallel()
+
+			registered := false
+			monitor := &testMonitor{
+				NewResourceF: func(args MockResourceArgs) (string, resource.PropertyMap, error) {
+					registered = true
+					return args.Name, resource.PropertyMap{}, nil
+				},
+			}
+			err := RunErr(func(ctx *Context) error {
+				var res testResource2
+				return ctx.RegisterResource("test:resource:type", "res", &testResource2Inputs{}, &res, tt.opt)
+			}, WithMocks("project", "stack", monitor), WrapResourceMonitorClient(
+				func(rmc pulumirpc.ResourceMonitorClient) pulumirpc.ResourceMonitorClient {
+					return resourceMonitorClientWithoutFeatures(rmc, tt.feature)
+				}))
+			assert.ErrorContains(t, err, "the Pulumi CLI does not support the")
+			assert.False(t, registered)
+		})
+	}
+}
+
 func TestSourcePosition(t *testing.T) {
 	t.Parallel()
 
@@ -389,6 +389,16 @@ type ResourceOptions struct {
 	// DeletedWith holds a container resource that, if deleted,
 	// also deletes this resource.
 	DeletedWith Resource
+
+	// Parallelism is the maximum number of concurrent operations
+	// on the resources managed by this provider resource.
+	// This will be zero if the operations are not limited.
+	Parallelism int
+
+	// Retries is the maximum number of times to retry a create or update
+	// of this resource that fails with a transient error.
+	// This will be zero if the deployment's retry policy applies.
+	Retries int
 }
 
 // NewResourceOptions builds a preview of the effect of the provided options.
@@ -423,6 +433,8 @@ type resourceOptions struct {
 	PluginDownloadURL       string
 	RetainOnDelete          bool
 	DeletedWith             Resource
+	Parallelism             int
+	Retries                 int
 }
 
 func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
@@ -478,6 +490,8 @@ func resourceOptionsSnapshot(ro *resourceOptions) *ResourceOptions {
 		PluginDownloadURL:       ro.PluginDownloadURL,
 		RetainOnDelete:          ro.RetainOnDelete,
 		DeletedWith:             ro.DeletedWith,
+		Parallelism:             ro.Parallelism,
+		Retries:                 ro.Retries,
 	}
 }
 
@@ -888,3 +902,21 @@ func DeletedWith(r Resource) ResourceOption {
 		ro.DeletedWith = r
 	})
 }
+
+// Parallelism limits the number of operations that the engine runs concurrently on the resources managed by a
+// provider resource, in addition to the limit on all operations set by `pulumi up --parallel`. This is useful for
+// providers whose APIs are rate limited. It only applies to provider resources.
+func Parallelism(n int) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.Parallelism = n
+	})
+}
+
+// Retries sets the maximum number of times to retry a create or update of the resource that fails with a transient
+// error, e.g. because the provider was throttled. The retries back off exponentially. This overrides the number of
+// retries in the deployment's retry policy, if any.
+func Retries(n int) ResourceOption {
+	return resourceOption(func(ro *resourceOptions) {
+		ro.Retries = n
+	})
+}
@@ -1130,6 +1130,16 @@ func TestNewResourceOptions(t *testing.T) {
 			give: DeletedWith(&testRes{foo: "a"}),
 			want: ResourceOptions{DeletedWith: &testRes{foo: "a"}},
 		},
+		{
+			desc: "Parallelism",
+			give: Parallelism(2),
+			want: ResourceOptions{Parallelism: 2},
+		},
+		{
+			desc: "Retries",
+			give: Retries(3),
+			want: ResourceOptions{Retries: 3},
+		},
 	}
 
 	for _, tt := range tests {
@@ -52,6 +52,25 @@ export interface PolicyEvent {
     enforcementLevel: "warning" | "mandatory";
 }
 
+// ProgressType is the kind of work a ProgressEvent reports on.
+export type ProgressType = "plugin-download" | "plugin-install";
+
+// ProgressEvent is emitted as long running work, such as downloading a plugin, progresses.
+export interface ProgressEvent {
+    // type is the kind of work being reported on.
+    type: ProgressType;
+    // id identifies the work, so that its progress events can be grouped.
+    id: string;
+    // message describes the work, e.g. "Downloading plugin aws-6.0.0".
+    message: string;
+    // completed is the amount of work done, e.g. the number of bytes downloaded.
+    completed: number;
+    // total is the total amount of work, or 0 if it's unknown.
+    total: number;
+    // done is true when the work has finished.
+    done: boolean;
+}
+
 // PreludeEvent is emitted at the start of an update.
 export interface PreludeEvent {
     // config contains the keys and values for the update.
@@ -99,6 +118,16 @@ export interface PropertyDiff {
     inputDiff: boolean;
 }
 
+// IgnoredChange describes a change to a resource's inputs that was suppressed by an ignoreChanges rule.
+export interface IgnoredChange {
+    // path is the property path whose change was ignored.
+    path: string;
+    // rule is the ignoreChanges rule that matched the path.
+    rule: string;
+    // default is true if the rule is one of the stack's default ignoreChanges rules.
+    default?: boolean;
+}
+
 // StepEventMetadata describes a "step" within the Pulumi engine, which is any concrete action
 // to migrate a set of cloud resources from one state to another.
 export interface StepEventMetadata {
@@ -122,6 +151,8 @@ export interface StepEventMetadata {
     logical?: boolean;
     // Provider actually performing the step.
     provider: string;
+    // IgnoredChanges are the changes to the resource's inputs that were suppressed by ignoreChanges rules.
+    ignoredChanges?: IgnoredChange[];
 }
 
 // StepEventStateMetadata is the more detailed state information for a resource as it relates to
@@ -159,6 +190,13 @@ export interface ResourcePreEvent {
     planning?: boolean;
 }
 
+// ResourceWaitingEvent is emitted when an operation on a resource has to wait for a per-provider or
+// per-type parallelism limit before it can start. A ResourcePreEvent is emitted once it does.
+export interface ResourceWaitingEvent {
+    metadata: StepEventMetadata;
+    planning?: boolean;
+}
+
 // ResOutputsEvent is emitted when a resource is finished being provisioned.
 export interface ResOutputsEvent {
     metadata: StepEventMetadata;
@@ -195,7 +233,9 @@ export interface EngineEvent {
     preludeEvent?: PreludeEvent;
     summaryEvent?: SummaryEvent;
     resourcePreEvent?: ResourcePreEvent;
+    resourceWaitingEvent?: ResourceWaitingEvent;
     resOutputsEvent?: ResOutputsEvent;
     resOpFailedEvent?: ResOpFailedEvent;
     policyEvent?: PolicyEvent;
+    progressEvent?: ProgressEvent;
 }
@@ -265,6 +265,28 @@ function deserialize_pulumirpc_InvokeResponse(buffer_arg) {
   return pulumi_provider_pb.InvokeResponse.deserializeBinary(new Uint8Array(buffer_arg));
 }
 
+function serialize_pulumirpc_ListResourcesRequest(arg) {
+  if (!(arg instanceof pulumi_provider_pb.ListResourcesRequest)) {
+    throw new Error('Expected argument of type pulumirpc.ListResourcesRequest');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ListResourcesRequest(buffer_arg) {
+  return pulumi_provider_pb.ListResourcesRequest.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
+function serialize_pulumirpc_ListResourcesResponse(arg) {
+  if (!(arg instanceof pulumi_provider_pb.ListResourcesResponse)) {
+    throw new Error('Expected argument of type pulumirpc.ListResourcesResponse');
+  }
+  return Buffer.from(arg.serializeBinary());
+}
+
+function deserialize_pulumirpc_ListResourcesResponse(buffer_arg) {
+  return pulumi_provider_pb.ListResourcesResponse.deserializeBinary(new Uint8Array(buffer_arg));
+}
+
 function serialize_pulumirpc_PluginAttach(arg) {
   if (!(arg instanceof pulumi_plugin_pb.PluginAttach)) {
     throw new Error('Expected argument of type pulumirpc.PluginAttach');
@@ -577,6 +599,19 @@ getMappings: {
     responseSerialize: serialize_pulumirpc_GetMappingsResponse,
     responseDeserialize: deserialize_pulumirpc_GetMappingsResponse,
   },
+  // ListResources is an optional method that enumerates the existing resources of a given type, for example so that
+// they can be discovered and imported. Providers that do not support listing a type should return UNIMPLEMENTED.
+listResources: {
+    path: '/pulumirpc.ResourceProvider/ListResources',
+    requestStream: false,
+    responseStream: false,
+    requestType: pulumi_provider_pb.ListResourcesRequest,
+    responseType: pulumi_provider_pb.ListResourcesResponse,
+    requestSerialize: serialize_pulumirpc_ListResourcesRequest,
+    requestDeserialize: deserialize_pulumirpc_ListResourcesRequest,
+    responseSerialize: serialize_pulumirpc_ListResourcesResponse,
+    responseDeserialize: deserialize_pulumirpc_ListResourcesResponse,
+  },
 };
 
 exports.ResourceProviderClient = grpc.makeGenericClientConstructor(ResourceProviderService);
@@ -1387,7 +1387,9 @@ proto.pulumirpc.RegisterResourceRequest.toObject = function(includeInstance, msg
     pulumi_alias_pb.Alias.toObject, includeInstance),
     deletedwith: jspb.Message.getFieldWithDefault(msg, 27, ""),
     aliasspecs: jspb.Message.getBooleanFieldWithDefault(msg, 28, false),
-    sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f)
+    sourceposition: (f = msg.getSourceposition()) && pulumi_source_pb.SourcePosition.toObject(includeInstance, f),
+    parallelism: jspb.Message.getFieldWithDefault(msg, 31, 0),
+    retries: jspb.Message.getFieldWithDefault(msg, 32, 0)
   };
 
   if (includeInstance) {
@@ -1554,6 +1556,14 @@ proto.pulumirpc.RegisterResourceRequest.deserializeBinaryFromReader = function(m
       reader.readMessage(value,pulumi_source_pb.SourcePosition.deserializeBinaryFromReader);
       msg.setSourceposition(value);
       break;
+    case 31:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setParallelism(value);
+      break;
+    case 32:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setRetries(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -1788,6 +1798,20 @@ proto.pulumirpc.RegisterResourceRequest.serializeBinaryToWriter = function(messa
       pulumi_source_pb.SourcePosition.serializeBinaryToWriter
     );
   }
+  f = message.getParallelism();
+  if (f !== 0) {
+    writer.writeInt32(
+      31,
+      f
+    );
+  }
+  f = message.getRetries();
+  if (f !== 0) {
+    writer.writeInt32(
+      32,
+      f
+    );
+  }
 };
 
 
@@ -2861,6 +2885,42 @@ proto.pulumirpc.RegisterResourceRequest.prototype.hasSourceposition = function()
 };
 
 
+/**
+ * optional int32 parallelism = 31;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getParallelism = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 31, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setParallelism = function(value) {
+  return jspb.Message.setProto3IntField(this, 31, value);
+};
+
+
+/**
+ * optional int32 retries = 32;
+ * @return {number}
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.getRetries = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 32, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.pulumirpc.RegisterResourceRequest} returns this
+ */
+proto.pulumirpc.RegisterResourceRequest.prototype.setRetries = function(value) {
+  return jspb.Message.setProto3IntField(this, 32, value);
+};
+
+
 
 /**
  * List of repeated fields within this message type.
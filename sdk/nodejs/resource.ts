@@ -720,6 +720,18 @@ export interface ResourceOptions {
      * if specified is being deleted as well.
      */
     deletedWith?: Resource;
+    /**
+     * The maximum number of concurrent operations that the engine runs on the resources managed by this provider
+     * resource, in addition to the limit on all operations set by `pulumi up --parallel`. This is useful for providers
+     * whose APIs are rate limited. It only applies to provider resources.
+     */
+    parallelism?: number;
+    /**
+     * The maximum number of times to retry a create or update of this resource that fails with a transient error, e.g.
+     * because the provider was throttled. The retries back off exponentially. This overrides the number of retries in
+     * the deployment's retry policy, if any.
+     */
+    retries?: number;
 
     // !!! IMPORTANT !!! If you add a new field to this type, make sure to add test that verifies
     // that mergeOptions works properly for it.
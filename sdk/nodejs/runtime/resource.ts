@@ -35,7 +35,7 @@ import {
     URN,
 } from "../resource";
 import { debuggablePromise, debugPromiseLeaks } from "./debuggable";
-import { monitorSupportsDeletedWith } from "./settings";
+import { monitorSupportsDeletedWith, monitorSupportsParallelism, monitorSupportsRetries } from "./settings";
 import { invoke } from "./invoke";
 
 import {
@@ -455,6 +455,8 @@ export function registerResource(
             req.setPlugindownloadurl(opts.pluginDownloadURL || "");
             req.setRetainondelete(opts.retainOnDelete || false);
             req.setDeletedwith(resop.deletedWithURN || "");
+            req.setParallelism(opts.parallelism || 0);
+            req.setRetries(opts.retries || 0);
             req.setAliasspecs(true);
             req.setSourceposition(marshalSourcePosition(sourcePosition));
 
@@ -464,6 +466,16 @@ export function registerResource(
                 );
             }
 
+            if (opts.parallelism && !(await monitorSupportsParallelism())) {
+                throw new Error(
+                    "The Pulumi CLI does not support the Parallelism option. Please update the Pulumi CLI.",
+                );
+            }
+
+            if (opts.retries && !(await monitorSupportsRetries())) {
+                throw new Error("The Pulumi CLI does not support the Retries option. Please update the Pulumi CLI.");
+            }
+
             const customTimeouts = new resproto.RegisterResourceRequest.CustomTimeouts();
             if (opts.customTimeouts != null) {
                 customTimeouts.setCreate(opts.customTimeouts.create);
@@ -513,6 +513,22 @@ export async function monitorSupportsDeletedWith(): Promise<boolean> {
     return monitorSupportsFeature("deletedWith");
 }
 
+/**
+ * monitorSupportsParallelism returns a promise that when resolved tells you if the resource monitor we are
+ * connected to is able to support the parallelism resource option across its RPC interface.
+ */
+export async function monitorSupportsParallelism(): Promise<boolean> {
+    return monitorSupportsFeature("parallelism");
+}
+
+/**
+ * monitorSupportsRetries returns a promise that when resolved tells you if the resource monitor we are
+ * connected to is able to support the retries resource option across its RPC interface.
+ */
+export async function monitorSupportsRetries(): Promise<boolean> {
+    return monitorSupportsFeature("retries");
+}
+
 /**
  * monitorSupportsAliasSpecs returns a promise that when resolved tells you if the resource monitor we are
  * connected to is able to support alias specs across its RPC interface. When it does, we marshal aliases
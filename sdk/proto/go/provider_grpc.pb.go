@@ -75,6 +75,9 @@ type ResourceProviderClient interface {
 	// implement this method the engine falls back to the old behaviour of just calling GetMapping without a name.
 	// If this method is implemented than the engine will then call GetMapping only with the names returned from this method.
 	GetMappings(ctx context.Context, in *GetMappingsRequest, opts ...grpc.CallOption) (*GetMappingsResponse, error)
+	// ListResources is an optional method that enumerates the existing resources of a given type, for example so that
+	// they can be discovered and imported. Providers that do not support listing a type should return UNIMPLEMENTED.
+	ListResources(ctx context.Context, in *ListResourcesRequest, opts ...grpc.CallOption) (*ListResourcesResponse, error)
 }
 
 type resourceProviderClient struct {
@@ -279,6 +282,15 @@ func (c *resourceProviderClient) GetMappings(ctx context.Context, in *GetMapping
 	return out, nil
 }
 
+func (c *resourceProviderClient) ListResources(ctx context.Context, in *ListResourcesRequest, opts ...grpc.CallOption) (*ListResourcesResponse, error) {
+	out := new(ListResourcesResponse)
+	err := c.cc.Invoke(ctx, "/pulumirpc.ResourceProvider/ListResources", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // ResourceProviderServer is the server API for ResourceProvider service.
 // All implementations must embed UnimplementedResourceProviderServer
 // for forward compatibility
@@ -335,6 +347,9 @@ type ResourceProviderServer interface {
 	// implement this method the engine falls back to the old behaviour of just calling GetMapping without a name.
 	// If this method is implemented than the engine will then call GetMapping only with the names returned from this method.
 	GetMappings(context.Context, *GetMappingsRequest) (*GetMappingsResponse, error)
+	// ListResources is an optional method that enumerates the existing resources of a given type, for example so that
+	// they can be discovered and imported. Providers that do not support listing a type should return UNIMPLEMENTED.
+	ListResources(context.Context, *ListResourcesRequest) (*ListResourcesResponse, error)
 	mustEmbedUnimplementedResourceProviderServer()
 }
 
@@ -399,6 +414,9 @@ func (UnimplementedResourceProviderServer) GetMapping(context.Context, *GetMappi
 func (UnimplementedResourceProviderServer) GetMappings(context.Context, *GetMappingsRequest) (*GetMappingsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetMappings not implemented")
 }
+func (UnimplementedResourceProviderServer) ListResources(context.Context, *ListResourcesRequest) (*ListResourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListResources not implemented")
+}
 func (UnimplementedResourceProviderServer) mustEmbedUnimplementedResourceProviderServer() {}
 
 // UnsafeResourceProviderServer may be embedded to opt out of forward compatibility for this service.
@@ -757,6 +775,24 @@ func _ResourceProvider_GetMappings_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ResourceProvider_ListResources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListResourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ResourceProviderServer).ListResources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/pulumirpc.ResourceProvider/ListResources",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ResourceProviderServer).ListResources(ctx, req.(*ListResourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // ResourceProvider_ServiceDesc is the grpc.ServiceDesc for ResourceProvider service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -836,6 +872,10 @@ var ResourceProvider_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetMappings",
 			Handler:    _ResourceProvider_GetMappings_Handler,
 		},
+		{
+			MethodName: "ListResources",
+			Handler:    _ResourceProvider_ListResources_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
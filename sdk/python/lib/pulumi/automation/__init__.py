@@ -119,11 +119,15 @@ from .events import (
     DiagnosticEvent,
     DiffKind,
     EngineEvent,
+    IgnoredChange,
     PolicyEvent,
     PreludeEvent,
+    ProgressEvent,
+    ProgressType,
     PropertyDiff,
     ResOutputsEvent,
     ResourcePreEvent,
+    ResourceWaitingEvent,
     ResOpFailedEvent,
     StdoutEngineEvent,
     StepEventStateMetadata,
@@ -193,11 +197,15 @@ __all__ = [
     "DiagnosticEvent",
     "DiffKind",
     "EngineEvent",
+    "IgnoredChange",
     "PolicyEvent",
     "PreludeEvent",
+    "ProgressEvent",
+    "ProgressType",
     "PropertyDiff",
     "ResOutputsEvent",
     "ResourcePreEvent",
+    "ResourceWaitingEvent",
     "ResOpFailedEvent",
     "StdoutEngineEvent",
     "StepEventStateMetadata",
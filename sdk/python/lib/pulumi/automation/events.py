@@ -197,6 +197,63 @@ class PolicyEvent(BaseEvent):
         )
 
 
+class ProgressType(str, Enum):
+    """
+    The kind of work a ProgressEvent reports on.
+    """
+
+    PLUGIN_DOWNLOAD = "plugin-download"
+    PLUGIN_INSTALL = "plugin-install"
+
+
+class ProgressEvent(BaseEvent):
+    """
+    ProgressEvent is emitted as long running work, such as downloading a plugin, progresses.
+
+    Attributes
+    ----------
+    type: ProgressType
+        The kind of work being reported on.
+    id: str
+        Identifies the work, so that its progress events can be grouped.
+    message: str
+        Describes the work, e.g. "Downloading plugin aws-6.0.0".
+    completed: int
+        The amount of work done, e.g. the number of bytes downloaded.
+    total: int
+        The total amount of work, or 0 if it's unknown.
+    done: bool
+        True when the work has finished.
+    """
+
+    def __init__(
+        self,
+        type: ProgressType,  # pylint: disable=redefined-builtin
+        id: str,  # pylint: disable=redefined-builtin
+        message: str,
+        completed: int,
+        total: int,
+        done: bool,
+    ) -> None:
+        self.type = type
+        self.id = id
+        self.message = message
+        self.completed = completed
+        self.total = total
+        self.done = done
+
+    @classmethod
+    def from_json(cls, data: dict) -> "ProgressEvent":
+        return cls(
+            type=ProgressType(data.get("type")),
+            id=data.get("id", ""),
+            message=data.get("message", ""),
+            completed=data.get("completed", 0),
+            total=data.get("total", 0),
+            done=data.get("done", False),
+        )
+
+
 class PreludeEvent(BaseEvent):
     """
     PreludeEvent is emitted at the start of an update.
@@ -305,6 +362,34 @@ class PropertyDiff(BaseEvent):
         )
 
 
+class IgnoredChange(BaseEvent):
+    """
+    IgnoredChange describes a change to a resource's inputs that was suppressed by an ignoreChanges rule.
+
+    Attributes
+    ----------
+    path: str
+        path is the property path whose change was ignored.
+    rule: str
+        rule is the ignoreChanges rule that matched the path.
+    default: bool
+        default is true if the rule is one of the stack's default ignoreChanges rules.
+    """
+
+    def __init__(self, path: str, rule: str, default: bool = False) -> None:
+        self.path = path
+        self.rule = rule
+        self.default = default
+
+    @classmethod
+    def from_json(cls, data: dict) -> "IgnoredChange":
+        return cls(
+            path=data.get("path", ""),
+            rule=data.get("rule", ""),
+            default=data.get("default", False),
+        )
+
+
 class StepEventStateMetadata(BaseEvent):
     """
     StepEventStateMetadata is the more detailed state information for a resource as it relates to
@@ -412,6 +497,8 @@ class StepEventMetadata(BaseEvent):
         The diff for this step as a list of property paths and difference types.
     logical: Optional[bool]
         Logical is set if the step is a logical operation in the program.
+    ignored_changes: Optional[List[IgnoredChange]]
+        The changes to the resource's inputs that were suppressed by ignoreChanges rules.
     """
 
     def __init__(
@@ -426,6 +513,7 @@ class StepEventMetadata(BaseEvent):
         diffs: Optional[List[str]] = None,
         detailed_diff: Optional[Mapping[str, PropertyDiff]] = None,
         logical: Optional[bool] = None,
+        ignored_changes: Optional[List[IgnoredChange]] = None,
     ):
         self.op = op
         self.urn = urn
@@ -437,11 +525,13 @@ class StepEventMetadata(BaseEvent):
         self.diffs = diffs
         self.detailed_diff = detailed_diff
         self.logical = logical
+        self.ignored_changes = ignored_changes
 
     @classmethod
     def from_json(cls, data: dict) -> "StepEventMetadata":
         old = data.get("old")
         new = data.get("new")
+        ignored_changes = data.get("ignoredChanges")
 
         return cls(
             op=OpType(data.get("op", "")),
@@ -454,6 +544,9 @@ class StepEventMetadata(BaseEvent):
             diffs=data.get("diffs"),
             detailed_diff=data.get("detailed_diff"),
             logical=data.get("logical"),
+            ignored_changes=[IgnoredChange.from_json(c) for c in ignored_changes]
+            if ignored_changes
+            else None,
         )
 
 
@@ -475,6 +568,25 @@ class ResourcePreEvent(BaseEvent):
         )
 
 
+class ResourceWaitingEvent(BaseEvent):
+    """
+    ResourceWaitingEvent is emitted when an operation on a resource has to wait for a per-provider or
+    per-type parallelism limit before it can start. A ResourcePreEvent is emitted once it does.
+    """
+
+    def __init__(self, metadata: StepEventMetadata, planning: Optional[bool] = None):
+        self.metadata = metadata
+        self.planning = planning
+
+    @classmethod
+    def from_json(cls, data: dict) -> "ResourceWaitingEvent":
+        metadata: dict = data.get("metadata", {})
+        return cls(
+            metadata=StepEventMetadata.from_json(metadata),
+            planning=data.get("planning"),
+        )
+
+
 class ResOutputsEvent(BaseEvent):
     """
     ResOutputsEvent is emitted when a resource is finished being provisioned.
@@ -546,6 +658,8 @@ class EngineEvent(BaseEvent):
         res_outputs_event: Optional[ResOutputsEvent] = None,
         res_op_failed_event: Optional[ResOpFailedEvent] = None,
         policy_event: Optional[PolicyEvent] = None,
+        progress_event: Optional[ProgressEvent] = None,
+        resource_waiting_event: Optional[ResourceWaitingEvent] = None,
     ):
         self.sequence = sequence
         self.timestamp = timestamp
@@ -558,6 +672,8 @@ class EngineEvent(BaseEvent):
         self.res_outputs_event = res_outputs_event
         self.res_op_failed_event = res_op_failed_event
         self.policy_event = policy_event
+        self.progress_event = progress_event
+        self.resource_waiting_event = resource_waiting_event
 
     @classmethod
     def from_json(cls, data: dict) -> "EngineEvent":
@@ -569,6 +685,8 @@ class EngineEvent(BaseEvent):
         res_outputs_event = data.get("resOutputsEvent")
         res_op_failed_event = data.get("resOpFailedEvent")
         policy_event = data.get("policyEvent")
+        progress_event = data.get("progressEvent")
+        resource_waiting_event = data.get("resourceWaitingEvent")
 
         return cls(
             sequence=data.get("sequence", 0),
@@ -596,4 +714,10 @@ class EngineEvent(BaseEvent):
             if res_op_failed_event
             else None,
             policy_event=PolicyEvent.from_json(policy_event) if policy_event else None,
+            progress_event=ProgressEvent.from_json(progress_event)
+            if progress_event
+            else None,
+            resource_waiting_event=ResourceWaitingEvent.from_json(resource_waiting_event)
+            if resource_waiting_event
+            else None,
         )
@@ -373,6 +373,20 @@ class ResourceOptions:
     if specified resource is being deleted as well.
     """
 
+    parallelism: Optional[int]
+    """
+    The maximum number of concurrent operations that the engine runs on the resources managed by this provider
+    resource, in addition to the limit on all operations set by `pulumi up --parallel`. This is useful for providers
+    whose APIs are rate limited. It only applies to provider resources.
+    """
+
+    retries: Optional[int]
+    """
+    The maximum number of times to retry a create or update of this resource that fails with a transient error, e.g.
+    because the provider was throttled. The retries back off exponentially. This overrides the number of retries in
+    the deployment's retry policy, if any.
+    """
+
     # pylint: disable=redefined-builtin
     def __init__(
         self,
@@ -399,6 +413,8 @@ class ResourceOptions:
         plugin_download_url: Optional[str] = None,
         retain_on_delete: Optional[bool] = None,
         deleted_with: Optional["Resource"] = None,
+        parallelism: Optional[int] = None,
+        retries: Optional[int] = None,
     ) -> None:
         """
         :param Optional[Resource] parent: If provided, the currently-constructing resource should be the child of
@@ -441,6 +457,10 @@ class ResourceOptions:
         :param Optional[bool] retain_on_delete: If set to True, the providers Delete method will not be called for this resource.
         :param Optional[Resource] deleted_with: If set, the providers Delete method will not be called for this resource
                if specified resource is being deleted as well.
+        :param Optional[int] parallelism: The maximum number of concurrent operations that the engine runs on the
+               resources managed by this provider resource. It only applies to provider resources.
+        :param Optional[int] retries: The maximum number of times to retry a create or update of this resource that
+               fails with a transient error. This overrides the number of retries in the deployment's retry policy.
         """
 
         # Expose 'merge' again this this object, but this time as an instance method.
@@ -467,6 +487,8 @@ class ResourceOptions:
         self.depends_on = depends_on
         self.retain_on_delete = retain_on_delete
         self.deleted_with = deleted_with
+        self.parallelism = parallelism
+        self.retries = retries
 
         # Proactively check that `depends_on` values are of type
         # `Resource`. We cannot complete the check in the general case
@@ -635,6 +657,10 @@ class ResourceOptions:
         dest.deleted_with = (
             dest.deleted_with if source.deleted_with is None else source.deleted_with
         )
+        dest.parallelism = (
+            dest.parallelism if source.parallelism is None else source.parallelism
+        )
+        dest.retries = dest.retries if source.retries is None else source.retries
 
         # Now, if we are left with a .providers that is just a single key/value pair, then
         # collapse that down into .provider form.
@@ -1259,6 +1259,26 @@ class ErrorResourceInitFailed(google.protobuf.message.Message):
 
 global___ErrorResourceInitFailed = ErrorResourceInitFailed
 
+@typing_extensions.final
+class ErrorRetryable(google.protobuf.message.Message):
+    """ErrorRetryable is sent as a Detail when `ResourceProvider.{Create, Update}` fail because of a transient error, e.g.
+    because the provider was throttled or the service was unavailable, and so may succeed if they're retried.
+    """
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    REASON_FIELD_NUMBER: builtins.int
+    reason: builtins.str
+    """a short description of why the operation may succeed if it's retried."""
+    def __init__(
+        self,
+        *,
+        reason: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["reason", b"reason"]) -> None: ...
+
+global___ErrorRetryable = ErrorRetryable
+
 @typing_extensions.final
 class GetMappingRequest(google.protobuf.message.Message):
     """GetMappingRequest allows providers to return ecosystem specific information to allow the provider to be
@@ -1352,3 +1372,82 @@ class GetMappingsResponse(google.protobuf.message.Message):
     def ClearField(self, field_name: typing_extensions.Literal["providers", b"providers"]) -> None: ...
 
 global___GetMappingsResponse = GetMappingsResponse
+
+@typing_extensions.final
+class ListResourcesRequest(google.protobuf.message.Message):
+    """ListResourcesRequest asks a provider to enumerate the existing resources of a given type."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    TYPE_FIELD_NUMBER: builtins.int
+    FILTERS_FIELD_NUMBER: builtins.int
+    PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    type: builtins.str
+    """the type token of the resources to list."""
+    @property
+    def filters(self) -> google.protobuf.struct_pb2.Struct:
+        """optional provider-specific filters used to narrow the set of resources returned."""
+    page_token: builtins.str
+    """the continuation token returned by a previous call, if any."""
+    def __init__(
+        self,
+        *,
+        type: builtins.str = ...,
+        filters: google.protobuf.struct_pb2.Struct | None = ...,
+        page_token: builtins.str = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing_extensions.Literal["filters", b"filters"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing_extensions.Literal["filters", b"filters", "page_token", b"page_token", "type", b"type"]) -> None: ...
+
+global___ListResourcesRequest = ListResourcesRequest
+
+@typing_extensions.final
+class ListResourcesResponse(google.protobuf.message.Message):
+    """ListResourcesResponse returns a page of existing resources."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    RESOURCES_FIELD_NUMBER: builtins.int
+    NEXT_PAGE_TOKEN_FIELD_NUMBER: builtins.int
+    @property
+    def resources(self) -> google.protobuf.internal.containers.RepeatedCompositeFieldContainer[global___ListedResource]:
+        """the resources in this page of results."""
+    next_page_token: builtins.str
+    """a continuation token for the next page of results. If this is empty there are no more results."""
+    def __init__(
+        self,
+        *,
+        resources: collections.abc.Iterable[global___ListedResource] | None = ...,
+        next_page_token: builtins.str = ...,
+    ) -> None: ...
+    def ClearField(self, field_name: typing_extensions.Literal["next_page_token", b"next_page_token", "resources", b"resources"]) -> None: ...
+
+global___ListResourcesResponse = ListResourcesResponse
+
+@typing_extensions.final
+class ListedResource(google.protobuf.message.Message):
+    """ListedResource describes a single existing resource returned by ListResources."""
+
+    DESCRIPTOR: google.protobuf.descriptor.Descriptor
+
+    ID_FIELD_NUMBER: builtins.int
+    NAME_FIELD_NUMBER: builtins.int
+    PROPERTIES_FIELD_NUMBER: builtins.int
+    id: builtins.str
+    """the ID of the resource, as would be passed to Read or used to import the resource."""
+    name: builtins.str
+    """an optional name suggested by the provider for the resource, e.g. derived from its tags or display name."""
+    @property
+    def properties(self) -> google.protobuf.struct_pb2.Struct:
+        """optional properties of the resource that may help users decide whether to import it."""
+    def __init__(
+        self,
+        *,
+        id: builtins.str = ...,
+        name: builtins.str = ...,
+        properties: google.protobuf.struct_pb2.Struct | None = ...,
+    ) -> None: ...
+    def HasField(self, field_name: typing_extensions.Literal["properties", b"properties"]) -> builtins.bool: ...
+    def ClearField(self, field_name: typing_extensions.Literal["id", b"id", "name", b"name", "properties", b"properties"]) -> None: ...
+
+global___ListedResource = ListedResource
@@ -113,6 +113,11 @@ class ResourceProviderStub(object):
                 request_serializer=pulumi_dot_provider__pb2.GetMappingsRequest.SerializeToString,
                 response_deserializer=pulumi_dot_provider__pb2.GetMappingsResponse.FromString,
                 )
+        self.ListResources = channel.unary_unary(
+                '/pulumirpc.ResourceProvider/ListResources',
+                request_serializer=pulumi_dot_provider__pb2.ListResourcesRequest.SerializeToString,
+                response_deserializer=pulumi_dot_provider__pb2.ListResourcesResponse.FromString,
+                )
 
 
 class ResourceProviderServicer(object):
@@ -267,6 +272,14 @@ class ResourceProviderServicer(object):
         context.set_details('Method not implemented!')
         raise NotImplementedError('Method not implemented!')
 
+    def ListResources(self, request, context):
+        """ListResources is an optional method that enumerates the existing resources of a given type, for example so that
+        they can be discovered and imported. Providers that do not support listing a type should return UNIMPLEMENTED.
+        """
+        context.set_code(grpc.StatusCode.UNIMPLEMENTED)
+        context.set_details('Method not implemented!')
+        raise NotImplementedError('Method not implemented!')
+
 
 def add_ResourceProviderServicer_to_server(servicer, server):
     rpc_method_handlers = {
@@ -365,6 +378,11 @@ def add_ResourceProviderServicer_to_server(servicer, server):
                     request_deserializer=pulumi_dot_provider__pb2.GetMappingsRequest.FromString,
                     response_serializer=pulumi_dot_provider__pb2.GetMappingsResponse.SerializeToString,
             ),
+            'ListResources': grpc.unary_unary_rpc_method_handler(
+                    servicer.ListResources,
+                    request_deserializer=pulumi_dot_provider__pb2.ListResourcesRequest.FromString,
+                    response_serializer=pulumi_dot_provider__pb2.ListResourcesResponse.SerializeToString,
+            ),
     }
     generic_handler = grpc.method_handlers_generic_handler(
             'pulumirpc.ResourceProvider', rpc_method_handlers)
@@ -699,3 +717,20 @@ class ResourceProvider(object):
             pulumi_dot_provider__pb2.GetMappingsResponse.FromString,
             options, channel_credentials,
             insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
+
+    @staticmethod
+    def ListResources(request,
+            target,
+            options=(),
+            channel_credentials=None,
+            call_credentials=None,
+            insecure=False,
+            compression=None,
+            wait_for_ready=None,
+            timeout=None,
+            metadata=None):
+        return grpc.experimental.unary_unary(request, target, '/pulumirpc.ResourceProvider/ListResources',
+            pulumi_dot_provider__pb2.ListResourcesRequest.SerializeToString,
+            pulumi_dot_provider__pb2.ListResourcesResponse.FromString,
+            options, channel_credentials,
+            insecure, call_credentials, compression, wait_for_ready, timeout, metadata)
@@ -146,6 +146,13 @@ class ResourceProviderStub:
     implement this method the engine falls back to the old behaviour of just calling GetMapping without a name.
     If this method is implemented than the engine will then call GetMapping only with the names returned from this method.
     """
+    ListResources: grpc.UnaryUnaryMultiCallable[
+        pulumi.provider_pb2.ListResourcesRequest,
+        pulumi.provider_pb2.ListResourcesResponse,
+    ]
+    """ListResources is an optional method that enumerates the existing resources of a given type, for example so that
+    they can be discovered and imported. Providers that do not support listing a type should return UNIMPLEMENTED.
+    """
 
 class ResourceProviderServicer(metaclass=abc.ABCMeta):
     """ResourceProvider is a service that understands how to create, read, update, or delete resources for types defined
@@ -306,5 +313,14 @@ class ResourceProviderServicer(metaclass=abc.ABCMeta):
         implement this method the engine falls back to the old behaviour of just calling GetMapping without a name.
         If this method is implemented than the engine will then call GetMapping only with the names returned from this method.
         """
+    
+    def ListResources(
+        self,
+        request: pulumi.provider_pb2.ListResourcesRequest,
+        context: grpc.ServicerContext,
+    ) -> pulumi.provider_pb2.ListResourcesResponse:
+        """ListResources is an optional method that enumerates the existing resources of a given type, for example so that
+        they can be discovered and imported. Providers that do not support listing a type should return UNIMPLEMENTED.
+        """
 
 def add_ResourceProviderServicer_to_server(servicer: ResourceProviderServicer, server: typing.Union[grpc.Server, grpc.aio.Server]) -> None: ...
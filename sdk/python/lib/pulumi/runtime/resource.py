@@ -919,6 +919,16 @@ def register_resource(
                     "The Pulumi CLI does not support the DeletedWith option. Please update the Pulumi CLI."
                 )
 
+            if opts.parallelism and not await settings.monitor_supports_parallelism():
+                raise Exception(
+                    "The Pulumi CLI does not support the Parallelism option. Please update the Pulumi CLI."
+                )
+
+            if opts.retries and not await settings.monitor_supports_retries():
+                raise Exception(
+                    "The Pulumi CLI does not support the Retries option. Please update the Pulumi CLI."
+                )
+
             accept_resources = not (
                 os.getenv("PULUMI_DISABLE_RESOURCE_REFERENCES", "").upper()
                 in {"TRUE", "1"}
@@ -959,6 +969,8 @@ def register_resource(
                 replaceOnChanges=replace_on_changes or [],
                 retainOnDelete=opts.retain_on_delete or False,
                 deletedWith=resolver.deleted_with_urn or "",
+                parallelism=opts.parallelism or 0,
+                retries=opts.retries or 0,
                 sourcePosition=source_position,
             )
 
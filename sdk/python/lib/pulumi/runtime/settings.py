@@ -305,6 +305,14 @@ async def monitor_supports_deleted_with() -> bool:
     return await monitor_supports_feature("deletedWith")
 
 
+async def monitor_supports_parallelism() -> bool:
+    return await monitor_supports_feature("parallelism")
+
+
+async def monitor_supports_retries() -> bool:
+    return await monitor_supports_feature("retries")
+
+
 async def monitor_supports_alias_specs() -> bool:
     return await monitor_supports_feature("aliasSpecs")
 